@@ -17,9 +17,15 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
 
+	"k8s.io/kubernetes/test/e2e/perftype"
 	"k8s.io/perf-tests/benchmark/pkg/comparer"
 	"k8s.io/perf-tests/benchmark/pkg/metricsfetcher/runselector"
 	"k8s.io/perf-tests/benchmark/pkg/metricsfetcher/scraper"
@@ -40,6 +46,28 @@ var (
 	comparisonScheme          string
 	matchThreshold            float64
 	minMetricAvgForCompare    float64
+	comparisonTimeout         time.Duration
+	percentOfBaseline         bool
+	showSparklines            bool
+	explainDrops              bool
+	platformLabel             string
+	sizeBucketLabel           string
+	populationLabel           string
+	policyFile                string
+	annotationsFile           string
+	minEnforcedTier           string
+	maskLabelPattern          string
+	maxDetectableEffect       float64
+	ignoreBelow               float64
+	forceLoad                 bool
+	contextLabel              string
+	nodeLabel                 string
+	selfConsistency           bool
+	pruneEmpty                bool
+	previousReportFile        string
+	escalationThreshold       float64
+	hysteresisMargin          float64
+	reportFile                string
 )
 
 func registerFlags(fs *pflag.FlagSet) {
@@ -50,9 +78,31 @@ func registerFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&nHoursCount, "n-hours-count", 24, "Value of 'n' to use in the last-n-hours run-selection scheme")
 	fs.IntVar(&nRunsCount, "n-runs-count", 20, "Value of 'n' to use in the last-n-runs run-selection scheme")
 	fs.IntVar(&minAllowedAPIRequestCount, "min-allowed-api-request-count", 10, "The minimum requests count for an API call (within a particular test of a particular run) to be included for comparison")
-	fs.StringVar(&comparisonScheme, "comparison-scheme", comparer.AvgTest, fmt.Sprintf("Statistical test to be used as the algorithm for comparison. Allowed options: %v, %v", comparer.AvgTest, comparer.KSTest))
-	fs.Float64Var(&matchThreshold, "match-threshold", 0.66, "The threshold for metric comparison, interpretation depends on test used (significance level for KSTest, bound for ratio of avgs in AvgTest)")
+	fs.StringVar(&comparisonScheme, "comparison-scheme", comparer.AvgTest, fmt.Sprintf("Statistical test to be used as the algorithm for comparison. Allowed options: %v", strings.Join(util.ComparisonSchemeNames(), ", ")))
+	fs.Float64Var(&matchThreshold, "match-threshold", 0.66, "The threshold for metric comparison, interpretation depends on test used (significance level for KSTest, bound for ratio of avgs in AvgTest, max posterior probability of right job being slower in BayesTest, max z-score of right avg, i.e. Glass's delta, in ZTest, base of the max relative change of avgs, loosened for noisier metrics, in AdaptiveTest, bound for ratio of P95s of the runs' percentiles in PercentileTest, bound for ratio of expected shortfalls of the samples in ShortfallTest, bound for ratio of means of the runs' worst-node values in MaxOverNodesTest, max confidence of the right job having regressed in RegressionProbabilityTest, bound for ratio of Qn robust scales of the samples in QnTest, significance level of the t-test or Mann-Whitney U test chosen by the samples' distributions in AutoTest)")
 	fs.Float64Var(&minMetricAvgForCompare, "min-metric-avg-for-compare", 50.0, "The minimum value for a metric's avg to consider it for comparison. If in both left & right job the avg is less than this, it's directly marked as matched.")
+	fs.Float64Var(&ignoreBelow, "ignore-below", 0, "If positive, metrics whose avgs in both the left & right job are less than this are left out of the results altogether, e.g. to declutter them of sub-microsecond metrics")
+	fs.BoolVar(&pruneEmpty, "prune-empty", false, "Whether to leave the metrics without any samples on either side (e.g. as all their values got filtered out) out of the results, rather than showing them with undefined stats")
+	fs.DurationVar(&comparisonTimeout, "comparison-timeout", 0, "If positive, the max time for comparing the jobs. Metrics not compared within it are reported as timed out")
+	fs.StringVar(&policyFile, "policy-file", "", "Path to a JSON file with the regression policy to compare metrics with. If set, it overrides the comparison-scheme, match-threshold and min-metric-avg-for-compare flags")
+	fs.StringVar(&annotationsFile, "annotations-file", "", "Path to a JSON file annotating metrics with their importance tier and owner, whether they are rates, and the transform (none, log or sqrt) to compare them with")
+	fs.StringVar(&minEnforcedTier, "min-enforced-tier", util.TierP2.String(), "The least important tier whose mismatches are enforced. Mismatches of less important tiers are only informational")
+	fs.BoolVar(&percentOfBaseline, "percent-of-baseline", false, "Whether to also show the averages and stats as percents of the left job's average in the results")
+	fs.BoolVar(&explainDrops, "explain-drops", false, "Whether to log the metric values left out while flattening, along with the reasons")
+	fs.StringVar(&platformLabel, "platform-label", "", "If set, the DataItem label holding the platform the metrics were measured on, which is then part of their identity. Comparing metrics across platforms is then refused")
+	fs.StringVar(&sizeBucketLabel, "size-bucket-label", "", "If set, the DataItem label holding the request size bucket the metrics were measured for, which is then part of their identity so that each bucket is compared on its own")
+	fs.StringVar(&populationLabel, "population-label", "", "If set, the DataItem label holding the sub-population (e.g. Namespace or Tenant) the metrics were measured for, which is then part of their identity so that each sub-population is compared on its own, along with their rollup")
+	fs.StringVar(&contextLabel, "context-label", "", "If set, the DataItem label holding a numeric context of the runs (e.g. Nodes, the cluster size) to divide the metrics' values by, e.g. to compare the latencies per node of jobs on clusters of different sizes. Values without a valid context are left out")
+	fs.StringVar(&nodeLabel, "node-label", "", fmt.Sprintf("If set, the DataItem label holding the node (or pod) per-node metrics were measured on. The values of all the nodes are then compared, and each run's worst node is kept for the %v comparison scheme", comparer.MaxOverNodesTest))
+	fs.StringVar(&maskLabelPattern, "mask-label-pattern", "", "If set, a regexp matching portions of the metrics' test names, verbs, resources, etc to redact in the results, e.g. internal cluster names")
+	fs.Float64Var(&maxDetectableEffect, "max-detectable-effect", 0, "If positive, the largest relative change of a metric's avg the comparison must be able to detect (at 5% significance with 80% power). Noisier metrics are warned about before comparing, and flagged as insufficiently powered in the results")
+	fs.BoolVar(&selfConsistency, "self-consistency", false, "Whether to compare the left job's even runs against its odd ones instead of against the right job, for checking that its runs agree. The mismatches found are then false positives, showing the job's noise floor for the comparison-scheme and match-threshold used")
+	fs.BoolVar(&forceLoad, "force-load", false, fmt.Sprintf("Whether to load the metrics files whose schema version is out of the supported range (v%v to v%v) anyway, with a warning, rather than skipping them", scraper.MinSupportedSchemaVersion, scraper.MaxSupportedSchemaVersion))
+	fs.BoolVar(&showSparklines, "show-sparklines", false, "Whether to also show sparklines of the left and right samples in the results")
+	fs.StringVar(&previousReportFile, "previous-report", "", "Path to the JSON report of the previous comparison (as written to report-file). If set, metrics that regressed in it too are escalated as sustained regressions, with their severity raised")
+	fs.Float64Var(&escalationThreshold, "escalation-threshold", 0, "If positive, the relative change of a metric's avg in the regression direction from which it's escalated into a mismatch if it regressed in the previous-report, even if matched now")
+	fs.Float64Var(&hysteresisMargin, "hysteresis-margin", 0, "If positive, the relative margin of the hysteresis band around the ratio bounds of the ratio schemes (e.g. 0.02 for a 1.10 bound to be [1.078, 1.122]). Metrics are then only newly flagged once their ratio exceeds the bound by the margin, and only cleared once it's within it by the margin, as per their verdicts in the previous-report")
+	fs.StringVar(&reportFile, "report-file", "", "If set, path to write the JSON report of the comparison to, e.g. to be the previous-report of the next one")
 }
 
 // Select the runs of the left and right jobs to be used for comparison using the given run-selection scheme.
@@ -90,66 +140,211 @@ func getMetrics(leftJobRuns, rightJobRuns []int) *util.JobComparisonData {
 		glog.Fatalf("Couldn't obtain log utils: %v", err)
 	}
 
+	loadOptions := scraper.LoadOptions{ForceLoadUnsupportedSchemaVersions: forceLoad}
 	glog.Infof("Fetching metrics for the chosen runs of job %v", leftJobName)
-	leftJobLatencyMetrics := scraper.GetMetricsForRuns(leftJobName, leftJobRuns, utils)
+	leftJobLatencyMetrics := scraper.GetMetricsForRunsWithOptions(leftJobName, leftJobRuns, utils, loadOptions)
 	if leftJobLatencyMetrics == nil {
 		glog.Fatalf("Could not collect metrics even for a single run of the job")
 	}
 
-	glog.Infof("Fetching metrics for the chosen runs of job %v", rightJobName)
-	rightJobLatencyMetrics := scraper.GetMetricsForRuns(rightJobName, rightJobRuns, utils)
-	if rightJobLatencyMetrics == nil {
-		glog.Fatalf("Could not collect metrics even for a single run of the job")
+	var rightJobLatencyMetrics []map[string][]perftype.PerfData
+	if selfConsistency {
+		glog.Infof("Comparing the even runs of job %v against its odd ones, for self-consistency", leftJobName)
+		leftJobLatencyMetrics, rightJobLatencyMetrics = util.SplitRuns(leftJobLatencyMetrics, util.SplitAlternate)
+		if rightJobLatencyMetrics == nil {
+			glog.Fatalf("Could not collect metrics for more than a single run of the job")
+		}
+	} else {
+		glog.Infof("Fetching metrics for the chosen runs of job %v", rightJobName)
+		rightJobLatencyMetrics = scraper.GetMetricsForRunsWithOptions(rightJobName, rightJobRuns, utils, loadOptions)
+		if rightJobLatencyMetrics == nil {
+			glog.Fatalf("Could not collect metrics even for a single run of the job")
+		}
 	}
 
 	glog.Infof("Flattening the metrics maps into per-metric structs")
-	jobComparisonData := util.GetFlattennedComparisonData(leftJobLatencyMetrics, rightJobLatencyMetrics, minAllowedAPIRequestCount)
+	jobComparisonData := util.GetFlattennedComparisonDataWithOptions(leftJobLatencyMetrics, rightJobLatencyMetrics, util.FlattenOptions{
+		MinAllowedAPIRequestCount: minAllowedAPIRequestCount,
+		RecordDrops:               explainDrops,
+		PlatformLabel:             platformLabel,
+		SizeBucketLabel:           sizeBucketLabel,
+		PopulationLabel:           populationLabel,
+		KeepPopulationRollup:      populationLabel != "",
+		ContextLabel:              contextLabel,
+		NodeLabel:                 nodeLabel,
+	})
+	if platformLabel != "" {
+		if err := jobComparisonData.CompareSamePlatform(); err != nil {
+			glog.Fatalf("The jobs aren't comparable: %v", err)
+		}
+	}
+	for _, dropRecord := range jobComparisonData.DropLog() {
+		glog.Infof("Dropped %v", dropRecord)
+	}
+	compatibilityReport := jobComparisonData.CompatibilityReport()
+	glog.Infof("Metrics compatibility across the jobs: %v", compatibilityReport)
+	if compatibilityReport.OneSidedFraction() > 0.5 {
+		glog.Warningf("Most metrics are present only in one of the jobs, they may not be running the same tests")
+	}
 	return jobComparisonData
 }
 
+// Annotate the metrics with their importance tiers and owners, if an annotations file is given.
+func annotate(jobComparisonData *util.JobComparisonData) {
+	if annotationsFile == "" {
+		return
+	}
+	annotations, err := util.LoadAnnotations(annotationsFile)
+	if err != nil {
+		glog.Fatalf("Failed to load the annotations: %v", err)
+	}
+	jobComparisonData.Annotate(annotations)
+}
+
+// Parameters of the minimum detectable effect of the metrics checked against the max-detectable-effect.
+const (
+	powerCheckAlpha = 0.05
+	powerCheckPower = 0.8
+)
+
+// Warn about the metrics too noisy for the comparison to detect the max-detectable-effect, if set.
+func checkPower(jobComparisonData *util.JobComparisonData) {
+	if maxDetectableEffect <= 0 {
+		return
+	}
+	effects := jobComparisonData.MinDetectableEffect(powerCheckAlpha, powerCheckPower)
+	for _, metricKey := range jobComparisonData.UnderpoweredMetrics(powerCheckAlpha, powerCheckPower, maxDetectableEffect) {
+		glog.Warningf("Metric %v is insufficiently powered: it can only reliably detect changes of %.1f%% (vs %.1f%% wanted)", metricKey, 100*effects[metricKey], 100*maxDetectableEffect)
+	}
+}
+
 // Compare jobs using the metrics data given with the chosen comparison scheme.
 func compare(jobComparisonData *util.JobComparisonData) {
+	if maxDetectableEffect > 0 {
+		defer jobComparisonData.FlagUnderpoweredMetrics(powerCheckAlpha, powerCheckPower, maxDetectableEffect)
+	}
+	ctx := context.Background()
+	if comparisonTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, comparisonTimeout)
+		defer cancel()
+	}
+	if policyFile != "" {
+		glog.Infof("Comparing metrics for the jobs using policy from %v", policyFile)
+		policy, err := util.LoadPolicy(policyFile)
+		if err != nil {
+			glog.Fatalf("Failed to load the policy: %v", err)
+		}
+		if err := jobComparisonData.ApplyPolicyWithContext(ctx, policy); err != nil {
+			glog.Fatalf("Failed to compare the jobs: %v", err)
+		}
+		return
+	}
 	glog.Infof("Comparing metrics for the jobs using scheme '%v' at a threshold value of %v (with min-metric-avg-for-compare=%v)", comparisonScheme, matchThreshold, minMetricAvgForCompare)
-	err := comparer.CompareJobsUsingScheme(jobComparisonData, comparisonScheme, matchThreshold, minMetricAvgForCompare)
+	err := comparer.CompareJobsUsingSchemeWithContext(ctx, jobComparisonData, comparisonScheme, matchThreshold, minMetricAvgForCompare)
 	if err != nil {
 		glog.Fatalf("Failed to compare the jobs: %v", err)
 	}
 }
 
+// Load the report of the previous comparison, if set.
+func loadPreviousReport() *util.JobComparisonData {
+	if previousReportFile == "" {
+		return nil
+	}
+	previous, err := util.LoadJSONReport(previousReportFile)
+	if err != nil {
+		glog.Fatalf("Failed to load the previous report: %v", err)
+	}
+	return previous
+}
+
+// Escalate the metrics that regressed in the previous report too, if set.
+func escalate(jobComparisonData, previous *util.JobComparisonData) {
+	if previous == nil {
+		return
+	}
+	for _, metricKey := range jobComparisonData.EscalateSustainedRegressions(previous, escalationThreshold) {
+		glog.Warningf("Metric %v is a sustained regression across %v comparisons", metricKey, jobComparisonData.Data[metricKey].SustainedRegressionCount)
+	}
+}
+
+// Write the JSON report of the comparison, if set.
+func writeReport(jobComparisonData *util.JobComparisonData) {
+	if reportFile == "" {
+		return
+	}
+	file, err := os.Create(reportFile)
+	if err != nil {
+		glog.Fatalf("Failed to create the report file: %v", err)
+	}
+	defer file.Close()
+	if err := jobComparisonData.WriteJSON(file, false); err != nil {
+		glog.Fatalf("Failed to write the report: %v", err)
+	}
+}
+
+// Pretty print the comparison data of metrics not filtered out.
+func printTable(jobComparisonData *util.JobComparisonData, filter util.MetricFilterFunc) {
+	options := util.PrettyPrintOptions{Filter: filter, PercentOfBaseline: percentOfBaseline, Sparklines: showSparklines}
+	if annotationsFile != "" {
+		enforcedTier := enforcedTier()
+		options.EnforcedTier = &enforcedTier
+	}
+	if maskLabelPattern != "" {
+		pattern, err := regexp.Compile(maskLabelPattern)
+		if err != nil {
+			glog.Fatalf("Invalid mask-label-pattern: %v", err)
+		}
+		options.Mask = &util.LabelMask{Pattern: pattern}
+	}
+	jobComparisonData.PrettyPrintWithOptions(options)
+}
+
+func enforcedTier() util.Tier {
+	tier, err := util.ParseTier(minEnforcedTier)
+	if err != nil {
+		glog.Fatalf("Invalid min-enforced-tier: %v", err)
+	}
+	return tier
+}
+
 // Pretty print results of the comparison.
 func printResults(jobComparisonData *util.JobComparisonData) {
 	glog.Infof("Comparison results for 99th percentile of latency metrics:")
 	glog.Infof("Mismatched metrics:")
-	jobComparisonData.PrettyPrintWithFilter(func(k util.MetricKey, d util.MetricComparisonData) bool {
+	printTable(jobComparisonData, func(k util.MetricKey, d util.MetricComparisonData) bool {
 		return k.Percentile != "Perc99" || d.Matched
 	})
 	glog.Infof("")
 	glog.Infof("Matched metrics:")
-	jobComparisonData.PrettyPrintWithFilter(func(k util.MetricKey, d util.MetricComparisonData) bool {
+	printTable(jobComparisonData, func(k util.MetricKey, d util.MetricComparisonData) bool {
 		return k.Percentile != "Perc99" || !d.Matched
 	})
 	glog.Infof("")
 	glog.Infof("Comparison results for 90th percentile of latency metrics:")
 	glog.Infof("Mismatched metrics:")
-	jobComparisonData.PrettyPrintWithFilter(func(k util.MetricKey, d util.MetricComparisonData) bool {
+	printTable(jobComparisonData, func(k util.MetricKey, d util.MetricComparisonData) bool {
 		return k.Percentile != "Perc90" || d.Matched
 	})
 	glog.Infof("")
 	glog.Infof("Matched metrics:")
-	jobComparisonData.PrettyPrintWithFilter(func(k util.MetricKey, d util.MetricComparisonData) bool {
+	printTable(jobComparisonData, func(k util.MetricKey, d util.MetricComparisonData) bool {
 		return k.Percentile != "Perc90" || !d.Matched
 	})
 	glog.Infof("")
 	glog.Infof("Comparison results for 50th percentile of latency metrics:")
 	glog.Infof("Mismatched metrics:")
-	jobComparisonData.PrettyPrintWithFilter(func(k util.MetricKey, d util.MetricComparisonData) bool {
+	printTable(jobComparisonData, func(k util.MetricKey, d util.MetricComparisonData) bool {
 		return k.Percentile != "Perc50" || d.Matched
 	})
 	glog.Infof("")
 	glog.Infof("Matched metrics:")
-	jobComparisonData.PrettyPrintWithFilter(func(k util.MetricKey, d util.MetricComparisonData) bool {
+	printTable(jobComparisonData, func(k util.MetricKey, d util.MetricComparisonData) bool {
 		return k.Percentile != "Perc50" || !d.Matched
 	})
+	glog.Infof("")
+	glog.Infof("All metrics of tier %v or more important matched: %v", minEnforcedTier, jobComparisonData.AllMatched(enforcedTier()))
 }
 
 func main() {
@@ -161,6 +356,26 @@ func main() {
 	// Perform comparison.
 	leftJobRuns, rightJobRuns := selectRuns()
 	jobComparisonData := getMetrics(leftJobRuns, rightJobRuns)
+	annotate(jobComparisonData)
+	checkPower(jobComparisonData)
+	previous := loadPreviousReport()
+	if previous != nil && hysteresisMargin > 0 {
+		jobComparisonData.SetHysteresis(previous, hysteresisMargin)
+	}
 	compare(jobComparisonData)
+	if ignoreBelow > 0 {
+		jobComparisonData.IgnoreBelow(ignoreBelow)
+	}
+	if pruneEmpty {
+		jobComparisonData.PruneEmpty()
+	}
+	escalate(jobComparisonData, previous)
 	printResults(jobComparisonData)
+	writeReport(jobComparisonData)
+	for _, metricKey := range jobComparisonData.MaskedPopulationRegressions() {
+		glog.Warningf("Metric %v regressed, though the rollup of its sub-populations didn't", metricKey)
+	}
+	if selfConsistency {
+		glog.Infof("False-positive rate of the self-consistency check: %.1f%%", 100*jobComparisonData.FalsePositiveRate())
+	}
 }
@@ -0,0 +1,122 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package comparer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/kubernetes/test/e2e/perftype"
+	"k8s.io/perf-tests/benchmark/pkg/util"
+)
+
+// JobPair bundles the metrics of the runs of a left and right job to be compared.
+type JobPair struct {
+	Name            string
+	LeftJobMetrics  []map[string][]perftype.PerfData
+	RightJobMetrics []map[string][]perftype.PerfData
+}
+
+// Options controls how CompareBatch compares each of the job pairs.
+type Options struct {
+	Scheme                 string
+	MatchThreshold         float64
+	MinMetricAvgForCompare float64
+	// Policy, if set, is used for comparison instead of the fields above.
+	Policy         *util.Policy
+	FlattenOptions util.FlattenOptions
+	// Parallelism is the max no. of pairs compared concurrently (sequential if < 2).
+	Parallelism int
+	// Timeout, if positive, bounds the comparison of each pair. Metrics not compared
+	// within it are marked as timed out.
+	Timeout time.Duration
+}
+
+// BatchError holds the errors for the job pairs that failed to be compared,
+// each of them wrapped with the name of its pair.
+type BatchError struct {
+	Errors []error
+}
+
+func (e *BatchError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+func comparePair(pair JobPair, opts Options) (*util.JobComparisonData, error) {
+	if len(pair.LeftJobMetrics) == 0 || len(pair.RightJobMetrics) == 0 {
+		return nil, fmt.Errorf("no metrics for the runs of both jobs")
+	}
+	jobComparisonData := util.GetFlattennedComparisonDataWithOptions(pair.LeftJobMetrics, pair.RightJobMetrics, opts.FlattenOptions)
+	ctx := context.Background()
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+	if opts.Policy != nil {
+		return jobComparisonData, jobComparisonData.ApplyPolicyWithContext(ctx, opts.Policy)
+	}
+	return jobComparisonData, CompareJobsUsingSchemeWithContext(ctx, jobComparisonData, opts.Scheme, opts.MatchThreshold, opts.MinMetricAvgForCompare)
+}
+
+// CompareBatch flattens and compares each of the given job pairs, returning their
+// comparison data in the same order as the pairs. If some pairs fail to be compared,
+// their entries are nil and a *BatchError holding their errors is returned too.
+func CompareBatch(pairs []JobPair, opts Options) ([]*util.JobComparisonData, error) {
+	results := make([]*util.JobComparisonData, len(pairs))
+	errs := make([]error, len(pairs))
+	parallelism := opts.Parallelism
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, parallelism)
+	for i := range pairs {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			jobComparisonData, err := comparePair(pairs[i], opts)
+			if err != nil {
+				errs[i] = fmt.Errorf("job pair '%v': %w", pairs[i].Name, err)
+				return
+			}
+			results[i] = jobComparisonData
+		}(i)
+	}
+	wg.Wait()
+
+	batchErr := &BatchError{}
+	for _, err := range errs {
+		if err != nil {
+			batchErr.Errors = append(batchErr.Errors, err)
+		}
+	}
+	if len(batchErr.Errors) > 0 {
+		return results, batchErr
+	}
+	return results, nil
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package comparer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"k8s.io/kubernetes/test/e2e/perftype"
+	"k8s.io/perf-tests/benchmark/pkg/util"
+)
+
+func runMetricsWithLatency(latency float64) map[string][]perftype.PerfData {
+	return map[string][]perftype.PerfData{
+		"Load": {
+			{
+				Version: "v1",
+				DataItems: []perftype.DataItem{
+					{
+						Data:   map[string]float64{"Perc99": latency},
+						Unit:   "ms",
+						Labels: map[string]string{"Count": "100", "Resource": "pods", "Verb": "LIST"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestCompareBatch(t *testing.T) {
+	metricKey := util.MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}
+	var pairs []JobPair
+	for i := 0; i < 10; i++ {
+		pairs = append(pairs, JobPair{
+			Name:            fmt.Sprintf("pair-%v", i),
+			LeftJobMetrics:  []map[string][]perftype.PerfData{runMetricsWithLatency(100)},
+			RightJobMetrics: []map[string][]perftype.PerfData{runMetricsWithLatency(float64(100 + 10*i))},
+		})
+	}
+	// Pair 3 has no runs for its right job.
+	pairs[3].RightJobMetrics = nil
+
+	for _, parallelism := range []int{0, 4} {
+		opts := Options{Scheme: AvgTest, MatchThreshold: 0.7, Parallelism: parallelism}
+		results, err := CompareBatch(pairs, opts)
+		var batchErr *BatchError
+		if !errors.As(err, &batchErr) || len(batchErr.Errors) != 1 || !strings.Contains(err.Error(), "pair-3") {
+			t.Fatalf("Expected a batch error for pair-3 with parallelism %v, got: %v", parallelism, err)
+		}
+		if len(results) != len(pairs) || results[3] != nil {
+			t.Fatalf("Expected %v results with a nil one for pair-3, got: %v", len(pairs), results)
+		}
+		for i, result := range results {
+			if i == 3 {
+				continue
+			}
+			// Results must be in input order, with ratios of avgs dropping below 0.7 from pair-5.
+			if avgR := result.Data[metricKey].AvgR; avgR != float64(100+10*i) {
+				t.Errorf("Result %v has right avg %v, but expected %v (parallelism %v)", i, avgR, 100+10*i, parallelism)
+			}
+			if matched := result.Data[metricKey].Matched; matched != (i < 5) {
+				t.Errorf("Result %v has matched=%v, but expected %v (parallelism %v)", i, matched, i < 5, parallelism)
+			}
+		}
+	}
+
+	_, err := CompareBatch(pairs[:1], Options{Scheme: "Unknown-Test"})
+	if err == nil || !strings.Contains(err.Error(), "pair-0") {
+		t.Errorf("Expected an error for the unknown scheme wrapped with the pair name, got: %v", err)
+	}
+}
@@ -17,7 +17,7 @@ limitations under the License.
 package comparer
 
 import (
-	"fmt"
+	"context"
 
 	"k8s.io/perf-tests/benchmark/pkg/comparer/schemes"
 	"k8s.io/perf-tests/benchmark/pkg/util"
@@ -25,22 +25,58 @@ import (
 
 // Allowed comparison schemes.
 const (
-	AvgTest = "Avg-Test"
-	KSTest  = "KS-Test"
+	AvgTest                   = "Avg-Test"
+	KSTest                    = "KS-Test"
+	BayesTest                 = "Bayes-Test"
+	ZTest                     = "Z-Test"
+	AdaptiveTest              = "Adaptive-Test"
+	PercentileTest            = "Percentile-Test"
+	ShortfallTest             = "Shortfall-Test"
+	MaxOverNodesTest          = "Max-Over-Nodes-Test"
+	RegressionProbabilityTest = "Regression-Probability-Test"
+	QnTest                    = "Qn-Test"
+	AutoTest                  = "Auto-Test"
 )
 
+func init() {
+	// matchThreshold is interpreted as the bound for ratio of left and right sample avgs for this test.
+	util.RegisterComparisonScheme(AvgTest, schemes.CompareJobsUsingAvgTest)
+	// matchThreshold is interpreted as the allowed significance value for this test.
+	util.RegisterContextComparisonScheme(KSTest, schemes.CompareJobsUsingKSTestWithContext)
+	// matchThreshold is interpreted as the max allowed posterior probability of right job being slower.
+	util.RegisterContextComparisonScheme(BayesTest, schemes.CompareJobsUsingBayesianTestWithContext)
+	// matchThreshold is interpreted as the max allowed no. of left std-devs between the left and right avgs.
+	util.RegisterComparisonScheme(ZTest, schemes.CompareJobsUsingZScoreTest)
+	// matchThreshold is interpreted as the base of the allowed relative change of avgs, loosened for noisier metrics.
+	util.RegisterComparisonScheme(AdaptiveTest, schemes.CompareJobsUsingAdaptiveThresholdTest)
+	// matchThreshold is interpreted as the bound for ratio of left and right samples' P95s for this test.
+	util.RegisterComparisonScheme(PercentileTest, schemes.CompareJobsUsingPercentileOfSamplesTest)
+	// matchThreshold is interpreted as the bound for ratio of left and right samples' expected shortfalls for this test.
+	util.RegisterComparisonScheme(ShortfallTest, schemes.CompareJobsUsingShortfallTest)
+	// matchThreshold is interpreted as the bound for ratio of left and right means of the runs' worst-node values for this test.
+	util.RegisterComparisonScheme(MaxOverNodesTest, schemes.CompareMaxOverNodes)
+	// matchThreshold is interpreted as the max allowed probability of the right job having regressed.
+	util.RegisterComparisonScheme(RegressionProbabilityTest, schemes.CompareJobsUsingRegressionProbabilityTest)
+	// matchThreshold is interpreted as the bound for ratio of left and right samples' Qn robust scales for this test.
+	util.RegisterComparisonScheme(QnTest, schemes.CompareJobsUsingQnTest)
+	// matchThreshold is interpreted as the allowed significance value of the test chosen (by the samples' distributions) for this test.
+	util.RegisterComparisonScheme(AutoTest, schemes.CompareJobsUsingAutoTest)
+}
+
 // CompareJobsUsingScheme is a wrapper function for various comparison schemes.
 func CompareJobsUsingScheme(jobComparisonData *util.JobComparisonData, scheme string, matchThreshold, minMetricAvgForCompare float64) error {
-	switch scheme {
-	case AvgTest:
-		// matchThreshold is interpreted as the bound for ratio of left and right sample avgs for this test.
-		schemes.CompareJobsUsingAvgTest(jobComparisonData, matchThreshold, minMetricAvgForCompare)
-		return nil
-	case KSTest:
-		// matchThreshold is interpreted as the allowed significance value for this test.
-		schemes.CompareJobsUsingKSTest(jobComparisonData, matchThreshold, minMetricAvgForCompare)
-		return nil
-	default:
-		return fmt.Errorf("unknown comparison scheme '%v'", scheme)
+	return CompareJobsUsingSchemeWithContext(context.Background(), jobComparisonData, scheme, matchThreshold, minMetricAvgForCompare)
+}
+
+// CompareJobsUsingSchemeWithContext is like CompareJobsUsingScheme, but bounds the comparison by
+// the context. Metrics not compared by the time it's done are marked as timed out. Metrics with
+// a transform are compared on the transformed scale, and the ratios with hysteresis if set (see
+// util.WithMetricSettings).
+func CompareJobsUsingSchemeWithContext(ctx context.Context, jobComparisonData *util.JobComparisonData, scheme string, matchThreshold, minMetricAvgForCompare float64) error {
+	compare, err := util.GetContextComparisonScheme(scheme)
+	if err != nil {
+		return err
 	}
+	util.WithMetricSettings(compare)(ctx, jobComparisonData, matchThreshold, minMetricAvgForCompare)
+	return nil
 }
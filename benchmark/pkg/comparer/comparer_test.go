@@ -0,0 +1,68 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package comparer
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/perf-tests/benchmark/pkg/util"
+)
+
+func TestCompareJobsUsingSchemeWithContext(t *testing.T) {
+	newJobComparisonData := func() *util.JobComparisonData {
+		return &util.JobComparisonData{
+			Data: map[util.MetricKey]*util.MetricComparisonData{
+				{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}: {
+					LeftJobSample:     []float64{100, 110},
+					RightJobSample:    []float64{200, 210},
+					LeftJobNodeMaxes:  []float64{100, 110},
+					RightJobNodeMaxes: []float64{200, 210},
+				},
+			},
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Both the context-aware schemes and the others time out on a done context.
+	for _, scheme := range []string{AvgTest, KSTest, BayesTest, ZTest, AdaptiveTest, PercentileTest, ShortfallTest, MaxOverNodesTest, RegressionProbabilityTest, QnTest, AutoTest} {
+		jobComparisonData := newJobComparisonData()
+		if err := CompareJobsUsingSchemeWithContext(ctx, jobComparisonData, scheme, 0.5, 0); err != nil {
+			t.Fatalf("Comparison using %v failed: %v", scheme, err)
+		}
+		for metricKey, metricData := range jobComparisonData.Data {
+			if !metricData.Inconclusive || metricData.Matched {
+				t.Errorf("Metric %v not marked as timed out by %v with a cancelled context: %+v", metricKey, scheme, metricData)
+			}
+		}
+
+		jobComparisonData = newJobComparisonData()
+		if err := CompareJobsUsingSchemeWithContext(context.Background(), jobComparisonData, scheme, 0.5, 0); err != nil {
+			t.Fatalf("Comparison using %v failed: %v", scheme, err)
+		}
+		for metricKey, metricData := range jobComparisonData.Data {
+			if metricData.Inconclusive {
+				t.Errorf("Metric %v marked as timed out by %v without bound: %+v", metricKey, scheme, metricData)
+			}
+		}
+	}
+
+	if err := CompareJobsUsingSchemeWithContext(ctx, newJobComparisonData(), "Unknown-Test", 0.5, 0); err == nil {
+		t.Errorf("Expected an error for an unknown scheme")
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemes
+
+import (
+	"fmt"
+	"math"
+
+	"k8s.io/perf-tests/benchmark/pkg/util"
+)
+
+// AdaptiveThresholdOptions tune how the adaptive threshold test sets each metric's threshold.
+// The threshold on the relative change of a metric's avg is
+//
+//	clamp(BaseThreshold + NoiseMultiplier * CV, MinThreshold, MaxThreshold)
+//
+// where CV is the coefficient of variation (std-dev over avg) of its left (baseline) sample.
+// So a stable metric (of CV close to 0) is held to about the base threshold, while a noisy one
+// gets a threshold that much looser, as its avg shifts by that much from noise alone, up to
+// the max threshold for its regressions to still be caught.
+type AdaptiveThresholdOptions struct {
+	BaseThreshold   float64 // Threshold for a noise-free metric, e.g. 0.1 for a 10% change
+	NoiseMultiplier float64 // Increase of the threshold per unit of CV
+	MinThreshold    float64 // Lower bound of the thresholds
+	MaxThreshold    float64 // Upper bound of the thresholds
+}
+
+// Defaults of AdaptiveThresholdOptions, other than the base threshold.
+const (
+	DefaultAdaptiveNoiseMultiplier = 2
+	DefaultAdaptiveMaxThreshold    = 0.5
+)
+
+// DefaultAdaptiveThresholdOptions returns the options of the adaptive threshold test for the
+// given base threshold, which is also its lower bound.
+func DefaultAdaptiveThresholdOptions(baseThreshold float64) AdaptiveThresholdOptions {
+	return AdaptiveThresholdOptions{
+		BaseThreshold:   baseThreshold,
+		NoiseMultiplier: DefaultAdaptiveNoiseMultiplier,
+		MinThreshold:    baseThreshold,
+		MaxThreshold:    math.Max(baseThreshold, DefaultAdaptiveMaxThreshold),
+	}
+}
+
+// threshold returns the threshold for a metric whose left sample has the given CV.
+func (o AdaptiveThresholdOptions) threshold(cv float64) float64 {
+	return math.Min(math.Max(o.BaseThreshold+o.NoiseMultiplier*cv, o.MinThreshold), o.MaxThreshold)
+}
+
+// CompareJobsUsingAdaptiveThresholdTest is like CompareJobsUsingAdaptiveThreshold, with the
+// default options for the given base threshold.
+func CompareJobsUsingAdaptiveThresholdTest(jobComparisonData *util.JobComparisonData, baseThreshold, minMetricAvgForCompare float64) {
+	CompareJobsUsingAdaptiveThreshold(jobComparisonData, DefaultAdaptiveThresholdOptions(baseThreshold), minMetricAvgForCompare)
+}
+
+// CompareJobsUsingAdaptiveThreshold takes a JobComparisonData object, compares left and right
+// job samples of each metric inside it and fills in the comparison results in the metric's
+// object after checking that the relative change of the avg (either way) is within the
+// metric's threshold, which scales with the noise of its left sample as per the options.
+// Metrics for which the relative change can't be computed (e.g. a zero left average) are
+// marked inconclusive.
+func CompareJobsUsingAdaptiveThreshold(jobComparisonData *util.JobComparisonData, options AdaptiveThresholdOptions, minMetricAvgForCompare float64) {
+	jobComparisonData.EnsureStats()
+	for _, metricData := range jobComparisonData.Data {
+		leftSampleCount := len(metricData.LeftJobSample)
+		rightSampleCount := len(metricData.RightJobSample)
+		metricData.ResetVerdict()
+		if leftSampleCount == 0 || rightSampleCount == 0 {
+			metricData.Matched = true
+			continue
+		}
+		cv, cvOK := util.SafeDiv(metricData.StDevL, metricData.AvgL)
+		ratio, ratioOK := metricData.RatioOnOriginalScale(metricData.AvgR, metricData.AvgL)
+		change, threshold := ratio-1, options.threshold(math.Abs(cv))
+		comments := fmt.Sprintf("Change=%+.1f%%\tCV-L=%.3f\tThreshold=%.1f%%\tAvgL(ms)=%.2f\tAvgR(ms)=%.2f\tN1=%v\tN2=%v", 100*change, cv, 100*threshold, metricData.AvgL, metricData.AvgR, leftSampleCount, rightSampleCount)
+		switch {
+		case metricData.BelowMinAvg(minMetricAvgForCompare):
+			metricData.Matched = true
+		case !cvOK || !ratioOK:
+			metricData.MarkInconclusive(util.CannotComputeRatio + "\t" + comments)
+			continue
+		case math.Abs(change) <= threshold:
+			metricData.Matched = true
+		}
+		metricData.Comments = comments
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemes
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"k8s.io/perf-tests/benchmark/pkg/util"
+)
+
+func TestCompareJobsUsingAdaptiveThreshold(t *testing.T) {
+	stable := util.MetricKey{TestName: "swag", Verb: "GET", Resource: "node", Percentile: "Perc99"}
+	noisy := util.MetricKey{TestName: "swag", Verb: "PUT", Resource: "pods", Percentile: "Perc99"}
+	zeroAvg := util.MetricKey{TestName: "swag", Verb: "LIST", Resource: "rc", Percentile: "Perc99"}
+	newJobComparisonData := func(shift float64) *util.JobComparisonData {
+		return &util.JobComparisonData{
+			Data: map[util.MetricKey]*util.MetricComparisonData{
+				// A CV of 0.01, for a threshold of 10% + 2%.
+				stable: {LeftJobSample: []float64{99, 101}, RightJobSample: []float64{100 * (1 + shift)}},
+				// A CV of 0.15, for a threshold of 10% + 30%.
+				noisy:   {LeftJobSample: []float64{85, 115}, RightJobSample: []float64{100 * (1 + shift)}},
+				zeroAvg: {LeftJobSample: []float64{0, 0}, RightJobSample: []float64{1}},
+			},
+		}
+	}
+
+	// A 20% shift stands out in the stable metric, but not in the noisy one.
+	jobComparisonData := newJobComparisonData(0.2)
+	CompareJobsUsingAdaptiveThreshold(jobComparisonData, DefaultAdaptiveThresholdOptions(0.1), 0)
+	if jobComparisonData.Data[stable].Matched || !jobComparisonData.Data[noisy].Matched {
+		t.Errorf("Wrong comparison result for adaptive threshold test of a 20%% shift: %+v, %+v", jobComparisonData.Data[stable], jobComparisonData.Data[noisy])
+	}
+	if !strings.Contains(jobComparisonData.Data[noisy].Comments, "Threshold=40.0%") {
+		t.Errorf("Comments lack the noisy metric's threshold: %v", jobComparisonData.Data[noisy].Comments)
+	}
+	if !jobComparisonData.Data[zeroAvg].Inconclusive || !strings.HasPrefix(jobComparisonData.Data[zeroAvg].Comments, util.CannotComputeRatio) {
+		t.Errorf("Metric of zero left avg not marked inconclusive: %+v", jobComparisonData.Data[zeroAvg])
+	}
+
+	// Shifts smaller than the base threshold match either way.
+	jobComparisonData = newJobComparisonData(-0.05)
+	CompareJobsUsingAdaptiveThresholdTest(jobComparisonData, 0.1, 0)
+	if !jobComparisonData.Data[stable].Matched || !jobComparisonData.Data[noisy].Matched {
+		t.Errorf("Wrong comparison result for adaptive threshold test of a -5%% shift")
+	}
+
+	// The max threshold still catches big enough shifts in noisy metrics.
+	jobComparisonData = newJobComparisonData(0.35)
+	options := DefaultAdaptiveThresholdOptions(0.1)
+	options.MaxThreshold = 0.3
+	CompareJobsUsingAdaptiveThreshold(jobComparisonData, options, 0)
+	if jobComparisonData.Data[noisy].Matched {
+		t.Errorf("Noisy metric's 35%% shift matched with a max threshold of 30%%: %v", jobComparisonData.Data[noisy].Comments)
+	}
+	if !math.IsNaN(jobComparisonData.Data[noisy].PValue) {
+		t.Errorf("Adaptive threshold test left a p-value of %v", jobComparisonData.Data[noisy].PValue)
+	}
+}
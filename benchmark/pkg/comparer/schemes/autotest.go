@@ -0,0 +1,105 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemes
+
+import (
+	"fmt"
+	"math"
+
+	"k8s.io/perf-tests/benchmark/pkg/util"
+)
+
+// Tests chosen among by the Auto-Test.
+const (
+	autoTestTTest       = "t-test"
+	autoTestLogTTest    = "log-t-test"
+	autoTestMannWhitney = "Mann-Whitney"
+)
+
+// chooseTest returns the test suiting samples of the given distribution types (see
+// util.ClassifyDistribution): Welch's t-test if both are normal (or too small to tell, as
+// is common for a few runs), the t-test of their logs if both are log-normal (or one is
+// normal, and the other log-normal), and the Mann-Whitney U test otherwise. As a normal sample
+// may have non-positive values, whose logs don't exist, the t-test of the logs falls back to
+// the Mann-Whitney U test for those (see CompareJobsUsingAutoTest).
+func chooseTest(left, right string) string {
+	normalOrUnknown := func(distribution string) bool {
+		return distribution == util.DistributionNormal || distribution == util.DistributionUnknown
+	}
+	switch {
+	case normalOrUnknown(left) && normalOrUnknown(right):
+		return autoTestTTest
+	case left == util.DistributionLogNormal && (right == util.DistributionLogNormal || right == util.DistributionNormal),
+		right == util.DistributionLogNormal && left == util.DistributionNormal:
+		return autoTestLogTTest
+	default:
+		return autoTestMannWhitney
+	}
+}
+
+// CompareJobsUsingAutoTest takes a JobComparisonData object, compares left and right job
+// samples of each metric inside it and fills in the comparison results in the metric's object
+// after running the test suiting the samples' distributions (see chooseTest), as classified by
+// util.ClassifyDistribution: the t-test assumes the means to be normally distributed, which
+// skewed samples of a few runs get badly wrong, while the rank-based Mann-Whitney U test has
+// less power on normal samples. The test chosen and the distributions are reported in the
+// comments. It's flagged as a mismatch if the test's p-value is below the significance level,
+// and marked inconclusive if the p-value can't be computed (e.g. a single run on a side),
+// unless the metric is below the min avg to compare.
+func CompareJobsUsingAutoTest(jobComparisonData *util.JobComparisonData, significanceLevel, minMetricAvgForCompare float64) {
+	jobComparisonData.EnsureStats()
+	for _, metricData := range jobComparisonData.Data {
+		leftSampleCount := len(metricData.LeftJobSample)
+		rightSampleCount := len(metricData.RightJobSample)
+		metricData.ResetVerdict()
+		if leftSampleCount == 0 || rightSampleCount == 0 {
+			metricData.Matched = true
+			continue
+		}
+		leftDistribution := util.ClassifyDistribution(metricData.LeftJobSample)
+		rightDistribution := util.ClassifyDistribution(metricData.RightJobSample)
+		test := chooseTest(leftDistribution, rightDistribution)
+		leftLogs, leftPositive := util.LogsOf(metricData.LeftJobSample)
+		rightLogs, rightPositive := util.LogsOf(metricData.RightJobSample)
+		if test == autoTestLogTTest && !(leftPositive && rightPositive) {
+			test = autoTestMannWhitney
+		}
+		var pValue float64
+		switch test {
+		case autoTestTTest:
+			pValue = util.WelchTTest(metricData.LeftJobSample, metricData.RightJobSample)
+		case autoTestLogTTest:
+			pValue = util.WelchTTest(leftLogs, rightLogs)
+		default:
+			pValue = util.MannWhitneyUTest(metricData.LeftJobSample, metricData.RightJobSample)
+		}
+		comments := fmt.Sprintf("Test=%v\tShapeL=%v\tShapeR=%v\tPvalue=%.4f\tN1=%v\tN2=%v", test, leftDistribution, rightDistribution, pValue, leftSampleCount, rightSampleCount)
+		if !math.IsNaN(pValue) {
+			metricData.PValue, metricData.HasPValue = pValue, true
+		}
+		switch {
+		case metricData.BelowMinAvg(minMetricAvgForCompare):
+			metricData.Matched = true
+		case math.IsNaN(pValue):
+			metricData.MarkInconclusive("can't compute the p-value\t" + comments)
+			continue
+		case pValue >= significanceLevel:
+			metricData.Matched = true
+		}
+		metricData.Comments = comments
+	}
+}
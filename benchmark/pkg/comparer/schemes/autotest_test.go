@@ -0,0 +1,102 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemes
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"k8s.io/perf-tests/benchmark/pkg/util"
+)
+
+func TestCompareJobsUsingAutoTest(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	sample := func(n int, value func() float64) []float64 {
+		values := make([]float64, n)
+		for i := range values {
+			values[i] = value()
+		}
+		return values
+	}
+	normal := func(mean float64) func() float64 {
+		return func() float64 { return mean + 5*random.NormFloat64() }
+	}
+	logNormal := func(mu float64) func() float64 {
+		return func() float64 { return math.Exp(mu + random.NormFloat64()) }
+	}
+	normalKey := util.MetricKey{TestName: "swag", Verb: "GET", Resource: "node", Percentile: "Perc99"}
+	logNormalKey := util.MetricKey{TestName: "swag", Verb: "PUT", Resource: "pods", Percentile: "Perc99"}
+	mixedKey := util.MetricKey{TestName: "swag", Verb: "LIST", Resource: "rc", Percentile: "Perc99"}
+	fewRunsKey := util.MetricKey{TestName: "swag", Verb: "POST", Resource: "rc", Percentile: "Perc99"}
+	singleRunKey := util.MetricKey{TestName: "swag", Verb: "DELETE", Resource: "rc", Percentile: "Perc99"}
+	jobComparisonData := &util.JobComparisonData{
+		Data: map[util.MetricKey]*util.MetricComparisonData{
+			normalKey:    {LeftJobSample: sample(100, normal(100)), RightJobSample: sample(100, normal(100))},
+			logNormalKey: {LeftJobSample: sample(100, logNormal(4)), RightJobSample: sample(100, logNormal(5))},
+			// The right job's runs are all fine, but for a few very slow ones.
+			mixedKey:     {LeftJobSample: sample(100, normal(100)), RightJobSample: append(sample(95, normal(100)), 1000, 1000, 1000, 1000, 1000)},
+			fewRunsKey:   {LeftJobSample: []float64{100, 102, 98}, RightJobSample: []float64{150, 152, 148}},
+			singleRunKey: {LeftJobSample: []float64{100}, RightJobSample: []float64{150}},
+		},
+	}
+
+	CompareJobsUsingAutoTest(jobComparisonData, 0.05, 0)
+	testCases := []struct {
+		key             util.MetricKey
+		expectedTest    string
+		expectedMatched bool
+	}{
+		{normalKey, "Test=t-test\tShapeL=normal\tShapeR=normal", true},
+		{logNormalKey, "Test=log-t-test\tShapeL=log-normal\tShapeR=log-normal", false},
+		// The outliers don't shift the ranks enough for the Mann-Whitney U test.
+		{mixedKey, "Test=Mann-Whitney\tShapeL=normal\tShapeR=heavy-tailed", true},
+		{fewRunsKey, "Test=t-test\tShapeL=unknown\tShapeR=unknown", false},
+	}
+	for _, testCase := range testCases {
+		metricData := jobComparisonData.Data[testCase.key]
+		if !strings.HasPrefix(metricData.Comments, testCase.expectedTest) {
+			t.Errorf("Metric %v was compared with '%v', but expected '%v'", testCase.key, metricData.Comments, testCase.expectedTest)
+		}
+		if metricData.Matched != testCase.expectedMatched || metricData.Inconclusive || !metricData.HasPValue {
+			t.Errorf("Wrong comparison result for metric %v (expected matched: %v): %+v", testCase.key, testCase.expectedMatched, metricData)
+		}
+	}
+	if !jobComparisonData.Data[singleRunKey].Inconclusive {
+		t.Errorf("Single-run metric not marked inconclusive: %+v", jobComparisonData.Data[singleRunKey])
+	}
+
+	// A normal sample with non-positive values has no logs to compare those of a log-normal
+	// one with, so the log-t-test falls back to the Mann-Whitney U test.
+	nonPositiveKey := util.MetricKey{TestName: "swag", Verb: "PATCH", Resource: "pods", Percentile: "Perc99"}
+	withNonPositive := &util.JobComparisonData{
+		Data: map[util.MetricKey]*util.MetricComparisonData{
+			nonPositiveKey: {LeftJobSample: sample(100, normal(5)), RightJobSample: sample(100, logNormal(4))},
+		},
+	}
+	CompareJobsUsingAutoTest(withNonPositive, 0.05, 0)
+	if metricData := withNonPositive.Data[nonPositiveKey]; !strings.HasPrefix(metricData.Comments, "Test=Mann-Whitney\tShapeL=normal\tShapeR=log-normal") || metricData.Matched || !metricData.HasPValue {
+		t.Errorf("Wrong comparison result for the metric with non-positive values: %+v", metricData)
+	}
+
+	// Metrics below the min avg to compare are matched, even if their p-value can't be computed.
+	CompareJobsUsingAutoTest(jobComparisonData, 0.05, 1000)
+	if metricData := jobComparisonData.Data[singleRunKey]; !metricData.Matched || metricData.Inconclusive {
+		t.Errorf("Single-run metric below the min avg not matched: %+v", metricData)
+	}
+}
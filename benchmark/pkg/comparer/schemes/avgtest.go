@@ -27,23 +27,29 @@ import (
 // and right jobs for each metric inside it and fills in the comparison
 // results in the metric's object after checking ratio of the averages
 // of its left and right samples is within the allowed ratio lower bound
-// and upper bound (which is the inverse of lower bound).
+// and upper bound (which is the inverse of lower bound). Metrics for which
+// the ratio can't be computed (e.g. a zero right average) are marked
+// inconclusive.
 func CompareJobsUsingAvgTest(jobComparisonData *util.JobComparisonData, allowedRatioLowerBound, minMetricAvgForCompare float64) {
-	jobComparisonData.ComputeStatsForMetricSamples()
+	jobComparisonData.EnsureStats()
 	for _, metricData := range jobComparisonData.Data {
 		leftSampleCount := len(metricData.LeftJobSample)
 		rightSampleCount := len(metricData.RightJobSample)
-		metricData.Matched = false
+		metricData.ResetVerdict()
 		if leftSampleCount == 0 || rightSampleCount == 0 {
 			metricData.AvgRatio = math.NaN()
 			metricData.Matched = true
 		} else {
-			metricData.AvgRatio = metricData.AvgL / metricData.AvgR
-			if allowedRatioLowerBound <= metricData.AvgRatio && metricData.AvgRatio <= 1/allowedRatioLowerBound {
+			var ok bool
+			metricData.AvgRatio, ok = metricData.RatioOnOriginalScale(metricData.AvgL, metricData.AvgR)
+			if metricData.WithinRatioBounds(metricData.AvgRatio, allowedRatioLowerBound) {
 				metricData.Matched = true
 			}
-			if metricData.AvgL < minMetricAvgForCompare && metricData.AvgR < minMetricAvgForCompare {
+			if metricData.BelowMinAvg(minMetricAvgForCompare) {
 				metricData.Matched = true
+			} else if !ok {
+				metricData.MarkInconclusive(fmt.Sprintf(util.CannotComputeRatio+"\tAvgL(ms)=%.2f\tAvgR(ms)=%.2f\tN1=%v\tN2=%v", metricData.AvgL, metricData.AvgR, leftSampleCount, rightSampleCount))
+				continue
 			}
 		}
 		metricData.Comments = fmt.Sprintf("AvgL/R=%.2f\tAvgL(ms)=%.2f\tAvgR(ms)=%.2f\tN1=%v\tN2=%v", metricData.AvgRatio, metricData.AvgL, metricData.AvgR, leftSampleCount, rightSampleCount)
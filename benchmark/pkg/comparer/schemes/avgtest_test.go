@@ -17,6 +17,9 @@ limitations under the License.
 package schemes
 
 import (
+	"context"
+	"math"
+	"strings"
 	"testing"
 
 	"k8s.io/perf-tests/benchmark/pkg/util"
@@ -70,3 +73,111 @@ func TestCompareJobsUsingAvgTest(t *testing.T) {
 		t.Errorf("Wrong comparison result for Avg-based test at an allowed ratio of %v with min-metric-avg-for-compare=1.5", highAvgRatioThreshold)
 	}
 }
+
+func TestCompareJobsUsingAvgTestWithInvalidRatio(t *testing.T) {
+	zeroRightAvg := util.MetricKey{TestName: "swag", Verb: "GET", Resource: "node", Percentile: "Perc99"}
+	nanRightAvg := util.MetricKey{TestName: "swag", Verb: "PUT", Resource: "pods", Percentile: "Perc90"}
+	jobComparisonData := &util.JobComparisonData{
+		Data: map[util.MetricKey]*util.MetricComparisonData{
+			zeroRightAvg: {
+				LeftJobSample:  []float64{100, 110},
+				RightJobSample: []float64{0, 0},
+			},
+			nanRightAvg: {
+				LeftJobSample:  []float64{100, 110},
+				RightJobSample: []float64{math.NaN()},
+			},
+		},
+	}
+
+	CompareJobsUsingAvgTest(jobComparisonData, highAvgRatioThreshold, 0)
+	for metricKey, metricData := range jobComparisonData.Data {
+		if !metricData.Inconclusive || !strings.HasPrefix(metricData.Comments, util.CannotComputeRatio) || !math.IsNaN(metricData.AvgRatio) {
+			t.Errorf("Metric %v with an invalid ratio not marked inconclusive: %+v", metricKey, metricData)
+		}
+	}
+
+	// Metrics below the min-metric-avg-for-compare are matched regardless of the ratio.
+	jobComparisonData.Data[zeroRightAvg].LeftJobSample = []float64{1, 2}
+	CompareJobsUsingAvgTest(jobComparisonData, highAvgRatioThreshold, 50)
+	if metricData := jobComparisonData.Data[zeroRightAvg]; metricData.Inconclusive || !metricData.Matched {
+		t.Errorf("Metric %v below min-metric-avg-for-compare not matched: %+v", zeroRightAvg, metricData)
+	}
+}
+
+func TestCompareJobsUsingAvgTestWithHysteresis(t *testing.T) {
+	// Right avgs 11% and 9% slower than the left ones, around a 1.10 bound, and far from it.
+	borderlineAbove := util.MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	borderlineBelow := util.MetricKey{TestName: "Load", Verb: "PUT", Resource: "pods", Percentile: "Perc99"}
+	farAbove := util.MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}
+	farBelow := util.MetricKey{TestName: "Load", Verb: "POST", Resource: "pods", Percentile: "Perc99"}
+	newData := func() *util.JobComparisonData {
+		j := util.NewJobComparisonData()
+		for key, right := range map[util.MetricKey]float64{borderlineAbove: 111, borderlineBelow: 109, farAbove: 115, farBelow: 105} {
+			j.Data[key] = &util.MetricComparisonData{LeftJobSample: []float64{100, 100}, RightJobSample: []float64{right, right}}
+		}
+		return j
+	}
+	threshold := 1 / 1.1
+
+	// Without hysteresis, the borderline metrics are judged by the bound alone.
+	j := newData()
+	CompareJobsUsingAvgTest(j, threshold, 0)
+	if j.Data[borderlineAbove].Matched || !j.Data[borderlineBelow].Matched {
+		t.Fatalf("Borderline metrics compared as %+v and %+v without hysteresis", j.Data[borderlineAbove], j.Data[borderlineBelow])
+	}
+
+	// Within the band, the metrics keep their previous verdicts, while those out of it don't.
+	for _, previouslyMatched := range []bool{true, false} {
+		previous := newData()
+		for _, metricData := range previous.Data {
+			metricData.Matched = previouslyMatched
+		}
+		j := newData()
+		j.SetHysteresis(previous, 0.02)
+		CompareJobsUsingAvgTest(j, threshold, 0)
+		for _, key := range []util.MetricKey{borderlineAbove, borderlineBelow} {
+			if data := j.Data[key]; data.Matched != previouslyMatched {
+				t.Errorf("Borderline metric %v (previously matched: %v) compared as %+v", key, previouslyMatched, data)
+			}
+		}
+		if j.Data[farAbove].Matched || !j.Data[farBelow].Matched {
+			t.Errorf("Metrics out of the band (previously matched: %v) compared as %+v and %+v", previouslyMatched, j.Data[farAbove], j.Data[farBelow])
+		}
+	}
+}
+
+func TestRatioSchemesWithTransforms(t *testing.T) {
+	regressed := util.MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}
+	steady := util.MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	fast := util.MetricKey{TestName: "Load", Verb: "PUT", Resource: "pods", Percentile: "Perc99"}
+	schemes := map[string]util.ComparisonScheme{
+		"Avg-Test":        CompareJobsUsingAvgTest,
+		"Percentile-Test": CompareJobsUsingPercentileOfSamplesTest,
+		"Shortfall-Test":  CompareJobsUsingShortfallTest,
+		"Adaptive-Test":   CompareJobsUsingAdaptiveThresholdTest,
+	}
+	for name, scheme := range schemes {
+		scheme := scheme
+		transformed := util.WithTransforms(func(_ context.Context, j *util.JobComparisonData, threshold, minMetricAvgForCompare float64) {
+			scheme(j, threshold, minMetricAvgForCompare)
+		})
+		for _, transform := range []util.Transform{util.TransformNone, util.TransformLog, util.TransformSqrt} {
+			// A 3x regression, a steady metric and a regressed one below the (default) min avg.
+			j := util.NewJobComparisonData()
+			j.Data[regressed] = &util.MetricComparisonData{LeftJobSample: []float64{95, 100, 105}, RightJobSample: []float64{290, 300, 310}, Transform: transform}
+			j.Data[steady] = &util.MetricComparisonData{LeftJobSample: []float64{95, 100, 105}, RightJobSample: []float64{96, 101, 104}, Transform: transform}
+			j.Data[fast] = &util.MetricComparisonData{LeftJobSample: []float64{9, 10, 11}, RightJobSample: []float64{29, 30, 31}, Transform: transform}
+			transformed(context.Background(), j, 0.66, 50)
+			if j.Data[regressed].Matched {
+				t.Errorf("%v with Transform=%v matched the 3x regression: %v", name, transform, j.Data[regressed].Comments)
+			}
+			if !j.Data[steady].Matched {
+				t.Errorf("%v with Transform=%v mismatched the steady metric: %v", name, transform, j.Data[steady].Comments)
+			}
+			if !j.Data[fast].Matched {
+				t.Errorf("%v with Transform=%v mismatched the metric below the min avg: %v", name, transform, j.Data[fast].Comments)
+			}
+		}
+	}
+}
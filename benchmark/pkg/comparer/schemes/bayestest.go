@@ -0,0 +1,148 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemes
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"k8s.io/perf-tests/benchmark/pkg/util"
+)
+
+// Parameters of the Bayesian test.
+//
+// The mean latency of each job is given a normal prior centered at the mean of both
+// jobs' samples pooled together (so that it favors neither side), with a standard
+// deviation of BayesPriorStDevMultiplier times the pooled samples' standard deviation.
+// This makes the prior weakly informative, letting the data dominate the posterior.
+// Each observation is modelled as normal around the job's mean, with the variance
+// taken to be the job's sample variance (or the pooled variance for single-run jobs).
+var (
+	BayesPriorStDevMultiplier = 10.0
+	BayesCredibleLevel        = 0.95
+)
+
+// posteriorOfMean returns the posterior mean and variance of a job's mean latency under
+// the normal-normal conjugate model with the given prior and observation variance.
+func posteriorOfMean(sample []float64, priorMean, priorVariance, observationVariance float64) (float64, float64) {
+	mean := util.Mean(sample)
+	if observationVariance == 0 {
+		return mean, 0
+	}
+	n := float64(len(sample))
+	precision := 1/priorVariance + n/observationVariance
+	return (priorMean/priorVariance + n*mean/observationVariance) / precision, 1 / precision
+}
+
+// BayesianPosteriorDifference returns the posterior probability that the right job's mean
+// latency is higher than the left job's, along with a credible interval (at BayesCredibleLevel)
+// for the difference of means (right - left). Both samples must be non-empty. They're all NaN
+// if the posterior can't be computed (e.g. due to NaN values in the samples).
+func BayesianPosteriorDifference(leftSample, rightSample []float64) (probSlower, diffLow, diffHigh float64) {
+	pooledSample := append(append([]float64{}, leftSample...), rightSample...)
+	pooledVariance := util.SampleVariance(pooledSample)
+	priorMean := util.Mean(pooledSample)
+	if math.IsNaN(pooledVariance) {
+		return math.NaN(), math.NaN(), math.NaN()
+	}
+	if pooledVariance == 0 {
+		// All the values are identical, there's nothing to tell the jobs apart.
+		return 0.5, 0, 0
+	}
+	priorVariance := BayesPriorStDevMultiplier * BayesPriorStDevMultiplier * pooledVariance
+
+	observationVariance := func(sample []float64) float64 {
+		if len(sample) < 2 {
+			return pooledVariance
+		}
+		return util.SampleVariance(sample)
+	}
+	meanL, varianceL := posteriorOfMean(leftSample, priorMean, priorVariance, observationVariance(leftSample))
+	meanR, varianceR := posteriorOfMean(rightSample, priorMean, priorVariance, observationVariance(rightSample))
+
+	diffMean := meanR - meanL
+	diffStDev := math.Sqrt(varianceL + varianceR)
+	if diffStDev == 0 {
+		switch {
+		case diffMean > 0:
+			return 1, diffMean, diffMean
+		case diffMean < 0:
+			return 0, diffMean, diffMean
+		default:
+			return 0.5, 0, 0
+		}
+	}
+	standardized, ok := util.SafeDiv(diffMean, diffStDev)
+	if !ok {
+		return math.NaN(), math.NaN(), math.NaN()
+	}
+	z := util.NormalQuantile((1 + BayesCredibleLevel) / 2)
+	return util.NormalCDF(standardized), diffMean - z*diffStDev, diffMean + z*diffStDev
+}
+
+// CompareJobsUsingBayesianTest takes a JobComparisonData object, compares left and
+// right job samples of each metric inside it and fills in the comparison results in
+// the metric's object after computing the posterior probability that the right job
+// is slower. It's flagged as a mismatch if that probability exceeds the threshold, and
+// marked inconclusive if it can't be computed.
+func CompareJobsUsingBayesianTest(jobComparisonData *util.JobComparisonData, probSlowerThreshold, minMetricAvgForCompare float64) {
+	CompareJobsUsingBayesianTestWithContext(context.Background(), jobComparisonData, probSlowerThreshold, minMetricAvgForCompare)
+}
+
+// CompareJobsUsingBayesianTestWithContext is like CompareJobsUsingBayesianTest, but stops comparing once
+// the context is done (even midway through a metric), marking the metrics not compared so far as
+// timed out.
+func CompareJobsUsingBayesianTestWithContext(ctx context.Context, jobComparisonData *util.JobComparisonData, probSlowerThreshold, minMetricAvgForCompare float64) {
+	if err := ctx.Err(); err != nil {
+		jobComparisonData.MarkTimedOut(err)
+		return
+	}
+	jobComparisonData.EnsureStats()
+	for _, metricData := range jobComparisonData.Data {
+		if err := ctx.Err(); err != nil {
+			metricData.MarkTimedOut(err)
+			continue
+		}
+		leftSampleCount := len(metricData.LeftJobSample)
+		rightSampleCount := len(metricData.RightJobSample)
+		metricData.ResetVerdict()
+		probSlower, diffLow, diffHigh := math.NaN(), math.NaN(), math.NaN()
+		if leftSampleCount == 0 || rightSampleCount == 0 {
+			metricData.Matched = true
+		} else {
+			left := append([]float64{}, metricData.LeftJobSample...)
+			right := append([]float64{}, metricData.RightJobSample...)
+			if err := runUnlessDone(ctx, func() {
+				probSlower, diffLow, diffHigh = BayesianPosteriorDifference(left, right)
+			}); err != nil {
+				metricData.MarkTimedOut(err)
+				continue
+			}
+			if probSlower <= probSlowerThreshold {
+				metricData.Matched = true
+			}
+			if metricData.BelowMinAvg(minMetricAvgForCompare) {
+				metricData.Matched = true
+			} else if math.IsNaN(probSlower) {
+				metricData.MarkInconclusive(fmt.Sprintf(util.CannotComputeRatio+"\tP(slower)=%.4f\tN1=%v\tN2=%v", probSlower, leftSampleCount, rightSampleCount))
+				continue
+			}
+		}
+		metricData.Comments = fmt.Sprintf("P(slower)=%.4f\tDiffCI(ms)=[%.2f,%.2f]\tN1=%v\tN2=%v", probSlower, diffLow, diffHigh, leftSampleCount, rightSampleCount)
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemes
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"k8s.io/perf-tests/benchmark/pkg/util"
+)
+
+func TestBayesianPosteriorDifference(t *testing.T) {
+	// Worked example: pooled mean is 17 and pooled variance 33.2, so the prior on each
+	// side's mean is N(17, 3320). Both sides have a sample variance of 4 over 3 values,
+	// giving posterior means 12.00201 (left) and 21.99799 (right) with variance 1.33280.
+	// The difference thus has posterior N(9.99599, 1.63267^2).
+	probSlower, diffLow, diffHigh := BayesianPosteriorDifference([]float64{10, 12, 14}, []float64{20, 22, 24})
+	if math.Abs(probSlower-1.0) > 1e-6 {
+		t.Errorf("P(slower) computed as %v, but expected ~1.0", probSlower)
+	}
+	if math.Abs(diffLow-6.79602) > 1e-4 || math.Abs(diffHigh-13.19595) > 1e-4 {
+		t.Errorf("Credible interval computed as [%v, %v], but expected [6.79602, 13.19595]", diffLow, diffHigh)
+	}
+
+	// A right sample interleaving with the left one is only slightly more likely to be slower.
+	probSlower, _, _ = BayesianPosteriorDifference([]float64{10, 12, 14}, []float64{11, 13, 12.5})
+	if math.Abs(probSlower-0.55087) > 1e-4 {
+		t.Errorf("P(slower) computed as %v, but expected 0.55087", probSlower)
+	}
+}
+
+func TestCompareJobsUsingBayesianTest(t *testing.T) {
+	metricKey1 := util.MetricKey{TestName: "swag", Verb: "GET", Resource: "node", Percentile: "Perc99"}
+	metricKey2 := util.MetricKey{TestName: "swag", Verb: "PUT", Resource: "pods", Percentile: "Perc90"}
+	metricKey3 := util.MetricKey{TestName: "swag", Verb: "LIST", Resource: "rc", Percentile: "Perc100"}
+	jobComparisonData := &util.JobComparisonData{
+		Data: map[util.MetricKey]*util.MetricComparisonData{
+			metricKey1: {
+				LeftJobSample:  []float64{10, 12, 14},
+				RightJobSample: []float64{11, 13, 12.5},
+			},
+			metricKey2: {
+				LeftJobSample:  []float64{10, 12, 14},
+				RightJobSample: []float64{20, 22, 24},
+			},
+			metricKey3: {
+				LeftJobSample:  []float64{1.00, 10.00, 100.00},
+				RightJobSample: []float64{},
+			},
+		},
+	}
+
+	CompareJobsUsingBayesianTest(jobComparisonData, 0.95, 0)
+	if !jobComparisonData.Data[metricKey1].Matched || jobComparisonData.Data[metricKey2].Matched || !jobComparisonData.Data[metricKey3].Matched {
+		t.Errorf("Wrong comparison result for Bayesian test at a threshold of 0.95")
+	}
+
+	CompareJobsUsingBayesianTest(jobComparisonData, 0.5, 0)
+	if jobComparisonData.Data[metricKey1].Matched || jobComparisonData.Data[metricKey2].Matched || !jobComparisonData.Data[metricKey3].Matched {
+		t.Errorf("Wrong comparison result for Bayesian test at a threshold of 0.5")
+	}
+
+	CompareJobsUsingBayesianTest(jobComparisonData, 0.5, 50)
+	if !jobComparisonData.Data[metricKey1].Matched || !jobComparisonData.Data[metricKey2].Matched || !jobComparisonData.Data[metricKey3].Matched {
+		t.Errorf("Wrong comparison result for Bayesian test at a threshold of 0.5 with min-metric-avg-for-compare=50")
+	}
+}
+
+func TestCompareJobsUsingBayesianTestWithInvalidRatio(t *testing.T) {
+	allZeros := util.MetricKey{TestName: "swag", Verb: "GET", Resource: "node", Percentile: "Perc99"}
+	nanRightSample := util.MetricKey{TestName: "swag", Verb: "PUT", Resource: "pods", Percentile: "Perc90"}
+	jobComparisonData := &util.JobComparisonData{
+		Data: map[util.MetricKey]*util.MetricComparisonData{
+			allZeros: {
+				LeftJobSample:  []float64{0, 0},
+				RightJobSample: []float64{0},
+			},
+			nanRightSample: {
+				LeftJobSample:  []float64{10, 12, 14},
+				RightJobSample: []float64{20, math.NaN()},
+			},
+		},
+	}
+
+	CompareJobsUsingBayesianTest(jobComparisonData, 0.95, 0)
+	if metricData := jobComparisonData.Data[allZeros]; !metricData.Matched || metricData.Inconclusive {
+		t.Errorf("Metric %v with identical zero samples not matched: %+v", allZeros, metricData)
+	}
+	if metricData := jobComparisonData.Data[nanRightSample]; !metricData.Inconclusive || !strings.HasPrefix(metricData.Comments, util.CannotComputeRatio) {
+		t.Errorf("Metric %v with a NaN right value not marked inconclusive: %+v", nanRightSample, metricData)
+	}
+}
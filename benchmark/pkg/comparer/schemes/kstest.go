@@ -17,6 +17,7 @@ limitations under the License.
 package schemes
 
 import (
+	"context"
 	"fmt"
 	"math"
 
@@ -29,24 +30,63 @@ import (
 // right job samples of each metric inside it and fills in the comparison
 // results in the metric's object after running a KS test on the two samples.
 func CompareJobsUsingKSTest(jobComparisonData *util.JobComparisonData, significanceLevel, minMetricAvgForCompare float64) {
-	jobComparisonData.ComputeStatsForMetricSamples()
+	CompareJobsUsingKSTestWithContext(context.Background(), jobComparisonData, significanceLevel, minMetricAvgForCompare)
+}
+
+// CompareJobsUsingKSTestWithContext is like CompareJobsUsingKSTest, but stops comparing once
+// the context is done (even midway through a metric), marking the metrics not compared so far
+// as timed out.
+func CompareJobsUsingKSTestWithContext(ctx context.Context, jobComparisonData *util.JobComparisonData, significanceLevel, minMetricAvgForCompare float64) {
+	if err := ctx.Err(); err != nil {
+		jobComparisonData.MarkTimedOut(err)
+		return
+	}
+	jobComparisonData.EnsureStats()
 	for _, metricData := range jobComparisonData.Data {
+		if err := ctx.Err(); err != nil {
+			metricData.MarkTimedOut(err)
+			continue
+		}
 		leftSampleCount := len(metricData.LeftJobSample)
 		rightSampleCount := len(metricData.RightJobSample)
-		metricData.Matched = false
+		metricData.ResetVerdict()
 		var pValue float64
 		if leftSampleCount == 0 || rightSampleCount == 0 {
 			pValue = math.NaN()
 			metricData.Matched = true
 		} else {
-			pValue = onlinestats.KS(metricData.LeftJobSample, metricData.RightJobSample)
+			// KS sorts the samples it's given, so it's run on copies of them.
+			left := append([]float64{}, metricData.LeftJobSample...)
+			right := append([]float64{}, metricData.RightJobSample...)
+			if err := runUnlessDone(ctx, func() { pValue = onlinestats.KS(left, right) }); err != nil {
+				metricData.MarkTimedOut(err)
+				continue
+			}
 			if pValue >= significanceLevel {
 				metricData.Matched = true
 			}
-			if metricData.AvgL < minMetricAvgForCompare && metricData.AvgR < minMetricAvgForCompare {
+			if metricData.BelowMinAvg(minMetricAvgForCompare) {
 				metricData.Matched = true
 			}
 		}
+		metricData.PValue, metricData.HasPValue = pValue, true
 		metricData.Comments = fmt.Sprintf("Pvalue=%.4f\t\tN1=%v\tN2=%v", pValue, leftSampleCount, rightSampleCount)
 	}
 }
+
+// runUnlessDone runs f, unless ctx gets done first, in which case it returns the context's
+// error without waiting for f to complete. f is then left running in the background, so it
+// must not touch any state other than its own.
+func runUnlessDone(ctx context.Context, f func()) error {
+	done := make(chan struct{})
+	go func() {
+		f()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
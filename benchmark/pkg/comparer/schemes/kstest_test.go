@@ -17,6 +17,9 @@ limitations under the License.
 package schemes
 
 import (
+	"context"
+	"math"
+	"strings"
 	"testing"
 
 	"k8s.io/perf-tests/benchmark/pkg/util"
@@ -77,3 +80,75 @@ func TestCompareJobsUsingKSTest(t *testing.T) {
 		t.Errorf("Wrong comparison result for KS test at a significance level of %v with min-metric-avg-for-compare=1.5", extremeSignificanceLevel)
 	}
 }
+
+func TestCompareJobsUsingKSTestWithZeroSamples(t *testing.T) {
+	metricKey := util.MetricKey{TestName: "swag", Verb: "GET", Resource: "node", Percentile: "Perc99"}
+	jobComparisonData := &util.JobComparisonData{
+		Data: map[util.MetricKey]*util.MetricComparisonData{
+			metricKey: {
+				LeftJobSample:  []float64{0, 0, 0},
+				RightJobSample: []float64{0, 0},
+			},
+		},
+	}
+	CompareJobsUsingKSTest(jobComparisonData, highSignificanceLevel, 0)
+	metricData := jobComparisonData.Data[metricKey]
+	if !metricData.Matched || metricData.Inconclusive {
+		t.Errorf("Metric with identical zero samples not matched: %+v", metricData)
+	}
+	// None of the ratios of the zero stats should be infinite.
+	if !math.IsNaN(metricData.MaxRatio) || !math.IsNaN(metricData.ZScoreOfRight) {
+		t.Errorf("Ratios of zero stats computed as %v (max) and %v (z-score), but expected NaN", metricData.MaxRatio, metricData.ZScoreOfRight)
+	}
+}
+
+func TestCompareJobsUsingKSTestWithCancelledContext(t *testing.T) {
+	metricKey := util.MetricKey{TestName: "swag", Verb: "GET", Resource: "node", Percentile: "Perc99"}
+	jobComparisonData := &util.JobComparisonData{
+		Data: map[util.MetricKey]*util.MetricComparisonData{
+			metricKey: {
+				// Would mismatch, if compared.
+				LeftJobSample:  []float64{0.90, 0.95, 1.00, 1.05, 1.10},
+				RightJobSample: []float64{1.90, 1.95, 2.00, 2.05, 2.10},
+			},
+		},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	CompareJobsUsingKSTestWithContext(ctx, jobComparisonData, lowSignificanceLevel, 0)
+	metricData := jobComparisonData.Data[metricKey]
+	// Timed out metrics fail closed, and the stats aren't computed for nothing.
+	if !metricData.Inconclusive || metricData.Matched || !strings.Contains(metricData.Comments, "Timed out") {
+		t.Errorf("Metric not marked as timed out with a cancelled context: %+v", metricData)
+	}
+	if metricData.AvgL != 0 || metricData.AvgR != 0 {
+		t.Errorf("Stats computed despite a cancelled context: %+v", metricData)
+	}
+
+	// A later comparison without bound reaches a verdict.
+	CompareJobsUsingKSTest(jobComparisonData, lowSignificanceLevel, 0)
+	if metricData.Inconclusive || metricData.Matched {
+		t.Errorf("Wrong comparison result for KS test after a timed out one: %+v", metricData)
+	}
+
+	// Re-comparing using a scheme not based on a test clears the KS p-value.
+	CompareJobsUsingAvgTest(jobComparisonData, 0.5, 0)
+	if metricData.HasPValue || !math.IsNaN(metricData.PValue) {
+		t.Errorf("Stale p-value %v left after comparing using avg test: %+v", metricData.PValue, metricData)
+	}
+}
+
+func TestRunUnlessDone(t *testing.T) {
+	if err := runUnlessDone(context.Background(), func() {}); err != nil {
+		t.Errorf("Unexpected error running without bound: %v", err)
+	}
+
+	// A computation that doesn't complete by itself is given up on once the context is done.
+	ctx, cancel := context.WithCancel(context.Background())
+	stuck := make(chan struct{})
+	defer close(stuck)
+	go cancel()
+	if err := runUnlessDone(ctx, func() { <-stuck }); err != context.Canceled {
+		t.Errorf("Expected %v running a stuck computation, but got %v", context.Canceled, err)
+	}
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemes
+
+import (
+	"fmt"
+
+	"k8s.io/perf-tests/benchmark/pkg/util"
+)
+
+// CompareMaxOverNodes takes a JobComparisonData object, compares left and right jobs for each
+// per-node metric inside it and fills in the comparison results in the metric's object after
+// checking that the ratio of the means of its left and right node maxes (the value of each run's
+// worst node, see FlattenOptions.NodeLabel) is within the allowed ratio lower bound and upper
+// bound (the inverse of the lower bound). The aggregation is so two-level: the max over the
+// nodes within each run, then the mean over the runs. It tells whether the slowest node got
+// slower, which comparing the values pooled across the nodes waters down. Metrics with samples
+// but no node maxes (i.e. not flattened per node), or for which the ratio can't be computed,
+// are marked inconclusive.
+func CompareMaxOverNodes(jobComparisonData *util.JobComparisonData, allowedRatioLowerBound, minMetricAvgForCompare float64) {
+	jobComparisonData.EnsureStats()
+	for _, metricData := range jobComparisonData.Data {
+		metricData.ResetVerdict()
+		if len(metricData.LeftJobSample) == 0 || len(metricData.RightJobSample) == 0 {
+			metricData.Matched = true
+			continue
+		}
+		leftRunCount := len(metricData.LeftJobNodeMaxes)
+		rightRunCount := len(metricData.RightJobNodeMaxes)
+		if leftRunCount == 0 || rightRunCount == 0 {
+			metricData.MarkInconclusive("no per-node values")
+			continue
+		}
+		maxL, maxR := util.Mean(metricData.LeftJobNodeMaxes), util.Mean(metricData.RightJobNodeMaxes)
+		ratio, ok := metricData.RatioOnOriginalScale(maxL, maxR)
+		comments := fmt.Sprintf("MaxOverNodesL/R=%.2f\tMaxOverNodesL(ms)=%.2f\tMaxOverNodesR(ms)=%.2f\tN1=%v\tN2=%v", ratio, maxL, maxR, leftRunCount, rightRunCount)
+		switch {
+		case metricData.OriginalScale(maxL) < minMetricAvgForCompare && metricData.OriginalScale(maxR) < minMetricAvgForCompare:
+			metricData.Matched = true
+		case !ok:
+			metricData.MarkInconclusive(util.CannotComputeRatio + "\t" + comments)
+			continue
+		case metricData.WithinRatioBounds(ratio, allowedRatioLowerBound):
+			metricData.Matched = true
+		}
+		metricData.Comments = comments
+	}
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemes
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/kubernetes/test/e2e/perftype"
+	"k8s.io/perf-tests/benchmark/pkg/util"
+)
+
+func TestCompareMaxOverNodes(t *testing.T) {
+	// Each run reports the metric for 10 nodes, the last of which got twice as slow in the right job.
+	runMetrics := func(slowestNode float64) map[string][]perftype.PerfData {
+		var dataItems []perftype.DataItem
+		for node := 0; node < 10; node++ {
+			value := 100.0
+			if node == 9 {
+				value = slowestNode
+			}
+			dataItems = append(dataItems, perftype.DataItem{
+				Data:   map[string]float64{"Perc99": value},
+				Unit:   "ms",
+				Labels: map[string]string{"Metric": "pod_startup", "Node": string(rune('a' + node))},
+			})
+		}
+		return map[string][]perftype.PerfData{"Density": {{Version: "v1", DataItems: dataItems}}}
+	}
+	leftMetrics := []map[string][]perftype.PerfData{runMetrics(120), runMetrics(110), runMetrics(130)}
+	rightMetrics := []map[string][]perftype.PerfData{runMetrics(240), runMetrics(220), runMetrics(260)}
+	metricKey := util.MetricKey{TestName: "Density", Verb: "Pod-Startup", Percentile: "Perc99"}
+	jobComparisonData := util.GetFlattennedComparisonDataWithOptions(leftMetrics, rightMetrics, util.FlattenOptions{NodeLabel: "Node"})
+
+	CompareMaxOverNodes(jobComparisonData, 0.66, 0)
+	metricData := jobComparisonData.Data[metricKey]
+	if metricData.Matched || metricData.Inconclusive {
+		t.Errorf("Regression of the slowest node was expected to mismatch: %+v", metricData)
+	}
+	if !strings.HasPrefix(metricData.Comments, "MaxOverNodesL/R=0.50") {
+		t.Errorf("Comments lack the ratio of the worst nodes: %v", metricData.Comments)
+	}
+
+	// Whereas comparing the values pooled across the nodes lets it through.
+	CompareJobsUsingAvgTest(jobComparisonData, 0.66, 0)
+	if !metricData.Matched {
+		t.Errorf("Avg test was expected to match the regression of a single node: %+v", metricData)
+	}
+
+	// Metrics not flattened per node can't be compared.
+	jobComparisonData = util.GetFlattennedComparisonDataWithOptions(leftMetrics, rightMetrics, util.FlattenOptions{})
+	CompareMaxOverNodes(jobComparisonData, 0.66, 0)
+	if !jobComparisonData.Data[metricKey].Inconclusive {
+		t.Errorf("Metric without node maxes not marked inconclusive: %+v", jobComparisonData.Data[metricKey])
+	}
+}
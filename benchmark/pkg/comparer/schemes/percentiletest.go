@@ -0,0 +1,72 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemes
+
+import (
+	"fmt"
+
+	"k8s.io/perf-tests/benchmark/pkg/util"
+)
+
+// PercentileOfSamplesForTest is the percentile of the samples (of per-run percentiles)
+// compared by CompareJobsUsingPercentileOfSamplesTest.
+const PercentileOfSamplesForTest = 0.95
+
+// CompareJobsUsingPercentileOfSamplesTest is like CompareJobsUsingPercentileOfSamples, comparing
+// the P95 of the samples.
+func CompareJobsUsingPercentileOfSamplesTest(jobComparisonData *util.JobComparisonData, allowedRatioLowerBound, minMetricAvgForCompare float64) {
+	CompareJobsUsingPercentileOfSamples(jobComparisonData, PercentileOfSamplesForTest, allowedRatioLowerBound, minMetricAvgForCompare)
+}
+
+// CompareJobsUsingPercentileOfSamples takes a JobComparisonData object, compares left and right
+// jobs for each metric inside it and fills in the comparison results in the metric's object
+// after checking that the ratio of the given percentile (as a fraction) of its left and right
+// samples is within the allowed ratio lower bound and upper bound (the inverse of the lower
+// bound), like the Avg-Test does with avgs. As the samples hold the percentiles reported by
+// each run, that's comparing a percentile of percentiles (see PercentileOfSamples). Metrics
+// for which the ratio can't be computed (e.g. a zero right percentile), or all of them if the
+// percentile is out of range, are marked inconclusive.
+func CompareJobsUsingPercentileOfSamples(jobComparisonData *util.JobComparisonData, p, allowedRatioLowerBound, minMetricAvgForCompare float64) {
+	jobComparisonData.EnsureStats()
+	for _, metricData := range jobComparisonData.Data {
+		leftSampleCount := len(metricData.LeftJobSample)
+		rightSampleCount := len(metricData.RightJobSample)
+		metricData.ResetVerdict()
+		if leftSampleCount == 0 || rightSampleCount == 0 {
+			metricData.Matched = true
+			continue
+		}
+		percentileL, err := metricData.PercentileOfSamples(p, true)
+		if err != nil {
+			metricData.MarkInconclusive(err.Error())
+			continue
+		}
+		percentileR, _ := metricData.PercentileOfSamples(p, false)
+		ratio, ok := metricData.RatioOnOriginalScale(percentileL, percentileR)
+		comments := fmt.Sprintf("P%vL/R=%.2f\tP%vL(ms)=%.2f\tP%vR(ms)=%.2f\tN1=%v\tN2=%v", 100*p, ratio, 100*p, percentileL, 100*p, percentileR, leftSampleCount, rightSampleCount)
+		switch {
+		case metricData.BelowMinAvg(minMetricAvgForCompare):
+			metricData.Matched = true
+		case !ok:
+			metricData.MarkInconclusive(util.CannotComputeRatio + "\t" + comments)
+			continue
+		case metricData.WithinRatioBounds(ratio, allowedRatioLowerBound):
+			metricData.Matched = true
+		}
+		metricData.Comments = comments
+	}
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemes
+
+import (
+	"fmt"
+
+	"k8s.io/perf-tests/benchmark/pkg/util"
+)
+
+// CompareJobsUsingQnTest takes a JobComparisonData object, compares left and right jobs for each
+// metric inside it and fills in the comparison results in the metric's object after checking that
+// the ratio of the Qn robust scales of its left and right samples (see QnL and QnR) is within the
+// allowed ratio lower bound and upper bound (the inverse of the lower bound). It gates on the
+// run-to-run spread of the metric changing, which the Qn estimates even with up to half of the
+// runs being bad, unlike the std-dev (thrown off by a single one). Metrics for which the ratio
+// can't be computed (e.g. without spread, as for a single value, on the right) are marked
+// inconclusive. As a ratio of spreads, it's independent of the unit on the log scale too (see
+// util.WithTransforms), so it's taken as it is, unlike the ratios of locations.
+func CompareJobsUsingQnTest(jobComparisonData *util.JobComparisonData, allowedRatioLowerBound, minMetricAvgForCompare float64) {
+	jobComparisonData.EnsureStats()
+	for _, metricData := range jobComparisonData.Data {
+		leftSampleCount := len(metricData.LeftJobSample)
+		rightSampleCount := len(metricData.RightJobSample)
+		metricData.ResetVerdict()
+		if leftSampleCount == 0 || rightSampleCount == 0 {
+			metricData.Matched = true
+			continue
+		}
+		ratio, ok := util.SafeDiv(metricData.QnL, metricData.QnR)
+		comments := fmt.Sprintf("QnL/R=%.2f\tQnL(ms)=%.2f\tQnR(ms)=%.2f\tN1=%v\tN2=%v", ratio, metricData.QnL, metricData.QnR, leftSampleCount, rightSampleCount)
+		switch {
+		case metricData.BelowMinAvg(minMetricAvgForCompare):
+			metricData.Matched = true
+		case !ok:
+			metricData.MarkInconclusive(util.CannotComputeRatio + "\t" + comments)
+			continue
+		case metricData.WithinRatioBounds(ratio, allowedRatioLowerBound):
+			metricData.Matched = true
+		}
+		metricData.Comments = comments
+	}
+}
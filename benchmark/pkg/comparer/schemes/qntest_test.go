@@ -0,0 +1,63 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemes
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/perf-tests/benchmark/pkg/util"
+)
+
+func TestCompareJobsUsingQnTest(t *testing.T) {
+	noisier := util.MetricKey{TestName: "swag", Verb: "GET", Resource: "node", Percentile: "Perc99"}
+	contaminated := util.MetricKey{TestName: "swag", Verb: "PUT", Resource: "pods", Percentile: "Perc99"}
+	singleRun := util.MetricKey{TestName: "swag", Verb: "LIST", Resource: "rc", Percentile: "Perc99"}
+	jobComparisonData := &util.JobComparisonData{
+		Data: map[util.MetricKey]*util.MetricComparisonData{
+			// The right job's runs spread 4 times as much.
+			noisier: {
+				LeftJobSample:  []float64{99, 100, 101, 102, 98, 100},
+				RightJobSample: []float64{96, 100, 104, 108, 92, 100},
+			},
+			// The right job's spread is the same, but for a wild run, which the Qn shrugs off.
+			contaminated: {
+				LeftJobSample:  []float64{99, 100, 101, 102, 98, 100},
+				RightJobSample: []float64{99, 100, 101, 102, 98, 1000},
+			},
+			singleRun: {
+				LeftJobSample:  []float64{100},
+				RightJobSample: []float64{100},
+			},
+		},
+	}
+
+	CompareJobsUsingQnTest(jobComparisonData, 0.5, 0)
+	if jobComparisonData.Data[noisier].Matched || !jobComparisonData.Data[contaminated].Matched {
+		t.Errorf("Wrong comparison result for Qn test at a ratio bound of 0.5: %+v, %+v", jobComparisonData.Data[noisier], jobComparisonData.Data[contaminated])
+	}
+	if !strings.HasPrefix(jobComparisonData.Data[noisier].Comments, "QnL/R=0.25") {
+		t.Errorf("Comments lack the ratio of Qns: %v", jobComparisonData.Data[noisier].Comments)
+	}
+	if !jobComparisonData.Data[singleRun].Inconclusive {
+		t.Errorf("Single-run metric not marked inconclusive: %+v", jobComparisonData.Data[singleRun])
+	}
+	// Whereas the std-devs of the contaminated metric are wildly different.
+	if ratio := jobComparisonData.Data[contaminated].StDevL / jobComparisonData.Data[contaminated].StDevR; ratio > 0.5 {
+		t.Errorf("Std-devs of the contaminated metric were expected to differ, ratio is %v", ratio)
+	}
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemes
+
+import (
+	"fmt"
+	"math"
+
+	"k8s.io/perf-tests/benchmark/pkg/util"
+)
+
+// CompareJobsUsingRegressionProbabilityTest takes a JobComparisonData object, compares left and
+// right job samples of each metric inside it and fills in the comparison results in the metric's
+// object after computing its RegressionProbability (see util.RegressionProbability), i.e. the
+// confidence that the right job regressed. It's flagged as a mismatch if that probability exceeds
+// the threshold (e.g. 0.95 gating on being 95% confident), and marked inconclusive if it can't be
+// computed (e.g. for single-run jobs). The p-value of the underlying one-sided test is set too.
+func CompareJobsUsingRegressionProbabilityTest(jobComparisonData *util.JobComparisonData, maxRegressionProbability, minMetricAvgForCompare float64) {
+	jobComparisonData.EnsureStats()
+	for _, metricData := range jobComparisonData.Data {
+		leftSampleCount := len(metricData.LeftJobSample)
+		rightSampleCount := len(metricData.RightJobSample)
+		metricData.ResetVerdict()
+		if leftSampleCount == 0 || rightSampleCount == 0 {
+			metricData.RegressionProbability = math.NaN()
+			metricData.Matched = true
+			continue
+		}
+		probability := util.RegressionProbability(metricData.LeftJobSample, metricData.RightJobSample, metricData.IsRate)
+		metricData.RegressionProbability = probability
+		comments := fmt.Sprintf("P(regressed)=%.4f\tAvgL(ms)=%.2f\tAvgR(ms)=%.2f\tN1=%v\tN2=%v", probability, metricData.AvgL, metricData.AvgR, leftSampleCount, rightSampleCount)
+		if math.IsNaN(probability) {
+			metricData.MarkInconclusive("can't compute the regression probability\t" + comments)
+			continue
+		}
+		metricData.PValue, metricData.HasPValue = 1-probability, true
+		if probability <= maxRegressionProbability || metricData.BelowMinAvg(minMetricAvgForCompare) {
+			metricData.Matched = true
+		}
+		metricData.Comments = comments
+	}
+}
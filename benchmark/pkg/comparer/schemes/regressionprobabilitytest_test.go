@@ -0,0 +1,63 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemes
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"k8s.io/perf-tests/benchmark/pkg/util"
+)
+
+func TestCompareJobsUsingRegressionProbabilityTest(t *testing.T) {
+	regressed := util.MetricKey{TestName: "swag", Verb: "GET", Resource: "node", Percentile: "Perc99"}
+	noisy := util.MetricKey{TestName: "swag", Verb: "PUT", Resource: "pods", Percentile: "Perc99"}
+	singleRun := util.MetricKey{TestName: "swag", Verb: "LIST", Resource: "rc", Percentile: "Perc99"}
+	jobComparisonData := &util.JobComparisonData{
+		Data: map[util.MetricKey]*util.MetricComparisonData{
+			regressed: {
+				LeftJobSample:  []float64{100, 102, 98, 101, 99},
+				RightJobSample: []float64{110, 112, 108, 111, 109},
+			},
+			// The avg went up about as much, but it's within the noise.
+			noisy: {
+				LeftJobSample:  []float64{60, 140, 80, 120, 100},
+				RightJobSample: []float64{70, 150, 90, 130, 110},
+			},
+			singleRun: {
+				LeftJobSample:  []float64{100},
+				RightJobSample: []float64{200},
+			},
+		},
+	}
+
+	CompareJobsUsingRegressionProbabilityTest(jobComparisonData, 0.95, 0)
+	if jobComparisonData.Data[regressed].Matched || !jobComparisonData.Data[noisy].Matched {
+		t.Errorf("Wrong comparison result for regression probability test at a threshold of 0.95: %+v, %+v", jobComparisonData.Data[regressed], jobComparisonData.Data[noisy])
+	}
+	regressedData := jobComparisonData.Data[regressed]
+	if !(regressedData.RegressionProbability > 0.95) || !regressedData.HasPValue || math.Abs(regressedData.PValue-(1-regressedData.RegressionProbability)) > 1e-12 {
+		t.Errorf("Wrong regression probability or p-value: %v, %v", regressedData.RegressionProbability, regressedData.PValue)
+	}
+	if !strings.HasPrefix(regressedData.Comments, "P(regressed)=") {
+		t.Errorf("Comments lack the regression probability: %v", regressedData.Comments)
+	}
+	if !jobComparisonData.Data[singleRun].Inconclusive || !math.IsNaN(jobComparisonData.Data[singleRun].RegressionProbability) {
+		t.Errorf("Single-run metric not marked inconclusive with a NaN regression probability: %+v", jobComparisonData.Data[singleRun])
+	}
+}
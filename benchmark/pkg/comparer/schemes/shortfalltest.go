@@ -0,0 +1,56 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemes
+
+import (
+	"fmt"
+
+	"k8s.io/perf-tests/benchmark/pkg/util"
+)
+
+// CompareJobsUsingShortfallTest takes a JobComparisonData object, compares left and right
+// jobs for each metric inside it and fills in the comparison results in the metric's object
+// after checking that the ratio of the expected shortfalls of its left and right samples (the
+// means of their worst values, see ExpectedShortfall) is within the allowed ratio lower bound
+// and upper bound (the inverse of the lower bound), like the Avg-Test does with avgs. It gates
+// on how bad the tail got, which the avgs (or even the P99s) of a few runs barely show.
+// Metrics for which the ratio can't be computed (e.g. a zero right shortfall) are marked
+// inconclusive.
+func CompareJobsUsingShortfallTest(jobComparisonData *util.JobComparisonData, allowedRatioLowerBound, minMetricAvgForCompare float64) {
+	jobComparisonData.EnsureStats()
+	for _, metricData := range jobComparisonData.Data {
+		leftSampleCount := len(metricData.LeftJobSample)
+		rightSampleCount := len(metricData.RightJobSample)
+		metricData.ResetVerdict()
+		if leftSampleCount == 0 || rightSampleCount == 0 {
+			metricData.Matched = true
+			continue
+		}
+		ratio, ok := metricData.RatioOnOriginalScale(metricData.ESL, metricData.ESR)
+		comments := fmt.Sprintf("ESL/R=%.2f\tESL(ms)=%.2f\tESR(ms)=%.2f\tN1=%v\tN2=%v", ratio, metricData.ESL, metricData.ESR, leftSampleCount, rightSampleCount)
+		switch {
+		case metricData.BelowMinAvg(minMetricAvgForCompare):
+			metricData.Matched = true
+		case !ok:
+			metricData.MarkInconclusive(util.CannotComputeRatio + "\t" + comments)
+			continue
+		case metricData.WithinRatioBounds(ratio, allowedRatioLowerBound):
+			metricData.Matched = true
+		}
+		metricData.Comments = comments
+	}
+}
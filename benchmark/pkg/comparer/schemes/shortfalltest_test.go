@@ -0,0 +1,65 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemes
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/perf-tests/benchmark/pkg/util"
+)
+
+func TestCompareJobsUsingShortfallTest(t *testing.T) {
+	tailRegression := util.MetricKey{TestName: "swag", Verb: "GET", Resource: "node", Percentile: "Perc99"}
+	unchanged := util.MetricKey{TestName: "swag", Verb: "PUT", Resource: "pods", Percentile: "Perc99"}
+	zeroShortfall := util.MetricKey{TestName: "swag", Verb: "LIST", Resource: "rc", Percentile: "Perc99"}
+	jobComparisonData := &util.JobComparisonData{
+		Data: map[util.MetricKey]*util.MetricComparisonData{
+			// The avgs are about the same, but the right job's worst run got twice as slow.
+			tailRegression: {
+				LeftJobSample:  []float64{100, 100, 100, 100, 100, 100, 100, 100, 100, 150},
+				RightJobSample: []float64{95, 95, 95, 95, 95, 95, 95, 95, 95, 300},
+			},
+			unchanged: {
+				LeftJobSample:  []float64{100, 110, 120},
+				RightJobSample: []float64{105, 110, 115},
+			},
+			zeroShortfall: {
+				LeftJobSample:  []float64{10},
+				RightJobSample: []float64{0},
+			},
+		},
+		ShortfallFraction: 0.1,
+	}
+
+	CompareJobsUsingShortfallTest(jobComparisonData, 0.8, 0)
+	if jobComparisonData.Data[tailRegression].Matched || !jobComparisonData.Data[unchanged].Matched {
+		t.Errorf("Wrong comparison result for shortfall test at a ratio bound of 0.8: %+v, %+v", jobComparisonData.Data[tailRegression], jobComparisonData.Data[unchanged])
+	}
+	if !strings.HasPrefix(jobComparisonData.Data[tailRegression].Comments, "ESL/R=0.50") {
+		t.Errorf("Comments lack the ratio of expected shortfalls: %v", jobComparisonData.Data[tailRegression].Comments)
+	}
+	if !jobComparisonData.Data[zeroShortfall].Inconclusive {
+		t.Errorf("Metric of zero right shortfall not marked inconclusive: %+v", jobComparisonData.Data[zeroShortfall])
+	}
+
+	// Whereas comparing the avgs lets the tail regression through.
+	CompareJobsUsingAvgTest(jobComparisonData, 0.8, 0)
+	if !jobComparisonData.Data[tailRegression].Matched {
+		t.Errorf("Avg test was expected to match the tail regression: %+v", jobComparisonData.Data[tailRegression])
+	}
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemes
+
+import (
+	"fmt"
+	"math"
+
+	"k8s.io/perf-tests/benchmark/pkg/util"
+)
+
+// CompareJobsUsingZScoreTest takes a JobComparisonData object, compares left and
+// right job samples of each metric inside it and fills in the comparison results
+// in the metric's object after checking that the right sample's avg is within the
+// allowed number of left sample's std-devs from the left sample's avg. That's gating on
+// |GlassDelta|, the effect size suited to a stable left (baseline) job, as unlike Cohen's d
+// it isn't affected by the right job's spread. Metrics for which the z-score can't be
+// computed (e.g. as the left sample has no spread, but the avgs differ) are marked
+// inconclusive.
+func CompareJobsUsingZScoreTest(jobComparisonData *util.JobComparisonData, maxAbsZScore, minMetricAvgForCompare float64) {
+	jobComparisonData.EnsureStats()
+	for _, metricData := range jobComparisonData.Data {
+		leftSampleCount := len(metricData.LeftJobSample)
+		rightSampleCount := len(metricData.RightJobSample)
+		metricData.ResetVerdict()
+		note := ""
+		if leftSampleCount != 0 && metricData.StDevL == 0 {
+			note = "\t(left sample has no spread)"
+		}
+		comments := fmt.Sprintf("Z=%.2f\tAvgL(ms)=%.2f\tAvgR(ms)=%.2f\tN1=%v\tN2=%v%v", metricData.ZScoreOfRight, metricData.AvgL, metricData.AvgR, leftSampleCount, rightSampleCount, note)
+		switch {
+		case leftSampleCount == 0 || rightSampleCount == 0:
+			metricData.Matched = true
+		case metricData.BelowMinAvg(minMetricAvgForCompare):
+			metricData.Matched = true
+		case math.IsNaN(metricData.ZScoreOfRight) && metricData.AvgL == metricData.AvgR:
+			// The left sample has no spread, but nothing changed either.
+			metricData.Matched = true
+		case math.IsNaN(metricData.ZScoreOfRight):
+			metricData.MarkInconclusive(util.CannotComputeRatio + "\t" + comments)
+			continue
+		case math.Abs(metricData.ZScoreOfRight) <= maxAbsZScore:
+			metricData.Matched = true
+		}
+		metricData.Comments = comments
+	}
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemes
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"k8s.io/perf-tests/benchmark/pkg/util"
+)
+
+func TestCompareJobsUsingZScoreTest(t *testing.T) {
+	metricKey1 := util.MetricKey{TestName: "swag", Verb: "GET", Resource: "node", Percentile: "Perc99"}
+	metricKey2 := util.MetricKey{TestName: "swag", Verb: "PUT", Resource: "pods", Percentile: "Perc90"}
+	metricKey3 := util.MetricKey{TestName: "swag", Verb: "LIST", Resource: "rc", Percentile: "Perc100"}
+	metricKey4 := util.MetricKey{TestName: "swag", Verb: "LIST", Resource: "rc", Percentile: "Perc50"}
+	jobComparisonData := &util.JobComparisonData{
+		Data: map[util.MetricKey]*util.MetricComparisonData{
+			metricKey1: {
+				// Right avg is 1 std-dev away from the left avg.
+				LeftJobSample:  []float64{90, 110},
+				RightJobSample: []float64{110},
+			},
+			metricKey2: {
+				// Right avg is 3 std-devs away from the left avg.
+				LeftJobSample:  []float64{90, 110},
+				RightJobSample: []float64{125, 135},
+			},
+			metricKey3: {
+				LeftJobSample:  []float64{1.00, 10.00, 100.00},
+				RightJobSample: []float64{},
+			},
+			metricKey4: {
+				// Left sample has no spread, so the z-score of any change can't be computed.
+				LeftJobSample:  []float64{100, 100},
+				RightJobSample: []float64{101},
+			},
+		},
+	}
+
+	CompareJobsUsingZScoreTest(jobComparisonData, 2, 0)
+	if !jobComparisonData.Data[metricKey1].Matched || jobComparisonData.Data[metricKey2].Matched || !jobComparisonData.Data[metricKey3].Matched || !jobComparisonData.Data[metricKey4].Inconclusive {
+		t.Errorf("Wrong comparison result for z-score test at a max z-score of 2")
+	}
+	if !strings.HasPrefix(jobComparisonData.Data[metricKey4].Comments, util.CannotComputeRatio) {
+		t.Errorf("Comments lack a note about the z-score not being computable: %v", jobComparisonData.Data[metricKey4].Comments)
+	}
+	if !strings.Contains(jobComparisonData.Data[metricKey4].Comments, "no spread") {
+		t.Errorf("Comments lack a note about left sample having no spread: %v", jobComparisonData.Data[metricKey4].Comments)
+	}
+
+	CompareJobsUsingZScoreTest(jobComparisonData, 0.5, 0)
+	if jobComparisonData.Data[metricKey1].Matched || jobComparisonData.Data[metricKey2].Matched || !jobComparisonData.Data[metricKey3].Matched {
+		t.Errorf("Wrong comparison result for z-score test at a max z-score of 0.5")
+	}
+
+	CompareJobsUsingZScoreTest(jobComparisonData, 0.5, 150)
+	if !jobComparisonData.Data[metricKey1].Matched || !jobComparisonData.Data[metricKey2].Matched || !jobComparisonData.Data[metricKey3].Matched || !jobComparisonData.Data[metricKey4].Matched {
+		t.Errorf("Wrong comparison result for z-score test at a max z-score of 0.5 with min-metric-avg-for-compare=150")
+	}
+	if jobComparisonData.Data[metricKey4].Inconclusive {
+		t.Errorf("Metric %v below min-metric-avg-for-compare marked inconclusive", metricKey4)
+	}
+}
+
+func TestCompareJobsUsingZScoreTestWithInvalidRatio(t *testing.T) {
+	unchanged := util.MetricKey{TestName: "swag", Verb: "GET", Resource: "node", Percentile: "Perc99"}
+	nanRightAvg := util.MetricKey{TestName: "swag", Verb: "PUT", Resource: "pods", Percentile: "Perc90"}
+	jobComparisonData := &util.JobComparisonData{
+		Data: map[util.MetricKey]*util.MetricComparisonData{
+			unchanged: {
+				LeftJobSample:  []float64{0, 0},
+				RightJobSample: []float64{0},
+			},
+			nanRightAvg: {
+				LeftJobSample:  []float64{90, 110},
+				RightJobSample: []float64{math.NaN()},
+			},
+		},
+	}
+
+	CompareJobsUsingZScoreTest(jobComparisonData, 2, 0)
+	if metricData := jobComparisonData.Data[unchanged]; !metricData.Matched || metricData.Inconclusive {
+		t.Errorf("Metric %v without spread or change not matched: %+v", unchanged, metricData)
+	}
+	if metricData := jobComparisonData.Data[nanRightAvg]; !metricData.Inconclusive || !strings.HasPrefix(metricData.Comments, util.CannotComputeRatio) {
+		t.Errorf("Metric %v with a NaN right avg not marked inconclusive: %+v", nanRightAvg, metricData)
+	}
+}
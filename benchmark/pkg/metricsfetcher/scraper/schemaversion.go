@@ -0,0 +1,95 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scraper
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	benchmarkutil "k8s.io/perf-tests/benchmark/pkg/util"
+
+	"github.com/golang/glog"
+)
+
+// The range of the (major) schema versions of the metrics files we know how to read.
+const (
+	MinSupportedSchemaVersion = 1
+	MaxSupportedSchemaVersion = 1
+)
+
+// LoadOptions tunes how the loaders (like GetMetricsForRunsWithOptions) read the metrics files.
+type LoadOptions struct {
+	// ForceLoadUnsupportedSchemaVersions makes the loaders read the metrics files whose schema
+	// version is out of the supported range anyway, with a warning rather than an error. It's
+	// meant for one-off comparisons of artifacts known to be compatible despite their version.
+	ForceLoadUnsupportedSchemaVersions bool
+}
+
+// schemaVersionFields are the fields the schema version of a metrics file may be held in.
+var schemaVersionFields = []string{"version", "Version", "schemaVersion"}
+
+// parseSchemaVersion returns the major version of a schema version, given like "v1", "1" or
+// "v1.2" (or as a JSON number).
+func parseSchemaVersion(version string) (int, error) {
+	major := strings.TrimPrefix(strings.TrimPrefix(version, "v"), "V")
+	if dot := strings.Index(major, "."); dot >= 0 {
+		major = major[:dot]
+	}
+	number, err := strconv.Atoi(major)
+	if err != nil {
+		return 0, fmt.Errorf("bad schema version '%v'", version)
+	}
+	return number, nil
+}
+
+// checkSchemaVersion errors if the schema version is out of the supported range (or can't be
+// parsed), unless the options force loading it, in which case it only warns. An empty version
+// is accepted, as the older artifacts may not have one.
+func checkSchemaVersion(version string, options LoadOptions) error {
+	if version == "" {
+		return nil
+	}
+	number, err := parseSchemaVersion(version)
+	if err == nil && (number < MinSupportedSchemaVersion || number > MaxSupportedSchemaVersion) {
+		err = fmt.Errorf("unsupported schema version '%v' (supported: v%v to v%v)", version, MinSupportedSchemaVersion, MaxSupportedSchemaVersion)
+	}
+	if err != nil && options.ForceLoadUnsupportedSchemaVersions {
+		glog.Warningf("Force-loading metrics file despite its schema version: %v", err)
+		return nil
+	}
+	return err
+}
+
+// schemaVersionOf returns the schema version held in any of the schemaVersionFields of a
+// metrics file's top-level fields, or "" if there's none. Numeric versions are returned as
+// their JSON text.
+func schemaVersionOf(fields map[string]json.RawMessage) string {
+	for _, field := range schemaVersionFields {
+		raw, ok := fields[field]
+		if !ok || string(raw) == "null" {
+			continue
+		}
+		var version string
+		if err := benchmarkutil.Unmarshaler(raw, &version); err == nil {
+			return version
+		}
+		return string(raw)
+	}
+	return ""
+}
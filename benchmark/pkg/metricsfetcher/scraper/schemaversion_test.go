@@ -0,0 +1,93 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scraper
+
+import (
+	"testing"
+)
+
+func TestDecodePerfDataSchemaVersion(t *testing.T) {
+	testCases := []struct {
+		name        string
+		contents    string
+		force       bool
+		expectError bool
+	}{
+		{
+			name:     "in range",
+			contents: `{"version": "v1", "dataItems": []}`,
+		},
+		{
+			name:     "in range minor version",
+			contents: `{"version": "v1.3", "dataItems": []}`,
+		},
+		{
+			name:     "in range schemaVersion",
+			contents: `{"schemaVersion": 1, "dataItems": []}`,
+		},
+		{
+			name:     "missing version",
+			contents: `{"dataItems": []}`,
+		},
+		{
+			name:        "too old",
+			contents:    `{"version": "v0", "dataItems": []}`,
+			expectError: true,
+		},
+		{
+			name:        "too new",
+			contents:    `{"schemaVersion": "2", "dataItems": []}`,
+			expectError: true,
+		},
+		{
+			name:        "unparsable",
+			contents:    `{"version": "latest", "dataItems": []}`,
+			expectError: true,
+		},
+		{
+			name:        "too old enveloped",
+			contents:    `{"version": "v1", "data": [{"version": "v1", "dataItems": []}, {"version": "v0", "dataItems": []}]}`,
+			expectError: true,
+		},
+		{
+			name:     "too old forced",
+			contents: `{"version": "v0", "dataItems": []}`,
+			force:    true,
+		},
+		{
+			name:     "too new enveloped forced",
+			contents: `{"version": "v2", "data": [{"version": "v2", "dataItems": []}]}`,
+			force:    true,
+		},
+	}
+	for _, testCase := range testCases {
+		perfData, err := DecodePerfDataWithOptions([]byte(testCase.contents), LoadOptions{ForceLoadUnsupportedSchemaVersions: testCase.force})
+		if testCase.expectError {
+			if err == nil {
+				t.Errorf("%v: expected an error, got %v", testCase.name, perfData)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", testCase.name, err)
+			continue
+		}
+		if len(perfData) == 0 {
+			t.Errorf("%v: expected the perf data to be loaded", testCase.name)
+		}
+	}
+}
@@ -17,11 +17,20 @@ limitations under the License.
 package scraper
 
 import (
+	"bytes"
+	"compress/gzip"
 	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 
 	"k8s.io/kubernetes/test/e2e/perftype"
 	"k8s.io/perf-tests/benchmark/pkg/metricsfetcher/util"
+	benchmarkutil "k8s.io/perf-tests/benchmark/pkg/util"
 
 	"github.com/golang/glog"
 )
@@ -64,9 +73,93 @@ func GetMetricsFilePathsForRun(job string, run int, utils util.JobLogUtils) map[
 	return latencyFilesForTest
 }
 
+// perfDataEnvelope is the shape of newer artifacts, which wrap a list of PerfData.
+type perfDataEnvelope struct {
+	Version string              `json:"version"`
+	Data    []perftype.PerfData `json:"data"`
+}
+
+// DecodePerfData parses the contents of a metrics file into a list of PerfData. Both the bare
+// PerfData shape and the {"version": ..., "data": [...]} envelope wrapping a list of PerfData
+// are accepted (the latter being detected by a "data" field in place of "dataItems"). It's an
+// error if the schema version of the file, or of any of the enveloped PerfData, is out of the
+// supported range (see checkSchemaVersion). It decodes with the Unmarshaler of the util package
+// (k8s.io/perf-tests/benchmark/pkg/util), which may be a faster decoder plugged in.
+func DecodePerfData(contents []byte) ([]perftype.PerfData, error) {
+	return DecodePerfDataWithOptions(contents, LoadOptions{})
+}
+
+// DecodePerfDataWithOptions is like DecodePerfData, but checks the schema versions as per the
+// given options.
+func DecodePerfDataWithOptions(contents []byte, options LoadOptions) ([]perftype.PerfData, error) {
+	var fields map[string]json.RawMessage
+	if err := benchmarkutil.Unmarshaler(contents, &fields); err != nil {
+		return nil, err
+	}
+	if err := checkSchemaVersion(schemaVersionOf(fields), options); err != nil {
+		return nil, err
+	}
+	_, hasData := fields["data"]
+	_, hasDataItems := fields["dataItems"]
+	if hasData && !hasDataItems {
+		envelope := perfDataEnvelope{}
+		if err := benchmarkutil.Unmarshaler(contents, &envelope); err != nil {
+			return nil, fmt.Errorf("malformed perf data envelope: %v", err)
+		}
+		for _, perfData := range envelope.Data {
+			if err := checkSchemaVersion(perfData.Version, options); err != nil {
+				return nil, err
+			}
+		}
+		return envelope.Data, nil
+	}
+	perfData := perftype.PerfData{}
+	if err := benchmarkutil.Unmarshaler(contents, &perfData); err != nil {
+		return nil, err
+	}
+	return []perftype.PerfData{perfData}, nil
+}
+
+// gzipMagic is the header all gzip streams start with.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// decompressIfGzipped returns the contents of a metrics file, gunzipping them if they're
+// gzipped. It tells by their magic bytes rather than the file name, as artifacts of different
+// pipeline versions may be compressed or not under the same name (or the other way round).
+func decompressIfGzipped(contents []byte) ([]byte, error) {
+	if !bytes.HasPrefix(contents, gzipMagic) {
+		return contents, nil
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(contents))
+	if err != nil {
+		return nil, fmt.Errorf("malformed gzipped metrics file: %v", err)
+	}
+	defer reader.Close()
+	decompressed, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("malformed gzipped metrics file: %v", err)
+	}
+	return decompressed, nil
+}
+
+// decodeMetricsFile parses the (possibly gzipped) contents of a metrics file into a list of PerfData.
+func decodeMetricsFile(contents []byte, options LoadOptions) ([]perftype.PerfData, error) {
+	contents, err := decompressIfGzipped(contents)
+	if err != nil {
+		return nil, err
+	}
+	return DecodePerfDataWithOptions(contents, options)
+}
+
 // GetMetricsForRun for a given run of a job, returns a map of testname ("load", "density", etc) to a
 // list of its latency metrics (API responsiveness, pod startup) in perfType.PerfData format.
 func GetMetricsForRun(job string, run int, utils util.JobLogUtils) map[string][]perftype.PerfData {
+	return GetMetricsForRunWithOptions(job, run, utils, LoadOptions{})
+}
+
+// GetMetricsForRunWithOptions is like GetMetricsForRun, but reads the latency files as per the
+// given options.
+func GetMetricsForRunWithOptions(job string, run int, utils util.JobLogUtils, options LoadOptions) map[string][]perftype.PerfData {
 	metricsForRun := make(map[string][]perftype.PerfData)
 	latencyFilesForTest := GetMetricsFilePathsForRun(job, run, utils)
 
@@ -77,12 +170,12 @@ func GetMetricsForRun(job string, run int, utils util.JobLogUtils) map[string][]
 				glog.V(0).Infof("Error reading latency metrics file for run %v:%v (skipping it): %v", job, run, err)
 				continue
 			}
-			perfData := perftype.PerfData{}
-			if err := json.Unmarshal(latencyFileContents, &perfData); err != nil {
+			perfData, err := decodeMetricsFile(latencyFileContents, options)
+			if err != nil {
 				glog.V(0).Infof("Error parsing latency metrics file %v for run %v:%v (skipping it): %v", latencyFile, job, run, err)
 				continue
 			}
-			metricsForRun[testName] = append(metricsForRun[testName], perfData)
+			metricsForRun[testName] = append(metricsForRun[testName], perfData...)
 		}
 	}
 	return metricsForRun
@@ -92,9 +185,15 @@ func GetMetricsForRun(job string, run int, utils util.JobLogUtils) map[string][]
 // an array of the obtained results. Neglects runs whose metrics could not be fetched.
 // Note: This does best-effort scraping, returning as much as could be scraped, without any error.
 func GetMetricsForRuns(job string, runs []int, utils util.JobLogUtils) []map[string][]perftype.PerfData {
+	return GetMetricsForRunsWithOptions(job, runs, utils, LoadOptions{})
+}
+
+// GetMetricsForRunsWithOptions is like GetMetricsForRuns, but reads the latency files as per
+// the given options.
+func GetMetricsForRunsWithOptions(job string, runs []int, utils util.JobLogUtils, options LoadOptions) []map[string][]perftype.PerfData {
 	var metricsForRuns []map[string][]perftype.PerfData
 	for _, run := range runs {
-		metricsForRun := GetMetricsForRun(job, run, utils)
+		metricsForRun := GetMetricsForRunWithOptions(job, run, utils, options)
 		if len(metricsForRun) == 0 {
 			glog.V(0).Infof("No metrics obtained at all for run %v:%v (skipping it)", job, run)
 			continue
@@ -103,3 +202,104 @@ func GetMetricsForRuns(job string, runs []int, utils util.JobLogUtils) []map[str
 	}
 	return metricsForRuns
 }
+
+// GetMetricsForRunDir is like GetMetricsForRun, but for a run whose latency files are in a local
+// directory (e.g. its downloaded artifacts), rather than fetched with the job log utils. Each
+// file may be plain or gzipped (e.g. .json or .json.gz), regardless of the others. Unlike the
+// scraping, it's an error if any of the latency files can't be read or parsed.
+func GetMetricsForRunDir(dir string) (map[string][]perftype.PerfData, error) {
+	return GetMetricsForRunDirWithOptions(dir, LoadOptions{})
+}
+
+// GetMetricsForRunDirWithOptions is like GetMetricsForRunDir, but reads the latency files as per
+// the given options.
+func GetMetricsForRunDirWithOptions(dir string, options LoadOptions) (map[string][]perftype.PerfData, error) {
+	fileInfos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	metricsForRun := make(map[string][]perftype.PerfData)
+	for _, fileInfo := range fileInfos {
+		name := fileInfo.Name()
+		if fileInfo.IsDir() || !(strings.HasPrefix(name, path.Base(APICallLatencyFilePrefix)) || strings.HasPrefix(name, path.Base(PodStartupLatencyFilePrefix))) {
+			continue
+		}
+		filenameParts := strings.Split(name, "_")
+		if len(filenameParts) < 3 {
+			glog.V(0).Infof("Could not get testname from filename '%v' (skipping it)", name)
+			continue
+		}
+		testName := filenameParts[len(filenameParts)-2]
+		contents, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		perfData, err := decodeMetricsFile(contents, options)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing latency metrics file %v: %v", filepath.Join(dir, name), err)
+		}
+		metricsForRun[testName] = append(metricsForRun[testName], perfData...)
+	}
+	return metricsForRun, nil
+}
+
+// GetMetricsForRunDirs returns the metrics of the runs of a job whose directory has a
+// subdirectory of latency files per run (see GetMetricsForRunDir), in the order of the runs
+// (see sortRunDirs). Like GetMetricsForRuns, it neglects the runs without any metrics.
+func GetMetricsForRunDirs(jobDir string) ([]map[string][]perftype.PerfData, error) {
+	return GetMetricsForRunDirsWithOptions(jobDir, LoadOptions{})
+}
+
+// GetMetricsForRunDirsWithOptions is like GetMetricsForRunDirs, but reads the latency files as
+// per the given options.
+func GetMetricsForRunDirsWithOptions(jobDir string, options LoadOptions) ([]map[string][]perftype.PerfData, error) {
+	fileInfos, err := ioutil.ReadDir(jobDir)
+	if err != nil {
+		return nil, err
+	}
+	var runDirs []string
+	for _, fileInfo := range fileInfos {
+		if fileInfo.IsDir() {
+			runDirs = append(runDirs, fileInfo.Name())
+		}
+	}
+	sortRunDirs(runDirs)
+	var metricsForRuns []map[string][]perftype.PerfData
+	for _, runDir := range runDirs {
+		metricsForRun, err := GetMetricsForRunDirWithOptions(filepath.Join(jobDir, runDir), options)
+		if err != nil {
+			return nil, err
+		}
+		if len(metricsForRun) == 0 {
+			glog.V(0).Infof("No metrics obtained at all for run dir %v (skipping it)", runDir)
+			continue
+		}
+		metricsForRuns = append(metricsForRuns, metricsForRun)
+	}
+	return metricsForRuns, nil
+}
+
+// runDirIndex returns the index of the run a directory is for, i.e. the number its name ends
+// with (like 10 for "run-10"), if any.
+func runDirIndex(runDir string) (int, bool) {
+	number := runDir[len(strings.TrimRight(runDir, "0123456789")):]
+	index, err := strconv.Atoi(number)
+	return index, err == nil
+}
+
+// sortRunDirs sorts the run directories by the index of their run (see runDirIndex), so that
+// run-10 comes after run-2, rather than before as by name. The ones without an index come
+// after the others, in the order of their names.
+func sortRunDirs(runDirs []string) {
+	sort.Slice(runDirs, func(a, b int) bool {
+		indexA, okA := runDirIndex(runDirs[a])
+		indexB, okB := runDirIndex(runDirs[b])
+		if okA != okB {
+			return okA
+		}
+		if okA && indexA != indexB {
+			return indexA < indexB
+		}
+		return runDirs[a] < runDirs[b]
+	})
+}
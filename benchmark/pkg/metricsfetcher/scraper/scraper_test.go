@@ -17,13 +17,18 @@ limitations under the License.
 package scraper
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"reflect"
+	"strconv"
 	"testing"
 
 	"k8s.io/kubernetes/test/e2e/perftype"
 	"k8s.io/perf-tests/benchmark/pkg/metricsfetcher/util"
+	benchmarkutil "k8s.io/perf-tests/benchmark/pkg/util"
 )
 
 func TestGetMetricsFilePathsForRun(t *testing.T) {
@@ -182,3 +187,177 @@ func TestGetMetricsForRun(t *testing.T) {
 		t.Errorf("Metric map mismatching from what was expected:\nReal: %v\nExpected: %v", metrics, expected)
 	}
 }
+
+func TestDecodePerfData(t *testing.T) {
+	dataItem := perftype.DataItem{
+		Data:   map[string]float64{"Perc99": 21.707},
+		Unit:   "ms",
+		Labels: map[string]string{"Resource": "pods", "Verb": "DELETE"},
+	}
+	testCases := []struct {
+		name        string
+		contents    string
+		expected    []perftype.PerfData
+		expectError bool
+	}{
+		{
+			name:     "bare",
+			contents: `{"version": "v1", "dataItems": [{"data": {"Perc99": 21.707}, "unit": "ms", "labels": {"Resource": "pods", "Verb": "DELETE"}}]}`,
+			expected: []perftype.PerfData{{Version: "v1", DataItems: []perftype.DataItem{dataItem}}},
+		},
+		{
+			name:     "envelope",
+			contents: `{"version": "v1", "data": [{"version": "v1", "dataItems": [{"data": {"Perc99": 21.707}, "unit": "ms", "labels": {"Resource": "pods", "Verb": "DELETE"}}]}, {"version": "v1", "dataItems": []}]}`,
+			expected: []perftype.PerfData{{Version: "v1", DataItems: []perftype.DataItem{dataItem}}, {Version: "v1", DataItems: []perftype.DataItem{}}},
+		},
+		{
+			name:        "malformed envelope",
+			contents:    `{"version": "v1", "data": {"dataItems": []}}`,
+			expectError: true,
+		},
+		{
+			name:        "not json",
+			contents:    `[Result:Performance]`,
+			expectError: true,
+		},
+	}
+	for _, testCase := range testCases {
+		perfData, err := DecodePerfData([]byte(testCase.contents))
+		if testCase.expectError {
+			if err == nil {
+				t.Errorf("%v: expected an error, got %v", testCase.name, perfData)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", testCase.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(perfData, testCase.expected) {
+			t.Errorf("%v: decoded perf data mismatched:\nReal: %v\nExpected: %v", testCase.name, perfData, testCase.expected)
+		}
+	}
+}
+
+func TestGetMetricsForRunDirs(t *testing.T) {
+	// The job's first run has plain latency files, and its second one gzipped copies of them.
+	runs, err := GetMetricsForRunDirs("test-data/mixed-job")
+	if err != nil {
+		t.Fatalf("Loading the runs failed: %v", err)
+	}
+	if len(runs) != 2 {
+		t.Fatalf("Loaded %v runs, but expected 2", len(runs))
+	}
+	if len(runs[0]) != 2 || !reflect.DeepEqual(runs[0], runs[1]) {
+		t.Errorf("Plain and gzipped runs loaded differently:\nPlain: %v\nGzipped: %v", runs[0], runs[1])
+	}
+
+	plain := benchmarkutil.GetFlattennedComparisonData(runs[:1], runs[:1], 0)
+	gzipped := benchmarkutil.GetFlattennedComparisonData(runs[1:], runs[1:], 0)
+	if len(plain.Data) == 0 || !reflect.DeepEqual(plain.Data, gzipped.Data) {
+		t.Errorf("Plain and gzipped runs flattened differently:\nPlain: %v\nGzipped: %v", plain.Data, gzipped.Data)
+	}
+}
+
+func TestSortRunDirs(t *testing.T) {
+	runDirs := []string{"run-10", "logs", "run-2", "run-1", "artifacts", "run-02"}
+	sortRunDirs(runDirs)
+	if expected := []string{"run-1", "run-02", "run-2", "run-10", "artifacts", "logs"}; !reflect.DeepEqual(runDirs, expected) {
+		t.Errorf("Run dirs sorted as %v, but expected %v", runDirs, expected)
+	}
+}
+
+func TestDecompressIfGzipped(t *testing.T) {
+	gzipped, err := ioutil.ReadFile("test-data/mixed-job/run-2/APIResponsiveness_testA_xyz123.json.gz")
+	if err != nil {
+		panic(err)
+	}
+	plain, err := ioutil.ReadFile("test-data/mixed-job/run-1/APIResponsiveness_testA_xyz123.json")
+	if err != nil {
+		panic(err)
+	}
+	for _, contents := range [][]byte{gzipped, plain} {
+		if decompressed, err := decompressIfGzipped(contents); err != nil || string(decompressed) != string(plain) {
+			t.Errorf("Decompressed contents %q (error %v), but expected %q", decompressed, err, plain)
+		}
+	}
+	if _, err := decompressIfGzipped(gzipped[:10]); err == nil {
+		t.Errorf("Expected an error for truncated gzipped contents")
+	}
+}
+
+func TestDecodePerfDataWithUnmarshaler(t *testing.T) {
+	defer func(unmarshaler func([]byte, interface{}) error) {
+		benchmarkutil.Unmarshaler = unmarshaler
+	}(benchmarkutil.Unmarshaler)
+	calls := 0
+	benchmarkutil.Unmarshaler = func(data []byte, v interface{}) error {
+		calls++
+		return json.Unmarshal(data, v)
+	}
+	contents := `{"version": "v1", "dataItems": [{"data": {"Perc99": 21.707}, "unit": "ms", "labels": {"Resource": "pods", "Verb": "DELETE"}}]}`
+	perfData, err := DecodePerfData([]byte(contents))
+	if err != nil || len(perfData) != 1 || len(perfData[0].DataItems) != 1 {
+		t.Fatalf("Decoded perf data %v (error %v), but expected a single DataItem", perfData, err)
+	}
+	// The fields, the schema version and the perf data are all decoded with it.
+	if calls != 3 {
+		t.Errorf("Unmarshaler called %v times, but expected 3", calls)
+	}
+
+	benchmarkutil.Unmarshaler = func(data []byte, v interface{}) error {
+		return errors.New("stub")
+	}
+	if _, err := DecodePerfData([]byte(contents)); err == nil || err.Error() != "stub" {
+		t.Errorf("Decoding with a failing unmarshaler returned error %v, but expected the stub's", err)
+	}
+}
+
+// largeArtifact returns the contents of a metrics file with the given no. of DataItems.
+func largeArtifact(dataItemCount int) []byte {
+	perfData := perftype.PerfData{Version: "v1"}
+	for i := 0; i < dataItemCount; i++ {
+		perfData.DataItems = append(perfData.DataItems, perftype.DataItem{
+			Data:   map[string]float64{"Perc50": float64(i), "Perc90": float64(2 * i), "Perc99": float64(3 * i)},
+			Unit:   "ms",
+			Labels: map[string]string{"Resource": fmt.Sprintf("resource-%v", i%100), "Verb": "LIST", "Scope": "namespace", "Count": strconv.Itoa(i)},
+		})
+	}
+	contents, err := json.Marshal(perfData)
+	if err != nil {
+		panic(err)
+	}
+	return contents
+}
+
+func BenchmarkDecodePerfData(b *testing.B) {
+	contents := largeArtifact(20000)
+	b.Run("default", func(b *testing.B) {
+		b.SetBytes(int64(len(contents)))
+		for i := 0; i < b.N; i++ {
+			if _, err := DecodePerfData(contents); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	// A plugged in decoder (here, a wrapper of the default) is what decodes the artifact.
+	b.Run("plugged", func(b *testing.B) {
+		defer func(unmarshaler func([]byte, interface{}) error) {
+			benchmarkutil.Unmarshaler = unmarshaler
+		}(benchmarkutil.Unmarshaler)
+		bytesDecoded := 0
+		benchmarkutil.Unmarshaler = func(data []byte, v interface{}) error {
+			bytesDecoded += len(data)
+			return json.Unmarshal(data, v)
+		}
+		b.SetBytes(int64(len(contents)))
+		for i := 0; i < b.N; i++ {
+			if _, err := DecodePerfData(contents); err != nil {
+				b.Fatal(err)
+			}
+		}
+		if bytesDecoded < b.N*len(contents) {
+			b.Fatalf("Plugged in unmarshaler decoded %v bytes, but expected at least %v", bytesDecoded, b.N*len(contents))
+		}
+	})
+}
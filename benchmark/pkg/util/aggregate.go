@@ -0,0 +1,118 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"sort"
+
+	"k8s.io/kubernetes/test/e2e/perftype"
+)
+
+// AggregateMetricKey is the key of the metric holding the run-level aggregate scores.
+var AggregateMetricKey = MetricKey{TestName: "Aggregate", Verb: "ALL", Percentile: "Sum"}
+
+// AggregateOptions tunes the run-level aggregate computed by GetAggregateComparisonData.
+type AggregateOptions struct {
+	// Filter tells which metrics to leave out of the aggregate (none, if nil). It's given each
+	// metric as flattened from a single run.
+	Filter MetricFilterFunc
+	// Weights holds the weights of the metrics in the aggregate (1 for metrics absent from it).
+	Weights map[MetricKey]float64
+}
+
+// GetAggregateComparisonData computes a run-level score for each of the left and right job
+// runs, as the (weighted) sum of the values of the metrics included in the aggregate, and
+// returns them as the left and right samples of the single metric AggregateMetricKey, to be
+// compared with the usual schemes (e.g. for telling if the whole test got slower). Each run
+// is flattened with the given options. As sums of values in different units are meaningless,
+// all the included metrics must have the same unit, and it's an error otherwise (filter out
+// or normalize the metrics first). As runs missing some of the included metrics (e.g. due to
+// a low request count) would get a spuriously lower score, they're left out of the aggregate.
+func GetAggregateComparisonData(leftJobMetrics, rightJobMetrics []map[string][]perftype.PerfData, flattenOptions FlattenOptions, options AggregateOptions) (*JobComparisonData, error) {
+	left, err := flattenRunsSeparately(leftJobMetrics, flattenOptions, options.Filter)
+	if err != nil {
+		return nil, err
+	}
+	right, err := flattenRunsSeparately(rightJobMetrics, flattenOptions, options.Filter)
+	if err != nil {
+		return nil, err
+	}
+	unit := ""
+	includedMetrics := make(map[MetricKey]bool)
+	for _, run := range append(append([]*JobComparisonData{}, left...), right...) {
+		for metricKey, metricData := range run.Data {
+			if len(includedMetrics) > 0 && metricData.Unit != unit {
+				return nil, fmt.Errorf("metric %v has unit '%v', but others '%v': normalize the metrics before aggregating", metricKey, metricData.Unit, unit)
+			}
+			unit = metricData.Unit
+			includedMetrics[metricKey] = true
+		}
+	}
+	aggregateData := &MetricComparisonData{Unit: unit}
+	aggregateData.LeftJobSample = runScores(left, includedMetrics, options.Weights)
+	aggregateData.RightJobSample = runScores(right, includedMetrics, options.Weights)
+	j := NewJobComparisonData()
+	j.Data[AggregateMetricKey] = aggregateData
+	return j, nil
+}
+
+// flattenRunsSeparately flattens each of the job's runs into JobComparisonData of its own, with
+// the run's values as left samples, leaving out the metrics filtered out.
+func flattenRunsSeparately(jobMetrics []map[string][]perftype.PerfData, flattenOptions FlattenOptions, filter MetricFilterFunc) ([]*JobComparisonData, error) {
+	var runs []*JobComparisonData
+	for _, singleRunMetrics := range jobMetrics {
+		run := GetFlattennedComparisonDataWithOptions([]map[string][]perftype.PerfData{singleRunMetrics}, nil, flattenOptions)
+		for metricKey, metricData := range run.Data {
+			if filter != nil && filter(metricKey, *metricData) {
+				delete(run.Data, metricKey)
+				continue
+			}
+			if len(metricData.LeftJobSample) != 1 {
+				return nil, fmt.Errorf("metric %v has %v values in a single run, so its runs can't be aggregated", metricKey, len(metricData.LeftJobSample))
+			}
+		}
+		runs = append(runs, run)
+	}
+	return runs, nil
+}
+
+// runScores returns the weighted sums of the included metrics' values of the runs having all of them.
+func runScores(runs []*JobComparisonData, includedMetrics map[MetricKey]bool, weights map[MetricKey]float64) []float64 {
+	var metricKeys []MetricKey
+	for metricKey := range includedMetrics {
+		metricKeys = append(metricKeys, metricKey)
+	}
+	// Sum in a stable order, for the scores not to depend on rounding differences.
+	sort.Slice(metricKeys, func(a, b int) bool { return metricKeyLess(metricKeys[a], metricKeys[b]) })
+	var scores []float64
+	for _, run := range runs {
+		if len(run.Data) != len(includedMetrics) {
+			continue
+		}
+		score := 0.0
+		for _, metricKey := range metricKeys {
+			weight, ok := weights[metricKey]
+			if !ok {
+				weight = 1
+			}
+			score += weight * run.Data[metricKey].LeftJobSample[0]
+		}
+		scores = append(scores, score)
+	}
+	return scores
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/kubernetes/test/e2e/perftype"
+)
+
+func TestGetAggregateComparisonData(t *testing.T) {
+	runMetrics := func(getLatency, listLatency float64, listCount string, startupUnit string) map[string][]perftype.PerfData {
+		return map[string][]perftype.PerfData{
+			"Load": {
+				{
+					Version: "v1",
+					DataItems: []perftype.DataItem{
+						{Data: map[string]float64{"Perc99": getLatency}, Unit: "ms", Labels: map[string]string{"Count": "10", "Resource": "pods", "Verb": "GET"}},
+						{Data: map[string]float64{"Perc99": listLatency}, Unit: "ms", Labels: map[string]string{"Count": listCount, "Resource": "pods", "Verb": "LIST"}},
+						{Data: map[string]float64{"Perc99": 5}, Unit: startupUnit, Labels: map[string]string{"Metric": "pod_startup"}},
+					},
+				},
+			},
+		}
+	}
+	leftJobMetrics := []map[string][]perftype.PerfData{runMetrics(10, 20, "10", "s"), runMetrics(12, 22, "10", "s")}
+	// The second right run's LIST has too few requests, leaving the run out.
+	rightJobMetrics := []map[string][]perftype.PerfData{runMetrics(20, 30, "10", "s"), runMetrics(1, 1, "1", "s"), runMetrics(20, 40, "10", "s")}
+	listPods99 := MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}
+	options := AggregateOptions{
+		Filter:  func(k MetricKey, d MetricComparisonData) bool { return d.Unit != "ms" },
+		Weights: map[MetricKey]float64{listPods99: 0.5},
+	}
+
+	j, err := GetAggregateComparisonData(leftJobMetrics, rightJobMetrics, FlattenOptions{MinAllowedAPIRequestCount: 10}, options)
+	if err != nil {
+		t.Fatalf("Aggregating runs failed: %v", err)
+	}
+	aggregateData := j.Data[AggregateMetricKey]
+	if len(j.Data) != 1 || aggregateData.Unit != "ms" {
+		t.Fatalf("Wrong aggregate comparison data: %+v", j.Data)
+	}
+	if expected := []float64{20, 23}; !reflect.DeepEqual(aggregateData.LeftJobSample, expected) {
+		t.Errorf("Left run scores are %v, but expected %v", aggregateData.LeftJobSample, expected)
+	}
+	if expected := []float64{35, 40}; !reflect.DeepEqual(aggregateData.RightJobSample, expected) {
+		t.Errorf("Right run scores are %v, but expected %v", aggregateData.RightJobSample, expected)
+	}
+
+	// Metrics of different units can't be summed up.
+	if _, err := GetAggregateComparisonData(leftJobMetrics, rightJobMetrics, FlattenOptions{MinAllowedAPIRequestCount: 10}, AggregateOptions{}); err == nil {
+		t.Errorf("Expected an error aggregating metrics of different units")
+	}
+}
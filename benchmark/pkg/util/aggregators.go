@@ -0,0 +1,104 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"math"
+)
+
+// Aggregator reduces the values of a metric across the runs of a job into a single one.
+type Aggregator func(values []float64) float64
+
+// MeanOfRuns aggregates the metric's values across runs into their mean.
+func MeanOfRuns(values []float64) float64 {
+	return Mean(values)
+}
+
+// MedianOfRuns aggregates the metric's values across runs into their median, which isn't
+// thrown off by a single bad run.
+func MedianOfRuns(values []float64) float64 {
+	return percentileOf(values, 0.5)
+}
+
+// P95OfRuns aggregates the metric's values across runs into their 95th percentile.
+func P95OfRuns(values []float64) float64 {
+	return percentileOf(values, 0.95)
+}
+
+// MaxOfRuns aggregates the metric's values across runs into the max of them, i.e. the worst run.
+func MaxOfRuns(values []float64) float64 {
+	max := math.Inf(-1)
+	for _, value := range values {
+		max = math.Max(max, value)
+	}
+	return max
+}
+
+// AggregateAllWith returns an AggregatorFor that aggregates all the metrics with the aggregator.
+func AggregateAllWith(aggregator Aggregator) func(MetricKey) Aggregator {
+	return func(MetricKey) Aggregator { return aggregator }
+}
+
+// sampleLengths holds the no. of left and right sample values of a metric.
+type sampleLengths struct {
+	left, right int
+}
+
+func (j *JobComparisonData) sampleLengths() map[MetricKey]sampleLengths {
+	lengths := make(map[MetricKey]sampleLengths, len(j.Data))
+	for metricKey, metricData := range j.Data {
+		lengths[metricKey] = sampleLengths{len(metricData.LeftJobSample), len(metricData.RightJobSample)}
+	}
+	return lengths
+}
+
+// aggregateRuns reduces the metrics' sample values added after the given lengths (all of them,
+// for metrics without any) into single ones, as per the options' AggregatorFor, if set.
+func (j *JobComparisonData) aggregateRuns(options *FlattenOptions, previousLengths map[MetricKey]sampleLengths) {
+	if options.AggregatorFor == nil {
+		return
+	}
+	for metricKey, metricData := range j.Data {
+		aggregator := options.AggregatorFor(metricKey)
+		if aggregator == nil {
+			continue
+		}
+		lengths := previousLengths[metricKey]
+		aggregateTail(&metricData.LeftJobSample, &metricData.LeftJobRequestCounts, lengths.left, aggregator)
+		aggregateTail(&metricData.RightJobSample, &metricData.RightJobRequestCounts, lengths.right, aggregator)
+		// The aggregates aren't from any single run.
+		metricData.LeftJobTimestamps, metricData.RightJobTimestamps = nil, nil
+		metricData.LeftJobRunIndices, metricData.RightJobRunIndices = nil, nil
+	}
+}
+
+// aggregateTail replaces the sample values from the given one on with their aggregate, backed
+// by the sum of their request counts (if retained).
+func aggregateTail(sample, requestCounts *[]float64, from int, aggregator Aggregator) {
+	if len(*sample) <= from {
+		return
+	}
+	aggregate := aggregator((*sample)[from:])
+	*sample = append((*sample)[:from], aggregate)
+	if len(*requestCounts) > from {
+		sum := 0.0
+		for _, count := range (*requestCounts)[from:] {
+			sum += count
+		}
+		*requestCounts = append((*requestCounts)[:from], sum)
+	}
+}
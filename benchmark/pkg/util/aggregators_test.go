@@ -0,0 +1,92 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/kubernetes/test/e2e/perftype"
+)
+
+func TestAggregatorFor(t *testing.T) {
+	runMetrics := func(getLatency, listLatency float64) map[string][]perftype.PerfData {
+		return map[string][]perftype.PerfData{
+			"Load": {{Version: "v1", DataItems: []perftype.DataItem{
+				{Data: map[string]float64{"Perc99": getLatency}, Unit: "ms", Labels: map[string]string{"Count": "10", "Resource": "pods", "Verb": "GET"}},
+				{Data: map[string]float64{"Perc99": listLatency}, Unit: "ms", Labels: map[string]string{"Count": "20", "Resource": "pods", "Verb": "LIST"}},
+			}}},
+		}
+	}
+	getKey := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	listKey := MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}
+	leftJobMetrics := []map[string][]perftype.PerfData{runMetrics(10, 100), runMetrics(30, 300), runMetrics(20, 200)}
+	rightJobMetrics := []map[string][]perftype.PerfData{runMetrics(40, 400), runMetrics(60, 600)}
+
+	for _, test := range []struct {
+		name                string
+		aggregator          Aggregator
+		expectedLeftSample  []float64
+		expectedRightSample []float64
+	}{
+		{"None", nil, []float64{10, 30, 20}, []float64{40, 60}},
+		{"Mean", MeanOfRuns, []float64{20}, []float64{50}},
+		{"Median", MedianOfRuns, []float64{20}, []float64{50}},
+		{"P95", P95OfRuns, []float64{29}, []float64{59}},
+		{"Max", MaxOfRuns, []float64{30}, []float64{60}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			// Only the GET metric is aggregated.
+			options := FlattenOptions{
+				MinAllowedAPIRequestCount: 10,
+				KeepRequestCounts:         true,
+				KeepRunIndices:            true,
+				AggregatorFor: func(metricKey MetricKey) Aggregator {
+					if metricKey.Verb == "GET" {
+						return test.aggregator
+					}
+					return nil
+				},
+			}
+			j := GetFlattennedComparisonDataWithOptions(leftJobMetrics, rightJobMetrics, options)
+			if metricData := j.Data[getKey]; !reflect.DeepEqual(metricData.LeftJobSample, test.expectedLeftSample) || !reflect.DeepEqual(metricData.RightJobSample, test.expectedRightSample) {
+				t.Errorf("Samples aggregated as %v and %v, but expected %v and %v", metricData.LeftJobSample, metricData.RightJobSample, test.expectedLeftSample, test.expectedRightSample)
+			}
+			if metricData := j.Data[listKey]; !reflect.DeepEqual(metricData.LeftJobSample, []float64{100, 300, 200}) || !reflect.DeepEqual(metricData.LeftJobRunIndices, []int{0, 1, 2}) {
+				t.Errorf("Metric without an aggregator aggregated: %+v", metricData)
+			}
+			if test.aggregator == nil {
+				return
+			}
+			if metricData := j.Data[getKey]; !reflect.DeepEqual(metricData.LeftJobRequestCounts, []float64{30}) || metricData.LeftJobRunIndices != nil {
+				t.Errorf("Aggregated metric has request counts %v and run indices %v, but expected the counts' sum and none", metricData.LeftJobRequestCounts, metricData.LeftJobRunIndices)
+			}
+
+			// Appended runs are aggregated separately.
+			j.AppendRuns(nil, []map[string][]perftype.PerfData{runMetrics(80, 800), runMetrics(80, 800)}, 10)
+			if rightSample := j.Data[getKey].RightJobSample; !reflect.DeepEqual(rightSample, append(test.expectedRightSample, 80)) {
+				t.Errorf("Right sample after appending runs is %v, but expected %v", rightSample, append(test.expectedRightSample, 80))
+			}
+		})
+	}
+
+	// All the metrics can be aggregated the same way.
+	j := GetFlattennedComparisonDataWithOptions(leftJobMetrics, rightJobMetrics, FlattenOptions{MinAllowedAPIRequestCount: 10, AggregatorFor: AggregateAllWith(MaxOfRuns)})
+	if leftSample := j.Data[listKey].LeftJobSample; !reflect.DeepEqual(leftSample, []float64{300}) {
+		t.Errorf("Left sample aggregated as %v, but expected the max of 300", leftSample)
+	}
+}
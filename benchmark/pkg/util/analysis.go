@@ -0,0 +1,298 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Thresholds on the relative change of the average and standard deviation between left and
+// right jobs, beyond which a metric is considered to have moved in the respective aspect.
+var (
+	MixedAvgChangeThreshold   = 0.1
+	MixedStDevChangeThreshold = 0.1
+)
+
+// relativeChange returns (right - left) / left, or NaN if it can't be computed.
+func relativeChange(left, right float64) float64 {
+	change, _ := SafeDiv(right-left, left)
+	return change
+}
+
+// MixedMetrics returns the keys (sorted) of metrics whose average and standard deviation moved
+// in opposite directions beyond MixedAvgChangeThreshold and MixedStDevChangeThreshold resp.
+// E.g. a metric whose average got worse while it got more predictable (or vice versa). These
+// deserve human judgement rather than being lumped into a single verdict. Metrics for which
+// either change can't be computed (e.g. as the left sample has no spread) are skipped. The
+// stats should have been computed already.
+func (j *JobComparisonData) MixedMetrics() []MetricKey {
+	var mixedMetrics []MetricKey
+	for _, metricPair := range getMetricsSortedByKey(j) {
+		data := metricPair.metricData
+		avgChange := relativeChange(data.AvgL, data.AvgR)
+		stDevChange := relativeChange(data.StDevL, data.StDevR)
+		if math.IsNaN(avgChange) || math.IsNaN(stDevChange) {
+			continue
+		}
+		if math.Abs(avgChange) <= MixedAvgChangeThreshold || math.Abs(stDevChange) <= MixedStDevChangeThreshold {
+			continue
+		}
+		if (avgChange > 0) != (stDevChange > 0) {
+			mixedMetrics = append(mixedMetrics, metricPair.metricKey)
+		}
+	}
+	return mixedMetrics
+}
+
+// MinDetectableEffect returns the smallest relative change of the average (w.r.t. the left
+// job's average) that a two-sided comparison at significance level alpha would detect with
+// the given power, given the metric's sample sizes and variances (normal approximation):
+//
+//	MDE = (z(1-alpha/2) + z(power)) * sqrt(varL/nL + varR/nR) / AvgL
+//
+// It's +Inf if either side has less than 2 samples (as the noise can't be estimated then),
+// and NaN if the left average is zero. A huge value means a "matched" verdict isn't telling
+// much, as the comparison is underpowered.
+func (d *MetricComparisonData) MinDetectableEffect(alpha, power float64) float64 {
+	leftCount, rightCount := len(d.LeftJobSample), len(d.RightJobSample)
+	if leftCount < 2 || rightCount < 2 {
+		return math.Inf(1)
+	}
+	standardError := math.Sqrt(SampleVariance(d.LeftJobSample)/float64(leftCount) + SampleVariance(d.RightJobSample)/float64(rightCount))
+	effect, _ := SafeDiv((NormalQuantile(1-alpha/2)+NormalQuantile(power))*standardError, math.Abs(Mean(d.LeftJobSample)))
+	return effect
+}
+
+// RelativeChangeCI returns the relative change of the metric's average, (AvgR-AvgL)/AvgL, along
+// with the bounds of its confidence interval at the given confidence level (e.g. 0.95), also
+// storing them in the metric's RelativeChange fields. The interval propagates the variances of
+// both sides' averages into that of their ratio with the delta method (normal approximation):
+//
+//	SE = sqrt(varR/nR + (AvgR/AvgL)^2 * varL/nL) / |AvgL|
+//
+// The approximation breaks down as the left average nears zero (the ratio being unbounded
+// then), so the bounds are NaN if the left average isn't significantly away from zero at the
+// same level. They're also NaN if either side has less than 2 samples, and the change itself
+// is NaN if the left average is zero.
+func (d *MetricComparisonData) RelativeChangeCI(confidence float64) (change, low, high float64) {
+	leftCount, rightCount := len(d.LeftJobSample), len(d.RightJobSample)
+	leftAvg, rightAvg := Mean(d.LeftJobSample), Mean(d.RightJobSample)
+	change, low, high = relativeChange(leftAvg, rightAvg), math.NaN(), math.NaN()
+	z := NormalQuantile((1 + confidence) / 2)
+	if leftCount >= 2 && rightCount >= 2 {
+		leftAvgVariance, rightAvgVariance := SampleVariance(d.LeftJobSample)/float64(leftCount), SampleVariance(d.RightJobSample)/float64(rightCount)
+		if math.Abs(leftAvg) > z*math.Sqrt(leftAvgVariance) {
+			ratio := rightAvg / leftAvg
+			standardError := math.Sqrt(rightAvgVariance+ratio*ratio*leftAvgVariance) / math.Abs(leftAvg)
+			low, high = change-z*standardError, change+z*standardError
+		}
+	}
+	d.RelativeChange, d.RelativeChangeLow, d.RelativeChangeHigh, d.ChangeConfidence = change, low, high, confidence
+	return change, low, high
+}
+
+// ComputeRelativeChangeCIs computes the relative change of each metric's average, along with
+// its confidence interval at the given level (see RelativeChangeCI), for the reports.
+func (j *JobComparisonData) ComputeRelativeChangeCIs(confidence float64) {
+	for _, metricData := range j.Data {
+		metricData.RelativeChangeCI(confidence)
+	}
+}
+
+// formatChangeCI formats the relative change of the metric's average, like "+12.0%", followed
+// by its confidence interval if computed, like "+12.0% (CI: +4.0% to +20.0%)".
+func (d *MetricComparisonData) formatChangeCI() string {
+	if d.ChangeConfidence == 0 {
+		return formatPercentChange(relativeChange(d.AvgL, d.AvgR))
+	}
+	if math.IsNaN(d.RelativeChangeLow) || math.IsNaN(d.RelativeChangeHigh) {
+		return fmt.Sprintf("%v (CI: ?)", formatPercentChange(d.RelativeChange))
+	}
+	return fmt.Sprintf("%v (CI: %v to %v)", formatPercentChange(d.RelativeChange), formatPercentChange(d.RelativeChangeLow), formatPercentChange(d.RelativeChangeHigh))
+}
+
+// MinDetectableEffect returns the minimum detectable effect of each metric (see
+// MetricComparisonData.MinDetectableEffect), i.e. the smallest relative change of its average
+// the comparison could reliably detect with its current sample sizes and variances. It doesn't
+// need the metrics to be compared, making for a precheck of whether the data is precise enough
+// for the regressions of interest.
+func (j *JobComparisonData) MinDetectableEffect(alpha, power float64) map[MetricKey]float64 {
+	effects := make(map[MetricKey]float64, len(j.Data))
+	for metricKey, metricData := range j.Data {
+		effects[metricKey] = metricData.MinDetectableEffect(alpha, power)
+	}
+	return effects
+}
+
+// InsufficientlyPowered is added to the comments of underpowered metrics by FlagUnderpoweredMetrics.
+const InsufficientlyPowered = "Insufficiently powered"
+
+// UnderpoweredMetrics returns the keys (sorted) of metrics whose minimum detectable effect
+// (see MinDetectableEffect) exceeds maxEffect, i.e. which are effectively un-gateable.
+func (j *JobComparisonData) UnderpoweredMetrics(alpha, power, maxEffect float64) []MetricKey {
+	var underpoweredMetrics []MetricKey
+	for _, metricPair := range getMetricsSortedByKey(j) {
+		if effect := metricPair.metricData.MinDetectableEffect(alpha, power); math.IsNaN(effect) || effect > maxEffect {
+			underpoweredMetrics = append(underpoweredMetrics, metricPair.metricKey)
+		}
+	}
+	return underpoweredMetrics
+}
+
+// FlagUnderpoweredMetrics flags the underpowered metrics (see UnderpoweredMetrics), adding
+// InsufficientlyPowered and their minimum detectable effect to their comments, so that their
+// verdicts (notably matching) aren't taken for more than they are. It should be called after
+// the comparison, which resets the comments, and returns the keys (sorted) of the metrics
+// flagged.
+func (j *JobComparisonData) FlagUnderpoweredMetrics(alpha, power, maxEffect float64) []MetricKey {
+	underpoweredMetrics := j.UnderpoweredMetrics(alpha, power, maxEffect)
+	for _, metricKey := range underpoweredMetrics {
+		metricData := j.Data[metricKey]
+		flag := fmt.Sprintf("%v (MDE=%v)", InsufficientlyPowered, formatPercent(100*metricData.MinDetectableEffect(alpha, power)))
+		if metricData.Comments != "" {
+			flag = "\t" + flag
+		}
+		metricData.Comments += flag
+	}
+	return underpoweredMetrics
+}
+
+// QQData returns the quantiles of the left and right job samples at the given number of
+// evenly spaced points (from min to max), for drawing a QQ-plot. Identical distributions
+// lie on the y=x line, and deviations show where in the distribution they differ.
+// It returns nil slices if either sample is empty or points isn't positive.
+func (d *MetricComparisonData) QQData(points int) (leftQuantiles, rightQuantiles []float64) {
+	if points <= 0 || len(d.LeftJobSample) == 0 || len(d.RightJobSample) == 0 {
+		return nil, nil
+	}
+	sortedL, sortedR := sortedCopy(d.LeftJobSample), sortedCopy(d.RightJobSample)
+	leftQuantiles = make([]float64, points)
+	rightQuantiles = make([]float64, points)
+	for i := 0; i < points; i++ {
+		fraction := 0.5
+		if points > 1 {
+			fraction = float64(i) / float64(points-1)
+		}
+		leftQuantiles[i] = percentileOfSorted(sortedL, fraction)
+		rightQuantiles[i] = percentileOfSorted(sortedR, fraction)
+	}
+	return leftQuantiles, rightQuantiles
+}
+
+// RegressionRanking tells by what TopRegressions ranks the metrics.
+type RegressionRanking int
+
+// Allowed regression rankings.
+const (
+	RankByAvgRatio RegressionRanking = iota // Ratio of right and left avgs
+	RankByMaxRatio                          // Ratio of right and left maxes, for tail regressions
+	RankBySNR                               // Signal-to-noise ratio of the change of avg, for confident regressions
+)
+
+// regressionRatio returns the right/left ratio of the metric's statistic used by the ranking,
+// or its SNR (negated for improvements, i.e. for rates that went up) when ranking by it.
+func (d *MetricComparisonData) regressionRatio(ranking RegressionRanking) float64 {
+	switch ranking {
+	case RankByMaxRatio:
+		return d.MaxRatio
+	case RankBySNR:
+		if !d.worsened() {
+			return -d.SNR
+		}
+		return d.SNR
+	}
+	ratio, _ := SafeDiv(d.AvgR, d.AvgL)
+	return ratio
+}
+
+// TopRegressions returns the keys of (at most) n metrics that regressed the most, i.e. with the
+// highest right/left ratio of the statistic used by the ranking, in decreasing order of it.
+// Ranking by the ratio of maxes catches new tail latency spikes, which often leave the avg flat,
+// while ranking by the SNR puts the most confidently slowed down metrics first, rather than the
+// ones that changed the most but are the noisiest (and noise-free changes, of infinite SNR, lead).
+// Metrics whose ratio can't be computed are left out. The stats should have been computed already.
+func (j *JobComparisonData) TopRegressions(n int, ranking RegressionRanking) []MetricKey {
+	var metricsList metricKeyDataPairList
+	for _, metricPair := range getMetricsSortedByKey(j) {
+		if ratio := metricPair.metricData.regressionRatio(ranking); !math.IsNaN(ratio) {
+			metricsList = append(metricsList, metricPair)
+		}
+	}
+	sort.SliceStable(metricsList, func(a, b int) bool {
+		return metricsList[a].metricData.regressionRatio(ranking) > metricsList[b].metricData.regressionRatio(ranking)
+	})
+	var topRegressions []MetricKey
+	for i := 0; i < len(metricsList) && i < n; i++ {
+		topRegressions = append(topRegressions, metricsList[i].metricKey)
+	}
+	return topRegressions
+}
+
+// Severities of mismatched metrics, as bucketed by SeverityBreakdown.
+const (
+	SeverityMinor    = "minor"
+	SeverityMajor    = "major"
+	SeverityCritical = "critical"
+)
+
+// severities lists the severities from the least to the most severe.
+var severities = []string{SeverityMinor, SeverityMajor, SeverityCritical}
+
+// DefaultSeverityThresholds are the severity thresholds (see SeverityBreakdown) used where none
+// are given, making a 25% slowdown major and a doubling critical.
+var DefaultSeverityThresholds = []float64{0.25, 1}
+
+// severity returns the severity of the metric's mismatch as per the thresholds (see SeverityBreakdown).
+func (d *MetricComparisonData) severity(thresholds []float64) string {
+	change := math.Abs(relativeChange(d.AvgL, d.AvgR))
+	if math.IsNaN(change) {
+		return severities[len(severities)-1]
+	}
+	level := 0
+	for level < len(severities)-1 && level < len(thresholds) && !(change < thresholds[level]) {
+		level++
+	}
+	// Sustained regressions are escalated (see EscalateSustainedRegressions).
+	if d.SustainedRegressionCount > 1 && level < len(severities)-1 {
+		level++
+	}
+	return severities[level]
+}
+
+// SeverityBreakdown buckets the mismatched metrics by severity, based on the magnitude of the
+// relative change of their average, and returns the number of metrics in each bucket (with all
+// the severities present). The thresholds are the (increasing) magnitudes from which a mismatch
+// is major and critical resp., e.g. {0.25, 1} makes a 30% slowdown major and a doubling critical.
+// Metrics whose change can't be computed (e.g. having a zero left average) are deemed critical,
+// while inconclusive ones are left out, having no verdict. Regressions sustained across
+// comparisons (see EscalateSustainedRegressions) are a level more severe. The stats should
+// have been computed already.
+func (j *JobComparisonData) SeverityBreakdown(thresholds []float64) map[string]int {
+	breakdown := make(map[string]int)
+	for _, severity := range severities {
+		breakdown[severity] = 0
+	}
+	for _, metricData := range j.Data {
+		if metricData.Matched || metricData.Inconclusive {
+			continue
+		}
+		breakdown[metricData.severity(thresholds)]++
+	}
+	return breakdown
+}
@@ -0,0 +1,305 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMixedMetrics(t *testing.T) {
+	slowerButSteadier := MetricKey{TestName: "xyz", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	fasterButNoisier := MetricKey{TestName: "xyz", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}
+	slowerAndNoisier := MetricKey{TestName: "xyz", Verb: "PUT", Resource: "pods", Percentile: "Perc99"}
+	unchanged := MetricKey{TestName: "xyz", Verb: "POST", Resource: "pods", Percentile: "Perc99"}
+	// The relative change of the std-dev can't be computed for a left sample without spread.
+	slowerWithoutLeftSpread := MetricKey{TestName: "xyz", Verb: "DELETE", Resource: "pods", Percentile: "Perc99"}
+	jobComparisonData := &JobComparisonData{
+		Data: map[MetricKey]*MetricComparisonData{
+			slowerButSteadier: {
+				LeftJobSample:  []float64{80, 120},
+				RightJobSample: []float64{148, 152},
+			},
+			fasterButNoisier: {
+				LeftJobSample:  []float64{95, 105},
+				RightJobSample: []float64{50, 110},
+			},
+			slowerAndNoisier: {
+				LeftJobSample:  []float64{95, 105},
+				RightJobSample: []float64{130, 170},
+			},
+			unchanged: {
+				LeftJobSample:  []float64{95, 105},
+				RightJobSample: []float64{96, 104},
+			},
+			slowerWithoutLeftSpread: {
+				LeftJobSample:  []float64{100, 100},
+				RightJobSample: []float64{150, 170},
+			},
+		},
+	}
+	jobComparisonData.ComputeStatsForMetricSamples()
+
+	mixedMetrics := jobComparisonData.MixedMetrics()
+	expected := []MetricKey{slowerButSteadier, fasterButNoisier}
+	if !reflect.DeepEqual(mixedMetrics, expected) {
+		t.Errorf("Mixed metrics mismatched:\nReal: %v\nExpected: %v", mixedMetrics, expected)
+	}
+}
+
+func TestMinDetectableEffect(t *testing.T) {
+	precise := MetricKey{TestName: "xyz", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	singleRun := MetricKey{TestName: "xyz", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}
+	noisy := MetricKey{TestName: "xyz", Verb: "PUT", Resource: "pods", Percentile: "Perc99"}
+	jobComparisonData := &JobComparisonData{
+		Data: map[MetricKey]*MetricComparisonData{
+			precise: {
+				LeftJobSample:  []float64{95, 100, 105, 100},
+				RightJobSample: []float64{90, 100, 110, 100},
+			},
+			singleRun: {
+				LeftJobSample:  []float64{95, 100, 105, 100},
+				RightJobSample: []float64{100},
+			},
+			noisy: {
+				LeftJobSample:  []float64{10, 100, 190},
+				RightJobSample: []float64{20, 200},
+			},
+		},
+	}
+
+	if effect := jobComparisonData.Data[precise].MinDetectableEffect(0.05, 0.8); math.Abs(effect-0.127874) > 1e-5 {
+		t.Errorf("Min detectable effect computed as %v, but expected 0.127874", effect)
+	}
+	if effect := jobComparisonData.Data[singleRun].MinDetectableEffect(0.05, 0.8); !math.IsInf(effect, 1) {
+		t.Errorf("Min detectable effect computed as %v for a single-run sample, but expected +Inf", effect)
+	}
+	underpowered := jobComparisonData.UnderpoweredMetrics(0.05, 0.8, 0.5)
+	if !reflect.DeepEqual(underpowered, []MetricKey{singleRun, noisy}) {
+		t.Errorf("Underpowered metrics computed as %v, but expected %v", underpowered, []MetricKey{singleRun, noisy})
+	}
+
+	effects := jobComparisonData.MinDetectableEffect(0.05, 0.8)
+	if len(effects) != 3 || math.Abs(effects[precise]-0.127874) > 1e-5 || !math.IsInf(effects[singleRun], 1) || !(effects[noisy] > 0.5) {
+		t.Errorf("Min detectable effects computed as %v", effects)
+	}
+	jobComparisonData.Data[noisy].Comments = "Change=+50.0%"
+	if flagged := jobComparisonData.FlagUnderpoweredMetrics(0.05, 0.8, 0.5); !reflect.DeepEqual(flagged, underpowered) {
+		t.Errorf("Flagged metrics %v, but expected %v", flagged, underpowered)
+	}
+	if comments := jobComparisonData.Data[singleRun].Comments; comments != "Insufficiently powered (MDE=-)" {
+		t.Errorf("Low-sample metric commented %q, but expected it flagged as insufficiently powered", comments)
+	}
+	if comments := jobComparisonData.Data[noisy].Comments; !strings.HasPrefix(comments, "Change=+50.0%\tInsufficiently powered (MDE=") {
+		t.Errorf("Noisy metric commented %q, but expected it flagged as insufficiently powered", comments)
+	}
+	if comments := jobComparisonData.Data[precise].Comments; comments != "" {
+		t.Errorf("Precise metric commented %q, but expected it not flagged", comments)
+	}
+}
+
+func TestRelativeChangeCI(t *testing.T) {
+	testCases := []struct {
+		name                      string
+		data                      MetricComparisonData
+		expectedChange            float64
+		expectedLow, expectedHigh float64
+		expectedFormat            string
+	}{
+		{
+			name:           "noisy increase",
+			data:           MetricComparisonData{LeftJobSample: []float64{90, 110}, RightJobSample: []float64{100, 120}},
+			expectedChange: 0.1,
+			expectedLow:    0.1 - 1.959964*math.Sqrt(221)/100,
+			expectedHigh:   0.1 + 1.959964*math.Sqrt(221)/100,
+			expectedFormat: "+10.0% (CI: -19.1% to +39.1%)",
+		},
+		{
+			name:           "left avg not away from zero",
+			data:           MetricComparisonData{LeftJobSample: []float64{1, 5}, RightJobSample: []float64{6, 6}},
+			expectedChange: 1,
+			expectedLow:    math.NaN(),
+			expectedHigh:   math.NaN(),
+			expectedFormat: "+100.0% (CI: ?)",
+		},
+		{
+			name:           "zero left avg",
+			data:           MetricComparisonData{LeftJobSample: []float64{-1, 1}, RightJobSample: []float64{6, 6}},
+			expectedChange: math.NaN(),
+			expectedLow:    math.NaN(),
+			expectedHigh:   math.NaN(),
+			expectedFormat: "? (CI: ?)",
+		},
+		{
+			name:           "single run",
+			data:           MetricComparisonData{LeftJobSample: []float64{100}, RightJobSample: []float64{120, 120}},
+			expectedChange: 0.2,
+			expectedLow:    math.NaN(),
+			expectedHigh:   math.NaN(),
+			expectedFormat: "+20.0% (CI: ?)",
+		},
+	}
+	sameFloat := func(a, b float64) bool {
+		return (math.IsNaN(a) && math.IsNaN(b)) || math.Abs(a-b) < 1e-6
+	}
+	for _, testCase := range testCases {
+		change, low, high := testCase.data.RelativeChangeCI(0.95)
+		if !sameFloat(change, testCase.expectedChange) || !sameFloat(low, testCase.expectedLow) || !sameFloat(high, testCase.expectedHigh) {
+			t.Errorf("%v: change is %v (CI: %v to %v), but expected %v (CI: %v to %v)", testCase.name, change, low, high, testCase.expectedChange, testCase.expectedLow, testCase.expectedHigh)
+		}
+		if testCase.data.ChangeConfidence != 0.95 || !sameFloat(testCase.data.RelativeChangeHigh, high) {
+			t.Errorf("%v: confidence interval not stored: %+v", testCase.name, testCase.data)
+		}
+		if formatted := testCase.data.formatChangeCI(); formatted != testCase.expectedFormat {
+			t.Errorf("%v: change formatted as %q, but expected %q", testCase.name, formatted, testCase.expectedFormat)
+		}
+	}
+}
+
+func TestQQData(t *testing.T) {
+	data := &MetricComparisonData{
+		LeftJobSample:  []float64{5, 1, 4, 2, 3},
+		RightJobSample: []float64{3, 4, 1, 5, 2},
+	}
+	leftQuantiles, rightQuantiles := data.QQData(5)
+	if !reflect.DeepEqual(leftQuantiles, rightQuantiles) || !reflect.DeepEqual(leftQuantiles, []float64{1, 2, 3, 4, 5}) {
+		t.Errorf("Quantiles of identical samples mismatched: %v vs %v", leftQuantiles, rightQuantiles)
+	}
+
+	// Samples of different (and short) lengths should be interpolated.
+	data.RightJobSample = []float64{10, 20}
+	leftQuantiles, rightQuantiles = data.QQData(3)
+	if !reflect.DeepEqual(leftQuantiles, []float64{1, 3, 5}) || !reflect.DeepEqual(rightQuantiles, []float64{10, 15, 20}) {
+		t.Errorf("Quantiles computed as %v and %v, but expected [1 3 5] and [10 15 20]", leftQuantiles, rightQuantiles)
+	}
+
+	data.RightJobSample = nil
+	if leftQuantiles, rightQuantiles = data.QQData(3); leftQuantiles != nil || rightQuantiles != nil {
+		t.Errorf("Expected no quantiles when a sample is empty, got %v and %v", leftQuantiles, rightQuantiles)
+	}
+}
+
+func TestTopRegressions(t *testing.T) {
+	flatAvgTailSpike := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	avgRegression := MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}
+	unchanged := MetricKey{TestName: "Load", Verb: "PUT", Resource: "pods", Percentile: "Perc99"}
+	zeroMax := MetricKey{TestName: "Load", Verb: "POST", Resource: "pods", Percentile: "Perc99"}
+	j := &JobComparisonData{
+		Data: map[MetricKey]*MetricComparisonData{
+			flatAvgTailSpike: {LeftJobSample: []float64{10, 10, 10, 10, 10}, RightJobSample: []float64{6, 6, 6, 6, 26}},
+			avgRegression:    {LeftJobSample: []float64{10, 10}, RightJobSample: []float64{15, 15}},
+			unchanged:        {LeftJobSample: []float64{10, 10}, RightJobSample: []float64{10, 10}},
+			zeroMax:          {LeftJobSample: []float64{0, 0}, RightJobSample: []float64{1, 1}},
+		},
+	}
+	j.ComputeStatsForMetricSamples()
+	if maxRatio := j.Data[flatAvgTailSpike].MaxRatio; maxRatio != 2.6 {
+		t.Errorf("Max ratio computed as %v, but expected 2.6", maxRatio)
+	}
+	if maxRatio := j.Data[zeroMax].MaxRatio; !math.IsNaN(maxRatio) {
+		t.Errorf("Max ratio computed as %v for a zero left max, but expected NaN", maxRatio)
+	}
+
+	// The tail spike doesn't show up in avgs, but tops the ranking by maxes.
+	if top := j.TopRegressions(2, RankByAvgRatio); !reflect.DeepEqual(top, []MetricKey{avgRegression, flatAvgTailSpike}) {
+		t.Errorf("Top regressions by avg ratio: %v", top)
+	}
+	if top := j.TopRegressions(10, RankByMaxRatio); !reflect.DeepEqual(top, []MetricKey{flatAvgTailSpike, avgRegression, unchanged}) {
+		t.Errorf("Top regressions by max ratio: %v", top)
+	}
+}
+
+func TestTopRegressionsBySNR(t *testing.T) {
+	bigButNoisy := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	smallButStable := MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}
+	improvement := MetricKey{TestName: "Load", Verb: "PUT", Resource: "pods", Percentile: "Perc99"}
+	noiseFree := MetricKey{TestName: "Load", Verb: "POST", Resource: "pods", Percentile: "Perc99"}
+	unchanged := MetricKey{TestName: "Load", Verb: "DELETE", Resource: "pods", Percentile: "Perc99"}
+	j := &JobComparisonData{
+		Data: map[MetricKey]*MetricComparisonData{
+			bigButNoisy:    {LeftJobSample: []float64{50, 150}, RightJobSample: []float64{100, 300}},
+			smallButStable: {LeftJobSample: []float64{99, 101}, RightJobSample: []float64{109, 111}},
+			improvement:    {LeftJobSample: []float64{99, 101}, RightJobSample: []float64{89, 91}},
+			noiseFree:      {LeftJobSample: []float64{10, 10}, RightJobSample: []float64{11, 11}},
+			unchanged:      {LeftJobSample: []float64{10, 10}, RightJobSample: []float64{10, 10}},
+		},
+	}
+	j.ComputeStatsForMetricSamples()
+	// |200-100| / sqrt(50^2 + 100^2) and |110-100| / sqrt(1^2 + 1^2).
+	if snr := j.Data[bigButNoisy].SNR; math.Abs(snr-0.89443) > 0.00001 {
+		t.Errorf("SNR computed as %v, but expected 0.89443", snr)
+	}
+	if snr := j.Data[smallButStable].SNR; math.Abs(snr-7.07107) > 0.00001 {
+		t.Errorf("SNR computed as %v, but expected 7.07107", snr)
+	}
+	if snr := j.Data[noiseFree].SNR; !math.IsInf(snr, 1) {
+		t.Errorf("SNR computed as %v for a noise-free change, but expected +Inf", snr)
+	}
+	if snr := j.Data[unchanged].SNR; !math.IsNaN(snr) {
+		t.Errorf("SNR computed as %v for a noise-free metric without change, but expected NaN", snr)
+	}
+
+	// The improvement, despite its SNR, comes last.
+	expected := []MetricKey{noiseFree, smallButStable, bigButNoisy, improvement}
+	if top := j.TopRegressions(10, RankBySNR); !reflect.DeepEqual(top, expected) {
+		t.Errorf("Top regressions by SNR are %v, but expected %v", top, expected)
+	}
+
+	// For rates, going up is the improvement and going down the regression.
+	j.Data[improvement].IsRate, j.Data[smallButStable].IsRate = true, true
+	expected = []MetricKey{noiseFree, improvement, bigButNoisy, smallButStable}
+	if top := j.TopRegressions(10, RankBySNR); !reflect.DeepEqual(top, expected) {
+		t.Errorf("Top regressions by SNR with rates are %v, but expected %v", top, expected)
+	}
+}
+
+func TestSeverityBreakdown(t *testing.T) {
+	newMetric := func(avgL, avgR float64, matched bool) *MetricComparisonData {
+		return &MetricComparisonData{LeftJobSample: []float64{avgL}, RightJobSample: []float64{avgR}, Matched: matched}
+	}
+	j := &JobComparisonData{
+		Data: map[MetricKey]*MetricComparisonData{
+			{TestName: "xyz", Verb: "GET", Percentile: "Perc50"}:    newMetric(100, 110, false), // +10%
+			{TestName: "xyz", Verb: "GET", Percentile: "Perc90"}:    newMetric(100, 130, false), // +30%
+			{TestName: "xyz", Verb: "GET", Percentile: "Perc99"}:    newMetric(100, 250, false), // +150%
+			{TestName: "xyz", Verb: "LIST", Percentile: "Perc50"}:   newMetric(100, 50, false),  // -50%
+			{TestName: "xyz", Verb: "LIST", Percentile: "Perc90"}:   newMetric(100, 300, true),
+			{TestName: "xyz", Verb: "LIST", Percentile: "Perc99"}:   newMetric(0, 10, false), // can't be computed
+			{TestName: "xyz", Verb: "DELETE", Percentile: "Perc99"}: newMetric(100, 300, false),
+		},
+	}
+	j.Data[MetricKey{TestName: "xyz", Verb: "DELETE", Percentile: "Perc99"}].MarkTimedOut(context.DeadlineExceeded)
+	j.ComputeStatsForMetricSamples()
+
+	testCases := []struct {
+		thresholds []float64
+		expected   map[string]int
+	}{
+		{[]float64{0.25, 1}, map[string]int{SeverityMinor: 1, SeverityMajor: 2, SeverityCritical: 2}},
+		{[]float64{0.5, 2}, map[string]int{SeverityMinor: 2, SeverityMajor: 2, SeverityCritical: 1}},
+		// Without thresholds, all the mismatches with a known change are minor.
+		{nil, map[string]int{SeverityMinor: 4, SeverityMajor: 0, SeverityCritical: 1}},
+	}
+	for _, testCase := range testCases {
+		if breakdown := j.SeverityBreakdown(testCase.thresholds); !reflect.DeepEqual(breakdown, testCase.expected) {
+			t.Errorf("Severity breakdown with thresholds %v is %v, but expected %v", testCase.thresholds, breakdown, testCase.expected)
+		}
+	}
+}
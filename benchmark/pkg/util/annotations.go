@@ -0,0 +1,151 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+// Tier is the importance of a metric, with lower tiers being more important.
+type Tier int
+
+// Importance tiers a metric can be annotated with.
+const (
+	TierP0 Tier = iota // Default for metrics without annotation
+	TierP1
+	TierP2
+)
+
+func (t Tier) String() string {
+	return fmt.Sprintf("P%d", int(t))
+}
+
+// ParseTier parses a tier of the form "P0", "P1", etc.
+func ParseTier(s string) (Tier, error) {
+	if !strings.HasPrefix(s, "P") {
+		return 0, fmt.Errorf("bad tier '%v'", s)
+	}
+	level, err := strconv.Atoi(s[1:])
+	if err != nil || level < 0 || Tier(level) > TierP2 {
+		return 0, fmt.Errorf("bad tier '%v'", s)
+	}
+	return Tier(level), nil
+}
+
+// MarshalJSON encodes the tier as a string (like "P1").
+func (t Tier) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+// UnmarshalJSON decodes a tier from a string (like "P1").
+func (t *Tier) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	tier, err := ParseTier(s)
+	if err != nil {
+		return err
+	}
+	*t = tier
+	return nil
+}
+
+// MetricAnnotation carries metadata about the metrics matching a pattern.
+type MetricAnnotation struct {
+	Metric MetricPattern `json:"metric"`
+	Tier   Tier          `json:"tier"`
+	Owner  string        `json:"owner,omitempty"`
+	// Rate tells if the metric is a rate (e.g. a throughput), to be averaged harmonically.
+	Rate bool `json:"rate,omitempty"`
+	// Transform is the transform (like "log") to compare the metric with, see WithTransforms.
+	Transform Transform `json:"transform,omitempty"`
+}
+
+// Annotations is a sidecar to the compared jobs' metrics, annotating them with metadata
+// that is not part of the metrics themselves. A metric gets the annotation whose pattern
+// matches it most specifically, with ties going to the earliest such annotation.
+type Annotations struct {
+	Annotations []MetricAnnotation `json:"annotations"`
+}
+
+// LoadAnnotations reads the annotations from the JSON file at the given path.
+func LoadAnnotations(filePath string) (*Annotations, error) {
+	contents, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read annotations file: %v", err)
+	}
+	annotations := &Annotations{}
+	if err := Unmarshaler(contents, annotations); err != nil {
+		return nil, fmt.Errorf("couldn't parse annotations file %v: %v", filePath, err)
+	}
+	return annotations, nil
+}
+
+// AnnotationFor returns the annotation of the given metric (nil if none).
+func (a *Annotations) AnnotationFor(key MetricKey) *MetricAnnotation {
+	var bestAnnotation *MetricAnnotation
+	for i := range a.Annotations {
+		annotation := &a.Annotations[i]
+		if !annotation.Metric.Matches(key) {
+			continue
+		}
+		if bestAnnotation == nil || annotation.Metric.specificity() > bestAnnotation.Metric.specificity() {
+			bestAnnotation = annotation
+		}
+	}
+	return bestAnnotation
+}
+
+// Annotate sets the tier, owner, whether it's a rate and the transform of each metric as per
+// the given annotations. Metrics without an annotation get tier P0, no owner, aren't rates and
+// are compared as they are.
+func (j *JobComparisonData) Annotate(a *Annotations) {
+	for metricKey, metricData := range j.Data {
+		wasRate := metricData.IsRate
+		metricData.Tier, metricData.Owner, metricData.IsRate, metricData.Transform = TierP0, "", false, TransformNone
+		if annotation := a.AnnotationFor(metricKey); annotation != nil {
+			metricData.Tier, metricData.Owner, metricData.IsRate, metricData.Transform = annotation.Tier, annotation.Owner, annotation.Rate, annotation.Transform
+		}
+		if metricData.IsRate != wasRate {
+			// The harmonic means are only computed for rates.
+			metricData.invalidateStats()
+		}
+	}
+}
+
+// AllMatched tells if all the metrics of tier minTier or a more important one matched.
+// Mismatches of less important tiers are only informational and don't count here.
+func (j *JobComparisonData) AllMatched(minTier Tier) bool {
+	for _, metricData := range j.Data {
+		if metricData.Tier <= minTier && !metricData.Matched {
+			return false
+		}
+	}
+	return true
+}
+
+// LessImportantThan returns a filter leaving out the metrics of tiers less important than the given one.
+func LessImportantThan(tier Tier) MetricFilterFunc {
+	return func(k MetricKey, d MetricComparisonData) bool {
+		return d.Tier > tier
+	}
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAnnotationTiers(t *testing.T) {
+	dir, err := ioutil.TempDir("", "annotations")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	annotationsPath := filepath.Join(dir, "annotations.json")
+	annotationsContents := `{
+		"annotations": [
+			{"metric": {"verb": "LIST"}, "tier": "P1", "owner": "sig-api-machinery"},
+			{"metric": {"verb": "LIST", "percentile": "Perc50"}, "tier": "P2"}
+		]
+	}`
+	if err := ioutil.WriteFile(annotationsPath, []byte(annotationsContents), 0644); err != nil {
+		t.Fatal(err)
+	}
+	annotations, err := LoadAnnotations(annotationsPath)
+	if err != nil {
+		t.Fatalf("Loading annotations failed: %v", err)
+	}
+
+	listPods99 := MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}
+	listPods50 := MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc50"}
+	getPods99 := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	j := &JobComparisonData{
+		Data: map[MetricKey]*MetricComparisonData{
+			listPods99: {Matched: true},
+			listPods50: {Matched: false, Comments: "regressed"},
+			getPods99:  {Matched: true},
+		},
+	}
+	j.Annotate(annotations)
+	if j.Data[listPods99].Tier != TierP1 || j.Data[listPods99].Owner != "sig-api-machinery" {
+		t.Errorf("Wrong annotation for %v: %+v", listPods99, j.Data[listPods99])
+	}
+	if j.Data[listPods50].Tier != TierP2 || j.Data[getPods99].Tier != TierP0 {
+		t.Errorf("Wrong tiers: %v for %v and %v for %v", j.Data[listPods50].Tier, listPods50, j.Data[getPods99].Tier, getPods99)
+	}
+
+	// A P2-only regression passes the gate enforcing P1, but is still reported as informational.
+	if !j.AllMatched(TierP1) {
+		t.Errorf("P2-only regression failed the gate enforcing tier P1")
+	}
+	if j.AllMatched(TierP2) {
+		t.Errorf("P2 regression passed the gate enforcing tier P2")
+	}
+	enforcedTier := TierP1
+	table := j.formatTable(PrettyPrintOptions{EnforcedTier: &enforcedTier})
+	if !strings.Contains(table, informationalMarker+"regressed") {
+		t.Errorf("P2 regression not marked as informational in table:\n%v", table)
+	}
+
+	// A P1 regression fails it.
+	j.Data[listPods99].Matched = false
+	if j.AllMatched(TierP1) {
+		t.Errorf("P1 regression passed the gate enforcing tier P1")
+	}
+
+	filter := LessImportantThan(TierP1)
+	if !filter(listPods50, *j.Data[listPods50]) || filter(listPods99, *j.Data[listPods99]) {
+		t.Errorf("LessImportantThan(P1) filtered out wrong metrics")
+	}
+}
+
+func TestRateAnnotation(t *testing.T) {
+	annotations := &Annotations{Annotations: []MetricAnnotation{{Metric: MetricPattern{TestName: "Throughput"}, Rate: true}}}
+	throughput := MetricKey{TestName: "Throughput", Verb: "POST", Resource: "pods", Percentile: "Perc50"}
+	latency := MetricKey{TestName: "Load", Verb: "POST", Resource: "pods", Percentile: "Perc50"}
+	j := &JobComparisonData{
+		Data: map[MetricKey]*MetricComparisonData{
+			throughput: {LeftJobSample: []float64{60, 40}, RightJobSample: []float64{0}},
+			latency:    {LeftJobSample: []float64{60, 40}, RightJobSample: []float64{50}},
+		},
+	}
+	j.Annotate(annotations)
+	j.ComputeStatsForMetricSamples()
+	if data := j.Data[throughput]; !data.IsRate || math.Abs(data.HarmonicMeanL-48) > 1e-9 || !math.IsNaN(data.HarmonicMeanR) {
+		t.Errorf("Rate's harmonic means computed as %v and %v, but expected 48 and NaN", data.HarmonicMeanL, data.HarmonicMeanR)
+	}
+	if data := j.Data[latency]; data.IsRate || data.HarmonicMeanL != 0 {
+		t.Errorf("Harmonic mean computed for a metric that isn't a rate: %+v", data)
+	}
+
+	var buf bytes.Buffer
+	if err := j.WriteJSON(&buf, false); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"harmonicMeanR": null`) || strings.Count(buf.String(), `"rate": true`) != 1 || strings.Count(buf.String(), "harmonicMeanL") != 1 {
+		t.Errorf("JSON output doesn't have the harmonic means of just the rate:\n%v", buf.String())
+	}
+}
+
+func TestParseTier(t *testing.T) {
+	for _, s := range []string{"P0", "P1", "P2"} {
+		tier, err := ParseTier(s)
+		if err != nil || tier.String() != s {
+			t.Errorf("ParseTier(%v) = %v, %v", s, tier, err)
+		}
+	}
+	for _, s := range []string{"", "P", "P3", "P-1", "1"} {
+		if _, err := ParseTier(s); err == nil {
+			t.Errorf("ParseTier(%v) succeeded, but expected an error", s)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"math"
+)
+
+// AnomalyScores scores each metric's right job avg (e.g. that of a new run) against the
+// metric's own history in the series, as the no. of historical std-devs it sits from the
+// historical mean, i.e. (avg - mean) / std-dev of the series' values, positive if it's higher.
+// Unlike comparing two jobs, it needs no paired baseline job, only a history of the metric.
+// Metrics without a series or right job samples are left out, while the score is NaN if the
+// series has less than 2 values, NaN values, or no spread at all (a std-dev of 0).
+func (ts *TimeSeries) AnomalyScores(j *JobComparisonData) map[MetricKey]float64 {
+	scores := make(map[MetricKey]float64)
+	for metricKey, metricData := range j.Data {
+		points := ts.Series[metricKey]
+		if len(points) == 0 || len(metricData.RightJobSample) == 0 {
+			continue
+		}
+		values := make([]float64, len(points))
+		for i, point := range points {
+			values[i] = point.Value
+		}
+		scores[metricKey], _ = SafeDiv(Mean(metricData.RightJobSample)-Mean(values), math.Sqrt(SampleVariance(values)))
+	}
+	return scores
+}
+
+// FlagAnomalies flags the metrics whose anomaly score (see AnomalyScores) is above the threshold
+// (e.g. 3 std-devs) as mismatches, being regressions for their history, and returns their keys
+// (sorted). For rates, it's the scores below the negated threshold that are anomalous, as
+// their regressions go down. The rest of the metrics with a score are matched, except those
+// with a NaN score, marked inconclusive, while those without a score (e.g. without history)
+// are matched. The metrics' comments give their scores.
+func (ts *TimeSeries) FlagAnomalies(j *JobComparisonData, threshold float64) []MetricKey {
+	scores := ts.AnomalyScores(j)
+	var anomalies []MetricKey
+	for _, metricPair := range getMetricsSortedByKey(j) {
+		key, data := metricPair.metricKey, metricPair.metricData
+		data.ResetVerdict()
+		score, ok := scores[key]
+		if !ok {
+			data.Matched = true
+			continue
+		}
+		comments := fmt.Sprintf("AnomalyScore=%.2f\tAvgR(ms)=%.2f\tN=%v", score, Mean(data.RightJobSample), len(ts.Series[key]))
+		if math.IsNaN(score) {
+			data.MarkInconclusive("Cannot score against the history\t" + comments)
+			continue
+		}
+		regression := score
+		if data.IsRate {
+			regression = -score
+		}
+		data.Matched = regression <= threshold
+		data.Comments = comments
+		if !data.Matched {
+			anomalies = append(anomalies, key)
+		}
+	}
+	return anomalies
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAnomalyScores(t *testing.T) {
+	anomalous := MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}
+	usual := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	rate := MetricKey{TestName: "Throughput", Verb: "POST", Resource: "pods", Percentile: "Perc50"}
+	flat := MetricKey{TestName: "Load", Verb: "PUT", Resource: "pods", Percentile: "Perc99"}
+	newMetric := MetricKey{TestName: "Load", Verb: "DELETE", Resource: "pods", Percentile: "Perc99"}
+	ts := NewTimeSeries()
+	// A history with a mean of 100 and a std-dev of 2 (90 and 110 for the rate).
+	for _, value := range []float64{98, 102, 98, 102, 100} {
+		ts.Series[anomalous] = append(ts.Series[anomalous], TimeSeriesPoint{Value: value})
+		ts.Series[usual] = append(ts.Series[usual], TimeSeriesPoint{Value: value})
+		ts.Series[rate] = append(ts.Series[rate], TimeSeriesPoint{Value: value})
+		ts.Series[flat] = append(ts.Series[flat], TimeSeriesPoint{Value: 100})
+	}
+	j := NewJobComparisonData()
+	j.Data[anomalous] = &MetricComparisonData{RightJobSample: []float64{150}}
+	j.Data[usual] = &MetricComparisonData{RightJobSample: []float64{101, 103}}
+	j.Data[rate] = &MetricComparisonData{RightJobSample: []float64{90}, IsRate: true}
+	j.Data[flat] = &MetricComparisonData{RightJobSample: []float64{101}}
+	j.Data[newMetric] = &MetricComparisonData{RightJobSample: []float64{500}}
+
+	scores := ts.AnomalyScores(j)
+	if len(scores) != 4 {
+		t.Errorf("Scored metrics %v, but expected those with a history", scores)
+	}
+	if score := scores[anomalous]; math.Abs(score-25) > 1e-9 {
+		t.Errorf("Anomalous metric scored %v, but expected 25", score)
+	}
+	if score := scores[usual]; math.Abs(score-1) > 1e-9 {
+		t.Errorf("Usual metric scored %v, but expected 1", score)
+	}
+	if score := scores[flat]; !math.IsNaN(score) {
+		t.Errorf("Metric without spread in its history scored %v, but expected NaN", score)
+	}
+
+	// The dropped rate is anomalous too.
+	if anomalies := ts.FlagAnomalies(j, 3); !reflect.DeepEqual(anomalies, []MetricKey{anomalous, rate}) {
+		t.Errorf("Flagged anomalies %v, but expected %v", anomalies, []MetricKey{anomalous, rate})
+	}
+	if data := j.Data[anomalous]; data.Matched || !strings.Contains(data.Comments, "AnomalyScore=25.00") {
+		t.Errorf("Anomalous metric compared as %+v", data)
+	}
+	if !j.Data[usual].Matched || !j.Data[newMetric].Matched || !j.Data[flat].Inconclusive {
+		t.Errorf("Usual, new and flat metrics compared as %+v, %+v and %+v", j.Data[usual], j.Data[newMetric], j.Data[flat])
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// DefaultBadgeLabel is the label of the badge written by ToBadgeJSON.
+const DefaultBadgeLabel = "perf"
+
+// BadgeOptions tunes the badge written by ToBadgeJSONWithOptions.
+type BadgeOptions struct {
+	// Label is the label of the badge, DefaultBadgeLabel if empty.
+	Label string
+}
+
+// Colors of the badge written by ToBadgeJSON.
+const (
+	BadgeColorPass         = "green"
+	BadgeColorFail         = "red"
+	BadgeColorInconclusive = "lightgrey"
+)
+
+// badge is the payload of a shields.io endpoint badge (see https://shields.io/endpoint).
+type badge struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+}
+
+// GeometricMeanRatio returns the geometric mean of the metrics' ratios of the right and left
+// averages (AvgR/AvgL), the overall factor by which the right job is slower: e.g. 1.08 for
+// 8% slower. Rates' ratios are inverted, so that above 1 is worse for every metric. The
+// metrics whose ratio isn't finite and positive (e.g. as their left avg is 0) are skipped,
+// it being NaN if there are none left. The stats should have been computed already.
+func (j *JobComparisonData) GeometricMeanRatio() float64 {
+	count, logSum := 0, 0.0
+	for _, metricData := range j.Data {
+		ratio, ok := SafeDiv(metricData.AvgR, metricData.AvgL)
+		if metricData.IsRate {
+			ratio, ok = SafeDiv(metricData.AvgL, metricData.AvgR)
+		}
+		if !ok || ratio <= 0 {
+			continue
+		}
+		count++
+		logSum += math.Log(ratio)
+	}
+	if count == 0 {
+		return math.NaN()
+	}
+	return math.Exp(logSum / float64(count))
+}
+
+// OverallSpeedup returns the geometric mean of the metrics' ratios of the left and right
+// averages (AvgL/AvgR), the overall factor by which the right job is faster: e.g. 1.25 for
+// 25% faster, or 0.8 for 25% slower. It's the headline inverse of GeometricMeanRatio, so the
+// rates' ratios are inverted too (a higher rate being a speedup), and the metrics whose ratio
+// isn't finite and positive (e.g. as either avg is NaN or 0) are skipped, it being NaN if there
+// are none left. The geometric mean (unlike the arithmetic one) weighs a 2x speedup and a 2x
+// slowdown the same, canceling each other out. The stats should have been computed already.
+func (j *JobComparisonData) OverallSpeedup() float64 {
+	return 1 / j.GeometricMeanRatio()
+}
+
+// ToBadgeJSON returns a shields.io endpoint badge summarizing the comparison, like
+// "perf: +8.0% (FAIL)": the overall change (as per GeometricMeanRatio), and whether any metric
+// regressed. It's red on regressions, green if there are none, and grey if none of the
+// metrics could be compared conclusively. The stats should have been computed already.
+func (j *JobComparisonData) ToBadgeJSON() ([]byte, error) {
+	return j.ToBadgeJSONWithOptions(BadgeOptions{})
+}
+
+// ToBadgeJSONWithOptions is like ToBadgeJSON, but tunes the badge as per the given options.
+func (j *JobComparisonData) ToBadgeJSONWithOptions(options BadgeOptions) ([]byte, error) {
+	label := options.Label
+	if label == "" {
+		label = DefaultBadgeLabel
+	}
+	verdict, color := "PASS", BadgeColorPass
+	conclusive := false
+	for _, metricData := range j.Data {
+		conclusive = conclusive || !metricData.Inconclusive
+	}
+	switch {
+	case j.RegressedCount() > 0:
+		verdict, color = "FAIL", BadgeColorFail
+	case !conclusive:
+		verdict, color = "INCONCLUSIVE", BadgeColorInconclusive
+	}
+	message := fmt.Sprintf("%v (%v)", formatPercentChange(j.GeometricMeanRatio()-1), verdict)
+	return json.Marshal(badge{SchemaVersion: 1, Label: label, Message: message, Color: color})
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"math"
+	"testing"
+)
+
+func TestToBadgeJSON(t *testing.T) {
+	getKey := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	listKey := MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}
+	throughputKey := MetricKey{TestName: "Throughput", Verb: "POST", Resource: "pods", Percentile: "Perc50"}
+	testCases := []struct {
+		name         string
+		data         map[MetricKey]*MetricComparisonData
+		inconclusive bool
+		expected     string
+	}{
+		{
+			name: "matched",
+			data: map[MetricKey]*MetricComparisonData{
+				getKey:  {LeftJobSample: []float64{100}, RightJobSample: []float64{104}, Matched: true},
+				listKey: {LeftJobSample: []float64{100}, RightJobSample: []float64{100}, Matched: true},
+			},
+			expected: `{"schemaVersion":1,"label":"perf","message":"+2.0% (PASS)","color":"green"}`,
+		},
+		{
+			// The overall change is that of the geometric mean: sqrt(1.44 * 1) - 1.
+			name: "regressed",
+			data: map[MetricKey]*MetricComparisonData{
+				getKey:  {LeftJobSample: []float64{100}, RightJobSample: []float64{144}},
+				listKey: {LeftJobSample: []float64{100}, RightJobSample: []float64{100}, Matched: true},
+			},
+			expected: `{"schemaVersion":1,"label":"perf","message":"+20.0% (FAIL)","color":"red"}`,
+		},
+		{
+			// A rate going up is an improvement, rather than a regression.
+			name: "improved rate",
+			data: map[MetricKey]*MetricComparisonData{
+				throughputKey: {LeftJobSample: []float64{100}, RightJobSample: []float64{125}, IsRate: true},
+			},
+			expected: `{"schemaVersion":1,"label":"perf","message":"-20.0% (PASS)","color":"green"}`,
+		},
+		{
+			name: "inconclusive",
+			data: map[MetricKey]*MetricComparisonData{
+				getKey: {LeftJobSample: []float64{100}},
+			},
+			inconclusive: true,
+			expected:     `{"schemaVersion":1,"label":"perf","message":"? (INCONCLUSIVE)","color":"lightgrey"}`,
+		},
+	}
+	for _, testCase := range testCases {
+		j := &JobComparisonData{Data: testCase.data}
+		j.ComputeStatsForMetricSamples()
+		if testCase.inconclusive {
+			for _, metricData := range j.Data {
+				metricData.MarkInconclusive("insufficient data")
+			}
+		}
+		badge, err := j.ToBadgeJSON()
+		if err != nil {
+			t.Fatalf("%v: ToBadgeJSON failed: %v", testCase.name, err)
+		}
+		if string(badge) != testCase.expected {
+			t.Errorf("%v: badge is %s, but expected %s", testCase.name, badge, testCase.expected)
+		}
+	}
+
+	j := &JobComparisonData{Data: map[MetricKey]*MetricComparisonData{getKey: {LeftJobSample: []float64{100}, RightJobSample: []float64{100}, Matched: true}}}
+	j.ComputeStatsForMetricSamples()
+	if badge, err := j.ToBadgeJSONWithOptions(BadgeOptions{Label: "scalability"}); err != nil || string(badge) != `{"schemaVersion":1,"label":"scalability","message":"+0.0% (PASS)","color":"green"}` {
+		t.Errorf("Badge with a custom label is %s (error %v)", badge, err)
+	}
+
+	if ratio := (&JobComparisonData{}).GeometricMeanRatio(); !math.IsNaN(ratio) {
+		t.Errorf("Geometric mean ratio without metrics is %v, but expected NaN", ratio)
+	}
+}
+
+func TestOverallSpeedup(t *testing.T) {
+	j := &JobComparisonData{Data: map[MetricKey]*MetricComparisonData{
+		{Verb: "GET"}:  {LeftJobSample: []float64{200}, RightJobSample: []float64{100}},
+		{Verb: "LIST"}: {LeftJobSample: []float64{100}, RightJobSample: []float64{200}},
+		// The rate doubled, which is a 2x speedup.
+		{Verb: "POD STARTUP"}: {LeftJobSample: []float64{10}, RightJobSample: []float64{20}, IsRate: true},
+		// These aren't comparable.
+		{Verb: "PUT"}:    {LeftJobSample: []float64{0}, RightJobSample: []float64{100}},
+		{Verb: "DELETE"}: {LeftJobSample: []float64{100}, RightJobSample: []float64{0}},
+		{Verb: "PATCH"}:  {LeftJobSample: []float64{100}},
+	}}
+	j.ComputeStatsForMetricSamples()
+	// The speedup and slowdown of the latencies cancel each other out, leaving the rate's.
+	if speedup, expected := j.OverallSpeedup(), math.Cbrt(2); math.Abs(speedup-expected) > 1e-9 {
+		t.Errorf("Overall speedup is %v, but expected %v", speedup, expected)
+	}
+	if speedup := (&JobComparisonData{}).OverallSpeedup(); !math.IsNaN(speedup) {
+		t.Errorf("Overall speedup without metrics is %v, but expected NaN", speedup)
+	}
+}
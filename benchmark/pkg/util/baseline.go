@@ -0,0 +1,103 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"math"
+
+	"k8s.io/kubernetes/test/e2e/perftype"
+)
+
+// Baseline retains the values of each metric from the most recent runs of a baseline job,
+// in the order the runs were added (oldest first), up to a capacity number of runs.
+type Baseline struct {
+	Capacity int
+	Values   map[MetricKey][]float64
+}
+
+// NewBaseline is a constructor for Baseline struct.
+func NewBaseline(capacity int) *Baseline {
+	return &Baseline{
+		Capacity: capacity,
+		Values:   make(map[MetricKey][]float64),
+	}
+}
+
+// AddRun records the values of the metrics from a baseline run, newer than the runs added
+// so far. The values of a metric from runs beyond the capacity are evicted, oldest first.
+func (b *Baseline) AddRun(values map[MetricKey]float64) {
+	for metricKey, value := range values {
+		metricValues := append(b.Values[metricKey], value)
+		if b.Capacity > 0 && len(metricValues) > b.Capacity {
+			metricValues = metricValues[len(metricValues)-b.Capacity:]
+		}
+		b.Values[metricKey] = metricValues
+	}
+}
+
+// AddPerfDataRun flattens the metrics of a baseline run and records them using AddRun.
+// A metric with several values within the run is recorded with their avg.
+func (b *Baseline) AddPerfDataRun(runMetrics map[string][]perftype.PerfData, options FlattenOptions) {
+	j := GetFlattennedComparisonDataWithOptions([]map[string][]perftype.PerfData{runMetrics}, nil, options)
+	values := make(map[MetricKey]float64, len(j.Data))
+	for metricKey, metricData := range j.Data {
+		if len(metricData.LeftJobSample) > 0 {
+			values[metricKey] = Mean(metricData.LeftJobSample)
+		}
+	}
+	b.AddRun(values)
+}
+
+// MovingMedian returns the median of the metric's values from the last window runs it was
+// recorded in (or all of them, if window isn't positive). It's NaN if it has no values.
+func (b *Baseline) MovingMedian(metricKey MetricKey, window int) float64 {
+	metricValues := b.Values[metricKey]
+	if window > 0 && len(metricValues) > window {
+		metricValues = metricValues[len(metricValues)-window:]
+	}
+	return percentileOf(metricValues, 0.5)
+}
+
+// CompareAgainstMovingMedian compares each metric's right job avg against the moving median
+// of its last window values in the Baseline (see MovingMedian), which unlike an avg isn't
+// skewed by an occasional bad baseline run. The metric is flagged as a mismatch if the ratio
+// of the right avg to the moving median exceeds maxRatio. Metrics without baseline values or
+// right job samples are treated as matched, while those for which the ratio can't be computed
+// otherwise (e.g. due to a zero moving median) are marked inconclusive.
+func (j *JobComparisonData) CompareAgainstMovingMedian(window int, maxRatio float64) {
+	for metricKey, metricData := range j.Data {
+		median := math.NaN()
+		if j.Baseline != nil {
+			median = j.Baseline.MovingMedian(metricKey, window)
+		}
+		avgR := Mean(metricData.RightJobSample)
+		ratio, ok := SafeDiv(avgR, median)
+		comments := fmt.Sprintf("AvgR/MovingMedian=%.2f\tMovingMedian(ms)=%.2f\tAvgR(ms)=%.2f\tN2=%v", ratio, median, avgR, len(metricData.RightJobSample))
+		metricData.ResetVerdict()
+		switch {
+		case math.IsNaN(median) || len(metricData.RightJobSample) == 0:
+			metricData.Matched = true
+		case !ok:
+			metricData.MarkInconclusive(CannotComputeRatio + "\t" + comments)
+			continue
+		default:
+			metricData.Matched = ratio <= maxRatio
+		}
+		metricData.Comments = comments
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/kubernetes/test/e2e/perftype"
+)
+
+func TestBaselineEviction(t *testing.T) {
+	metricKey := MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}
+	b := NewBaseline(3)
+	for _, value := range []float64{1, 2, 3, 4, 5} {
+		b.AddRun(map[MetricKey]float64{metricKey: value})
+	}
+	if !reflect.DeepEqual(b.Values[metricKey], []float64{3, 4, 5}) {
+		t.Errorf("Baseline retained %v, but expected the last 3 values", b.Values[metricKey])
+	}
+	if median := b.MovingMedian(metricKey, 2); median != 4.5 {
+		t.Errorf("Moving median over last 2 runs computed as %v, but expected 4.5", median)
+	}
+	if median := b.MovingMedian(metricKey, 0); median != 4 {
+		t.Errorf("Moving median over all runs computed as %v, but expected 4", median)
+	}
+	if median := b.MovingMedian(MetricKey{}, 2); !math.IsNaN(median) {
+		t.Errorf("Moving median of unknown metric computed as %v, but expected NaN", median)
+	}
+}
+
+func TestCompareAgainstMovingMedian(t *testing.T) {
+	runMetrics := func(latency float64) map[string][]perftype.PerfData {
+		return map[string][]perftype.PerfData{
+			"Load": {
+				{
+					Version: "v1",
+					DataItems: []perftype.DataItem{
+						{
+							Data:   map[string]float64{"Perc99": latency},
+							Unit:   "ms",
+							Labels: map[string]string{"Count": "10", "Resource": "pods", "Verb": "LIST"},
+						},
+					},
+				},
+			},
+		}
+	}
+	metricKey := MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}
+	unknownKey := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	baseline := NewBaseline(10)
+	// One bad historical run, which would push the avg of the last 5 runs to 280
+	// and hide the regression below.
+	for _, latency := range []float64{100, 100, 1000, 100, 100, 100} {
+		baseline.AddPerfDataRun(runMetrics(latency), FlattenOptions{})
+	}
+
+	j := &JobComparisonData{
+		Data: map[MetricKey]*MetricComparisonData{
+			metricKey:  {RightJobSample: []float64{150}},
+			unknownKey: {RightJobSample: []float64{150}},
+		},
+		Baseline: baseline,
+	}
+	j.CompareAgainstMovingMedian(5, 1.2)
+	if j.Data[metricKey].Matched {
+		t.Errorf("Right avg 1.5x the moving median matched: %v", j.Data[metricKey].Comments)
+	}
+	if !j.Data[unknownKey].Matched {
+		t.Errorf("Metric without baseline values mismatched: %v", j.Data[unknownKey].Comments)
+	}
+
+	j.Data[metricKey].RightJobSample = []float64{110}
+	j.CompareAgainstMovingMedian(5, 1.2)
+	if !j.Data[metricKey].Matched {
+		t.Errorf("Right avg 1.1x the moving median mismatched: %v", j.Data[metricKey].Comments)
+	}
+
+	// A zero moving median can't be compared against.
+	for i := 0; i < 5; i++ {
+		baseline.AddPerfDataRun(runMetrics(0), FlattenOptions{})
+	}
+	j.CompareAgainstMovingMedian(5, 1.2)
+	if !j.Data[metricKey].Inconclusive || !strings.HasPrefix(j.Data[metricKey].Comments, CannotComputeRatio) {
+		t.Errorf("Metric with a zero moving median not marked inconclusive: %v", j.Data[metricKey].Comments)
+	}
+}
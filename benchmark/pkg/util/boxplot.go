@@ -0,0 +1,79 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"text/tabwriter"
+)
+
+// FiveNumbers is the five-number summary of a sample, i.e. what its box plot is drawn from: the
+// box spans the quartiles, split at the median, and the whiskers reach out to the extremes.
+type FiveNumbers struct {
+	Min    float64 `json:"min"`
+	Q1     float64 `json:"q1"`
+	Median float64 `json:"median"`
+	Q3     float64 `json:"q3"`
+	Max    float64 `json:"max"`
+}
+
+// FiveNumberSummary returns the five-number summary of the sample (all NaN if it's empty), with
+// the quartiles interpolated like ComputePercentile does. Unlike the avg and std-dev, it shows
+// the skew of latencies, e.g. a long upper whisker of a few slow runs.
+func FiveNumberSummary(sample []float64) FiveNumbers {
+	if len(sample) == 0 {
+		return FiveNumbers{math.NaN(), math.NaN(), math.NaN(), math.NaN(), math.NaN()}
+	}
+	sorted := sortedCopy(sample)
+	return FiveNumbers{
+		Min:    sorted[0],
+		Q1:     percentileOfSorted(sorted, 0.25),
+		Median: percentileOfSorted(sorted, 0.5),
+		Q3:     percentileOfSorted(sorted, 0.75),
+		Max:    sorted[len(sorted)-1],
+	}
+}
+
+// IQR returns the interquartile range, i.e. the length of the box.
+func (s FiveNumbers) IQR() float64 {
+	return s.Q3 - s.Q1
+}
+
+// String returns the five numbers as "min/q1/median/q3/max" (with "-" for NaNs).
+func (s FiveNumbers) String() string {
+	return fmt.Sprintf("%v/%v/%v/%v/%v", formatRatio(s.Min), formatRatio(s.Q1), formatRatio(s.Median), formatRatio(s.Q3), formatRatio(s.Max))
+}
+
+// FiveNumberSummaries returns the five-number summaries of the metric's left and right samples.
+func (d *MetricComparisonData) FiveNumberSummaries() (left, right FiveNumbers) {
+	return FiveNumberSummary(d.LeftJobSample), FiveNumberSummary(d.RightJobSample)
+}
+
+// WriteBoxPlots writes the five-number summaries of the metrics' left and right samples to w,
+// as a table with columns aligned, sorted by metric key.
+func (j *JobComparisonData) WriteBoxPlots(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "E2E TEST\tVERB\tRESOURCE\tSUBRESOURCE\tSCOPE\tPERCENTILE\tLEFT MIN/Q1/MEDIAN/Q3/MAX\tRIGHT MIN/Q1/MEDIAN/Q3/MAX\n")
+	for _, metricPair := range getMetricsSortedByKey(j) {
+		key := metricPair.metricKey
+		left, right := metricPair.metricData.FiveNumberSummaries()
+		fmt.Fprintf(tw, "%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\n", key.TestName, key.Verb, key.Resource, key.Subresource, key.Scope, key.Percentile, left, right)
+	}
+	return tw.Flush()
+}
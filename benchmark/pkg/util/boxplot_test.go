@@ -0,0 +1,66 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestFiveNumberSummary(t *testing.T) {
+	summary := FiveNumberSummary([]float64{9, 1, 5, 3, 7, 100})
+	expected := FiveNumbers{Min: 1, Q1: 3.5, Median: 6, Q3: 8.5, Max: 100}
+	if summary != expected {
+		t.Errorf("Five-number summary is %+v, but expected %+v", summary, expected)
+	}
+	if summary.IQR() != 5 {
+		t.Errorf("IQR is %v, but expected 5", summary.IQR())
+	}
+	if summary.String() != "1.00/3.50/6.00/8.50/100.00" {
+		t.Errorf("Five-number summary formatted as %v", summary)
+	}
+	if empty := FiveNumberSummary(nil); !math.IsNaN(empty.Min) || !math.IsNaN(empty.Max) || empty.String() != "-/-/-/-/-" {
+		t.Errorf("Five-number summary of an empty sample is %+v, but expected NaNs", empty)
+	}
+}
+
+func TestWriteBoxPlots(t *testing.T) {
+	j := NewJobComparisonData()
+	j.Data[MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}] = &MetricComparisonData{
+		LeftJobSample:  []float64{10, 20, 30},
+		RightJobSample: []float64{10, 20, 90},
+	}
+	j.Data[MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}] = &MetricComparisonData{
+		LeftJobSample: []float64{5},
+	}
+	var buf bytes.Buffer
+	if err := j.WriteWithOptions(&buf, BoxPlotFormat, WriteOptions{}); err != nil {
+		t.Fatalf("Writing box plots failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Box plots written as %v lines, but expected 3:\n%v", len(lines), buf.String())
+	}
+	if fields := strings.Fields(lines[1]); fields[1] != "GET" || fields[len(fields)-2] != "5.00/5.00/5.00/5.00/5.00" || fields[len(fields)-1] != "-/-/-/-/-" {
+		t.Errorf("GET written as %v", lines[1])
+	}
+	if fields := strings.Fields(lines[2]); fields[1] != "LIST" || fields[len(fields)-2] != "10.00/15.00/20.00/25.00/30.00" || fields[len(fields)-1] != "10.00/15.00/20.00/55.00/90.00" {
+		t.Errorf("LIST written as %v", lines[2])
+	}
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"time"
+)
+
+// Clone returns a deep copy of the job comparison data: the metrics map, each metric's data, and
+// their samples (along with the request counts, timestamps, run indices, node maxes and labels) are all
+// copied, so that the copy can be mutated (e.g. compared, or its samples transformed) without
+// touching the original. The Baseline isn't copied, as it's only read.
+func (j *JobComparisonData) Clone() *JobComparisonData {
+	cloned := *j
+	cloned.Data = make(map[MetricKey]*MetricComparisonData, len(j.Data))
+	for metricKey, metricData := range j.Data {
+		cloned.Data[metricKey] = metricData.clone()
+	}
+	cloned.dropLog = append([]DropRecord(nil), j.dropLog...)
+	cloned.warnings = append([]Warning(nil), j.warnings...)
+	cloned.collisions = collisionTracker{err: j.collisions.err}
+	cloned.nodeMaxes = nodeMaxTracker{}
+	if j.droppedNegatives != nil {
+		cloned.droppedNegatives = make(map[MetricKey]int, len(j.droppedNegatives))
+		for metricKey, count := range j.droppedNegatives {
+			cloned.droppedNegatives[metricKey] = count
+		}
+	}
+	return &cloned
+}
+
+// clone returns a deep copy of the metric's comparison data.
+func (d *MetricComparisonData) clone() *MetricComparisonData {
+	cloned := *d
+	cloned.LeftJobSample, cloned.RightJobSample = copyFloats(d.LeftJobSample), copyFloats(d.RightJobSample)
+	cloned.LeftJobRequestCounts, cloned.RightJobRequestCounts = copyFloats(d.LeftJobRequestCounts), copyFloats(d.RightJobRequestCounts)
+	cloned.LeftJobTimestamps, cloned.RightJobTimestamps = copyTimes(d.LeftJobTimestamps), copyTimes(d.RightJobTimestamps)
+	cloned.LeftJobRunIndices, cloned.RightJobRunIndices = copyInts(d.LeftJobRunIndices), copyInts(d.RightJobRunIndices)
+	cloned.LeftJobNodeMaxes, cloned.RightJobNodeMaxes = copyFloats(d.LeftJobNodeMaxes), copyFloats(d.RightJobNodeMaxes)
+	if d.Labels != nil {
+		cloned.Labels = copyLabels(d.Labels)
+	}
+	return &cloned
+}
+
+// copyFloats, copyTimes and copyInts copy the slices, keeping nil ones nil.
+func copyFloats(values []float64) []float64 {
+	if values == nil {
+		return nil
+	}
+	return append(make([]float64, 0, len(values)), values...)
+}
+
+func copyTimes(values []time.Time) []time.Time {
+	if values == nil {
+		return nil
+	}
+	return append(make([]time.Time, 0, len(values)), values...)
+}
+
+func copyInts(values []int) []int {
+	if values == nil {
+		return nil
+	}
+	return append(make([]int, 0, len(values)), values...)
+}
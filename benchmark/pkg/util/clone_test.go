@@ -0,0 +1,76 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestClone(t *testing.T) {
+	key := MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}
+	j := NewJobComparisonData()
+	j.Data[key] = &MetricComparisonData{
+		LeftJobSample:         []float64{100, 110},
+		RightJobSample:        []float64{120, 130},
+		LeftJobRequestCounts:  []float64{10, 20},
+		RightJobRequestCounts: []float64{30, 40},
+		LeftJobTimestamps:     []time.Time{time.Unix(1, 0), time.Unix(2, 0)},
+		RightJobTimestamps:    []time.Time{time.Unix(3, 0), time.Unix(4, 0)},
+		LeftJobRunIndices:     []int{0, 1},
+		RightJobRunIndices:    []int{0, 1},
+		Labels:                map[string]string{"Verb": "LIST"},
+		Comments:              "original",
+	}
+	j.ComputeStatsForMetricSamples()
+	j.dropLog = []DropRecord{{Metric: key, Reason: DropNaN}}
+	// The expected data is built the same way, rather than cloned, not to depend on Clone.
+	expected := NewJobComparisonData()
+	expected.Data[key] = &MetricComparisonData{}
+	*expected.Data[key] = *j.Data[key]
+	expected.Data[key].LeftJobSample, expected.Data[key].RightJobSample = []float64{100, 110}, []float64{120, 130}
+	expected.Data[key].LeftJobRequestCounts, expected.Data[key].RightJobRequestCounts = []float64{10, 20}, []float64{30, 40}
+	expected.Data[key].LeftJobTimestamps = []time.Time{time.Unix(1, 0), time.Unix(2, 0)}
+	expected.Data[key].RightJobTimestamps = []time.Time{time.Unix(3, 0), time.Unix(4, 0)}
+	expected.Data[key].LeftJobRunIndices, expected.Data[key].RightJobRunIndices = []int{0, 1}, []int{0, 1}
+	expected.Data[key].Labels = map[string]string{"Verb": "LIST"}
+	expected.dropLog = []DropRecord{{Metric: key, Reason: DropNaN}}
+	expected.statsWeighted, expected.statsShortfallFraction = j.statsWeighted, j.statsShortfallFraction
+
+	cloned := j.Clone()
+	if !reflect.DeepEqual(cloned, j) {
+		t.Fatalf("Clone differs from the original:\nClone: %+v\nOriginal: %+v", cloned.Data[key], j.Data[key])
+	}
+
+	// Mutate everything of the clone in place, and add and remove metrics.
+	clonedData := cloned.Data[key]
+	clonedData.LeftJobSample[0], clonedData.RightJobSample[1] = -1, -1
+	clonedData.LeftJobRequestCounts[0], clonedData.RightJobRequestCounts[0] = -1, -1
+	clonedData.LeftJobTimestamps[0], clonedData.RightJobTimestamps[0] = time.Time{}, time.Time{}
+	clonedData.LeftJobRunIndices[0], clonedData.RightJobRunIndices[0] = -1, -1
+	clonedData.Labels["Verb"] = "GET"
+	clonedData.Matched, clonedData.Comments = true, "mutated"
+	cloned.ComputeStatsForMetricSamples()
+	cloned.dropLog[0].Reason = DropNegative
+	cloned.Data[MetricKey{TestName: "Other"}] = &MetricComparisonData{}
+	delete(cloned.Data, key)
+
+	if !reflect.DeepEqual(j, expected) {
+		t.Errorf("Mutating the clone changed the original:\nReal: %+v\nExpected: %+v", j.Data[key], expected.Data[key])
+	}
+}
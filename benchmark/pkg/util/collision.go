@@ -0,0 +1,137 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+)
+
+// CollisionPolicy tells what to do when a metric gets more than one value within a single run
+// while flattening, e.g. as its test reported its DataItem twice, or legitimately measured it
+// in two phases. Values merged on purpose (see FlattenOptions.MergedSubresources), values of
+// different nodes (see FlattenOptions.NodeLabel) and values of sub-populations rolled up (see
+// FlattenOptions.KeepPopulationRollup) aren't collisions.
+//
+// By default, the values are all added to the sample, as if they were from separate runs.
+// That makes the run contribute several (correlated) values, biasing the stats towards it,
+// which the aggregating policies avoid by folding the run's values into a single one:
+//   - ReplaceOnCollision keeps the last of them, e.g. for a final phase superseding the others.
+//   - AverageOnCollision averages them, e.g. for same-sized phases measuring the same thing.
+//   - SumOnCollision sums them, e.g. for counts of events split across the phases.
+//
+// The averaging and summing policies sum the request counts backing the values (if retained),
+// as the single value stands for the requests of all the phases.
+type CollisionPolicy int
+
+// Allowed collision policies.
+const (
+	AppendOnCollision  CollisionPolicy = iota // Add the value to the sample, as if it was from another run
+	ReplaceOnCollision                        // Replace the run's earlier value with it (i.e. keep the last one)
+	ErrorOnCollision                          // Drop it, failing the flattening (see CollisionError)
+	AverageOnCollision                        // Replace the run's earlier value with the average of the run's values
+	SumOnCollision                            // Add it to the run's earlier value
+)
+
+// collisionTracker tracks the metrics having got a value in the run being flattened.
+type collisionTracker struct {
+	run         int
+	fromLeftJob bool
+	metricKeys  map[MetricKey]*runValues
+	err         error // First collision, with ErrorOnCollision
+}
+
+// runValues tells where in its sample a metric's value from the run is, and how many values
+// (aggregated into it) the metric got in the run.
+type runValues struct {
+	index, count int
+}
+
+// resolveCollision handles the value of the metric from the given run as per the options'
+// CollisionPolicy, telling if it's to be added to the sample. The policies aggregating the
+// run's values (including ReplaceOnCollision) fold it into the run's earlier one themselves.
+func (j *JobComparisonData) resolveCollision(sample float64, metricKey MetricKey, latency DataItemLike, run sampleRun, fromLeftJob bool, options *FlattenOptions) bool {
+	tracker := &j.collisions
+	if tracker.metricKeys == nil || tracker.run != run.index || tracker.fromLeftJob != fromLeftJob {
+		*tracker = collisionTracker{run: run.index, fromLeftJob: fromLeftJob, metricKeys: make(map[MetricKey]*runValues), err: tracker.err}
+	}
+	var values, requestCounts *[]float64
+	if metricData, ok := j.Data[metricKey]; ok {
+		values, requestCounts = &metricData.RightJobSample, &metricData.RightJobRequestCounts
+		if fromLeftJob {
+			values, requestCounts = &metricData.LeftJobSample, &metricData.LeftJobRequestCounts
+		}
+	}
+	subresource := latency.GetLabels()["Subresource"]
+	if subresource != "" && options.mergesSubresource(subresource) || options.fromNode(latency) || options.rollsUpPopulationOf(metricKey, latency) {
+		return true
+	}
+	earlier := tracker.metricKeys[metricKey]
+	if earlier == nil {
+		index := 0
+		if values != nil {
+			index = len(*values)
+		}
+		tracker.metricKeys[metricKey] = &runValues{index: index, count: 1}
+		return true
+	}
+	switch options.CollisionPolicy {
+	case AppendOnCollision:
+		return true
+	case ErrorOnCollision:
+		side := "right"
+		if fromLeftJob {
+			side = "left"
+		}
+		if tracker.err == nil {
+			tracker.err = fmt.Errorf("metric %v got more than one value in run %v of the %v job", metricName(metricKey), run.index, side)
+		}
+		j.recordDrop(options, metricKey, fromLeftJob, DropCollision, fmt.Sprintf("another value in run %v", run.index))
+		return false
+	}
+	switch options.CollisionPolicy {
+	case ReplaceOnCollision:
+		(*values)[earlier.index] = sample
+	case SumOnCollision:
+		(*values)[earlier.index] += sample
+	case AverageOnCollision:
+		// Keep a running average of the run's values.
+		(*values)[earlier.index] += (sample - (*values)[earlier.index]) / float64(earlier.count+1)
+	}
+	if options.KeepRequestCounts {
+		if options.CollisionPolicy == ReplaceOnCollision {
+			(*requestCounts)[earlier.index] = requestCount(latency)
+		} else {
+			(*requestCounts)[earlier.index] += requestCount(latency)
+		}
+	}
+	j.Data[metricKey].invalidateStats()
+	earlier.count++
+	return false
+}
+
+// forgetRunMetrics resets the tracking of the metrics having got a value in the run, once
+// the job's runs are flattened, keeping the first collision.
+func (j *JobComparisonData) forgetRunMetrics() {
+	j.collisions = collisionTracker{err: j.collisions.err}
+}
+
+// CollisionError returns the first collision met while flattening with the ErrorOnCollision
+// policy, if any, i.e. a metric getting more than one value within a run. As a producer bug
+// is its likely cause, strict callers should refuse to compare the data then.
+func (j *JobComparisonData) CollisionError() error {
+	return j.collisions.err
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/kubernetes/test/e2e/perftype"
+)
+
+func TestCollisionPolicy(t *testing.T) {
+	dataItem := func(subresource string, latency float64, count string) perftype.DataItem {
+		return perftype.DataItem{
+			Data:   map[string]float64{"Perc99": latency},
+			Unit:   "ms",
+			Labels: map[string]string{"Count": count, "Resource": "pods", "Subresource": subresource, "Verb": "GET"},
+		}
+	}
+	runMetrics := func(dataItems ...perftype.DataItem) map[string][]perftype.PerfData {
+		return map[string][]perftype.PerfData{"Load": {{Version: "v1", DataItems: dataItems}}}
+	}
+	// The first run reports the metric thrice (e.g. for its phases), and the binding
+	// subresource merged into it too.
+	jobMetrics := []map[string][]perftype.PerfData{
+		runMetrics(dataItem("", 10, "10"), dataItem("binding", 15, "10"), dataItem("", 30, "20"), dataItem("", 50, "30")),
+		runMetrics(dataItem("", 20, "10")),
+	}
+	metricKey := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	testCases := []struct {
+		policy                CollisionPolicy
+		expectedSample        []float64
+		expectedRequestCounts []float64
+		expectedRunIndices    []int
+		expectError           bool
+	}{
+		{policy: AppendOnCollision, expectedSample: []float64{10, 15, 30, 50, 20}, expectedRequestCounts: []float64{10, 10, 20, 30, 10}, expectedRunIndices: []int{0, 0, 0, 0, 1}},
+		{policy: ReplaceOnCollision, expectedSample: []float64{50, 15, 20}, expectedRequestCounts: []float64{30, 10, 10}, expectedRunIndices: []int{0, 0, 1}},
+		{policy: ErrorOnCollision, expectedSample: []float64{10, 15, 20}, expectedRequestCounts: []float64{10, 10, 10}, expectedRunIndices: []int{0, 0, 1}, expectError: true},
+		{policy: AverageOnCollision, expectedSample: []float64{30, 15, 20}, expectedRequestCounts: []float64{60, 10, 10}, expectedRunIndices: []int{0, 0, 1}},
+		{policy: SumOnCollision, expectedSample: []float64{90, 15, 20}, expectedRequestCounts: []float64{60, 10, 10}, expectedRunIndices: []int{0, 0, 1}},
+	}
+	for _, testCase := range testCases {
+		options := FlattenOptions{CollisionPolicy: testCase.policy, MergedSubresources: []string{"binding"}, KeepRequestCounts: true, KeepRunIndices: true, RecordDrops: true}
+		j := GetFlattennedComparisonDataWithOptions(jobMetrics, jobMetrics, options)
+		metricData := j.Data[metricKey]
+		if !reflect.DeepEqual(metricData.LeftJobSample, testCase.expectedSample) || !reflect.DeepEqual(metricData.RightJobSample, testCase.expectedSample) {
+			t.Errorf("Policy %v: samples are %v and %v, but expected %v", testCase.policy, metricData.LeftJobSample, metricData.RightJobSample, testCase.expectedSample)
+		}
+		if !reflect.DeepEqual(metricData.LeftJobRequestCounts, testCase.expectedRequestCounts) {
+			t.Errorf("Policy %v: request counts are %v, but expected %v", testCase.policy, metricData.LeftJobRequestCounts, testCase.expectedRequestCounts)
+		}
+		if !reflect.DeepEqual(metricData.LeftJobRunIndices, testCase.expectedRunIndices) {
+			t.Errorf("Policy %v: run indices are %v, but expected %v", testCase.policy, metricData.LeftJobRunIndices, testCase.expectedRunIndices)
+		}
+		if err := j.CollisionError(); (err != nil) != testCase.expectError {
+			t.Errorf("Policy %v: collision error is %v, but expected one: %v", testCase.policy, err, testCase.expectError)
+		}
+		if testCase.expectError {
+			dropLog := j.DropLog()
+			if len(dropLog) != 4 || dropLog[0].Reason != DropCollision || !dropLog[1].FromLeftJob || dropLog[2].FromLeftJob {
+				t.Errorf("Policy %v: drop log is %v, but expected a collision in each job", testCase.policy, dropLog)
+			}
+		}
+	}
+}
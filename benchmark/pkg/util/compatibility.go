@@ -0,0 +1,88 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+)
+
+// CompatibilityReport tells which metrics are present on either side of a job comparison.
+type CompatibilityReport struct {
+	LeftOnly  []MetricKey // Metrics with samples only from the left job
+	RightOnly []MetricKey // Metrics with samples only from the right job
+	Both      []MetricKey // Metrics with samples from both the jobs
+}
+
+// CompatibilityReport lists the metrics present only on the left, only on the right and
+// on both sides (sorted by key). A large one-sided set usually means that the jobs
+// didn't run the same tests, and that their comparison is driven by a thin intersection.
+func (j *JobComparisonData) CompatibilityReport() CompatibilityReport {
+	var report CompatibilityReport
+	for _, metricPair := range getMetricsSortedByKey(j) {
+		hasLeft := len(metricPair.metricData.LeftJobSample) > 0
+		hasRight := len(metricPair.metricData.RightJobSample) > 0
+		switch {
+		case hasLeft && hasRight:
+			report.Both = append(report.Both, metricPair.metricKey)
+		case hasLeft:
+			report.LeftOnly = append(report.LeftOnly, metricPair.metricKey)
+		case hasRight:
+			report.RightOnly = append(report.RightOnly, metricPair.metricKey)
+		}
+	}
+	return report
+}
+
+// CompareSamePlatform guards against comparing metrics across platforms (see
+// FlattenOptions.PlatformLabel) by accident: it returns an error if a metric is present only
+// on one side while the other side has it for another platform instead, e.g. as the left job
+// ran on amd64 and the right one on arm64. Such metrics aren't compared against each other,
+// so this tells that the jobs aren't comparable, rather than having their metrics silently
+// reported as one-sided. To compare across platforms deliberately, flatten the runs without
+// the PlatformLabel.
+func (j *JobComparisonData) CompareSamePlatform() error {
+	report := j.CompatibilityReport()
+	rightOnlyPlatforms := make(map[MetricKey][]string)
+	for _, metricKey := range report.RightOnly {
+		platform := metricKey.Platform
+		metricKey.Platform = ""
+		rightOnlyPlatforms[metricKey] = append(rightOnlyPlatforms[metricKey], platform)
+	}
+	for _, metricKey := range report.LeftOnly {
+		platform := metricKey.Platform
+		metricKey.Platform = ""
+		for _, rightPlatform := range rightOnlyPlatforms[metricKey] {
+			if rightPlatform != platform {
+				return fmt.Errorf("metric %v is measured on platform '%v' in the left job, but on '%v' in the right one", metricName(metricKey), platform, rightPlatform)
+			}
+		}
+	}
+	return nil
+}
+
+// OneSidedFraction returns the fraction of metrics that are present only on one side.
+func (r CompatibilityReport) OneSidedFraction() float64 {
+	total := len(r.LeftOnly) + len(r.RightOnly) + len(r.Both)
+	if total == 0 {
+		return 0
+	}
+	return float64(len(r.LeftOnly)+len(r.RightOnly)) / float64(total)
+}
+
+func (r CompatibilityReport) String() string {
+	return fmt.Sprintf("%v metrics only in left job, %v only in right job, %v in both", len(r.LeftOnly), len(r.RightOnly), len(r.Both))
+}
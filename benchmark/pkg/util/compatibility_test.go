@@ -0,0 +1,96 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/kubernetes/test/e2e/perftype"
+)
+
+func TestCompatibilityReport(t *testing.T) {
+	metricKey1 := MetricKey{TestName: "swag", Verb: "GET", Resource: "node", Percentile: "Perc99"}
+	metricKey2 := MetricKey{TestName: "swag", Verb: "PUT", Resource: "pods", Percentile: "Perc90"}
+	metricKey3 := MetricKey{TestName: "swag", Verb: "LIST", Resource: "rc", Percentile: "Perc50"}
+	metricKey4 := MetricKey{TestName: "swag", Verb: "LIST", Resource: "rc", Percentile: "Perc99"}
+	jobComparisonData := &JobComparisonData{
+		Data: map[MetricKey]*MetricComparisonData{
+			metricKey1: {LeftJobSample: []float64{1}, RightJobSample: []float64{2}},
+			metricKey2: {LeftJobSample: []float64{1}},
+			metricKey3: {RightJobSample: []float64{2}},
+			metricKey4: {LeftJobSample: []float64{1}, RightJobSample: []float64{2}},
+		},
+	}
+
+	report := jobComparisonData.CompatibilityReport()
+	expected := CompatibilityReport{
+		LeftOnly:  []MetricKey{metricKey2},
+		RightOnly: []MetricKey{metricKey3},
+		Both:      []MetricKey{metricKey1, metricKey4},
+	}
+	if !reflect.DeepEqual(report, expected) {
+		t.Errorf("Compatibility report mismatched from what was expected:\nReal: %v\nExpected: %v", report, expected)
+	}
+	if report.OneSidedFraction() != 0.5 {
+		t.Errorf("One-sided fraction computed as %v, but expected 0.5", report.OneSidedFraction())
+	}
+}
+
+func TestCompareSamePlatform(t *testing.T) {
+	runMetrics := func(platform string, latency float64) map[string][]perftype.PerfData {
+		return map[string][]perftype.PerfData{
+			"Load": {
+				{
+					Version: "v1",
+					DataItems: []perftype.DataItem{
+						{
+							Data:   map[string]float64{"Perc99": latency},
+							Unit:   "ms",
+							Labels: map[string]string{"Count": "10", "Resource": "pods", "Verb": "GET", "Platform": platform},
+						},
+					},
+				},
+			},
+		}
+	}
+	amd64Metrics := []map[string][]perftype.PerfData{runMetrics("amd64", 10)}
+	arm64Metrics := []map[string][]perftype.PerfData{runMetrics("arm64", 20)}
+	mixedMetrics := []map[string][]perftype.PerfData{runMetrics("amd64", 11), runMetrics("arm64", 21)}
+	options := FlattenOptions{MinAllowedAPIRequestCount: 10, PlatformLabel: "Platform"}
+
+	// The platform is part of the key, keeping the platforms' values apart.
+	j := GetFlattennedComparisonDataWithOptions(mixedMetrics, mixedMetrics, options)
+	amd64Key := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99", Platform: "amd64"}
+	if len(j.Data) != 2 || !reflect.DeepEqual(j.Data[amd64Key].LeftJobSample, []float64{11}) {
+		t.Errorf("Mixed-platform metrics wrongly flattened: %v", j.Data)
+	}
+	if err := j.CompareSamePlatform(); err != nil {
+		t.Errorf("Unexpected error comparing the same platforms: %v", err)
+	}
+
+	j = GetFlattennedComparisonDataWithOptions(amd64Metrics, arm64Metrics, options)
+	if err := j.CompareSamePlatform(); err == nil {
+		t.Errorf("Expected an error comparing metrics across platforms")
+	}
+
+	// Without the platform label, the platforms are compared deliberately.
+	j = GetFlattennedComparisonData(amd64Metrics, arm64Metrics, 10)
+	if err := j.CompareSamePlatform(); err != nil || len(j.Data) != 1 {
+		t.Errorf("Metrics not compared across platforms without the platform label: %v (error: %v)", j.Data, err)
+	}
+}
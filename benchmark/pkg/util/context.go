@@ -0,0 +1,90 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// ContextCombiner combines a metric's value with the (numeric) context of the run it's from,
+// e.g. the no. of nodes of the cluster, into the value to sample.
+type ContextCombiner func(value, context float64) float64
+
+// DivideByContext combines the value with the context into their quotient, e.g. the latency
+// per node, so that jobs on clusters of different sizes are compared on an equal footing.
+func DivideByContext(value, context float64) float64 {
+	return value / context
+}
+
+// MultiplyByContext combines the value with the context into their product, e.g. the total
+// over all the nodes of a per-node value.
+func MultiplyByContext(value, context float64) float64 {
+	return value * context
+}
+
+// CombineAllWith returns a ContextCombinerFor that combines all the metrics with the combiner.
+func CombineAllWith(combiner ContextCombiner) func(MetricKey) ContextCombiner {
+	return func(MetricKey) ContextCombiner { return combiner }
+}
+
+// MissingContextPolicy tells what to do with the values of DataItems whose context label is
+// missing or isn't a valid context (i.e. a positive finite number) while flattening.
+type MissingContextPolicy int
+
+// Allowed missing context policies.
+const (
+	SkipMissingContext        MissingContextPolicy = iota // Discard them (recording the drops)
+	PassthroughMissingContext                             // Keep them as they are, uncombined
+)
+
+// parseContext returns the context held in a context label's value, telling if it's valid.
+func parseContext(label string) (float64, bool) {
+	context, err := strconv.ParseFloat(label, 64)
+	if err != nil || !(context > 0) || math.IsInf(context, 0) {
+		return math.NaN(), false
+	}
+	return context, true
+}
+
+// combineWithContext returns the metric's value combined with the context of the DataItem it's
+// from, as per the options' ContextLabel and ContextCombinerFor (dividing by the context if the
+// latter isn't set), telling if the value is to be sampled. Metrics without a combiner, like
+// all of them without a context label, are sampled as they are. Values without a valid context
+// are handled as per the MissingContextPolicy.
+func (j *JobComparisonData) combineWithContext(value float64, metricKey MetricKey, labels map[string]string, fromLeftJob bool, options *FlattenOptions) (float64, bool) {
+	if options.ContextLabel == "" {
+		return value, true
+	}
+	combiner := ContextCombiner(DivideByContext)
+	if options.ContextCombinerFor != nil {
+		combiner = options.ContextCombinerFor(metricKey)
+	}
+	if combiner == nil {
+		return value, true
+	}
+	context, ok := parseContext(labels[options.ContextLabel])
+	if !ok {
+		if options.MissingContextPolicy == PassthroughMissingContext {
+			return value, true
+		}
+		j.recordDrop(options, metricKey, fromLeftJob, DropMissingContext, fmt.Sprintf("context label %v is '%v'", options.ContextLabel, labels[options.ContextLabel]))
+		return value, false
+	}
+	return combiner(value, context), true
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/kubernetes/test/e2e/perftype"
+)
+
+func TestCombineWithContext(t *testing.T) {
+	runMetrics := func(nodes string, perc50, perc99 float64) map[string][]perftype.PerfData {
+		labels := map[string]string{"Count": "10", "Resource": "pods", "Verb": "LIST"}
+		if nodes != "" {
+			labels["Nodes"] = nodes
+		}
+		return map[string][]perftype.PerfData{"Load": {{Version: "v1", DataItems: []perftype.DataItem{{
+			Data:   map[string]float64{"Perc50": perc50, "Perc99": perc99},
+			Unit:   "ms",
+			Labels: labels,
+		}}}}}
+	}
+	// The right job's cluster is 5 times larger, and so are its raw latencies.
+	leftMetrics := []map[string][]perftype.PerfData{runMetrics("100", 50, 200), runMetrics("", 30, 100)}
+	rightMetrics := []map[string][]perftype.PerfData{runMetrics("500", 250, 1000), runMetrics("many", 150, 500)}
+	perc50Key := MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc50"}
+	perc99Key := MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}
+
+	testCases := []struct {
+		name                    string
+		options                 FlattenOptions
+		perc50Left, perc50Right []float64
+		perc99Left, perc99Right []float64
+		expectedDrops           int
+	}{
+		{
+			name:        "no context label",
+			options:     FlattenOptions{},
+			perc50Left:  []float64{50, 30},
+			perc50Right: []float64{250, 150},
+			perc99Left:  []float64{200, 100},
+			perc99Right: []float64{1000, 500},
+		},
+		{
+			name:          "divided, skipping missing context",
+			options:       FlattenOptions{ContextLabel: "Nodes"},
+			perc50Left:    []float64{0.5},
+			perc50Right:   []float64{0.5},
+			perc99Left:    []float64{2},
+			perc99Right:   []float64{2},
+			expectedDrops: 4,
+		},
+		{
+			name:        "divided, passing missing context through",
+			options:     FlattenOptions{ContextLabel: "Nodes", MissingContextPolicy: PassthroughMissingContext},
+			perc50Left:  []float64{0.5, 30},
+			perc50Right: []float64{0.5, 150},
+			perc99Left:  []float64{2, 100},
+			perc99Right: []float64{2, 500},
+		},
+		{
+			name: "combined per metric",
+			options: FlattenOptions{ContextLabel: "Nodes", ContextCombinerFor: func(metricKey MetricKey) ContextCombiner {
+				if metricKey.Percentile == "Perc99" {
+					return MultiplyByContext
+				}
+				return nil
+			}},
+			perc50Left:    []float64{50, 30},
+			perc50Right:   []float64{250, 150},
+			perc99Left:    []float64{20000},
+			perc99Right:   []float64{500000},
+			expectedDrops: 2,
+		},
+	}
+	for _, testCase := range testCases {
+		testCase.options.MinAllowedAPIRequestCount = 10
+		testCase.options.RecordDrops = true
+		j := GetFlattennedComparisonDataWithOptions(leftMetrics, rightMetrics, testCase.options)
+		for _, expected := range []struct {
+			key         MetricKey
+			left, right []float64
+		}{{perc50Key, testCase.perc50Left, testCase.perc50Right}, {perc99Key, testCase.perc99Left, testCase.perc99Right}} {
+			metricData, ok := j.Data[expected.key]
+			if !ok {
+				t.Errorf("%v: metric %v missing", testCase.name, expected.key)
+				continue
+			}
+			if !reflect.DeepEqual(metricData.LeftJobSample, expected.left) || !reflect.DeepEqual(metricData.RightJobSample, expected.right) {
+				t.Errorf("%v: metric %v sampled as %v vs %v, but expected %v vs %v", testCase.name, expected.key,
+					metricData.LeftJobSample, metricData.RightJobSample, expected.left, expected.right)
+			}
+		}
+		drops := 0
+		for _, record := range j.DropLog() {
+			if record.Reason == DropMissingContext {
+				drops++
+			}
+		}
+		if drops != testCase.expectedDrops {
+			t.Errorf("%v: %v values dropped for missing context, but expected %v", testCase.name, drops, testCase.expectedDrops)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+)
+
+// CrossPercentileVerdict is the result of comparing a percentile of a metric in the right job
+// against a (typically higher) percentile of the same metric in the left job.
+type CrossPercentileVerdict struct {
+	LeftKey, RightKey MetricKey
+	AvgL, AvgR        float64
+	Matched           bool
+	Inconclusive      bool
+	Comments          string
+}
+
+// CompareCrossPercentile checks an SLO-style guardrail saying that the avg of the right job's
+// rightPercentile of a metric shouldn't exceed maxRatio times the avg of the left job's
+// leftPercentile of it (e.g. right Perc50 shouldn't exceed left Perc90, with maxRatio=1).
+// The Percentile of the given key is ignored. It errors if either of the percentiles has
+// no samples on its side. The verdict is inconclusive (and matched) if the ratio of the avgs
+// can't be computed, e.g. due to a zero left avg.
+func (j *JobComparisonData) CompareCrossPercentile(key MetricKey, leftPercentile, rightPercentile string, maxRatio float64) (*CrossPercentileVerdict, error) {
+	verdict := &CrossPercentileVerdict{LeftKey: key, RightKey: key}
+	verdict.LeftKey.Percentile = leftPercentile
+	verdict.RightKey.Percentile = rightPercentile
+	leftData, ok := j.Data[verdict.LeftKey]
+	if !ok || len(leftData.LeftJobSample) == 0 {
+		return nil, fmt.Errorf("no left job samples for metric %v", verdict.LeftKey)
+	}
+	rightData, ok := j.Data[verdict.RightKey]
+	if !ok || len(rightData.RightJobSample) == 0 {
+		return nil, fmt.Errorf("no right job samples for metric %v", verdict.RightKey)
+	}
+	verdict.AvgL = Mean(leftData.LeftJobSample)
+	verdict.AvgR = Mean(rightData.RightJobSample)
+	ratio, ok := SafeDiv(verdict.AvgR, verdict.AvgL)
+	verdict.Comments = fmt.Sprintf("AvgR(%v)/AvgL(%v)=%.2f\tAvgL(ms)=%.2f\tAvgR(ms)=%.2f\tN1=%v\tN2=%v",
+		rightPercentile, leftPercentile, ratio, verdict.AvgL, verdict.AvgR, len(leftData.LeftJobSample), len(rightData.RightJobSample))
+	if !ok {
+		verdict.Matched, verdict.Inconclusive = true, true
+		verdict.Comments = CannotComputeRatio + "\t" + verdict.Comments
+		return verdict, nil
+	}
+	verdict.Matched = verdict.AvgR <= maxRatio*verdict.AvgL
+	return verdict, nil
+}
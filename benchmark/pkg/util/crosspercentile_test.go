@@ -0,0 +1,63 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestCompareCrossPercentile(t *testing.T) {
+	key := MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods"}
+	perc50, perc90 := key, key
+	perc50.Percentile, perc90.Percentile = "Perc50", "Perc90"
+	j := &JobComparisonData{
+		Data: map[MetricKey]*MetricComparisonData{
+			perc50: {LeftJobSample: []float64{40, 60}, RightJobSample: []float64{90, 110}},
+			perc90: {LeftJobSample: []float64{90, 110}, RightJobSample: []float64{}},
+		},
+	}
+
+	verdict, err := j.CompareCrossPercentile(key, "Perc90", "Perc50", 1)
+	if err != nil {
+		t.Fatalf("Cross-percentile comparison failed: %v", err)
+	}
+	if !verdict.Matched || verdict.AvgL != 100 || verdict.AvgR != 100 || verdict.LeftKey != perc90 || verdict.RightKey != perc50 {
+		t.Errorf("Wrong verdict for right Perc50 equal to left Perc90: %+v", verdict)
+	}
+	if verdict, _ := j.CompareCrossPercentile(key, "Perc90", "Perc50", 0.9); verdict.Matched {
+		t.Errorf("Right Perc50 exceeding 0.9 x left Perc90 matched: %+v", verdict)
+	}
+
+	// A zero left avg (or a NaN right one) gives a ratio that can't be computed.
+	for _, samples := range [][2][]float64{{{0, 0}, {90, 110}}, {{90, 110}, {math.NaN()}}} {
+		j.Data[perc90].LeftJobSample, j.Data[perc50].RightJobSample = samples[0], samples[1]
+		verdict, err := j.CompareCrossPercentile(key, "Perc90", "Perc50", 1)
+		if err != nil || !verdict.Inconclusive || !strings.HasPrefix(verdict.Comments, CannotComputeRatio) {
+			t.Errorf("Verdict with an invalid ratio not inconclusive: %+v (err: %v)", verdict, err)
+		}
+	}
+
+	// Right job has no Perc90 samples, and no job has Perc99 ones.
+	if _, err := j.CompareCrossPercentile(key, "Perc50", "Perc90", 1); err == nil {
+		t.Errorf("Expected an error for right percentile without samples")
+	}
+	if _, err := j.CompareCrossPercentile(key, "Perc99", "Perc50", 1); err == nil {
+		t.Errorf("Expected an error for missing left percentile")
+	}
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// WriteCSV writes the job comparison data to w in CSV, with a row per metric (sorted by metric
+// key) holding its key, verdict and main stats. Non-finite values are left empty.
+func (j *JobComparisonData) WriteCSV(w io.Writer) error {
+	return j.WriteCSVWithNaNPolicy(w, NaNDefault)
+}
+
+// WriteCSVWithNaNPolicy is like WriteCSV, but represents the non-finite values as per the
+// policy (see NaNPolicy).
+func (j *JobComparisonData) WriteCSVWithNaNPolicy(w io.Writer, policy NaNPolicy) error {
+	csvWriter := csv.NewWriter(w)
+	// The header is the names of the columns.
+	header := []string{"testName", "verb", "resource", "subresource", "scope", "percentile", "platform", "sizeBucket", "population",
+		"unit", "matched", "inconclusive", "avgL", "avgR", "avgRatio", "stDevL", "stDevR", "maxRatio", "n1", "n2", "comments"}
+	format := &tableFormat{formatFloat: func(value float64) string { return formatCSVFloat(value, policy) }}
+	_, rows, err := j.table(header, getMetricsSortedByKey(j), format, nil)
+	if err != nil {
+		return err
+	}
+	if err := csvWriter.Write(header); err != nil {
+		return err
+	}
+	for _, record := range rows {
+		if err := csvWriter.Write(record); err != nil {
+			return err
+		}
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestWriteCSV(t *testing.T) {
+	j := &JobComparisonData{
+		Data: map[MetricKey]*MetricComparisonData{
+			{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99", Population: "tenant-a"}: {
+				LeftJobSample:  []float64{100, 100},
+				RightJobSample: []float64{150, 150},
+				Unit:           "ms",
+				Comments:       "AvgR/L=1.50, too slow",
+			},
+		},
+	}
+	j.ComputeStatsForMetricSamples()
+	j.Data[MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99", Population: "tenant-a"}].AvgRatio = math.NaN()
+
+	var buffer bytes.Buffer
+	if err := j.WriteCSV(&buffer); err != nil {
+		t.Fatalf("Failed to write CSV: %v", err)
+	}
+	expected := "testName,verb,resource,subresource,scope,percentile,platform,sizeBucket,population,unit,matched,inconclusive,avgL,avgR,avgRatio,stDevL,stDevR,maxRatio,n1,n2,comments\n" +
+		"Load,LIST,pods,,,Perc99,,,tenant-a,ms,false,false,100,150,,0,0,1.5,2,2,\"AvgR/L=1.50, too slow\"\n"
+	if buffer.String() != expected {
+		t.Errorf("Wrong CSV written:\n%v\nexpected:\n%v", buffer.String(), expected)
+	}
+
+	buffer.Reset()
+	if err := j.WriteWithOptions(&buffer, CSVFormat, WriteOptions{NaNPolicy: NaNAsString}); err != nil {
+		t.Fatalf("Failed to write CSV: %v", err)
+	}
+	if !bytes.Contains(buffer.Bytes(), []byte(",150,NaN,0,")) {
+		t.Errorf("Non-finite avg ratio not written as NaN: %v", buffer.String())
+	}
+}
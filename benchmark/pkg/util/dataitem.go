@@ -0,0 +1,69 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"k8s.io/kubernetes/test/e2e/perftype"
+)
+
+// DataItemLike is the minimal view of a perf data item needed for flattening it. It lets
+// callers flatten their own data item types (e.g. a vendored perftype of a different
+// shape) by implementing it, instead of converting them to perftype.DataItem. Note that
+// this package still imports perftype, for the flattening functions taking perftype data
+// (like GetFlattennedComparisonData) and the PerfTypeDataItem adapter, so its users still
+// build against it.
+type DataItemLike interface {
+	// GetData returns the values of the item, keyed by percentile.
+	GetData() map[string]float64
+	// GetUnit returns the unit of the values.
+	GetUnit() string
+	// GetLabels returns the labels of the item ("Verb", "Resource", "Count", etc).
+	GetLabels() map[string]string
+}
+
+// PerfTypeDataItem adapts a perftype.DataItem to DataItemLike.
+type PerfTypeDataItem struct {
+	*perftype.DataItem
+}
+
+// GetData returns the data item's values.
+func (d PerfTypeDataItem) GetData() map[string]float64 {
+	return d.Data
+}
+
+// GetUnit returns the data item's unit.
+func (d PerfTypeDataItem) GetUnit() string {
+	return d.Unit
+}
+
+// GetLabels returns the data item's labels.
+func (d PerfTypeDataItem) GetLabels() map[string]string {
+	return d.Labels
+}
+
+// PerfDataToItems adapts the data items of a run's perf data (keyed by test name) to DataItemLike.
+func PerfDataToItems(runMetrics map[string][]perftype.PerfData) map[string][]DataItemLike {
+	items := make(map[string][]DataItemLike, len(runMetrics))
+	for testName, perfDataArray := range runMetrics {
+		for i := range perfDataArray {
+			for k := range perfDataArray[i].DataItems {
+				items[testName] = append(items[testName], PerfTypeDataItem{&perfDataArray[i].DataItems[k]})
+			}
+		}
+	}
+	return items
+}
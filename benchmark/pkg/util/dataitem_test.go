@@ -0,0 +1,68 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/kubernetes/test/e2e/perftype"
+)
+
+// customDataItem mimics a data item type of a different shape than perftype.DataItem.
+type customDataItem struct {
+	percentiles map[string]float64
+	verb        string
+}
+
+func (c customDataItem) GetData() map[string]float64 { return c.percentiles }
+func (c customDataItem) GetUnit() string             { return "ms" }
+func (c customDataItem) GetLabels() map[string]string {
+	return map[string]string{"Verb": c.verb, "Resource": "pods", "Count": "10"}
+}
+
+func TestGetFlattennedComparisonDataFromItems(t *testing.T) {
+	perfTypeRun := map[string][]perftype.PerfData{
+		"Load": {
+			{
+				Version: "v1",
+				DataItems: []perftype.DataItem{
+					{
+						Data:   map[string]float64{"Perc50": 5, "Perc99": 10},
+						Unit:   "ms",
+						Labels: map[string]string{"Verb": "LIST", "Resource": "pods", "Count": "10"},
+					},
+				},
+			},
+		},
+	}
+	customRun := map[string][]DataItemLike{
+		"Load": {customDataItem{percentiles: map[string]float64{"Perc50": 5, "Perc99": 10}, verb: "LIST"}},
+	}
+
+	// Custom items flatten just like the equivalent perftype ones.
+	options := FlattenOptions{MinAllowedAPIRequestCount: 10}
+	expected := GetFlattennedComparisonDataWithOptions([]map[string][]perftype.PerfData{perfTypeRun}, []map[string][]perftype.PerfData{perfTypeRun}, options)
+	fromCustom := GetFlattennedComparisonDataFromItems([]map[string][]DataItemLike{customRun}, []map[string][]DataItemLike{customRun}, options)
+	if !reflect.DeepEqual(fromCustom, expected) {
+		t.Errorf("Flattening custom items mismatched:\nReal: %v\nExpected: %v", fromCustom, expected)
+	}
+	adapted := GetFlattennedComparisonDataFromItems([]map[string][]DataItemLike{PerfDataToItems(perfTypeRun)}, []map[string][]DataItemLike{PerfDataToItems(perfTypeRun)}, options)
+	if !reflect.DeepEqual(adapted, expected) {
+		t.Errorf("Flattening adapted perftype items mismatched:\nReal: %v\nExpected: %v", adapted, expected)
+	}
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// VerdictDigest returns a digest (hex-encoded SHA-256) of the set of failing metrics, i.e.
+// those conclusively mismatched, for deduplicating the alerts on an ongoing regression: the
+// digest only depends on which metrics fail (not by how much, nor in which order they're
+// stored), so it stays the same as long as they do, and changes once another one starts (or
+// stops) failing. It's that of the empty set if none are failing.
+func (j *JobComparisonData) VerdictDigest() string {
+	hash := sha256.New()
+	for _, metricPair := range getMetricsSortedByKey(j) {
+		key, data := metricPair.metricKey, metricPair.metricData
+		if data.Matched || data.Inconclusive {
+			continue
+		}
+		// Quoting the fields keeps the encoding unambiguous, whatever they contain.
+		fmt.Fprintf(hash, "%q %q %q %q %q %q %q %q", key.TestName, key.Verb, key.Resource, key.Subresource, key.Scope, key.Percentile, key.Platform, key.SizeBucket)
+		// The sub-population is only encoded if set, keeping the digests of the other metrics as they were.
+		if key.Population != "" {
+			fmt.Fprintf(hash, " %q", key.Population)
+		}
+		fmt.Fprintln(hash)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+)
+
+func TestVerdictDigest(t *testing.T) {
+	getKey := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	listKey := MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}
+	putKey := MetricKey{TestName: "Load", Verb: "PUT", Resource: "pods", Percentile: "Perc99"}
+	newData := func(rightAvg float64, listMatched bool) *JobComparisonData {
+		j := &JobComparisonData{
+			Data: map[MetricKey]*MetricComparisonData{
+				getKey:  {LeftJobSample: []float64{100}, RightJobSample: []float64{rightAvg}},
+				listKey: {LeftJobSample: []float64{100}, RightJobSample: []float64{rightAvg}, Matched: listMatched},
+				putKey:  {LeftJobSample: []float64{100}, RightJobSample: []float64{100}, Matched: true},
+			},
+		}
+		j.ComputeStatsForMetricSamples()
+		return j
+	}
+
+	digest := newData(150, true).VerdictDigest()
+	if len(digest) != 64 {
+		t.Errorf("Digest %v isn't a hex-encoded SHA-256", digest)
+	}
+	// The magnitude of the failures doesn't matter.
+	if other := newData(300, true).VerdictDigest(); other != digest {
+		t.Errorf("Digests of the same failing metrics differ: %v and %v", digest, other)
+	}
+	// Another metric failing does.
+	if other := newData(150, false).VerdictDigest(); other == digest {
+		t.Errorf("Digests of different failing metrics are the same: %v", digest)
+	}
+	// Inconclusive metrics aren't failing.
+	j := newData(150, true)
+	j.Data[listKey].MarkInconclusive("timed out")
+	if other := j.VerdictDigest(); other != digest {
+		t.Errorf("Digest changed by an inconclusive metric: %v and %v", digest, other)
+	}
+	// Fields are told apart, even if their concatenation is the same.
+	j = &JobComparisonData{Data: map[MetricKey]*MetricComparisonData{{TestName: "ab", Verb: "c"}: {}}}
+	other := &JobComparisonData{Data: map[MetricKey]*MetricComparisonData{{TestName: "a", Verb: "bc"}: {}}}
+	if j.VerdictDigest() == other.VerdictDigest() {
+		t.Errorf("Digests of different failing metrics are the same: %v", j.VerdictDigest())
+	}
+	if empty := (&JobComparisonData{}).VerdictDigest(); empty != "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855" {
+		t.Errorf("Digest without failing metrics is %v, but expected that of nothing", empty)
+	}
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"math"
+)
+
+// Distribution types told by ClassifyDistribution.
+const (
+	DistributionNormal      = "normal"       // Approximately normal
+	DistributionLogNormal   = "log-normal"   // Positive and right-skewed, with approximately normal logs
+	DistributionHeavyTailed = "heavy-tailed" // Neither, with tails heavier than normal ones
+	DistributionOther       = "other"        // Neither, without heavy tails (e.g. uniform, or bimodal)
+	DistributionUnknown     = "unknown"      // Too few values (or no spread) to tell
+)
+
+// MinClassifiedSampleSize is the min no. of values ClassifyDistribution needs to tell the shape
+// of a sample, smaller ones being of an unknown distribution.
+var MinClassifiedSampleSize = 8
+
+// centralMoments returns the second, third and fourth central moments of the sample.
+func centralMoments(sample []float64) (m2, m3, m4 float64) {
+	mean := Mean(sample)
+	for _, value := range sample {
+		deviation := value - mean
+		m2 += deviation * deviation
+		m3 += deviation * deviation * deviation
+		m4 += deviation * deviation * deviation * deviation
+	}
+	n := float64(len(sample))
+	return m2 / n, m3 / n, m4 / n
+}
+
+// Skewness returns the (moment) skewness of the sample, m3/m2^1.5, which is 0 for symmetric
+// distributions and positive for right-skewed ones (e.g. latencies with a long tail of slow
+// values). It's NaN for less than 3 values, or no spread.
+func Skewness(sample []float64) float64 {
+	if len(sample) < 3 {
+		return math.NaN()
+	}
+	m2, m3, _ := centralMoments(sample)
+	skewness, _ := SafeDiv(m3, math.Pow(m2, 1.5))
+	return skewness
+}
+
+// ExcessKurtosis returns the (moment) excess kurtosis of the sample, m4/m2²-3, which is 0 for
+// normal distributions and positive for heavier-tailed ones (i.e. with more extreme values).
+// It's NaN for less than 4 values, or no spread.
+func ExcessKurtosis(sample []float64) float64 {
+	if len(sample) < 4 {
+		return math.NaN()
+	}
+	m2, _, m4 := centralMoments(sample)
+	kurtosis, ok := SafeDiv(m4, m2*m2)
+	if !ok {
+		return math.NaN()
+	}
+	return kurtosis - 3
+}
+
+// shapeWithinNormal tells if the skewness and excess kurtosis of a sample of n values are both
+// within 2 standard errors (about √(6/n) and √(24/n) for normal data) of 0, i.e. if the sample's
+// shape is consistent with it being normal.
+func shapeWithinNormal(skewness, excessKurtosis float64, n int) bool {
+	return math.Abs(skewness) <= 2*math.Sqrt(6/float64(n)) && math.Abs(excessKurtosis) <= 2*math.Sqrt(24/float64(n))
+}
+
+// ClassifyDistribution roughly tells the type of distribution the sample is from, from simple
+// shape tests, e.g. for choosing a comparison appropriate for it. The sample is deemed:
+//   - DistributionNormal if its skewness and excess kurtosis are both within 2 standard errors
+//     of 0 (as for normal data).
+//   - Otherwise DistributionLogNormal if its values are all positive, it's right-skewed, and
+//     the logs of its values pass the above normality check.
+//   - Otherwise DistributionHeavyTailed if its excess kurtosis is more than 2 standard errors
+//     above 0, and DistributionOther if not.
+//
+// It's DistributionUnknown for less than MinClassifiedSampleSize values (for which the shape
+// tests have little power), values without any spread, or NaN values. Note that being deemed
+// normal only means that normality couldn't be ruled out.
+func ClassifyDistribution(sample []float64) string {
+	n := len(sample)
+	if n < MinClassifiedSampleSize || n < 4 || hasNaN(sample) {
+		return DistributionUnknown
+	}
+	skewness, excessKurtosis := Skewness(sample), ExcessKurtosis(sample)
+	if math.IsNaN(skewness) || math.IsNaN(excessKurtosis) {
+		return DistributionUnknown
+	}
+	if shapeWithinNormal(skewness, excessKurtosis, n) {
+		return DistributionNormal
+	}
+	if skewness > 0 {
+		if logs, ok := LogsOf(sample); ok && shapeWithinNormal(Skewness(logs), ExcessKurtosis(logs), n) {
+			return DistributionLogNormal
+		}
+	}
+	if excessKurtosis > 2*math.Sqrt(24/float64(n)) {
+		return DistributionHeavyTailed
+	}
+	return DistributionOther
+}
+
+// LogsOf returns the natural logs of the values, telling if they're all positive (and so have any).
+func LogsOf(sample []float64) ([]float64, bool) {
+	logs := make([]float64, len(sample))
+	for i, value := range sample {
+		if !(value > 0) {
+			return nil, false
+		}
+		logs[i] = math.Log(value)
+	}
+	return logs, true
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestSkewnessAndExcessKurtosis(t *testing.T) {
+	if skewness := Skewness([]float64{1, 2, 3, 4, 5}); skewness != 0 {
+		t.Errorf("Skewness of a symmetric sample is %v, but expected 0", skewness)
+	}
+	// m2 = 2.4, m3 = 4.8 for 1, 1, 1, 2, 5 (mean 2).
+	if skewness := Skewness([]float64{1, 1, 1, 2, 5}); math.Abs(skewness-4.8/math.Pow(2.4, 1.5)) > 1e-9 {
+		t.Errorf("Skewness of a right-skewed sample is %v, but expected %v", skewness, 4.8/math.Pow(2.4, 1.5))
+	}
+	// The uniform 1 to 4 has m2 = 1.25 and m4 = 2.5625.
+	if kurtosis := ExcessKurtosis([]float64{1, 2, 3, 4}); math.Abs(kurtosis-(2.5625/1.5625-3)) > 1e-9 {
+		t.Errorf("Excess kurtosis is %v, but expected %v", kurtosis, 2.5625/1.5625-3)
+	}
+	if skewness, kurtosis := Skewness([]float64{3, 3, 3, 3}), ExcessKurtosis([]float64{1, 2}); !math.IsNaN(skewness) || !math.IsNaN(kurtosis) {
+		t.Errorf("Expected NaN skewness and excess kurtosis without spread or enough values, got %v and %v", skewness, kurtosis)
+	}
+}
+
+func TestClassifyDistribution(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	sample := func(n int, value func() float64) []float64 {
+		values := make([]float64, n)
+		for i := range values {
+			values[i] = value()
+		}
+		return values
+	}
+	testCases := []struct {
+		name     string
+		sample   []float64
+		expected string
+	}{
+		{"normal", sample(200, func() float64 { return 100 + 10*random.NormFloat64() }), DistributionNormal},
+		{"log-normal", sample(200, func() float64 { return math.Exp(4 + random.NormFloat64()) }), DistributionLogNormal},
+		// Student's t with 3 degrees of freedom.
+		{"heavy-tailed", sample(200, func() float64 {
+			chiSquare := 0.0
+			for i := 0; i < 3; i++ {
+				normal := random.NormFloat64()
+				chiSquare += normal * normal
+			}
+			return random.NormFloat64() / math.Sqrt(chiSquare/3)
+		}), DistributionHeavyTailed},
+		{"uniform", sample(200, func() float64 { return random.Float64() }), DistributionOther},
+		{"too small", []float64{1, 2, 3}, DistributionUnknown},
+		{"no spread", []float64{5, 5, 5, 5, 5, 5, 5, 5}, DistributionUnknown},
+	}
+	for _, testCase := range testCases {
+		if distribution := ClassifyDistribution(testCase.sample); distribution != testCase.expected {
+			t.Errorf("Sample from a %v distribution classified as %v (skewness %v, excess kurtosis %v)", testCase.name, distribution, Skewness(testCase.sample), ExcessKurtosis(testCase.sample))
+		}
+	}
+}
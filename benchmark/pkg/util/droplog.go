@@ -0,0 +1,70 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+)
+
+// DropReason tells why a value was left out while flattening.
+type DropReason string
+
+// Reasons for dropping values while flattening.
+const (
+	DropLowCount           DropReason = "LowCount"           // API call's request count below the minimum allowed (or unparsable)
+	DropNaN                DropReason = "NaN"                // Value is NaN
+	DropNegative           DropReason = "Negative"           // Value is negative, with DropNegativeSamples policy
+	DropBelowFloor         DropReason = "BelowFloor"         // Value is below FlattenOptions.MinSampleValue
+	DropPercentileMismatch DropReason = "PercentileMismatch" // Percentile doesn't match FlattenOptions.PercentilePattern
+	DropBadPercentile      DropReason = "BadPercentile"      // Percentile isn't valid, with FlattenOptions.ValidatePercentiles
+	DropCollision          DropReason = "Collision"          // Metric already got a value in the run, with ErrorOnCollision policy
+	DropMissingContext     DropReason = "MissingContext"     // Context label is missing or invalid, with SkipMissingContext policy
+)
+
+// DropRecord describes a value (or whole DataItem) left out while flattening.
+type DropRecord struct {
+	// Metric is the key the value would have had. For a whole DataItem being
+	// dropped (due to low request count), its Percentile is empty.
+	Metric      MetricKey
+	FromLeftJob bool
+	Reason      DropReason
+	Detail      string
+}
+
+func (r DropRecord) String() string {
+	return fmt.Sprintf("%v (%v job): %v (%v)", r.Metric, jobSide(r.FromLeftJob), r.Reason, r.Detail)
+}
+
+// jobSide names the side of the job, "left" or "right".
+func jobSide(fromLeftJob bool) string {
+	if fromLeftJob {
+		return "left"
+	}
+	return "right"
+}
+
+func (j *JobComparisonData) recordDrop(options *FlattenOptions, metricKey MetricKey, fromLeftJob bool, reason DropReason, detail string) {
+	if options.RecordDrops {
+		j.dropLog = append(j.dropLog, DropRecord{Metric: metricKey, FromLeftJob: fromLeftJob, Reason: reason, Detail: detail})
+	}
+}
+
+// DropLog returns the values left out while flattening along with the reasons, in the order they
+// were encountered. It's only recorded if requested with FlattenOptions.RecordDrops (nil otherwise).
+func (j *JobComparisonData) DropLog() []DropRecord {
+	return j.dropLog
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"k8s.io/kubernetes/test/e2e/perftype"
+)
+
+func TestDropLog(t *testing.T) {
+	jobMetrics := []map[string][]perftype.PerfData{
+		{
+			"Load": []perftype.PerfData{
+				{
+					Version: "v1",
+					DataItems: []perftype.DataItem{
+						{
+							Data:   map[string]float64{"Perc99": 15},
+							Unit:   "ms",
+							Labels: map[string]string{"Count": "3", "Resource": "pods", "Verb": "LIST"},
+						},
+						{
+							Data:   map[string]float64{"Perc99": 15},
+							Unit:   "ms",
+							Labels: map[string]string{"Count": "lots", "Resource": "pods", "Verb": "POST"},
+						},
+						{
+							Data:   map[string]float64{"Perc99": math.NaN(), "Perc90": -1, "Perc50": 0.5, "Perc95": 10},
+							Unit:   "ms",
+							Labels: map[string]string{"Count": "10", "Resource": "pods", "Verb": "GET"},
+						},
+						{
+							Data:   map[string]float64{"Perc99": 20},
+							Unit:   "ms",
+							Labels: map[string]string{"Count": "10", "Resource": "nodes", "Verb": "GET"},
+						},
+					},
+				},
+			},
+		},
+	}
+	options := FlattenOptions{
+		MinAllowedAPIRequestCount: 10,
+		NegativeSamplePolicy:      DropNegativeSamples,
+		MinSampleValue:            1,
+		PercentilePattern:         "Perc9*",
+		RecordDrops:               true,
+	}
+	j := GetFlattennedComparisonDataWithOptions(jobMetrics, nil, options)
+
+	expectedReasons := map[MetricKey]DropReason{
+		{TestName: "Load", Verb: "LIST", Resource: "pods"}:                      DropLowCount,
+		{TestName: "Load", Verb: "POST", Resource: "pods"}:                      DropLowCount,
+		{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}: DropNaN,
+		{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc90"}: DropNegative,
+		{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc50"}: DropPercentileMismatch,
+	}
+	reasons := make(map[MetricKey]DropReason)
+	for _, dropRecord := range j.DropLog() {
+		if !dropRecord.FromLeftJob || dropRecord.Detail == "" {
+			t.Errorf("Bad drop record: %v", dropRecord)
+		}
+		reasons[dropRecord.Metric] = dropRecord.Reason
+	}
+	if !reflect.DeepEqual(reasons, expectedReasons) {
+		t.Errorf("Drop reasons mismatched:\nReal: %v\nExpected: %v", reasons, expectedReasons)
+	}
+	// Like with NaNs, metrics whose values all got dropped for being negative aren't kept.
+	if len(j.Data) != 2 {
+		t.Errorf("Expected 2 metrics to be kept, got: %v", j.Data)
+	}
+
+	// Values within the percentile pattern but below the floor are dropped for that reason.
+	j = GetFlattennedComparisonDataWithOptions(nil, jobMetrics, FlattenOptions{MinSampleValue: 12, RecordDrops: true})
+	belowFloorKey := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc95"}
+	found := false
+	for _, dropRecord := range j.DropLog() {
+		if dropRecord.Metric == belowFloorKey {
+			found = dropRecord.Reason == DropBelowFloor && !dropRecord.FromLeftJob
+		}
+	}
+	if !found {
+		t.Errorf("No below-floor drop record for %v in: %v", belowFloorKey, j.DropLog())
+	}
+
+	// Negative values kept (or clamped) by the policy are subject to the floor too.
+	for _, policy := range []NegativeSamplePolicy{WarnOnNegativeSamples, ClampNegativeSamples} {
+		j = GetFlattennedComparisonDataWithOptions(jobMetrics, nil, FlattenOptions{NegativeSamplePolicy: policy, MinSampleValue: 1, RecordDrops: true})
+		negativeKey := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc90"}
+		if _, ok := j.Data[negativeKey]; ok {
+			t.Errorf("With policy %v, negative value below the floor kept: %v", policy, j.Data[negativeKey])
+		}
+		found = false
+		for _, dropRecord := range j.DropLog() {
+			if dropRecord.Metric == negativeKey {
+				found = dropRecord.Reason == DropBelowFloor
+			}
+		}
+		if !found {
+			t.Errorf("With policy %v, no below-floor drop record for %v in: %v", policy, negativeKey, j.DropLog())
+		}
+	}
+
+	// Nothing is recorded unless asked for.
+	options.RecordDrops = false
+	if dropLog := GetFlattennedComparisonDataWithOptions(jobMetrics, nil, options).DropLog(); dropLog != nil {
+		t.Errorf("Drop log recorded without RecordDrops: %v", dropLog)
+	}
+}
+
+func TestValidatePercentiles(t *testing.T) {
+	jobMetrics := []map[string][]perftype.PerfData{
+		{
+			"Load": []perftype.PerfData{
+				{
+					Version: "v1",
+					DataItems: []perftype.DataItem{
+						{
+							Data:   map[string]float64{"Perc99": 15, "PercAbc": 20, "Perc150": 25},
+							Unit:   "ms",
+							Labels: map[string]string{"Count": "10", "Resource": "pods", "Verb": "LIST"},
+						},
+					},
+				},
+			},
+		},
+	}
+	j := GetFlattennedComparisonDataWithOptions(jobMetrics, nil, FlattenOptions{MinAllowedAPIRequestCount: 10, ValidatePercentiles: true, RecordDrops: true})
+	if len(j.Data) != 1 || j.Data[MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}] == nil {
+		t.Errorf("Flattened metrics are %v, but expected just the valid percentile's", j.Data)
+	}
+	dropped := make(map[string]DropReason)
+	for _, record := range j.DropLog() {
+		dropped[record.Metric.Percentile] = record.Reason
+	}
+	if expected := map[string]DropReason{"PercAbc": DropBadPercentile, "Perc150": DropBadPercentile}; !reflect.DeepEqual(dropped, expected) {
+		t.Errorf("Dropped percentiles are %v, but expected %v", dropped, expected)
+	}
+
+	// Without validation, anything goes.
+	if j := GetFlattennedComparisonData(jobMetrics, nil, 10); len(j.Data) != 3 {
+		t.Errorf("Flattened %v metrics without validation, but expected 3", len(j.Data))
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"math"
+)
+
+// welchTestStats returns the difference of the right and left sample means, its standard
+// error and the Welch-Satterthwaite degrees of freedom (NaN if either sample has less than 2
+// values).
+func welchTestStats(left, right []float64) (diff, standardError, df float64) {
+	nL, nR := float64(len(left)), float64(len(right))
+	varL, varR := SampleVariance(left)/nL, SampleVariance(right)/nR
+	standardError = math.Sqrt(varL + varR)
+	df = (varL + varR) * (varL + varR) / (varL*varL/(nL-1) + varR*varR/(nR-1))
+	return Mean(right) - Mean(left), standardError, df
+}
+
+// EquivalenceTest runs Two One-Sided Tests (TOST, using Welch's t-test) of the right sample's
+// mean not being below the left one's by margin (as a fraction of the left mean) and not being
+// above it by margin either, returning their p-values. Both being below the significance level
+// establishes the means to be equivalent within ±margin. The p-values are NaN if either sample
+// has less than 2 values.
+func EquivalenceTest(left, right []float64, margin float64) (pLower, pUpper float64) {
+	diff, standardError, df := welchTestStats(left, right)
+	bound := margin * math.Abs(Mean(left))
+	if standardError == 0 {
+		// The difference is known exactly, the tests are certain.
+		pLower, pUpper = 1, 1
+		if diff > -bound {
+			pLower = 0
+		}
+		if diff < bound {
+			pUpper = 0
+		}
+		return pLower, pUpper
+	}
+	pLower = 1 - StudentTCDF((diff+bound)/standardError, df)
+	pUpper = StudentTCDF((diff-bound)/standardError, df)
+	return pLower, pUpper
+}
+
+// CompareByEquivalence compares each metric's left and right samples with equivalence testing
+// (see EquivalenceTest), marking the metric matched only if its right job avg is established
+// to be within ±margin (as a fraction) of its left job avg at significance level alpha. Unlike
+// other schemes, which match metrics when no difference was detected, this verifies there's no
+// regression beyond the margin, so a noisy or small sample fails rather than passes. Metrics
+// with less than 2 values on either side are marked inconclusive, but left unmatched as well.
+func (j *JobComparisonData) CompareByEquivalence(margin, alpha float64) {
+	for _, metricData := range j.Data {
+		metricData.ResetVerdict()
+		leftSampleCount, rightSampleCount := len(metricData.LeftJobSample), len(metricData.RightJobSample)
+		pLower, pUpper := EquivalenceTest(metricData.LeftJobSample, metricData.RightJobSample, margin)
+		comments := fmt.Sprintf("P(lower)=%.4f\tP(upper)=%.4f\tMargin=%.1f%%\tN1=%v\tN2=%v", pLower, pUpper, margin*100, leftSampleCount, rightSampleCount)
+		if math.IsNaN(pLower) || math.IsNaN(pUpper) {
+			metricData.Inconclusive = true
+		} else {
+			metricData.Matched = pLower < alpha && pUpper < alpha
+		}
+		metricData.Comments = comments
+	}
+}
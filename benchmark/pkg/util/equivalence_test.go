@@ -0,0 +1,73 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestEquivalenceTest(t *testing.T) {
+	// Worked example: means 100 and 102, both variances 2, so the standard error is sqrt(2)
+	// with 2 degrees of freedom. With a 5% margin, the bounds are ±5 around the left mean:
+	//   t(lower) = (2 + 5) / sqrt(2) = 4.9497, p(lower) = 1 - T(4.9497; 2) = 0.0192
+	//   t(upper) = (2 - 5) / sqrt(2) = -2.1213, p(upper) = T(-2.1213; 2) = 0.0840
+	left, right := []float64{99, 101}, []float64{101, 103}
+	pLower, pUpper := EquivalenceTest(left, right, 0.05)
+	if math.Abs(pLower-0.0192) > 1e-4 || math.Abs(pUpper-0.0840) > 1e-4 {
+		t.Errorf("TOST p-values are %v and %v, but expected 0.0192 and 0.0840", pLower, pUpper)
+	}
+
+	// Without spread, the difference is known and the tests are certain.
+	if pLower, pUpper := EquivalenceTest([]float64{100, 100}, []float64{110, 110}, 0.05); pLower != 0 || pUpper != 1 {
+		t.Errorf("TOST p-values for samples without spread are %v and %v, but expected 0 and 1", pLower, pUpper)
+	}
+	if pLower, pUpper := EquivalenceTest([]float64{100}, right, 0.05); !math.IsNaN(pLower) || !math.IsNaN(pUpper) {
+		t.Errorf("TOST p-values for a single left value are %v and %v, but expected NaN", pLower, pUpper)
+	}
+}
+
+func TestCompareByEquivalence(t *testing.T) {
+	worked := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	noisy := MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}
+	single := MetricKey{TestName: "Load", Verb: "POST", Resource: "pods", Percentile: "Perc99"}
+	j := &JobComparisonData{
+		Data: map[MetricKey]*MetricComparisonData{
+			worked: {LeftJobSample: []float64{99, 101}, RightJobSample: []float64{101, 103}},
+			// No difference would be detected, but neither can equivalence be established.
+			noisy:  {LeftJobSample: []float64{50, 150}, RightJobSample: []float64{60, 140}},
+			single: {LeftJobSample: []float64{100}, RightJobSample: []float64{100, 101}},
+		},
+	}
+
+	j.CompareByEquivalence(0.05, 0.1)
+	if !j.Data[worked].Matched || !strings.Contains(j.Data[worked].Comments, "P(lower)=0.0192\tP(upper)=0.0840") {
+		t.Errorf("Metric %v not established as equivalent at alpha 0.1: %+v", worked, j.Data[worked])
+	}
+	if j.Data[noisy].Matched {
+		t.Errorf("Noisy metric %v established as equivalent: %+v", noisy, j.Data[noisy])
+	}
+	if j.Data[single].Matched || !j.Data[single].Inconclusive {
+		t.Errorf("Metric %v with a single left value not inconclusive and unmatched: %+v", single, j.Data[single])
+	}
+
+	j.CompareByEquivalence(0.05, 0.05)
+	if j.Data[worked].Matched {
+		t.Errorf("Metric %v established as equivalent at alpha 0.05: %+v", worked, j.Data[worked])
+	}
+}
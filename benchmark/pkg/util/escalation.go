@@ -0,0 +1,71 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"math"
+)
+
+// regressionChange returns the relative change of the metric's avg in the direction of a
+// regression, i.e. positive for a slowdown, or for a drop of a rate.
+func (d *MetricComparisonData) regressionChange() float64 {
+	change := relativeChange(d.AvgL, d.AvgR)
+	if d.IsRate {
+		return -change
+	}
+	return change
+}
+
+// EscalateSustainedRegressions escalates the metrics that regressed in the previous comparison
+// (e.g. read with LoadJSONReport) too, as a sustained problem rather than a one-off, setting
+// the metrics' SustainedRegressionCount:
+//   - A metric regressed again gets the severity of its mismatch raised by a level (see
+//     SeverityBreakdown), and "sustained regression across N comparisons" in its comments.
+//   - A metric matched now, but whose avg still changed by more than the tightened threshold
+//     in the regression direction (e.g. 0.05 for 5% slower, with a positive threshold), gets
+//     its verdict escalated into a mismatch, as its earlier regression is only partly gone.
+//
+// The other metrics have a count of 1 if they regressed (for the first time), and 0 if not.
+// It returns the keys (sorted) of the escalated metrics. The jobs should have been compared
+// already, and a report of the result written with WriteJSON carries the counts over to the
+// next comparison.
+func (j *JobComparisonData) EscalateSustainedRegressions(previous *JobComparisonData, tightenedThreshold float64) []MetricKey {
+	var escalated []MetricKey
+	for _, metricPair := range getMetricsSortedByKey(j) {
+		key, data := metricPair.metricKey, metricPair.metricData
+		data.SustainedRegressionCount = 0
+		if data.regressed() {
+			data.SustainedRegressionCount = 1
+		}
+		previousData, ok := previous.Data[key]
+		if !ok || !previousData.regressed() || data.Inconclusive {
+			continue
+		}
+		if !data.regressed() {
+			if !(tightenedThreshold > 0 && data.regressionChange() > tightenedThreshold) {
+				continue
+			}
+			data.Matched = false
+			data.Comments += fmt.Sprintf("\tescalated at a tightened threshold of %.1f%%", 100*tightenedThreshold)
+		}
+		data.SustainedRegressionCount = int(math.Max(float64(previousData.SustainedRegressionCount), 1)) + 1
+		data.Comments += fmt.Sprintf("\tsustained regression across %v comparisons", data.SustainedRegressionCount)
+		escalated = append(escalated, key)
+	}
+	return escalated
+}
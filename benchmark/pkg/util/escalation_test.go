@@ -0,0 +1,100 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEscalateSustainedRegressions(t *testing.T) {
+	sustained := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	oneOff := MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}
+	borderline := MetricKey{TestName: "Load", Verb: "PUT", Resource: "pods", Percentile: "Perc99"}
+	recovered := MetricKey{TestName: "Load", Verb: "DELETE", Resource: "pods", Percentile: "Perc99"}
+	// Compares jobs where the metrics have the given right avgs (the left ones being 100), and
+	// escalates them using the report of the previous comparison.
+	compareAndEscalate := func(previous *JobComparisonData, rightAvgs map[MetricKey]float64) *JobComparisonData {
+		j := NewJobComparisonData()
+		for key, right := range rightAvgs {
+			j.Data[key] = &MetricComparisonData{LeftJobSample: []float64{100, 100}, RightJobSample: []float64{right, right}}
+		}
+		compareAvgsWithin(j, 0.2, 0)
+		if previous == nil {
+			previous = NewJobComparisonData()
+		}
+		j.EscalateSustainedRegressions(previous, 0.1)
+		// Hand the comparison over as a report, as the next one would get it.
+		var buf bytes.Buffer
+		if err := j.WriteJSON(&buf, false); err != nil {
+			t.Fatalf("Writing the report failed: %v", err)
+		}
+		report, err := ReadJSONReport(&buf)
+		if err != nil {
+			t.Fatalf("Reading the report failed: %v", err)
+		}
+		return report
+	}
+
+	first := compareAndEscalate(nil, map[MetricKey]float64{sustained: 150, borderline: 130, recovered: 150})
+	for _, key := range []MetricKey{sustained, borderline, recovered} {
+		if count := first.Data[key].SustainedRegressionCount; count != 1 {
+			t.Errorf("Metric %v regressed for the first time, but got a count of %v", key, count)
+		}
+	}
+
+	// The metric regressed twice in a row, while the borderline one is now only 15% slower.
+	j := NewJobComparisonData()
+	for key, right := range map[MetricKey]float64{sustained: 150, oneOff: 150, borderline: 115, recovered: 100} {
+		j.Data[key] = &MetricComparisonData{LeftJobSample: []float64{100, 100}, RightJobSample: []float64{right, right}}
+	}
+	compareAvgsWithin(j, 0.2, 0)
+	if severity := j.Data[sustained].severity(DefaultSeverityThresholds); severity != SeverityMajor {
+		t.Fatalf("Metric %v is %v before escalating, but expected %v", sustained, severity, SeverityMajor)
+	}
+	escalated := j.EscalateSustainedRegressions(first, 0.1)
+	if expected := []MetricKey{sustained, borderline}; !reflect.DeepEqual(escalated, expected) {
+		t.Errorf("Escalated metrics %v, but expected %v", escalated, expected)
+	}
+	data := j.Data[sustained]
+	if data.Matched || data.SustainedRegressionCount != 2 || !strings.Contains(data.Comments, "sustained regression across 2 comparisons") {
+		t.Errorf("Metric %v regressed twice in a row, but got escalated to %+v", sustained, data)
+	}
+	if severity := data.severity(DefaultSeverityThresholds); severity != SeverityCritical {
+		t.Errorf("Metric %v is %v once escalated, but expected %v", sustained, severity, SeverityCritical)
+	}
+	if breakdown := j.SeverityBreakdown(DefaultSeverityThresholds); breakdown[SeverityCritical] != 1 || breakdown[SeverityMajor] != 2 || breakdown[SeverityMinor] != 0 {
+		t.Errorf("Severity breakdown %v, but expected the escalated metrics a level more severe than the one-off", breakdown)
+	}
+	if data := j.Data[borderline]; data.Matched || data.SustainedRegressionCount != 2 || !strings.Contains(data.Comments, "tightened threshold of 10.0%") {
+		t.Errorf("Metric %v matched within the threshold but not the tightened one, was escalated to %+v", borderline, data)
+	}
+	if data := j.Data[oneOff]; data.SustainedRegressionCount != 1 || strings.Contains(data.Comments, "sustained") {
+		t.Errorf("Metric %v regressed for the first time, but got escalated to %+v", oneOff, data)
+	}
+	if data := j.Data[recovered]; !data.Matched || data.SustainedRegressionCount != 0 {
+		t.Errorf("Metric %v recovered, but got escalated to %+v", recovered, data)
+	}
+
+	// The count goes on across the reports.
+	third := compareAndEscalate(compareAndEscalate(first, map[MetricKey]float64{sustained: 150}), map[MetricKey]float64{sustained: 150})
+	if data := third.Data[sustained]; data.SustainedRegressionCount != 3 || !strings.Contains(data.Comments, "sustained regression across 3 comparisons") {
+		t.Errorf("Metric %v regressed thrice in a row, but got escalated to %+v", sustained, data)
+	}
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"time"
+)
+
+// VerdictRegressed is the verdict of the metrics that regressed, i.e. mismatched for the worse.
+const VerdictRegressed = "regressed"
+
+// RegressionEvent is the machine-readable event of a metric's regression in a build, e.g. for
+// publishing on an event bus that routes it to the metric's owner. Unlike the reports, it's
+// meant to be stable rather than human readable.
+type RegressionEvent struct {
+	BuildID     string `json:"buildId"`
+	TestName    string `json:"testName"`
+	Verb        string `json:"verb"`
+	Resource    string `json:"resource,omitempty"`
+	Subresource string `json:"subresource,omitempty"`
+	Scope       string `json:"scope,omitempty"`
+	Percentile  string `json:"percentile"`
+	Platform    string `json:"platform,omitempty"`
+	SizeBucket  string `json:"sizeBucket,omitempty"`
+	Population  string `json:"population,omitempty"`
+	Unit        string `json:"unit,omitempty"`
+
+	PercentChange jsonFloat `json:"percentChange"` // Relative change of the avg, in percents (null if it can't be computed)
+	Verdict       string    `json:"verdict"`
+	Severity      string    `json:"severity"` // As per the DefaultSeverityThresholds (see SeverityBreakdown)
+	Tier          Tier      `json:"tier"`
+	Owner         string    `json:"owner,omitempty"`
+	// Timestamp is the time of the metric's latest right job run, if known, or else the time
+	// the event was made.
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// RegressionEvents returns the event of each metric that regressed in the build (i.e. in its
+// right job), sorted by metric key, with the tier and owner they were annotated with (see
+// Annotate) and their severity. Like for the RegressionManifest, improvements and inconclusive
+// metrics are left out, and the metrics should have been compared already.
+func (j *JobComparisonData) RegressionEvents(buildID string) []RegressionEvent {
+	now := time.Now()
+	var events []RegressionEvent
+	for _, metricPair := range getMetricsSortedByKey(j) {
+		key, data := metricPair.metricKey, metricPair.metricData
+		if !data.regressed() {
+			continue
+		}
+		event := RegressionEvent{
+			BuildID:       buildID,
+			TestName:      key.TestName,
+			Verb:          key.Verb,
+			Resource:      key.Resource,
+			Subresource:   key.Subresource,
+			Scope:         key.Scope,
+			Percentile:    key.Percentile,
+			Platform:      key.Platform,
+			SizeBucket:    key.SizeBucket,
+			Population:    key.Population,
+			Unit:          data.Unit,
+			PercentChange: jsonFloat{value: 100 * relativeChange(data.AvgL, data.AvgR)},
+			Verdict:       VerdictRegressed,
+			Severity:      data.severity(DefaultSeverityThresholds),
+			Tier:          data.Tier,
+			Owner:         data.Owner,
+			Timestamp:     now,
+		}
+		if latest, ok := latestTimestamp(data.RightJobTimestamps); ok {
+			event.Timestamp = latest
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// latestTimestamp returns the latest of the (non-zero) timestamps, telling if there's any.
+func latestTimestamp(timestamps []time.Time) (time.Time, bool) {
+	var latest time.Time
+	for _, timestamp := range timestamps {
+		if timestamp.After(latest) {
+			latest = timestamp
+		}
+	}
+	return latest, !latest.IsZero()
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestRegressionEvents(t *testing.T) {
+	slower := MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}
+	muchSlower := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	faster := MetricKey{TestName: "Load", Verb: "PUT", Resource: "pods", Percentile: "Perc99"}
+	lowerThroughput := MetricKey{TestName: "Load", Verb: "POST", Resource: "pods", Percentile: "Throughput"}
+	unchanged := MetricKey{TestName: "Load", Verb: "DELETE", Resource: "pods", Percentile: "Perc99"}
+	buildTime := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	j := NewJobComparisonData()
+	j.Data[slower] = &MetricComparisonData{AvgL: 100, AvgR: 110, Unit: "ms", RightJobTimestamps: []time.Time{buildTime.Add(-time.Hour), buildTime, {}}}
+	j.Data[muchSlower] = &MetricComparisonData{AvgL: 100, AvgR: 300, Unit: "ms"}
+	j.Data[faster] = &MetricComparisonData{AvgL: 100, AvgR: 50}
+	j.Data[lowerThroughput] = &MetricComparisonData{AvgL: 100, AvgR: 60}
+	j.Data[unchanged] = &MetricComparisonData{AvgL: 100, AvgR: 101, Matched: true}
+	j.Annotate(&Annotations{Annotations: []MetricAnnotation{
+		{Metric: MetricPattern{Verb: "LIST"}, Tier: TierP1, Owner: "sig-api-machinery"},
+		{Metric: MetricPattern{Percentile: "Throughput"}, Tier: TierP2, Owner: "sig-scalability", Rate: true},
+	}})
+
+	before := time.Now()
+	events := j.RegressionEvents("1234")
+	if len(events) != 3 {
+		t.Fatalf("Got %v events, but expected the 3 regressions: %+v", len(events), events)
+	}
+	for i, expected := range []struct {
+		verb, severity, owner string
+		tier                  Tier
+		percentChange         float64
+	}{
+		{"GET", SeverityCritical, "", TierP0, 200},
+		{"LIST", SeverityMinor, "sig-api-machinery", TierP1, 10},
+		{"POST", SeverityMajor, "sig-scalability", TierP2, -40},
+	} {
+		event := events[i]
+		if event.BuildID != "1234" || event.Verb != expected.verb || event.Severity != expected.severity || event.Owner != expected.owner ||
+			event.Tier != expected.tier || math.Abs(event.PercentChange.value-expected.percentChange) > 1e-9 || event.Verdict != VerdictRegressed {
+			t.Errorf("Event %v is %+v, but expected %+v", i, event, expected)
+		}
+	}
+	if !events[1].Timestamp.Equal(buildTime) {
+		t.Errorf("Event timestamped %v, but expected the latest right job run's time %v", events[1].Timestamp, buildTime)
+	}
+	if events[0].Timestamp.Before(before) {
+		t.Errorf("Event of unknown run times timestamped %v, but expected the time it was made", events[0].Timestamp)
+	}
+
+	contents, err := json.Marshal(events[1])
+	if err != nil {
+		t.Fatalf("Marshaling the event failed: %v", err)
+	}
+	expectedJSON := `{"buildId":"1234","testName":"Load","verb":"LIST","resource":"pods","percentile":"Perc99","unit":"ms",` +
+		`"percentChange":10,"verdict":"regressed","severity":"minor","tier":"P1","owner":"sig-api-machinery","timestamp":"2026-10-14T12:00:00Z"}`
+	if string(contents) != expectedJSON {
+		t.Errorf("Event marshaled as:\n%v\nExpected:\n%v", string(contents), expectedJSON)
+	}
+}
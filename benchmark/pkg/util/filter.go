@@ -0,0 +1,47 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+// Filter returns the job comparison data restricted to the metrics the predicate holds for,
+// e.g. those with an AvgR over 100ms and regressed by more than 10%:
+//
+//	j.Filter(func(k MetricKey, d *MetricComparisonData) bool {
+//		return d.AvgR > 100 && (d.AvgR-d.AvgL)/d.AvgL > 0.1
+//	})
+//
+// The stats are computed (see EnsureStats) before the predicate is evaluated, so that it can
+// rely on them. The new data can be written, compared, etc like any other (e.g. with the
+// writers of the reports), and is otherwise like the original (e.g. its flatten options, run
+// counts and warnings). The metrics' data is shared with the original though, so that changes
+// to either are seen in both: it should be cloned (see Clone) to be mutated on its own.
+func (j *JobComparisonData) Filter(pred func(MetricKey, *MetricComparisonData) bool) *JobComparisonData {
+	j.EnsureStats()
+	return j.filter(pred)
+}
+
+// filter is like Filter, but evaluates the predicate on the metrics as they are, e.g. for the
+// metrics' keys or verdicts, without the stats being ensured first (which updates the data).
+func (j *JobComparisonData) filter(pred func(MetricKey, *MetricComparisonData) bool) *JobComparisonData {
+	filtered := *j
+	filtered.Data = make(map[MetricKey]*MetricComparisonData)
+	for metricKey, metricData := range j.Data {
+		if pred(metricKey, metricData) {
+			filtered.Data[metricKey] = metricData
+		}
+	}
+	return &filtered
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"k8s.io/kubernetes/test/e2e/perftype"
+)
+
+func TestFilter(t *testing.T) {
+	slowRegressed := MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}
+	slowSteady := MetricKey{TestName: "Load", Verb: "LIST", Resource: "nodes", Percentile: "Perc99"}
+	fastRegressed := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	runMetrics := map[string][]perftype.PerfData{"Load": {{Version: "v1"}}}
+	j := GetFlattennedComparisonData([]map[string][]perftype.PerfData{runMetrics}, []map[string][]perftype.PerfData{runMetrics, runMetrics}, 0)
+	j.Data[slowRegressed] = &MetricComparisonData{LeftJobSample: []float64{150, 150}, RightJobSample: []float64{200, 200}}
+	j.Data[slowSteady] = &MetricComparisonData{LeftJobSample: []float64{150, 150}, RightJobSample: []float64{155, 155}}
+	j.Data[fastRegressed] = &MetricComparisonData{LeftJobSample: []float64{10, 10}, RightJobSample: []float64{20, 20}}
+
+	// The stats aren't computed yet, but are by the time the predicate is evaluated.
+	filtered := j.Filter(func(k MetricKey, d *MetricComparisonData) bool {
+		return d.AvgR > 100 && relativeChange(d.AvgL, d.AvgR) > 0.1
+	})
+	if len(filtered.Data) != 1 || filtered.Data[slowRegressed] == nil {
+		t.Fatalf("Filtered metrics are %v, but expected only %v", filtered.Data, slowRegressed)
+	}
+	if len(j.Data) != 3 {
+		t.Errorf("Original has %v metrics once filtered, but expected 3", len(j.Data))
+	}
+	// The metrics' data is shared, while the rest is kept as it is.
+	if filtered.Data[slowRegressed] != j.Data[slowRegressed] {
+		t.Errorf("Filtered metric's data isn't shared with the original")
+	}
+	if left, right := filtered.RunCounts(); left != 1 || right != 2 {
+		t.Errorf("Filtered data has run counts %v and %v, but expected 1 and 2", left, right)
+	}
+
+	// It composes with the writers.
+	var buf bytes.Buffer
+	if err := filtered.WriteMarkdown(&buf); err != nil {
+		t.Fatalf("WriteMarkdown failed: %v", err)
+	}
+	if report := buf.String(); !strings.Contains(report, "| Load | LIST | pods |") || strings.Contains(report, "nodes") || strings.Contains(report, "GET") {
+		t.Errorf("Report of the filtered data has other metrics:\n%v", report)
+	}
+
+	if none := j.Filter(func(MetricKey, *MetricComparisonData) bool { return false }); len(none.Data) != 0 {
+		t.Errorf("Nothing filtered in has metrics %v", none.Data)
+	}
+}
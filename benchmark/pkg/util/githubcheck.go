@@ -0,0 +1,98 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Conclusions of a GitHub check run, as expected by the Checks API.
+const (
+	CheckConclusionSuccess = "success"
+	CheckConclusionFailure = "failure"
+	CheckConclusionNeutral = "neutral"
+)
+
+// checkOutputTextLimit is the max no. of characters of a check run's output text, past which
+// the Checks API rejects the check run.
+const checkOutputTextLimit = 65535
+
+// GitHubCheckConclusion packages the verdicts into the conclusion and output of a GitHub check
+// run (see the Checks API): "failure" if any metric conclusively mismatched, "neutral" if none
+// could be compared conclusively (e.g. there wasn't enough data), and "success" otherwise. The
+// summary is a Markdown paragraph counting the verdicts and naming the mismatched metrics, and
+// the text the full Markdown report (see WriteMarkdown), truncated to the limit of the Checks
+// API (see truncateCheckText).
+func (j *JobComparisonData) GitHubCheckConclusion() (conclusion string, summary string, text string) {
+	var mismatched []string
+	matched, inconclusive := 0, 0
+	for _, metricPair := range getMetricsSortedByKey(j) {
+		key, data := metricPair.metricKey, metricPair.metricData
+		switch {
+		case data.Inconclusive:
+			inconclusive++
+		case data.Matched:
+			matched++
+		default:
+			mismatched = append(mismatched, fmt.Sprintf("`%v` (%v)", metricName(key), formatPercentChange(relativeChange(data.AvgL, data.AvgR))))
+		}
+	}
+
+	var summaryLines []string
+	switch {
+	case len(mismatched) > 0:
+		conclusion = CheckConclusionFailure
+		summaryLines = append(summaryLines, fmt.Sprintf("**%v of %v metrics mismatched.**", len(mismatched), len(j.Data)))
+	case matched == 0:
+		conclusion = CheckConclusionNeutral
+		summaryLines = append(summaryLines, fmt.Sprintf("**None of the %v metrics could be compared conclusively.**", len(j.Data)))
+	default:
+		conclusion = CheckConclusionSuccess
+		summaryLines = append(summaryLines, fmt.Sprintf("**All the %v conclusively compared metrics matched.**", matched))
+	}
+	summaryLines = append(summaryLines, "", fmt.Sprintf("Matched: %v, mismatched: %v, inconclusive: %v", matched, len(mismatched), inconclusive))
+	if len(mismatched) > 0 {
+		summaryLines = append(summaryLines, "")
+		for _, metric := range mismatched {
+			summaryLines = append(summaryLines, "- "+metric)
+		}
+	}
+
+	var report bytes.Buffer
+	// Writing to a buffer can't fail.
+	j.WriteMarkdown(&report)
+	return conclusion, strings.Join(summaryLines, "\n"), truncateCheckText(report.String(), checkOutputTextLimit)
+}
+
+// truncateCheckText truncates the text to at most limit characters, if longer, at the end of a
+// line (so as not to cut a table row), noting how many more characters there were.
+func truncateCheckText(text string, limit int) string {
+	length := utf8.RuneCountInString(text)
+	if length <= limit {
+		return text
+	}
+	// The note can only get shorter once the no. of characters cut is known.
+	budget := limit - utf8.RuneCountInString(fmt.Sprintf("\n…%v more", length))
+	kept := string([]rune(text)[:budget])
+	if end := strings.LastIndex(kept, "\n"); end >= 0 {
+		kept = kept[:end+1]
+	}
+	return kept + fmt.Sprintf("…%v more", length-utf8.RuneCountInString(kept))
+}
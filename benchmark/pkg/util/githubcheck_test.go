@@ -0,0 +1,116 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestGitHubCheckConclusion(t *testing.T) {
+	getKey := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	listKey := MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}
+	matched := func() *MetricComparisonData {
+		return &MetricComparisonData{LeftJobSample: []float64{100}, RightJobSample: []float64{101}, Matched: true}
+	}
+	mismatched := func() *MetricComparisonData {
+		return &MetricComparisonData{LeftJobSample: []float64{100}, RightJobSample: []float64{150}}
+	}
+	inconclusive := func() *MetricComparisonData {
+		d := &MetricComparisonData{LeftJobSample: []float64{100}}
+		d.MarkInconclusive("insufficient data")
+		return d
+	}
+	testCases := []struct {
+		name               string
+		data               map[MetricKey]*MetricComparisonData
+		expectedConclusion string
+		expectedSummary    string
+	}{
+		{
+			name:               "all matched",
+			data:               map[MetricKey]*MetricComparisonData{getKey: matched(), listKey: matched()},
+			expectedConclusion: CheckConclusionSuccess,
+			expectedSummary:    "**All the 2 conclusively compared metrics matched.**\n\nMatched: 2, mismatched: 0, inconclusive: 0",
+		},
+		{
+			name:               "matched and inconclusive",
+			data:               map[MetricKey]*MetricComparisonData{getKey: matched(), listKey: inconclusive()},
+			expectedConclusion: CheckConclusionSuccess,
+			expectedSummary:    "**All the 1 conclusively compared metrics matched.**\n\nMatched: 1, mismatched: 0, inconclusive: 1",
+		},
+		{
+			name:               "mismatched",
+			data:               map[MetricKey]*MetricComparisonData{getKey: mismatched(), listKey: inconclusive()},
+			expectedConclusion: CheckConclusionFailure,
+			expectedSummary:    "**1 of 2 metrics mismatched.**\n\nMatched: 0, mismatched: 1, inconclusive: 1\n\n- `Load GET pods Perc99` (+50.0%)",
+		},
+		{
+			name:               "all inconclusive",
+			data:               map[MetricKey]*MetricComparisonData{getKey: inconclusive(), listKey: inconclusive()},
+			expectedConclusion: CheckConclusionNeutral,
+			expectedSummary:    "**None of the 2 metrics could be compared conclusively.**\n\nMatched: 0, mismatched: 0, inconclusive: 2",
+		},
+		{
+			name:               "no metrics",
+			data:               map[MetricKey]*MetricComparisonData{},
+			expectedConclusion: CheckConclusionNeutral,
+			expectedSummary:    "**None of the 0 metrics could be compared conclusively.**\n\nMatched: 0, mismatched: 0, inconclusive: 0",
+		},
+	}
+	for _, testCase := range testCases {
+		j := &JobComparisonData{Data: testCase.data}
+		j.ComputeStatsForMetricSamples()
+		conclusion, summary, text := j.GitHubCheckConclusion()
+		if conclusion != testCase.expectedConclusion {
+			t.Errorf("%v: conclusion is %v, but expected %v", testCase.name, conclusion, testCase.expectedConclusion)
+		}
+		if summary != testCase.expectedSummary {
+			t.Errorf("%v: summary mismatched:\nReal:\n%v\nExpected:\n%v", testCase.name, summary, testCase.expectedSummary)
+		}
+		if !strings.HasPrefix(text, "| E2E Test |") || strings.Count(text, "\n") != len(testCase.data)+2 {
+			t.Errorf("%v: text isn't the Markdown report:\n%v", testCase.name, text)
+		}
+	}
+}
+
+func TestTruncateCheckText(t *testing.T) {
+	if text := truncateCheckText("| a |\n| b |\n", 20); text != "| a |\n| b |\n" {
+		t.Errorf("Text within the limit truncated to %q", text)
+	}
+	// The text is cut at the end of the last line fitting along with the note.
+	if text, expected := truncateCheckText("| a |\n| b |\n| c |\n", 16), "| a |\n…12 more"; text != expected {
+		t.Errorf("Text truncated to %q, but expected %q", text, expected)
+	}
+
+	// The report of many metrics is truncated to the limit of the Checks API.
+	j := NewJobComparisonData()
+	for i := 0; i < 2000; i++ {
+		j.Data[MetricKey{TestName: "Load", Verb: "GET", Resource: strings.Repeat("r", i%50) + string(rune('a'+i%26)), Subresource: string(rune('a' + i/26)), Percentile: "Perc99"}] = &MetricComparisonData{LeftJobSample: []float64{100}, RightJobSample: []float64{101}, Matched: true}
+	}
+	j.ComputeStatsForMetricSamples()
+	var report strings.Builder
+	j.WriteMarkdown(&report)
+	_, _, text := j.GitHubCheckConclusion()
+	length, kept := utf8.RuneCountInString(report.String()), text[:strings.LastIndex(text, "…")]
+	if length <= checkOutputTextLimit || utf8.RuneCountInString(text) > checkOutputTextLimit || !strings.HasPrefix(report.String(), kept) ||
+		!strings.HasSuffix(text, fmt.Sprintf("\n…%v more", length-utf8.RuneCountInString(kept))) {
+		t.Errorf("Report of %v characters truncated to %v:\n%v", length, utf8.RuneCountInString(text), text[len(text)-200:])
+	}
+}
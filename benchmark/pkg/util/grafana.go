@@ -0,0 +1,70 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// grafanaAnnotation is the payload of Grafana's annotation API (POST /api/annotations).
+type grafanaAnnotation struct {
+	Time int64    `json:"time"`
+	Tags []string `json:"tags"`
+	Text string   `json:"text"`
+}
+
+// metricName returns a human readable name of the metric, made of its key's non-empty fields.
+func metricName(key MetricKey) string {
+	var fields []string
+	for _, field := range append(key.fields(), key.Platform, key.SizeBucket, key.Population) {
+		if field != "" {
+			fields = append(fields, field)
+		}
+	}
+	return strings.Join(fields, " ")
+}
+
+// ToGrafanaAnnotations returns a JSON array of Grafana annotations (as expected by its annotation
+// API) marking the given build at dashboardTime (in milliseconds since the Unix epoch), one per
+// regressed (i.e. conclusively mismatched) metric, sorted by metric key. The text of each gives
+// the metric's name and the percent change of its average, and the tags name the build and test.
+func (j *JobComparisonData) ToGrafanaAnnotations(buildID string, dashboardTime int64) ([]byte, error) {
+	annotations := []grafanaAnnotation{}
+	for _, metricPair := range getMetricsSortedByKey(j) {
+		key, data := metricPair.metricKey, metricPair.metricData
+		if data.Matched || data.Inconclusive {
+			continue
+		}
+		annotations = append(annotations, grafanaAnnotation{
+			Time: dashboardTime,
+			Tags: []string{"benchmark-regression", "build:" + buildID, "test:" + key.TestName},
+			Text: fmt.Sprintf("Build %v: %v changed by %v", buildID, metricName(key), formatPercentChange(relativeChange(data.AvgL, data.AvgR))),
+		})
+	}
+	return json.Marshal(annotations)
+}
+
+// formatPercentChange formats a relative change as a signed percent ("?" if it's unknown).
+func formatPercentChange(change float64) string {
+	if math.IsNaN(change) || math.IsInf(change, 0) {
+		return "?"
+	}
+	return fmt.Sprintf("%+.1f%%", change*100)
+}
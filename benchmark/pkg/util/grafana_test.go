@@ -0,0 +1,76 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestToGrafanaAnnotations(t *testing.T) {
+	j := &JobComparisonData{
+		Data: map[MetricKey]*MetricComparisonData{
+			{TestName: "Load", Verb: "LIST", Resource: "pods", Scope: "cluster", Percentile: "Perc99"}: {
+				LeftJobSample:  []float64{100},
+				RightJobSample: []float64{150},
+			},
+			{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}: {
+				LeftJobSample:  []float64{100},
+				RightJobSample: []float64{100},
+				Matched:        true,
+			},
+			{TestName: "Density", Verb: "Pod-Startup", Percentile: "Perc50"}: {
+				LeftJobSample:  []float64{0},
+				RightJobSample: []float64{5},
+			},
+			{TestName: "Density", Verb: "Pod-Startup", Percentile: "Perc99"}: {
+				Inconclusive: true,
+			},
+		},
+	}
+	j.ComputeStatsForMetricSamples()
+
+	payload, err := j.ToGrafanaAnnotations("1234", 1791072000000)
+	if err != nil {
+		t.Fatalf("Creating Grafana annotations failed: %v", err)
+	}
+	var annotations []map[string]interface{}
+	if err := json.Unmarshal(payload, &annotations); err != nil {
+		t.Fatalf("Couldn't parse Grafana annotations %s: %v", payload, err)
+	}
+	expected := []map[string]interface{}{
+		{
+			"time": 1791072000000.0,
+			"tags": []interface{}{"benchmark-regression", "build:1234", "test:Density"},
+			"text": "Build 1234: Density Pod-Startup Perc50 changed by ?",
+		},
+		{
+			"time": 1791072000000.0,
+			"tags": []interface{}{"benchmark-regression", "build:1234", "test:Load"},
+			"text": "Build 1234: Load LIST pods cluster Perc99 changed by +50.0%",
+		},
+	}
+	if !reflect.DeepEqual(annotations, expected) {
+		t.Errorf("Grafana annotations mismatched:\nReal: %v\nExpected: %v", annotations, expected)
+	}
+
+	// Without regressions, the payload is an empty array.
+	if payload, err := NewJobComparisonData().ToGrafanaAnnotations("1234", 0); err != nil || string(payload) != "[]" {
+		t.Errorf("Expected an empty array of annotations, got %s (error: %v)", payload, err)
+	}
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"html/template"
+	"io"
+)
+
+var htmlReportTemplate = template.Must(template.New("report").Parse(`{{if .RunCounts}}<p>{{.RunCounts}}</p>
+{{end}}<table>
+<thead>
+<tr>{{range .Header}}<th>{{.}}</th>{{end}}</tr>
+</thead>
+<tbody>
+{{range .Rows}}<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{end}}</tbody>
+</table>
+`))
+
+// WriteHTML writes the job comparison data to w as an HTML table, sorted by metric key. Like
+// the Markdown one (see reportTable), but it always has the change column, with its confidence
+// interval if computed, and it's headed by the run counts of the jobs too.
+func (j *JobComparisonData) WriteHTML(w io.Writer) error {
+	header, rows := j.reportTable(true)
+	return htmlReportTemplate.Execute(w, struct {
+		RunCounts string
+		Header    []string
+		Rows      [][]string
+	}{j.runCountsHeader(), header, rows})
+}
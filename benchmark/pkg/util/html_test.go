@@ -0,0 +1,63 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"html"
+	"strings"
+	"testing"
+)
+
+func TestWriteHTML(t *testing.T) {
+	jobComparisonData := &JobComparisonData{
+		Data: map[MetricKey]*MetricComparisonData{
+			{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}: {
+				LeftJobSample:  []float64{90, 110},
+				RightJobSample: []float64{100, 120},
+				Comments:       "<b>noisy</b>",
+			},
+			{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}: {
+				LeftJobSample:  []float64{100},
+				RightJobSample: []float64{150},
+			},
+		},
+	}
+	jobComparisonData.ComputeStatsForMetricSamples()
+	jobComparisonData.Data[MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}].RelativeChangeCI(0.95)
+
+	var buf bytes.Buffer
+	if err := jobComparisonData.WriteWithOptions(&buf, HTMLFormat, WriteOptions{}); err != nil {
+		t.Fatalf("Writing the HTML output failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "<td>&lt;b&gt;noisy&lt;/b&gt;</td>") {
+		t.Errorf("HTML output doesn't escape the comments:\n%v", buf.String())
+	}
+	for _, expected := range []string{
+		"<th>Change</th><th>Comments</th>",
+		"<td>+10.0% (CI: -19.1% to +39.1%)</td>",
+		// The change without a confidence interval computed is still shown.
+		"<td>+50.0%</td><td></td>",
+	} {
+		if !strings.Contains(html.UnescapeString(buf.String()), expected) {
+			t.Errorf("HTML output doesn't contain %q:\n%v", expected, buf.String())
+		}
+	}
+	if contentType := contentTypeForFormat(HTMLFormat); contentType != "text/html; charset=utf-8" {
+		t.Errorf("HTML content type is %v", contentType)
+	}
+}
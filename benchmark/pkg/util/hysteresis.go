@@ -0,0 +1,96 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// hysteresisState is the previous verdict of a metric, for its ratio to be compared with
+// hysteresis (see SetHysteresis).
+type hysteresisState struct {
+	margin            float64 // Relative margin of the band around the ratio bounds, 0 if unset
+	previouslyMatched bool
+	held              bool // Whether the band held the previous verdict, which the bounds wouldn't
+}
+
+// SetHysteresis makes the ratio schemes (like Avg-Test, see WithinRatioBounds) compare the
+// metrics with hysteresis around their ratio bounds, using their verdicts in the previous
+// comparison (e.g. read with LoadJSONReport), so that a metric hovering right at a bound (e.g.
+// at a 1.10 ratio) doesn't flap between matched and mismatched from run to run. The hysteresis
+// band of a bound B is [B/(1+margin), B*(1+margin)], i.e. with a margin of 0.02, a ratio bound
+// of 1.10 has a band of about [1.078, 1.122]:
+//   - A metric matched in the previous comparison is only newly flagged once its ratio is out
+//     of the band, i.e. exceeds the bound by the margin.
+//   - A metric mismatched in the previous comparison is only cleared once its ratio is within
+//     the bound by the margin, i.e. is back below the band.
+//
+// Within the band, a metric keeps its prior state. Metrics not in the previous comparison, or
+// inconclusive in it, are compared against the bounds as they are, and so are all the metrics
+// if the margin isn't positive. It should be set before comparing the metrics.
+func (j *JobComparisonData) SetHysteresis(previous *JobComparisonData, margin float64) {
+	for metricKey, metricData := range j.Data {
+		metricData.hysteresis = hysteresisState{}
+		previousData, ok := previous.Data[metricKey]
+		if margin > 0 && ok && !previousData.Inconclusive {
+			metricData.hysteresis = hysteresisState{margin: margin, previouslyMatched: previousData.Matched}
+		}
+	}
+}
+
+// WithinRatioBounds tells if the ratio (of a left and right stat of the metric) is within
+// [lowerBound, 1/lowerBound], the bounds of the ratio schemes, or within the hysteresis band
+// around them if the metric has a previous verdict to keep (see SetHysteresis). It's for the
+// ratio schemes (in pkg/comparer/schemes) to decide their verdict with.
+func (d *MetricComparisonData) WithinRatioBounds(ratio, lowerBound float64) bool {
+	within := lowerBound <= ratio && ratio <= 1/lowerBound
+	if d.hysteresis.margin <= 0 {
+		return within
+	}
+	// The bounds are widened by the margin for a matched metric, and narrowed for a mismatched
+	// one, though not past each other (for bounds close to 1), which would leave no ratio within.
+	bandLowerBound := lowerBound / (1 + d.hysteresis.margin)
+	if !d.hysteresis.previouslyMatched {
+		bandLowerBound = math.Min(lowerBound*(1+d.hysteresis.margin), 1)
+	}
+	withinBand := bandLowerBound <= ratio && ratio <= 1/bandLowerBound
+	d.hysteresis.held = withinBand != within
+	return withinBand
+}
+
+// noteHysteresis notes in their comments which metrics kept their previous verdict only due to
+// the hysteresis band.
+func (j *JobComparisonData) noteHysteresis() {
+	for _, metricData := range j.Data {
+		state := metricData.hysteresis
+		if state.held && !metricData.Inconclusive && metricData.Matched == state.previouslyMatched {
+			metricData.Comments += fmt.Sprintf("\tHysteresis kept the previous verdict (margin %.1f%%)", 100*state.margin)
+		}
+	}
+}
+
+// WithHysteresis returns the comparison scheme noting in the comments of the metrics (after
+// comparing them) if they kept their previous verdict only due to the hysteresis band (see
+// SetHysteresis), i.e. if their ratio was within it but on the other side of the bound.
+func WithHysteresis(scheme ContextComparisonScheme) ContextComparisonScheme {
+	return func(ctx context.Context, j *JobComparisonData, matchThreshold, minMetricAvgForCompare float64) {
+		scheme(ctx, j, matchThreshold, minMetricAvgForCompare)
+		j.noteHysteresis()
+	}
+}
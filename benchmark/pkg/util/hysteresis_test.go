@@ -0,0 +1,98 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestWithinRatioBounds(t *testing.T) {
+	lowerBound := 1 / 1.1
+	testCases := []struct {
+		ratio             float64 // Of the right stat to the left one, i.e. the inverse of the L/R ratio compared
+		hasPrevious       bool
+		previouslyMatched bool
+		expected          bool
+	}{
+		{ratio: 1.11, expected: false},
+		{ratio: 1.09, expected: true},
+		// A matched metric is only flagged once it exceeds the bound by the margin (1.122)...
+		{ratio: 1.11, hasPrevious: true, previouslyMatched: true, expected: true},
+		{ratio: 1.13, hasPrevious: true, previouslyMatched: true, expected: false},
+		// ...and the same goes for the lower bound, i.e. improvements.
+		{ratio: 1 / 1.11, hasPrevious: true, previouslyMatched: true, expected: true},
+		// A mismatched one is only cleared once it's within the bound by the margin (1.078).
+		{ratio: 1.09, hasPrevious: true, previouslyMatched: false, expected: false},
+		{ratio: 1.11, hasPrevious: true, previouslyMatched: false, expected: false},
+		{ratio: 1.07, hasPrevious: true, previouslyMatched: false, expected: true},
+	}
+	for _, testCase := range testCases {
+		d := &MetricComparisonData{}
+		if testCase.hasPrevious {
+			d.hysteresis = hysteresisState{margin: 0.02, previouslyMatched: testCase.previouslyMatched}
+		}
+		if within := d.WithinRatioBounds(1/testCase.ratio, lowerBound); within != testCase.expected {
+			t.Errorf("Ratio %v (previous verdict: %v, matched: %v) within the bounds: %v, but expected %v", testCase.ratio, testCase.hasPrevious, testCase.previouslyMatched, within, testCase.expected)
+		}
+	}
+
+	// With a bound close to 1, narrowing it by the margin would leave no ratio within, so a
+	// mismatched metric is still cleared once it's unchanged.
+	d := &MetricComparisonData{hysteresis: hysteresisState{margin: 0.02, previouslyMatched: false}}
+	if !d.WithinRatioBounds(1, 0.99) || d.WithinRatioBounds(1.001, 0.99) {
+		t.Errorf("Mismatched metric isn't cleared exactly at a ratio of 1 with a bound of 0.99")
+	}
+}
+
+func TestSetHysteresis(t *testing.T) {
+	borderline := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	inconclusive := MetricKey{TestName: "Load", Verb: "PUT", Resource: "pods", Percentile: "Perc99"}
+	newMetric := MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}
+	previous := NewJobComparisonData()
+	previous.Data[borderline] = &MetricComparisonData{Matched: true}
+	previous.Data[inconclusive] = &MetricComparisonData{Matched: true, Inconclusive: true}
+	j := NewJobComparisonData()
+	for _, key := range []MetricKey{borderline, inconclusive, newMetric} {
+		j.Data[key] = &MetricComparisonData{LeftJobSample: []float64{100, 100}, RightJobSample: []float64{111, 111}}
+	}
+
+	// The borderline metric was matched in the previous comparison, and stays so within the band.
+	policy := &Policy{Rules: []PolicyRule{{Statistic: StatisticMean, Threshold: 1 / 1.1}}}
+	j.SetHysteresis(previous, 0.02)
+	if err := j.ApplyPolicyWithContext(context.Background(), policy); err != nil {
+		t.Fatalf("Applying the policy failed: %v", err)
+	}
+	if data := j.Data[borderline]; !data.Matched || !strings.Contains(data.Comments, "Hysteresis kept the previous verdict (margin 2.0%)") {
+		t.Errorf("Borderline metric compared as %+v, but expected it to stay matched", data)
+	}
+	for _, key := range []MetricKey{inconclusive, newMetric} {
+		if data := j.Data[key]; data.Matched || strings.Contains(data.Comments, "Hysteresis") {
+			t.Errorf("Metric %v without a previous verdict compared as %+v, but expected it mismatched", key, data)
+		}
+	}
+
+	// Without a margin, there's no hysteresis.
+	j.SetHysteresis(previous, 0)
+	if err := j.ApplyPolicyWithContext(context.Background(), policy); err != nil {
+		t.Fatalf("Applying the policy failed: %v", err)
+	}
+	if data := j.Data[borderline]; data.Matched {
+		t.Errorf("Borderline metric compared without a margin as %+v, but expected it mismatched", data)
+	}
+}
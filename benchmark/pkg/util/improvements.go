@@ -0,0 +1,79 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"math"
+)
+
+// improved tells if the metric mismatched for the better, i.e. its avg went down, or up for
+// rates. Like regressed, it follows the verdict of the scheme, so it's only set for a change
+// the scheme found significant (in whichever direction the scheme is sensitive to).
+func (d *MetricComparisonData) improved() bool {
+	if d.Matched || d.Inconclusive {
+		return false
+	}
+	if d.IsRate {
+		return d.AvgR > d.AvgL
+	}
+	return d.AvgR < d.AvgL
+}
+
+// RegressedCount returns the number of metrics that regressed, i.e. mismatched for the worse.
+func (j *JobComparisonData) RegressedCount() int {
+	count := 0
+	for _, metricData := range j.Data {
+		if metricData.regressed() {
+			count++
+		}
+	}
+	return count
+}
+
+// ImprovedCount returns the number of metrics that improved, i.e. mismatched for the better,
+// the right job being significantly faster (or of a higher rate) than the left one.
+func (j *JobComparisonData) ImprovedCount() int {
+	count := 0
+	for _, metricData := range j.Data {
+		if metricData.improved() {
+			count++
+		}
+	}
+	return count
+}
+
+// ImprovementRatio returns the fraction of the metrics that improved (NaN if there are none).
+func (j *JobComparisonData) ImprovementRatio() float64 {
+	if len(j.Data) == 0 {
+		return math.NaN()
+	}
+	return float64(j.ImprovedCount()) / float64(len(j.Data))
+}
+
+// verdictSummary returns a line counting the regressed and improved metrics, for the report to
+// give the good news along with the bad.
+func (j *JobComparisonData) verdictSummary() string {
+	regressed, improved := j.RegressedCount(), j.ImprovedCount()
+	fraction := func(count int) string {
+		if len(j.Data) == 0 {
+			return "-"
+		}
+		return formatPercent(100 * float64(count) / float64(len(j.Data)))
+	}
+	return fmt.Sprintf("%v of %v metrics regressed (%v), %v improved (%v)", regressed, len(j.Data), fraction(regressed), improved, fraction(improved))
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestImprovedCount(t *testing.T) {
+	regression := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	improvement := MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}
+	rateImprovement := MetricKey{TestName: "Throughput", Verb: "POST", Resource: "pods", Percentile: "Perc50"}
+	inconclusive := MetricKey{TestName: "Load", Verb: "PUT", Resource: "pods", Percentile: "Perc99"}
+	matched := MetricKey{TestName: "Load", Verb: "DELETE", Resource: "pods", Percentile: "Perc99"}
+	j := &JobComparisonData{
+		Data: map[MetricKey]*MetricComparisonData{
+			regression:      {LeftJobSample: []float64{100}, RightJobSample: []float64{150}},
+			improvement:     {LeftJobSample: []float64{100}, RightJobSample: []float64{50}},
+			rateImprovement: {LeftJobSample: []float64{15}, RightJobSample: []float64{20}, IsRate: true},
+			inconclusive:    {LeftJobSample: []float64{100}, RightJobSample: []float64{20}},
+			matched:         {LeftJobSample: []float64{100}, RightJobSample: []float64{99}},
+		},
+	}
+	j.ComputeStatsForMetricSamples()
+	for _, metricData := range j.Data {
+		metricData.ResetVerdict()
+	}
+	j.Data[inconclusive].MarkInconclusive("timed out")
+	j.Data[matched].Matched = true
+
+	if count := j.ImprovedCount(); count != 2 {
+		t.Errorf("Improved count is %v, but expected 2", count)
+	}
+	if count := j.RegressedCount(); count != 1 {
+		t.Errorf("Regressed count is %v, but expected 1", count)
+	}
+	if ratio := j.ImprovementRatio(); ratio != 0.4 {
+		t.Errorf("Improvement ratio is %v, but expected 0.4", ratio)
+	}
+	table := j.formatTable(PrettyPrintOptions{})
+	if !strings.HasSuffix(table, "1 of 5 metrics regressed (20.0%), 2 improved (40.0%)\n") {
+		t.Errorf("Table lacks the summary of regressions and improvements:\n%v", table)
+	}
+
+	if ratio := (&JobComparisonData{}).ImprovementRatio(); !math.IsNaN(ratio) {
+		t.Errorf("Improvement ratio without metrics is %v, but expected NaN", ratio)
+	}
+}
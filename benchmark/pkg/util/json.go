@@ -0,0 +1,288 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"sort"
+)
+
+// jsonFloat is a float64 that is marshalled as per the NaN policy it carries when it isn't a
+// finite number, as JSON has no representation for NaN and infinities: as null by default.
+type jsonFloat struct {
+	value  float64
+	policy NaNPolicy
+}
+
+// newJSONFloat returns the value to marshal as per the policy, or nil if it's to be left out
+// (i.e. a non-finite value with NaNOmitted), for the fields holding it to be tagged omitempty.
+func newJSONFloat(value float64, policy NaNPolicy) *jsonFloat {
+	if policy == NaNOmitted && !isFinite(value) {
+		return nil
+	}
+	return &jsonFloat{value: value, policy: policy}
+}
+
+// float returns the value, NaN if it was left out (or null).
+func (f *jsonFloat) float() float64 {
+	if f == nil {
+		return math.NaN()
+	}
+	return f.value
+}
+
+func (f jsonFloat) MarshalJSON() ([]byte, error) {
+	if isFinite(f.value) {
+		return json.Marshal(f.value)
+	}
+	if f.policy == NaNAsString {
+		return json.Marshal(nonFiniteString(f.value))
+	}
+	return []byte("null"), nil
+}
+
+// UnmarshalJSON decodes a float written by MarshalJSON, or with any of the NaN policies: null
+// and "NaN" are NaN, and the strings "+Inf" and "-Inf" are the infinities.
+func (f *jsonFloat) UnmarshalJSON(data []byte) error {
+	switch string(bytes.TrimSpace(data)) {
+	case "null", `"NaN"`:
+		f.value = math.NaN()
+	case `"+Inf"`:
+		f.value = math.Inf(1)
+	case `"-Inf"`:
+		f.value = math.Inf(-1)
+	default:
+		var value float64
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+		f.value = value
+	}
+	return nil
+}
+
+// metricRecord is the JSON representation of a single metric's comparison.
+type metricRecord struct {
+	TestName     string     `json:"testName"`
+	Verb         string     `json:"verb"`
+	Resource     string     `json:"resource,omitempty"`
+	Subresource  string     `json:"subresource,omitempty"`
+	Scope        string     `json:"scope,omitempty"`
+	Percentile   string     `json:"percentile"`
+	Platform     string     `json:"platform,omitempty"`
+	SizeBucket   string     `json:"sizeBucket,omitempty"`
+	Population   string     `json:"population,omitempty"`
+	Unit         string     `json:"unit,omitempty"`
+	Matched      bool       `json:"matched"`
+	Inconclusive bool       `json:"inconclusive,omitempty"`
+	Comments     string     `json:"comments,omitempty"`
+	AvgL         *jsonFloat `json:"avgL,omitempty"`
+	AvgR         *jsonFloat `json:"avgR,omitempty"`
+	AvgRatio     *jsonFloat `json:"avgRatio,omitempty"`
+	StDevL       *jsonFloat `json:"stDevL,omitempty"`
+	StDevR       *jsonFloat `json:"stDevR,omitempty"`
+	MaxL         *jsonFloat `json:"maxL,omitempty"`
+	MaxR         *jsonFloat `json:"maxR,omitempty"`
+	MaxRatio     *jsonFloat `json:"maxRatio,omitempty"`
+	MADL         *jsonFloat `json:"madL,omitempty"`
+	MADR         *jsonFloat `json:"madR,omitempty"`
+	QnL          *jsonFloat `json:"qnL,omitempty"`
+	QnR          *jsonFloat `json:"qnR,omitempty"`
+	GlassDelta   *jsonFloat `json:"glassDelta,omitempty"`
+	CDFArea      *jsonFloat `json:"cdfArea,omitempty"`
+	SNR          *jsonFloat `json:"snr,omitempty"`
+	N1           int        `json:"n1"`
+	N2           int        `json:"n2"`
+	Tier         Tier       `json:"tier"`
+	Owner        string     `json:"owner,omitempty"`
+	Transform    Transform  `json:"transform,omitempty"`
+
+	NegativeSampleCount  int `json:"negativeSampleCount,omitempty"`
+	SustainedRegressions int `json:"sustainedRegressions,omitempty"`
+
+	// Fields below are only filled in for rates.
+	Rate          bool       `json:"rate,omitempty"`
+	HarmonicMeanL *jsonFloat `json:"harmonicMeanL,omitempty"`
+	HarmonicMeanR *jsonFloat `json:"harmonicMeanR,omitempty"`
+
+	// Fields below are only filled in verbose mode.
+	LeftJobSample  []float64         `json:"leftJobSample,omitempty"`
+	RightJobSample []float64         `json:"rightJobSample,omitempty"`
+	Labels         map[string]string `json:"labels,omitempty"`
+}
+
+// metricKeyLess orders metric keys field by field, to give a stable output order. The size
+// buckets come before the percentiles, keeping each bucket's percentiles together, and are
+// ordered by size rather than lexicographically.
+func metricKeyLess(a, b MetricKey) bool {
+	if a.TestName != b.TestName {
+		return a.TestName < b.TestName
+	}
+	if a.Verb != b.Verb {
+		return a.Verb < b.Verb
+	}
+	if a.Resource != b.Resource {
+		return a.Resource < b.Resource
+	}
+	if a.Subresource != b.Subresource {
+		return a.Subresource < b.Subresource
+	}
+	if a.Scope != b.Scope {
+		return a.Scope < b.Scope
+	}
+	if a.SizeBucket != b.SizeBucket {
+		return sizeBucketLess(a.SizeBucket, b.SizeBucket)
+	}
+	// The rollup of the sub-populations (without a Population) comes before them.
+	if a.Population != b.Population {
+		return a.Population < b.Population
+	}
+	if a.Percentile != b.Percentile {
+		return a.Percentile < b.Percentile
+	}
+	return a.Platform < b.Platform
+}
+
+func getMetricsSortedByKey(j *JobComparisonData) metricKeyDataPairList {
+	metricsList := make(metricKeyDataPairList, 0, len(j.Data))
+	for metricKey, metricData := range j.Data {
+		metricsList = append(metricsList, metricKeyDataPair{metricKey, metricData})
+	}
+	sort.Slice(metricsList, func(a, b int) bool {
+		return metricKeyLess(metricsList[a].metricKey, metricsList[b].metricKey)
+	})
+	return metricsList
+}
+
+// newMetricRecord returns the record of the metric, with its stats to be marshalled as per the policy.
+func newMetricRecord(key MetricKey, data *MetricComparisonData, verbose bool, policy NaNPolicy) metricRecord {
+	record := metricRecord{
+		TestName:     key.TestName,
+		Verb:         key.Verb,
+		Resource:     key.Resource,
+		Subresource:  key.Subresource,
+		Scope:        key.Scope,
+		Percentile:   key.Percentile,
+		Platform:     key.Platform,
+		SizeBucket:   key.SizeBucket,
+		Population:   key.Population,
+		Unit:         data.Unit,
+		Matched:      data.Matched,
+		Inconclusive: data.Inconclusive,
+		Comments:     data.Comments,
+		AvgL:         newJSONFloat(data.AvgL, policy),
+		AvgR:         newJSONFloat(data.AvgR, policy),
+		AvgRatio:     newJSONFloat(data.AvgRatio, policy),
+		StDevL:       newJSONFloat(data.StDevL, policy),
+		StDevR:       newJSONFloat(data.StDevR, policy),
+		MaxL:         newJSONFloat(data.MaxL, policy),
+		MaxR:         newJSONFloat(data.MaxR, policy),
+		MaxRatio:     newJSONFloat(data.MaxRatio, policy),
+		MADL:         newJSONFloat(data.MADL, policy),
+		MADR:         newJSONFloat(data.MADR, policy),
+		QnL:          newJSONFloat(data.QnL, policy),
+		QnR:          newJSONFloat(data.QnR, policy),
+		GlassDelta:   newJSONFloat(data.GlassDelta, policy),
+		CDFArea:      newJSONFloat(data.CDFArea, policy),
+		SNR:          newJSONFloat(data.SNR, policy),
+		N1:           len(data.LeftJobSample),
+		N2:           len(data.RightJobSample),
+		Tier:         data.Tier,
+		Owner:        data.Owner,
+		Transform:    data.Transform,
+
+		NegativeSampleCount:  data.NegativeSampleCount,
+		SustainedRegressions: data.SustainedRegressionCount,
+	}
+	if data.IsRate {
+		record.Rate = true
+		record.HarmonicMeanL, record.HarmonicMeanR = newJSONFloat(data.HarmonicMeanL, policy), newJSONFloat(data.HarmonicMeanR, policy)
+	}
+	if verbose {
+		record.LeftJobSample = data.LeftJobSample
+		record.RightJobSample = data.RightJobSample
+		record.Labels = data.Labels
+	}
+	return record
+}
+
+func (j *JobComparisonData) metricRecords(verbose bool, policy NaNPolicy) []metricRecord {
+	records := make([]metricRecord, 0, len(j.Data))
+	for _, metricPair := range getMetricsSortedByKey(j) {
+		records = append(records, newMetricRecord(metricPair.metricKey, metricPair.metricData, verbose, policy))
+	}
+	return records
+}
+
+// WriteJSON writes the job comparison data to w as a JSON array of per-metric records,
+// sorted by metric key. In verbose mode, the records also carry the raw samples and
+// the labels retained while flattening (if any).
+func (j *JobComparisonData) WriteJSON(w io.Writer, verbose bool) error {
+	return j.writeJSON(w, verbose, true, NaNDefault)
+}
+
+// WriteJSONWithNaNPolicy is like WriteJSON, but represents the non-finite stats as per the
+// policy (see NaNPolicy), rather than as null.
+func (j *JobComparisonData) WriteJSONWithNaNPolicy(w io.Writer, verbose bool, policy NaNPolicy) error {
+	return j.writeJSON(w, verbose, true, policy)
+}
+
+// PrintJSON writes the (non-verbose) job comparison data to stdout in the format of WriteJSON,
+// e.g. for piping to jq. Without indentation, the whole array goes on a single line.
+func (j *JobComparisonData) PrintJSON(indent bool) error {
+	return j.writeJSON(os.Stdout, false, indent, NaNDefault)
+}
+
+func (j *JobComparisonData) writeJSON(w io.Writer, verbose, indent bool, policy NaNPolicy) error {
+	return writeIndentedJSON(w, j.metricRecords(verbose, policy), indent)
+}
+
+// minimalMetricRecord is the minimal JSON representation of a mismatched metric.
+type minimalMetricRecord struct {
+	Metric  string    `json:"metric"`            // Name of the metric (see metricName)
+	Change  jsonFloat `json:"change"`            // Percent change of the avg
+	Comment string    `json:"comment,omitempty"` // Comments of the comparison
+}
+
+// ToMinimalJSON returns the smallest JSON array describing the mismatched metrics, sorted by
+// metric key, with just the name, percent change of the avg and comments of each, e.g. for
+// webhook payloads with size limits (and without the full set of metrics). The jobs should
+// have been compared with a scheme already, and an error is returned if the samples changed
+// since (as per StatsDirty).
+func (j *JobComparisonData) ToMinimalJSON() ([]byte, error) {
+	if j.StatsDirty() {
+		return nil, errors.New("samples changed since the jobs were compared")
+	}
+	records := []minimalMetricRecord{}
+	for _, metricPair := range getMetricsSortedByKey(j) {
+		key, data := metricPair.metricKey, metricPair.metricData
+		if data.Matched {
+			continue
+		}
+		records = append(records, minimalMetricRecord{
+			Metric:  metricName(key),
+			Change:  jsonFloat{value: 100 * relativeChange(data.AvgL, data.AvgR)},
+			Comment: data.Comments,
+		})
+	}
+	return json.Marshal(records)
+}
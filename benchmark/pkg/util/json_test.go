@@ -0,0 +1,160 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/kubernetes/test/e2e/perftype"
+)
+
+func TestWriteJSONWithLabels(t *testing.T) {
+	jobMetrics := []map[string][]perftype.PerfData{
+		{
+			"Load": []perftype.PerfData{
+				{
+					Version: "v1",
+					DataItems: []perftype.DataItem{
+						{
+							Data: map[string]float64{"Perc99": 15},
+							Unit: "ms",
+							Labels: map[string]string{
+								"Count":     "10",
+								"Resource":  "pods",
+								"Verb":      "LIST",
+								"Namespace": "test-ns",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	metricKey := MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}
+	expectedLabels := map[string]string{"Count": "10", "Resource": "pods", "Verb": "LIST", "Namespace": "test-ns"}
+
+	// Labels shouldn't be retained unless asked for.
+	j := GetFlattennedComparisonData(jobMetrics, jobMetrics, 10)
+	if j.Data[metricKey].Labels != nil {
+		t.Errorf("Labels retained without KeepLabels: %v", j.Data[metricKey].Labels)
+	}
+
+	j = GetFlattennedComparisonDataWithOptions(jobMetrics, jobMetrics, FlattenOptions{MinAllowedAPIRequestCount: 10, KeepLabels: true})
+	if !reflect.DeepEqual(j.Data[metricKey].Labels, expectedLabels) {
+		t.Errorf("Retained labels mismatched:\nReal: %v\nExpected: %v", j.Data[metricKey].Labels, expectedLabels)
+	}
+	j.ComputeStatsForMetricSamples()
+
+	for _, verbose := range []bool{false, true} {
+		var buf bytes.Buffer
+		if err := j.WriteJSON(&buf, verbose); err != nil {
+			t.Fatalf("WriteJSON(verbose=%v) failed: %v", verbose, err)
+		}
+		var records []metricRecord
+		if err := json.Unmarshal(buf.Bytes(), &records); err != nil {
+			t.Fatalf("Couldn't parse JSON output %q: %v", buf.String(), err)
+		}
+		if len(records) != 1 {
+			t.Fatalf("Expected 1 record, got %v", len(records))
+		}
+		if verbose && !reflect.DeepEqual(records[0].Labels, expectedLabels) {
+			t.Errorf("Verbose JSON labels mismatched:\nReal: %v\nExpected: %v", records[0].Labels, expectedLabels)
+		}
+		if records[0].MaxRatio.float() != 1 {
+			t.Errorf("JSON max ratio is %v, but expected 1", records[0].MaxRatio.float())
+		}
+		if !verbose && records[0].Labels != nil {
+			t.Errorf("Non-verbose JSON contains labels: %v", records[0].Labels)
+		}
+	}
+}
+
+func TestPrintJSON(t *testing.T) {
+	j := &JobComparisonData{
+		Data: map[MetricKey]*MetricComparisonData{
+			{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}: {LeftJobSample: []float64{10}, RightJobSample: []float64{20}},
+			{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}:  {LeftJobSample: []float64{10}, RightJobSample: []float64{10}},
+		},
+	}
+	j.ComputeStatsForMetricSamples()
+
+	for _, indent := range []bool{false, true} {
+		reader, writer, err := os.Pipe()
+		if err != nil {
+			t.Fatalf("Couldn't create pipe: %v", err)
+		}
+		stdout := os.Stdout
+		os.Stdout = writer
+		printErr := j.PrintJSON(indent)
+		os.Stdout = stdout
+		writer.Close()
+		output, err := ioutil.ReadAll(reader)
+		if err != nil {
+			t.Fatalf("Couldn't read printed JSON: %v", err)
+		}
+		if printErr != nil {
+			t.Fatalf("PrintJSON(indent=%v) failed: %v", indent, printErr)
+		}
+
+		var records []metricRecord
+		if err := json.Unmarshal(output, &records); err != nil {
+			t.Fatalf("Couldn't parse printed JSON %q: %v", output, err)
+		}
+		if len(records) != 2 || records[0].Verb != "GET" || records[1].Verb != "LIST" {
+			t.Errorf("Printed records aren't sorted by metric key: %+v", records)
+		}
+		if lines := strings.Count(strings.TrimSpace(string(output)), "\n") + 1; (lines == 1) == indent {
+			t.Errorf("PrintJSON(indent=%v) printed %v lines:\n%s", indent, lines, output)
+		}
+	}
+}
+
+func TestToMinimalJSON(t *testing.T) {
+	j := &JobComparisonData{
+		Data: map[MetricKey]*MetricComparisonData{
+			{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}:  {LeftJobSample: []float64{100}, RightJobSample: []float64{150}},
+			{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}: {LeftJobSample: []float64{100}, RightJobSample: []float64{101}},
+			{TestName: "Load", Verb: "PUT", Percentile: "Perc99"}:                    {LeftJobSample: []float64{0}, RightJobSample: []float64{10}},
+		},
+	}
+	j.ComputeStatsForMetricSamples()
+	for key, metricData := range j.Data {
+		metricData.Matched = key.Verb == "LIST"
+		metricData.Comments = "AvgL/R=0.67"
+	}
+
+	payload, err := j.ToMinimalJSON()
+	if err != nil {
+		t.Fatalf("ToMinimalJSON failed: %v", err)
+	}
+	// The matched metric is left out.
+	expected := `[{"metric":"Load GET pods Perc99","change":50,"comment":"AvgL/R=0.67"},{"metric":"Load PUT Perc99","change":null,"comment":"AvgL/R=0.67"}]`
+	if string(payload) != expected {
+		t.Errorf("Minimal JSON is %s, but expected %s", payload, expected)
+	}
+
+	j.AppendRuns(nil, nil, 10)
+	if _, err := j.ToMinimalJSON(); err == nil {
+		t.Errorf("Expected an error for samples changed since the comparison")
+	}
+}
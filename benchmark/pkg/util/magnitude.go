@@ -0,0 +1,54 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"github.com/golang/glog"
+)
+
+// IgnoreBelow drops the metrics whose averages are both below the floor minAvg, e.g. the
+// sub-microsecond ones, whose large percent changes on tiny values are noise that clutters
+// the reports. Unlike the schemes' min avg for comparison, which marks such metrics matched,
+// it removes them from the data altogether. Metrics present on one side only are kept. The
+// stats should have been computed already. It logs and returns the number of metrics dropped.
+func (j *JobComparisonData) IgnoreBelow(minAvg float64) int {
+	ignored := 0
+	for metricKey, metricData := range j.Data {
+		if metricData.AvgL < minAvg && metricData.AvgR < minAvg {
+			delete(j.Data, metricKey)
+			ignored++
+		}
+	}
+	glog.Infof("Ignored %v metrics with both averages below %v", ignored, minAvg)
+	return ignored
+}
+
+// PruneEmpty drops the metrics without any sample values on either side, e.g. as all of them
+// got filtered out, which would otherwise linger in the reports with NaN stats. It's up to the
+// callers, as some want to see that a metric got filtered out entirely. It logs and returns the
+// number of metrics dropped.
+func (j *JobComparisonData) PruneEmpty() int {
+	pruned := 0
+	for metricKey, metricData := range j.Data {
+		if len(metricData.LeftJobSample) == 0 && len(metricData.RightJobSample) == 0 {
+			delete(j.Data, metricKey)
+			pruned++
+		}
+	}
+	glog.Infof("Pruned %v metrics without any samples", pruned)
+	return pruned
+}
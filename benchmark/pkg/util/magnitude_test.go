@@ -0,0 +1,67 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+)
+
+func TestIgnoreBelow(t *testing.T) {
+	tiny := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc50"}
+	grownTiny := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc90"}
+	large := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	leftOnly := MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc50"}
+	j := &JobComparisonData{
+		Data: map[MetricKey]*MetricComparisonData{
+			tiny:      {LeftJobSample: []float64{0.1, 0.2}, RightJobSample: []float64{0.4}},
+			grownTiny: {LeftJobSample: []float64{0.5}, RightJobSample: []float64{5}},
+			large:     {LeftJobSample: []float64{100}, RightJobSample: []float64{120}},
+			leftOnly:  {LeftJobSample: []float64{0.1}},
+		},
+	}
+	j.ComputeStatsForMetricSamples()
+
+	if ignored := j.IgnoreBelow(1); ignored != 1 {
+		t.Errorf("Ignored %v metrics, but expected 1", ignored)
+	}
+	if _, ok := j.Data[tiny]; ok || len(j.Data) != 3 {
+		t.Errorf("Metrics left after ignoring those below the floor are %v, but expected all but %v", j.Data, tiny)
+	}
+}
+
+func TestPruneEmpty(t *testing.T) {
+	empty := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc50"}
+	leftOnly := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc90"}
+	rightOnly := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	j := &JobComparisonData{
+		Data: map[MetricKey]*MetricComparisonData{
+			empty:     {LeftJobSample: []float64{}},
+			leftOnly:  {LeftJobSample: []float64{10}},
+			rightOnly: {RightJobSample: []float64{10}},
+		},
+	}
+	j.ComputeStatsForMetricSamples()
+	if pruned := j.PruneEmpty(); pruned != 1 {
+		t.Errorf("Pruned %v metrics, but expected 1", pruned)
+	}
+	if _, ok := j.Data[empty]; ok || len(j.Data) != 2 {
+		t.Errorf("Metrics left after pruning: %v, but expected the ones with samples on a side", j.Data)
+	}
+	if pruned := j.PruneEmpty(); pruned != 0 {
+		t.Errorf("Pruned %v metrics again, but expected none", pruned)
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+)
+
+// RegressedMetric is a regressed metric's entry of the RegressionManifest.
+type RegressedMetric struct {
+	TestName    string `json:"testName"`
+	Verb        string `json:"verb"`
+	Resource    string `json:"resource,omitempty"`
+	Subresource string `json:"subresource,omitempty"`
+	Scope       string `json:"scope,omitempty"`
+	Percentile  string `json:"percentile"`
+	Platform    string `json:"platform,omitempty"`
+	SizeBucket  string `json:"sizeBucket,omitempty"`
+	Population  string `json:"population,omitempty"`
+	Unit        string `json:"unit,omitempty"`
+
+	AbsoluteDelta jsonFloat `json:"absoluteDelta"` // AvgR - AvgL
+	PercentDelta  jsonFloat `json:"percentDelta"`  // Relative change of the avg, in percents (null if it can't be computed)
+	// PValue is that of the comparison scheme's test, if based on one (null otherwise).
+	PValue jsonFloat `json:"pValue"`
+	SNR    jsonFloat `json:"snr"` // Signal-to-noise ratio of the change
+	N1     int       `json:"n1"`
+	N2     int       `json:"n2"`
+}
+
+// RegressionManifest lists the regressed metrics with the magnitude and significance of their
+// regressions, for automation (e.g. auto-bisection deciding whether to keep going) to consume.
+// Unlike the reports, it's meant to be stable rather than human readable.
+type RegressionManifest struct {
+	Regressed   bool              `json:"regressed"`
+	Regressions []RegressedMetric `json:"regressions"`
+}
+
+// regressed tells if the metric mismatched for the worse, i.e. its avg went up, or down for rates.
+func (d *MetricComparisonData) regressed() bool {
+	return !d.Matched && !d.Inconclusive && d.worsened()
+}
+
+// worsened tells if the metric's avg changed for the worse (up, or down for rates), whatever
+// the verdict.
+func (d *MetricComparisonData) worsened() bool {
+	if d.IsRate {
+		return d.AvgR < d.AvgL
+	}
+	return d.AvgR > d.AvgL
+}
+
+// RegressionManifest returns the manifest of the metrics that regressed, i.e. mismatched for
+// the worse (with their avg going up, or down for rates), sorted by metric key. Improvements
+// and inconclusive metrics are left out. The metrics should have been compared already.
+func (j *JobComparisonData) RegressionManifest() RegressionManifest {
+	manifest := RegressionManifest{Regressions: []RegressedMetric{}}
+	for _, metricPair := range getMetricsSortedByKey(j) {
+		key, data := metricPair.metricKey, metricPair.metricData
+		if !data.regressed() {
+			continue
+		}
+		regression := RegressedMetric{
+			TestName:      key.TestName,
+			Verb:          key.Verb,
+			Resource:      key.Resource,
+			Subresource:   key.Subresource,
+			Scope:         key.Scope,
+			Percentile:    key.Percentile,
+			Platform:      key.Platform,
+			SizeBucket:    key.SizeBucket,
+			Population:    key.Population,
+			Unit:          data.Unit,
+			AbsoluteDelta: jsonFloat{value: data.AvgR - data.AvgL},
+			PercentDelta:  jsonFloat{value: 100 * relativeChange(data.AvgL, data.AvgR)},
+			PValue:        jsonFloat{value: data.PValue},
+			SNR:           jsonFloat{value: data.SNR},
+			N1:            len(data.LeftJobSample),
+			N2:            len(data.RightJobSample),
+		}
+		if !data.HasPValue {
+			regression.PValue = jsonFloat{value: math.NaN()}
+		}
+		manifest.Regressions = append(manifest.Regressions, regression)
+	}
+	manifest.Regressed = len(manifest.Regressions) > 0
+	return manifest
+}
+
+// WriteRegressionManifest writes the regression manifest to w as JSON.
+func (j *JobComparisonData) WriteRegressionManifest(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(j.RegressionManifest())
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestRegressionManifest(t *testing.T) {
+	regression := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	improvement := MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}
+	rateRegression := MetricKey{TestName: "Throughput", Verb: "POST", Resource: "pods", Percentile: "Perc50"}
+	inconclusive := MetricKey{TestName: "Load", Verb: "PUT", Resource: "pods", Percentile: "Perc99"}
+	matched := MetricKey{TestName: "Load", Verb: "DELETE", Resource: "pods", Percentile: "Perc99"}
+	j := &JobComparisonData{
+		Data: map[MetricKey]*MetricComparisonData{
+			regression:     {LeftJobSample: []float64{90, 110}, RightJobSample: []float64{150}, Unit: "ms"},
+			improvement:    {LeftJobSample: []float64{100}, RightJobSample: []float64{50}},
+			rateRegression: {LeftJobSample: []float64{20}, RightJobSample: []float64{15}, IsRate: true},
+			inconclusive:   {LeftJobSample: []float64{100}, RightJobSample: []float64{200}},
+			matched:        {LeftJobSample: []float64{100}, RightJobSample: []float64{101}},
+		},
+	}
+	j.ComputeStatsForMetricSamples()
+	for _, metricData := range j.Data {
+		metricData.ResetVerdict()
+	}
+	j.Data[regression].PValue, j.Data[regression].HasPValue = 0.01, true
+	j.Data[inconclusive].MarkInconclusive("timed out")
+	j.Data[matched].Matched = true
+
+	manifest := j.RegressionManifest()
+	if !manifest.Regressed || len(manifest.Regressions) != 2 {
+		t.Fatalf("Manifest has regressions %+v, but expected those of %v and %v", manifest.Regressions, regression, rateRegression)
+	}
+	expected := RegressedMetric{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99", Unit: "ms", AbsoluteDelta: jsonFloat{value: 50}, PercentDelta: jsonFloat{value: 50}, PValue: jsonFloat{value: 0.01}, SNR: jsonFloat{value: 5}, N1: 2, N2: 1}
+	if !reflect.DeepEqual(manifest.Regressions[0], expected) {
+		t.Errorf("Manifest has regression %+v, but expected %+v", manifest.Regressions[0], expected)
+	}
+	// The rate regressed by going down, and has no p-value.
+	if rate := manifest.Regressions[1]; rate.TestName != "Throughput" || rate.PercentDelta.value != -25 || !math.IsNaN(rate.PValue.value) {
+		t.Errorf("Manifest has rate regression %+v, but expected one of -25%% without a p-value", rate)
+	}
+
+	var buf bytes.Buffer
+	if err := j.WriteWithOptions(&buf, RegressionManifestFormat, WriteOptions{}); err != nil {
+		t.Fatalf("Writing the manifest failed: %v", err)
+	}
+	var decoded struct {
+		Regressed   bool
+		Regressions []map[string]interface{}
+	}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("Couldn't decode the manifest: %v\n%v", err, buf.String())
+	}
+	if !decoded.Regressed || len(decoded.Regressions) != 2 || decoded.Regressions[0]["percentDelta"] != 50.0 || decoded.Regressions[1]["pValue"] != nil {
+		t.Errorf("Manifest written as:\n%v", buf.String())
+	}
+
+	// Without regressions, the list is still there, but empty.
+	delete(j.Data, regression)
+	delete(j.Data, rateRegression)
+	buf.Reset()
+	if err := j.WriteRegressionManifest(&buf); err != nil {
+		t.Fatalf("Writing the manifest failed: %v", err)
+	}
+	if expected := "{\n  \"regressed\": false,\n  \"regressions\": []\n}\n"; buf.String() != expected {
+		t.Errorf("Manifest without regressions written as %q, but expected %q", buf.String(), expected)
+	}
+}
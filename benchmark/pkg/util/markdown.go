@@ -0,0 +1,95 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+var markdownCellEscaper = strings.NewReplacer("|", `\|`, "\t", " ", "\n", " ")
+
+func formatMarkdownFloat(value float64) string {
+	if math.IsNaN(value) {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f", value)
+}
+
+// reportTableHeaders are the headers of the columns of the reports' table (see reportTable).
+var reportTableHeaders = map[string]string{
+	"testName": "E2E Test", "verb": "Verb", "resource": "Resource", "subresource": "Subresource", "scope": "Scope",
+	"sizeBucket": "Size", "population": "Population", "percentile": "Percentile", "matched": "Matched",
+	"avgL": "AvgL", "avgR": "AvgR", "avgRatio": "AvgL/R", "maxRatio": "MaxR/L", "change": "Change", "comments": "Comments",
+}
+
+// reportTable returns the header and rows of the table of the job comparison data written by
+// the reports, sorted by metric key. If any of the metrics is for a size bucket (see
+// FlattenOptions.SizeBucketLabel), it has a column for it, before the percentile's, and so does
+// it for the sub-populations (see FlattenOptions.PopulationLabel). If any has the confidence
+// interval of its change computed (see RelativeChangeCI), or if asked to, it has a column for
+// the change, before the comments.
+func (j *JobComparisonData) reportTable(withChange bool) (header []string, rows [][]string) {
+	metricsList := getMetricsSortedByKey(j)
+	hasSizeBuckets, hasPopulations := false, false
+	for _, metricPair := range metricsList {
+		hasSizeBuckets = hasSizeBuckets || metricPair.metricKey.SizeBucket != ""
+		hasPopulations = hasPopulations || metricPair.metricKey.Population != ""
+		withChange = withChange || metricPair.metricData.ChangeConfidence != 0
+	}
+	cols := []string{"testName", "verb", "resource", "subresource", "scope"}
+	if hasSizeBuckets {
+		cols = append(cols, "sizeBucket")
+	}
+	if hasPopulations {
+		cols = append(cols, "population")
+	}
+	cols = append(cols, "percentile", "matched", "avgL", "avgR", "avgRatio", "maxRatio")
+	if withChange {
+		cols = append(cols, "change")
+	}
+	cols = append(cols, "comments")
+	// The columns are all known, so there's no error.
+	_, rows, _ = j.table(cols, metricsList, &tableFormat{formatFloat: formatMarkdownFloat}, nil)
+	for _, name := range cols {
+		header = append(header, reportTableHeaders[name])
+	}
+	return header, rows
+}
+
+// WriteMarkdown writes the job comparison data to w as a Markdown table, sorted by metric key
+// (see reportTable for the optional columns). The table is headed by the run counts of the jobs
+// (see RunCounts), if any runs were flattened.
+func (j *JobComparisonData) WriteMarkdown(w io.Writer) error {
+	header, rows := j.reportTable(false)
+	bw := bufio.NewWriter(w)
+	if runCounts := j.runCountsHeader(); runCounts != "" {
+		fmt.Fprintf(bw, "**%v**\n\n", runCounts)
+	}
+	fmt.Fprintf(bw, "| %v |\n", strings.Join(header, " | "))
+	fmt.Fprintf(bw, "|%v\n", strings.Repeat("---|", len(header)))
+	for _, cells := range rows {
+		for i := range cells {
+			cells[i] = markdownCellEscaper.Replace(cells[i])
+		}
+		fmt.Fprintf(bw, "| %v |\n", strings.Join(cells, " | "))
+	}
+	return bw.Flush()
+}
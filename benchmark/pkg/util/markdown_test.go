@@ -0,0 +1,86 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestWriteMarkdown(t *testing.T) {
+	jobComparisonData := &JobComparisonData{
+		Data: map[MetricKey]*MetricComparisonData{
+			{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}: {
+				LeftJobSample:  []float64{10},
+				RightJobSample: []float64{20},
+				AvgRatio:       0.5,
+				Comments:       "a|b\tc",
+			},
+			{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}: {
+				LeftJobSample: []float64{10},
+				AvgRatio:      math.NaN(),
+			},
+		},
+	}
+	jobComparisonData.ComputeStatsForMetricSamples()
+
+	var buf bytes.Buffer
+	if err := jobComparisonData.WriteMarkdown(&buf); err != nil {
+		t.Fatalf("WriteMarkdown failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	expectedLines := []string{
+		"| E2E Test | Verb | Resource | Subresource | Scope | Percentile | Matched | AvgL | AvgR | AvgL/R | MaxR/L | Comments |",
+		"|---|---|---|---|---|---|---|---|---|---|---|---|",
+		`| Load | GET | pods |  |  | Perc99 | false | 10.00 | 20.00 | 0.50 | 2.00 | a\|b c |`,
+		"| Load | LIST | pods |  |  | Perc99 | false | 10.00 | - | - | - |  |",
+	}
+	if strings.Join(lines, "\n") != strings.Join(expectedLines, "\n") {
+		t.Errorf("Markdown output mismatched:\nReal:\n%v\nExpected:\n%v", buf.String(), strings.Join(expectedLines, "\n"))
+	}
+}
+
+func TestWriteMarkdownWithChangeCI(t *testing.T) {
+	jobComparisonData := &JobComparisonData{
+		Data: map[MetricKey]*MetricComparisonData{
+			{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}: {
+				LeftJobSample:  []float64{90, 110},
+				RightJobSample: []float64{100, 120},
+				Matched:        true,
+				AvgRatio:       1,
+			},
+		},
+	}
+	jobComparisonData.ComputeStatsForMetricSamples()
+	jobComparisonData.ComputeRelativeChangeCIs(0.95)
+
+	var buf bytes.Buffer
+	if err := jobComparisonData.WriteMarkdown(&buf); err != nil {
+		t.Fatalf("WriteMarkdown failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	expectedLines := []string{
+		"| E2E Test | Verb | Resource | Subresource | Scope | Percentile | Matched | AvgL | AvgR | AvgL/R | MaxR/L | Change | Comments |",
+		"|---|---|---|---|---|---|---|---|---|---|---|---|---|",
+		"| Load | GET | pods |  |  | Perc99 | true | 100.00 | 110.00 | 1.00 | 1.09 | +10.0% (CI: -19.1% to +39.1%) |  |",
+	}
+	if strings.Join(lines, "\n") != strings.Join(expectedLines, "\n") {
+		t.Errorf("Markdown output mismatched:\nReal:\n%v\nExpected:\n%v", buf.String(), strings.Join(expectedLines, "\n"))
+	}
+}
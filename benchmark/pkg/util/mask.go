@@ -0,0 +1,201 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DefaultMaskPlaceholder replaces the masked portions of the metric keys' fields, unless a
+// LabelMask gives its own placeholder.
+const DefaultMaskPlaceholder = "[masked]"
+
+// LabelMask tells which portions of the metric keys' fields to redact in the reports written,
+// e.g. for sharing them externally without the internal cluster names some labels encode. It
+// applies to the keys only when writing them out, the keys of the data itself being unchanged.
+type LabelMask struct {
+	// Pattern, if set, matches the portions of the fields to mask.
+	Pattern *regexp.Regexp
+	// Allowlist, if set, lists the field values to leave as they are. Without a pattern, the
+	// values not listed are masked whole.
+	Allowlist []string
+	// Fields lists the names of the MetricKey fields to mask (e.g. "TestName", "Resource"),
+	// all of them being masked if empty.
+	Fields []string
+	// Placeholder replaces the masked portions (DefaultMaskPlaceholder, if empty).
+	Placeholder string
+}
+
+// maskableKeyFields returns pointers to the key's fields, by name.
+func maskableKeyFields(key *MetricKey) map[string]*string {
+	return map[string]*string{
+		"TestName":    &key.TestName,
+		"Verb":        &key.Verb,
+		"Resource":    &key.Resource,
+		"Subresource": &key.Subresource,
+		"Scope":       &key.Scope,
+		"Percentile":  &key.Percentile,
+		"Platform":    &key.Platform,
+		"SizeBucket":  &key.SizeBucket,
+		"Population":  &key.Population,
+	}
+}
+
+// Validate tells if the mask is usable, i.e. all its fields are MetricKey fields.
+func (m *LabelMask) Validate() error {
+	keyFields := maskableKeyFields(&MetricKey{})
+	for _, field := range m.Fields {
+		if _, ok := keyFields[field]; !ok {
+			return fmt.Errorf("unknown metric key field '%v'", field)
+		}
+	}
+	return nil
+}
+
+func (m *LabelMask) placeholder() string {
+	if m.Placeholder == "" {
+		return DefaultMaskPlaceholder
+	}
+	return m.Placeholder
+}
+
+func (m *LabelMask) masksField(field string) bool {
+	if len(m.Fields) == 0 {
+		return true
+	}
+	for _, maskedField := range m.Fields {
+		if maskedField == field {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *LabelMask) maskValue(value string) string {
+	if value == "" {
+		return value
+	}
+	for _, allowed := range m.Allowlist {
+		if value == allowed {
+			return value
+		}
+	}
+	if m.Pattern != nil {
+		return m.Pattern.ReplaceAllLiteralString(value, m.placeholder())
+	}
+	if m.Allowlist != nil {
+		return m.placeholder()
+	}
+	return value
+}
+
+// Apply returns the key with its fields masked. A nil mask leaves the key as it is.
+func (m *LabelMask) Apply(key MetricKey) MetricKey {
+	if m == nil {
+		return key
+	}
+	for field, value := range maskableKeyFields(&key) {
+		if m.masksField(field) {
+			*value = m.maskValue(*value)
+		}
+	}
+	return key
+}
+
+// redactor returns the replacer of the values the mask redacts in the metric keys of the data
+// (including those of its warnings and drop log) with their masked values, for masking them in
+// free text too, e.g. the comments, which can embed metric names (see metricName). The longer
+// values are replaced first, so that a value containing another one is masked whole.
+func (m *LabelMask) redactor(j *JobComparisonData) *strings.Replacer {
+	maskedValues := make(map[string]string)
+	addKey := func(key MetricKey) {
+		for field, value := range maskableKeyFields(&key) {
+			if !m.masksField(field) {
+				continue
+			}
+			if maskedValue := m.maskValue(*value); maskedValue != *value {
+				maskedValues[*value] = maskedValue
+			}
+		}
+	}
+	for key := range j.Data {
+		addKey(key)
+	}
+	for _, warning := range j.warnings {
+		addKey(warning.Metric)
+	}
+	for _, record := range j.dropLog {
+		addKey(record.Metric)
+	}
+	values := make([]string, 0, len(maskedValues))
+	for value := range maskedValues {
+		values = append(values, value)
+	}
+	sort.Slice(values, func(a, b int) bool {
+		if len(values[a]) != len(values[b]) {
+			return len(values[a]) > len(values[b])
+		}
+		return values[a] < values[b]
+	})
+	var pairs []string
+	for _, value := range values {
+		pairs = append(pairs, value, maskedValues[value])
+	}
+	return strings.NewReplacer(pairs...)
+}
+
+// Masked returns a copy of the job comparison data with the metric keys masked, for writing it
+// out with any of the writers (e.g. WriteJSON or ToGrafanaAnnotations). Metrics whose keys become
+// the same get a numeric suffix on their test name, so that none of them is lost. The values
+// masked in the keys are masked in the metrics' comments, the warnings and the drop log too,
+// while the metrics' Labels (holding the raw label values) are left out. A nil mask returns
+// the data as it is.
+func (j *JobComparisonData) Masked(mask *LabelMask) *JobComparisonData {
+	if mask == nil {
+		return j
+	}
+	redactor := mask.redactor(j)
+	masked := *j
+	masked.Data = make(map[MetricKey]*MetricComparisonData, len(j.Data))
+	// Going through the metrics in order keeps the suffixes stable across calls.
+	for _, metricPair := range getMetricsSortedByKey(j) {
+		maskedKey := mask.Apply(metricPair.metricKey)
+		for i, testName := 2, maskedKey.TestName; masked.Data[maskedKey] != nil; i++ {
+			maskedKey.TestName = fmt.Sprintf("%v#%v", testName, i)
+		}
+		maskedData := *metricPair.metricData
+		maskedData.Comments = redactor.Replace(maskedData.Comments)
+		maskedData.Labels = nil
+		masked.Data[maskedKey] = &maskedData
+	}
+	masked.warnings = nil
+	for _, warning := range j.warnings {
+		warning.Metric = mask.Apply(warning.Metric)
+		warning.Message = redactor.Replace(warning.Message)
+		masked.warnings = append(masked.warnings, warning)
+	}
+	masked.dropLog = nil
+	for _, record := range j.dropLog {
+		record.Metric = mask.Apply(record.Metric)
+		record.Detail = redactor.Replace(record.Detail)
+		masked.dropLog = append(masked.dropLog, record)
+	}
+	return &masked
+}
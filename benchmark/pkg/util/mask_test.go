@@ -0,0 +1,154 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestLabelMask(t *testing.T) {
+	key := MetricKey{TestName: "Load-prod-cluster-7", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	for _, test := range []struct {
+		name     string
+		mask     *LabelMask
+		expected MetricKey
+	}{
+		{"Nil", nil, key},
+		{
+			"Pattern",
+			&LabelMask{Pattern: regexp.MustCompile(`prod-cluster-[0-9]+`)},
+			MetricKey{TestName: "Load-[masked]", Verb: "GET", Resource: "pods", Percentile: "Perc99"},
+		},
+		{
+			"AllowlistOfFields",
+			&LabelMask{Allowlist: []string{"GET", "pods"}, Fields: []string{"TestName", "Verb", "Resource"}, Placeholder: "***"},
+			MetricKey{TestName: "***", Verb: "GET", Resource: "pods", Percentile: "Perc99"},
+		},
+		{
+			"PatternWithAllowlist",
+			&LabelMask{Pattern: regexp.MustCompile(`[a-z]+`), Allowlist: []string{"pods"}, Fields: []string{"Verb", "Resource"}},
+			key,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if masked := test.mask.Apply(key); masked != test.expected {
+				t.Errorf("Masked key is %+v, but expected %+v", masked, test.expected)
+			}
+		})
+	}
+
+	if err := (&LabelMask{Fields: []string{"Cluster"}}).Validate(); err == nil {
+		t.Errorf("Expected an error for masking an unknown field")
+	}
+}
+
+func TestMaskedWrite(t *testing.T) {
+	leftKey := MetricKey{TestName: "Load-cluster-a", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	rightKey := MetricKey{TestName: "Load-cluster-b", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	j := &JobComparisonData{
+		Data: map[MetricKey]*MetricComparisonData{
+			leftKey:  {LeftJobSample: []float64{10}, RightJobSample: []float64{20}},
+			rightKey: {LeftJobSample: []float64{30}, RightJobSample: []float64{40}},
+		},
+	}
+	j.ComputeStatsForMetricSamples()
+	mask := &LabelMask{Pattern: regexp.MustCompile(`cluster-[a-z]+`)}
+
+	for _, format := range []string{JSONFormat, MarkdownFormat, OpenMetricsFormat} {
+		var buf bytes.Buffer
+		if err := j.WriteWithOptions(&buf, format, WriteOptions{Mask: mask}); err != nil {
+			t.Fatalf("Writing %v failed: %v", format, err)
+		}
+		if strings.Contains(buf.String(), "cluster-") {
+			t.Errorf("The %v output isn't redacted:\n%v", format, buf.String())
+		}
+		// The keys becoming the same are told apart, rather than either metric being lost.
+		if !strings.Contains(buf.String(), "Load-[masked]#2") {
+			t.Errorf("The %v output is missing a metric:\n%v", format, buf.String())
+		}
+	}
+	table := j.formatTable(PrettyPrintOptions{Mask: mask})
+	if strings.Contains(table, "cluster-") || !strings.Contains(table, "Load-[masked]") {
+		t.Errorf("The table isn't redacted:\n%v", table)
+	}
+	annotations, err := j.Masked(mask).ToGrafanaAnnotations("1", 0)
+	if err != nil {
+		t.Fatalf("ToGrafanaAnnotations failed: %v", err)
+	}
+	if strings.Contains(string(annotations), "cluster-") {
+		t.Errorf("The annotations aren't redacted: %s", annotations)
+	}
+
+	// The data itself keeps the full keys.
+	var keys []MetricKey
+	for key := range j.Data {
+		keys = append(keys, key)
+	}
+	if len(keys) != 2 || j.Data[leftKey] == nil || j.Data[rightKey] == nil {
+		t.Errorf("Masking changed the keys of the data: %v", keys)
+	}
+	if masked := j.Masked(mask); !reflect.DeepEqual(masked.Data[MetricKey{TestName: "Load-[masked]", Verb: "GET", Resource: "pods", Percentile: "Perc99"}].LeftJobSample, []float64{10}) {
+		t.Errorf("Masked data doesn't keep the samples of the metrics in order of their keys: %+v", masked.Data)
+	}
+}
+
+func TestMaskedFreeText(t *testing.T) {
+	latency := MetricKey{TestName: "Load-cluster-a", Verb: "POST", Resource: "pods", Percentile: "Perc99"}
+	throughput := MetricKey{TestName: "Load-cluster-a", Verb: "POST", Resource: "pods", Percentile: "Perc50"}
+	j := &JobComparisonData{
+		Data: map[MetricKey]*MetricComparisonData{
+			latency:    {LeftJobSample: []float64{100}, RightJobSample: []float64{130}, Labels: map[string]string{"Cluster": "cluster-a"}},
+			throughput: {LeftJobSample: []float64{10}, RightJobSample: []float64{15}, IsRate: true},
+		},
+		warnings: []Warning{{Type: WarningBadRequestCount, Metric: latency, Message: "for Load-cluster-a"}},
+		dropLog:  []DropRecord{{Metric: latency, Reason: DropNegative, Detail: "in Load-cluster-a"}},
+	}
+	j.ComputeStatsForMetricSamples()
+	j.Data[latency].Comments = "Change=+30.0%"
+	if invalidated := j.InvalidateOnThroughputChange(0.1); len(invalidated) != 1 || !strings.Contains(j.Data[latency].Comments, "Load-cluster-a") {
+		t.Fatalf("Latency not invalidated with the throughput's name in its comments: %+v", j.Data[latency])
+	}
+
+	masked := j.Masked(&LabelMask{Pattern: regexp.MustCompile(`cluster-[a-z]+`)})
+	maskedLatency := masked.Data[MetricKey{TestName: "Load-[masked]", Verb: "POST", Resource: "pods", Percentile: "Perc99"}]
+	if maskedLatency == nil || maskedLatency.Comments != "Throughput Load-[masked] POST pods Perc50 changed by +50.0%\tChange=+30.0%" {
+		t.Errorf("Masked latency is %+v, but expected its comments masked", maskedLatency)
+	}
+	if maskedLatency != nil && maskedLatency.Labels != nil {
+		t.Errorf("Masked latency has the raw labels %v", maskedLatency.Labels)
+	}
+	if masked.warnings[0].Message != "for Load-[masked]" || masked.dropLog[0].Detail != "in Load-[masked]" || masked.dropLog[0].Metric.TestName != "Load-[masked]" {
+		t.Errorf("Masked warnings are %+v and drop log is %+v, but expected them masked", masked.warnings, masked.dropLog)
+	}
+	for _, format := range []string{JSONFormat, MarkdownFormat, CSVFormat} {
+		var buf bytes.Buffer
+		if err := j.WriteWithOptions(&buf, format, WriteOptions{Mask: &LabelMask{Pattern: regexp.MustCompile(`cluster-[a-z]+`)}, IncludeWarnings: true}); err != nil {
+			t.Fatalf("Writing %v failed: %v", format, err)
+		}
+		if strings.Contains(buf.String(), "cluster-") {
+			t.Errorf("The %v output isn't redacted:\n%v", format, buf.String())
+		}
+	}
+	// The data itself is left as it is.
+	if !strings.Contains(j.Data[latency].Comments, "Load-cluster-a") || j.Data[latency].Labels == nil || j.dropLog[0].Detail != "in Load-cluster-a" {
+		t.Errorf("Masking changed the data: %+v", j.Data[latency])
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// BenjaminiHochberg returns the Benjamini-Hochberg adjusted p-values (q-values) for the given
+// p-values, in the same order. Rejecting the hypotheses with an adjusted p-value of at most
+// alpha controls the false discovery rate at alpha. NaN p-values are left out of the procedure
+// (and stay NaN).
+func BenjaminiHochberg(pValues []float64) []float64 {
+	adjusted := make([]float64, len(pValues))
+	var order []int
+	for i, pValue := range pValues {
+		adjusted[i] = math.NaN()
+		if !math.IsNaN(pValue) {
+			order = append(order, i)
+		}
+	}
+	sort.SliceStable(order, func(a, b int) bool { return pValues[order[a]] < pValues[order[b]] })
+	m := float64(len(order))
+	// Going from the largest p-value down, the adjusted one is min(m/rank * p, the next larger adjusted one).
+	minAdjusted := 1.0
+	for rank := len(order); rank >= 1; rank-- {
+		i := order[rank-1]
+		minAdjusted = math.Min(minAdjusted, m/float64(rank)*pValues[i])
+		adjusted[i] = minAdjusted
+	}
+	return adjusted
+}
+
+// AdjustPValuesBH applies the Benjamini-Hochberg multiple-testing correction to the p-values of
+// the metrics (those with HasPValue, as set by test-based schemes like KS-Test) and re-decides
+// the metrics' verdicts: a metric stays flagged as mismatched only if its adjusted p-value is
+// at most alpha. As metrics aren't ever newly flagged, gating done by the scheme (like the min
+// metric avg for compare) is preserved. This keeps the expected fraction of spurious mismatches
+// among the flagged metrics at alpha, instead of having alpha x N metrics flagged by chance.
+func (j *JobComparisonData) AdjustPValuesBH(alpha float64) {
+	var metrics []*MetricComparisonData
+	var pValues []float64
+	for _, metricData := range j.Data {
+		if metricData.HasPValue {
+			metrics = append(metrics, metricData)
+			pValues = append(pValues, metricData.PValue)
+		}
+	}
+	for i, adjustedPValue := range BenjaminiHochberg(pValues) {
+		if math.IsNaN(adjustedPValue) {
+			continue
+		}
+		if adjustedPValue > alpha {
+			metrics[i].Matched = true
+		}
+		metrics[i].Comments = withoutCommentField(metrics[i].Comments, bhPValueField) + fmt.Sprintf("\t%v%.4f", bhPValueField, adjustedPValue)
+	}
+}
+
+// bhPValueField prefixes the adjusted p-value that AdjustPValuesBH adds to the comments.
+const bhPValueField = "BH-Pvalue="
+
+// withoutCommentField returns the (tab-separated) comments without the field of the given
+// prefix, so that re-adding the field replaces it, rather than duplicating it.
+func withoutCommentField(comments, prefix string) string {
+	fields := strings.Split(comments, "\t")
+	kept := fields[:0]
+	for _, field := range fields {
+		if !strings.HasPrefix(field, prefix) {
+			kept = append(kept, field)
+		}
+	}
+	return strings.Join(kept, "\t")
+}
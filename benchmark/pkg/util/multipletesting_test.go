@@ -0,0 +1,82 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+// bhPaperPValues are the p-values of the example in Benjamini & Hochberg (1995), for which the
+// procedure rejects the 4 smallest at a false discovery rate of 0.05 (Bonferroni rejects 3).
+var bhPaperPValues = []float64{0.0001, 0.0004, 0.0019, 0.0095, 0.0201, 0.0278, 0.0298, 0.0344, 0.0459, 0.3240, 0.4262, 0.5719, 0.6528, 0.7590, 1.000}
+
+func TestBenjaminiHochberg(t *testing.T) {
+	adjusted := BenjaminiHochberg([]float64{0.04, math.NaN(), 0.01, 0.03})
+	expected := []float64{0.04, math.NaN(), 0.03, 0.04}
+	for i := range expected {
+		if math.IsNaN(expected[i]) != math.IsNaN(adjusted[i]) || math.Abs(adjusted[i]-expected[i]) > 1e-9 {
+			t.Errorf("Adjusted p-values computed as %v, but expected %v", adjusted, expected)
+			break
+		}
+	}
+}
+
+func TestAdjustPValuesBH(t *testing.T) {
+	j := NewJobComparisonData()
+	for i, pValue := range bhPaperPValues {
+		// As a test-based scheme would at a significance level of 0.05.
+		j.Data[MetricKey{TestName: "Load", Verb: fmt.Sprintf("VERB%02d", i), Percentile: "Perc99"}] = &MetricComparisonData{
+			PValue:    pValue,
+			HasPValue: true,
+			Matched:   pValue >= 0.05,
+		}
+	}
+	// Metrics without p-values are left alone.
+	noPValueKey := MetricKey{TestName: "Load", Verb: "GET", Percentile: "Perc99"}
+	j.Data[noPValueKey] = &MetricComparisonData{Matched: false}
+
+	j.AdjustPValuesBH(0.05)
+	for i := range bhPaperPValues {
+		metricKey := MetricKey{TestName: "Load", Verb: fmt.Sprintf("VERB%02d", i), Percentile: "Perc99"}
+		if matched := j.Data[metricKey].Matched; matched != (i >= 4) {
+			t.Errorf("Metric with p-value %v has matched=%v after BH correction, but expected %v", bhPaperPValues[i], matched, i >= 4)
+		}
+	}
+	if j.Data[noPValueKey].Matched {
+		t.Errorf("Metric without p-value got matched by BH correction")
+	}
+
+	// Re-applying the correction replaces the adjusted p-values in the comments.
+	j.AdjustPValuesBH(0.05)
+	for metricKey, metricData := range j.Data {
+		if count := strings.Count(metricData.Comments, "BH-Pvalue="); metricData.HasPValue && count != 1 {
+			t.Errorf("Metric %v has %v adjusted p-values in its comments after re-applying BH correction: %q", metricKey, count, metricData.Comments)
+		}
+	}
+
+	// Metrics timed out since aren't corrected with their stale p-values.
+	timedOutKey := MetricKey{TestName: "Load", Verb: "VERB00", Percentile: "Perc99"}
+	j.Data[timedOutKey].MarkTimedOut(context.DeadlineExceeded)
+	j.AdjustPValuesBH(0.05)
+	if metricData := j.Data[timedOutKey]; metricData.HasPValue || metricData.Matched || strings.Contains(metricData.Comments, "BH-Pvalue=") {
+		t.Errorf("Timed out metric corrected with a stale p-value: %+v", metricData)
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"sync"
+)
+
+// ReportSummary summarizes the verdicts of a comparison, e.g. for a team's gate on it.
+type ReportSummary struct {
+	Matched, Mismatched, Inconclusive int
+	Regressed, Improved               int
+	// FailingMetrics are the conclusively mismatched metrics, sorted by key.
+	FailingMetrics []MetricKey
+	// Digest identifies the failing metrics, for deduplicating alerts (see VerdictDigest).
+	Digest string
+	// Err tells why the comparison couldn't be made at all, e.g. as the policy is invalid.
+	Err error
+	// Data is the compared data the summary is of, e.g. for writing out the full report.
+	Data *JobComparisonData
+}
+
+// Passed tells if the comparison could be made and none of the metrics conclusively mismatched.
+func (s *ReportSummary) Passed() bool {
+	return s.Err == nil && s.Mismatched == 0
+}
+
+// Summary summarizes the verdicts the metrics have been given (see ReportSummary).
+func (j *JobComparisonData) Summary() *ReportSummary {
+	summary := &ReportSummary{Data: j}
+	for _, metricPair := range getMetricsSortedByKey(j) {
+		key, data := metricPair.metricKey, metricPair.metricData
+		switch {
+		case data.Inconclusive:
+			summary.Inconclusive++
+		case data.Matched:
+			summary.Matched++
+		default:
+			summary.Mismatched++
+			summary.FailingMetrics = append(summary.FailingMetrics, key)
+		}
+		if data.regressed() {
+			summary.Regressed++
+		}
+		if data.improved() {
+			summary.Improved++
+		}
+	}
+	summary.Digest = j.VerdictDigest()
+	return summary
+}
+
+// EvaluatePolicies compares the metrics as described by each of the policies, keyed by name
+// (e.g. of the team whose gate it is), and returns the summary of each comparison, keyed the
+// same. As comparing sets the metrics' verdicts, each policy is applied to its own deep copy
+// of the data, so that the policies don't see each other's verdicts, and the data itself is
+// left untouched. Each summary (and its Data) only covers the metrics the policy has a rule
+// for, e.g. those of the team, so that the others (never compared) don't fail its gate. The
+// copies are made from a snapshot whose stats are computed once, up front, and the policies
+// are then applied concurrently. A policy failing to apply (e.g. as it's invalid) gets a
+// summary with just the Err set.
+func (j *JobComparisonData) EvaluatePolicies(policies map[string]*Policy) map[string]*ReportSummary {
+	snapshot := j.Clone()
+	snapshot.EnsureStats()
+	summaries := make(map[string]*ReportSummary, len(policies))
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	for name, policy := range policies {
+		wg.Add(1)
+		go func(name string, policy *Policy) {
+			defer wg.Done()
+			data := snapshot.Clone()
+			summary := &ReportSummary{Err: data.ApplyPolicy(policy)}
+			if summary.Err == nil {
+				for metricKey := range data.Data {
+					if policy.RuleFor(metricKey) == nil {
+						delete(data.Data, metricKey)
+					}
+				}
+				summary = data.Summary()
+			}
+			lock.Lock()
+			defer lock.Unlock()
+			summaries[name] = summary
+		}(name, policy)
+	}
+	wg.Wait()
+	return summaries
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestEvaluatePolicies is meant to be run with -race too, as the policies are applied concurrently.
+func TestEvaluatePolicies(t *testing.T) {
+	listPods := MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}
+	getPods := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	j := NewJobComparisonData()
+	// LIST got 20% slower in the mean, but only 5% in the median. GET didn't change.
+	j.Data[listPods] = &MetricComparisonData{LeftJobSample: []float64{100, 100, 100}, RightJobSample: []float64{105, 105, 150}, Labels: map[string]string{"Verb": "LIST"}}
+	j.Data[getPods] = &MetricComparisonData{LeftJobSample: []float64{50, 50}, RightJobSample: []float64{50, 50}}
+	original := j.Clone()
+
+	policies := map[string]*Policy{
+		"strict":  {Default: PolicyRule{Statistic: StatisticMean, Threshold: 0.9}},
+		"lenient": {Default: PolicyRule{Statistic: StatisticMedian, Threshold: 0.9}},
+		"get-only": {Rules: []PolicyRule{
+			{Metric: MetricPattern{Verb: "GET"}, Statistic: StatisticMax, Threshold: 0.99},
+		}},
+		"invalid": {Default: PolicyRule{Scheme: "No-Such-Scheme"}},
+	}
+	for i := 0; i < 4; i++ {
+		policies[string(rune('a'+i))] = policies["strict"]
+	}
+	summaries := j.EvaluatePolicies(policies)
+	if len(summaries) != len(policies) {
+		t.Fatalf("Got %v summaries for %v policies", len(summaries), len(policies))
+	}
+
+	if strict := summaries["strict"]; strict.Passed() || !reflect.DeepEqual(strict.FailingMetrics, []MetricKey{listPods}) || strict.Regressed != 1 || strict.Matched != 1 {
+		t.Errorf("Strict policy summarized as %+v, but expected LIST to have regressed", strict)
+	}
+	for i := 0; i < 4; i++ {
+		if summary := summaries[string(rune('a'+i))]; !reflect.DeepEqual(summary.FailingMetrics, summaries["strict"].FailingMetrics) || summary.Digest != summaries["strict"].Digest {
+			t.Errorf("Copy of the strict policy summarized as %+v, but expected the same as the strict one", summary)
+		}
+	}
+	if lenient := summaries["lenient"]; !lenient.Passed() || lenient.Matched != 2 {
+		t.Errorf("Lenient policy summarized as %+v, but expected all metrics to match", lenient)
+	}
+	if getOnly := summaries["get-only"]; !getOnly.Passed() || getOnly.Matched != 1 || len(getOnly.Data.Data) != 1 || !getOnly.Data.Data[getPods].Matched {
+		t.Errorf("GET-only policy summarized as %+v, but expected only GET to be compared", getOnly)
+	}
+	if invalid := summaries["invalid"]; invalid.Err == nil || invalid.Passed() {
+		t.Errorf("Invalid policy summarized as %+v, but expected an error", invalid)
+	}
+
+	// Each policy got its own copy of the data, and the data itself is untouched.
+	if summaries["strict"].Data.Data[listPods] == summaries["lenient"].Data.Data[listPods] {
+		t.Errorf("Policies were applied to the same copy of the data")
+	}
+	if !reflect.DeepEqual(j, original) {
+		t.Errorf("Evaluating policies changed the data:\nReal: %+v\nExpected: %+v", j.Data[listPods], original.Data[listPods])
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+	"strconv"
+)
+
+// NaNPolicy tells how the writers represent non-finite numbers (NaN, but also infinities),
+// which have no standard representation in either JSON or CSV, for downstream parsers being
+// picky about it differently.
+//
+// In JSON, the values are written as null by default (and with NaNAsNull), as the strings
+// "NaN", "+Inf" or "-Inf" with NaNAsString, and the fields holding them are left out
+// altogether with NaNOmitted.
+//
+// In CSV, the cells are left empty by default (and with NaNOmitted), hold null with
+// NaNAsNull, and "NaN", "+Inf" or "-Inf" with NaNAsString.
+type NaNPolicy int
+
+// Allowed NaN policies.
+const (
+	NaNDefault  NaNPolicy = iota // The format's default: null in JSON, empty in CSV
+	NaNAsNull                    // null
+	NaNAsString                  // "NaN", "+Inf" or "-Inf"
+	NaNOmitted                   // Field left out in JSON, empty in CSV
+)
+
+// nonFiniteString returns the string representing a non-finite value.
+func nonFiniteString(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	default:
+		return "NaN"
+	}
+}
+
+// formatCSVFloat formats a value for a CSV cell as per the policy.
+func formatCSVFloat(value float64, policy NaNPolicy) string {
+	if isFinite(value) {
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+	switch policy {
+	case NaNAsNull:
+		return "null"
+	case NaNAsString:
+		return nonFiniteString(value)
+	default:
+		return ""
+	}
+}
+
+func isFinite(value float64) bool {
+	return !math.IsNaN(value) && !math.IsInf(value, 0)
+}
+
+// writeIndentedJSON writes the value to w as JSON (indenting by 2 spaces, if asked to), the
+// jsonFloats in it being represented as per the policies they carry (see newJSONFloat).
+func writeIndentedJSON(w io.Writer, value interface{}, indent bool) error {
+	encoder := json.NewEncoder(w)
+	if indent {
+		encoder.SetIndent("", "  ")
+	}
+	return encoder.Encode(value)
+}
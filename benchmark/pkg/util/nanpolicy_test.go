@@ -0,0 +1,141 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestNaNPolicyJSON(t *testing.T) {
+	// The right job's avg is 0, so the ratio of avgs can't be computed.
+	j := &JobComparisonData{
+		Data: map[MetricKey]*MetricComparisonData{
+			{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}: {LeftJobSample: []float64{10}, RightJobSample: []float64{0}, AvgRatio: math.NaN()},
+		},
+	}
+	j.ComputeStatsForMetricSamples()
+
+	for _, test := range []struct {
+		policy        NaNPolicy
+		expectedRatio interface{} // As decoded, or nil if omitted
+		hasRatio      bool
+	}{
+		{NaNDefault, nil, true},
+		{NaNAsNull, nil, true},
+		{NaNAsString, "NaN", true},
+		{NaNOmitted, nil, false},
+	} {
+		var buf bytes.Buffer
+		if err := j.WriteWithOptions(&buf, JSONFormat, WriteOptions{NaNPolicy: test.policy}); err != nil {
+			t.Fatalf("Writing JSON with NaN policy %v failed: %v", test.policy, err)
+		}
+		var records []map[string]interface{}
+		if err := json.Unmarshal(buf.Bytes(), &records); err != nil {
+			t.Fatalf("Couldn't decode JSON written with NaN policy %v: %v\n%v", test.policy, err, buf.String())
+		}
+		ratio, hasRatio := records[0]["avgRatio"]
+		if hasRatio != test.hasRatio || ratio != test.expectedRatio {
+			t.Errorf("With NaN policy %v, avgRatio is %v (present: %v), but expected %v (present: %v)", test.policy, ratio, hasRatio, test.expectedRatio, test.hasRatio)
+		}
+		// So is Glass's delta, with the left sample having no spread.
+		if glassDelta := records[0]["glassDelta"]; test.policy == NaNAsString && glassDelta != "NaN" {
+			t.Errorf("With NaN policy %v, glassDelta is %v, but expected NaN", test.policy, glassDelta)
+		}
+		// Finite values are as always.
+		if avgL := records[0]["avgL"]; avgL != 10.0 {
+			t.Errorf("With NaN policy %v, avgL is %v, but expected 10", test.policy, avgL)
+		}
+	}
+
+	// The default is the same as WriteJSON's.
+	var defaultBuf, policyBuf bytes.Buffer
+	if err := j.WriteJSON(&defaultBuf, true); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+	if err := j.WriteJSONWithNaNPolicy(&policyBuf, true, NaNAsNull); err != nil {
+		t.Fatalf("WriteJSONWithNaNPolicy failed: %v", err)
+	}
+	if defaultBuf.String() != policyBuf.String() {
+		t.Errorf("JSON with nulls for NaNs differs from the default:\n%v\n%v", policyBuf.String(), defaultBuf.String())
+	}
+}
+
+func TestNaNPolicyTimeSeries(t *testing.T) {
+	key := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	ts := NewTimeSeries()
+	ts.Series[key] = []TimeSeriesPoint{{BuildID: "101", Value: 15}, {BuildID: "102", Value: math.NaN()}, {BuildID: "103", Value: math.Inf(1)}}
+
+	for _, test := range []struct {
+		policy       NaNPolicy
+		expectedCSV  string
+		expectedJSON string
+	}{
+		{NaNDefault, "Load,GET,pods,,,Perc99,102,\nLoad,GET,pods,,,Perc99,103,\n", `{"buildId":"102","value":null},{"buildId":"103","value":null}`},
+		{NaNAsNull, "Load,GET,pods,,,Perc99,102,null\nLoad,GET,pods,,,Perc99,103,null\n", `{"buildId":"102","value":null},{"buildId":"103","value":null}`},
+		{NaNAsString, "Load,GET,pods,,,Perc99,102,NaN\nLoad,GET,pods,,,Perc99,103,+Inf\n", `{"buildId":"102","value":"NaN"},{"buildId":"103","value":"+Inf"}`},
+		{NaNOmitted, "Load,GET,pods,,,Perc99,102,\nLoad,GET,pods,,,Perc99,103,\n", `{"buildId":"102"},{"buildId":"103"}`},
+	} {
+		var buf bytes.Buffer
+		if err := ts.WriteCSVWithNaNPolicy(&buf, test.policy); err != nil {
+			t.Fatalf("WriteCSVWithNaNPolicy failed: %v", err)
+		}
+		if !strings.HasSuffix(buf.String(), "Load,GET,pods,,,Perc99,101,15\n"+test.expectedCSV) {
+			t.Errorf("With NaN policy %v, CSV is:\n%v\nbut expected it to end with:\n%v", test.policy, buf.String(), test.expectedCSV)
+		}
+		contents, err := ts.ToJSONWithNaNPolicy(test.policy)
+		if err != nil {
+			t.Fatalf("ToJSONWithNaNPolicy failed: %v", err)
+		}
+		if !strings.Contains(string(contents), `[{"buildId":"101","value":15},`+test.expectedJSON+`]`) {
+			t.Errorf("With NaN policy %v, JSON is %s, but expected points %v", test.policy, contents, test.expectedJSON)
+		}
+	}
+}
+
+func TestNaNPolicyJSONWithWarnings(t *testing.T) {
+	j := &JobComparisonData{
+		Data: map[MetricKey]*MetricComparisonData{
+			{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}: {LeftJobSample: []float64{10}, RightJobSample: []float64{0}, AvgRatio: math.NaN()},
+		},
+	}
+	j.ComputeStatsForMetricSamples()
+
+	// The policy also holds for the records nested in the report.
+	for _, test := range []struct {
+		policy        NaNPolicy
+		expectedRatio string
+	}{
+		{NaNAsNull, `"avgRatio": null`},
+		{NaNAsString, `"avgRatio": "NaN"`},
+		{NaNOmitted, ""},
+	} {
+		var buf bytes.Buffer
+		if err := j.WriteJSONWithWarnings(&buf, false, test.policy); err != nil {
+			t.Fatalf("WriteJSONWithWarnings with NaN policy %v failed: %v", test.policy, err)
+		}
+		if hasRatio := strings.Contains(buf.String(), `"avgRatio"`); hasRatio != (test.expectedRatio != "") || !strings.Contains(buf.String(), test.expectedRatio) {
+			t.Errorf("With NaN policy %v, JSON is:\n%v\nbut expected it to have %q", test.policy, buf.String(), test.expectedRatio)
+		}
+		if !strings.Contains(buf.String(), `"avgL": 10,`) {
+			t.Errorf("With NaN policy %v, JSON is:\n%v\nbut expected it to have an avgL of 10", test.policy, buf.String())
+		}
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"math"
+)
+
+// Per-node (or per-pod) metrics, flattened with a FlattenOptions.NodeLabel, are aggregated at
+// two levels:
+//   - Within each run, across the nodes: every node's value is added to the metric's sample,
+//     which so holds the distribution across the nodes (pooled over the runs), and the max of
+//     them, i.e. the run's worst node, is kept apart in the metric's node maxes (one per run).
+//   - Across the runs of each job: the schemes comparing the samples see the pooled values of
+//     all the nodes, while CompareMaxOverNodes compares the means of the runs' worst nodes.
+//
+// A slowdown of a single node can then go unnoticed by the former (as the other nodes water it
+// down), but not by the latter.
+
+// nodeMaxTracker tracks where the metrics' node maxes of the run being flattened are.
+type nodeMaxTracker struct {
+	run         int
+	fromLeftJob bool
+	indices     map[MetricKey]int
+}
+
+// fromNode tells if the item is a node's value of a per-node metric, as per the options'
+// NodeLabel. The values of different nodes within a run aren't collisions.
+func (o *FlattenOptions) fromNode(latency DataItemLike) bool {
+	return o.NodeLabel != "" && latency.GetLabels()[o.NodeLabel] != ""
+}
+
+// trackNodeMax folds a node's value of the metric into the metric's max across the nodes within
+// the run, adding the run's node max if it's the run's first value of the metric.
+func (j *JobComparisonData) trackNodeMax(metricData *MetricComparisonData, sample float64, metricKey MetricKey, run sampleRun, fromLeftJob bool) {
+	tracker := &j.nodeMaxes
+	if tracker.indices == nil || tracker.run != run.index || tracker.fromLeftJob != fromLeftJob {
+		*tracker = nodeMaxTracker{run: run.index, fromLeftJob: fromLeftJob, indices: make(map[MetricKey]int)}
+	}
+	maxes := &metricData.RightJobNodeMaxes
+	if fromLeftJob {
+		maxes = &metricData.LeftJobNodeMaxes
+	}
+	if index, ok := tracker.indices[metricKey]; ok {
+		(*maxes)[index] = math.Max((*maxes)[index], sample)
+		return
+	}
+	tracker.indices[metricKey] = len(*maxes)
+	*maxes = append(*maxes, sample)
+}
+
+// forgetRunNodes resets the tracking of the node maxes of the run, once the job's runs are flattened.
+func (j *JobComparisonData) forgetRunNodes() {
+	j.nodeMaxes = nodeMaxTracker{}
+}
+
+// NodeMaxes returns the metric's left or right job node maxes, i.e. the value of the worst node
+// of each run (if flattened with a FlattenOptions.NodeLabel).
+func (d *MetricComparisonData) NodeMaxes(fromLeftJob bool) []float64 {
+	if fromLeftJob {
+		return d.LeftJobNodeMaxes
+	}
+	return d.RightJobNodeMaxes
+}
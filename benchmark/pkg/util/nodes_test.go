@@ -0,0 +1,71 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/kubernetes/test/e2e/perftype"
+)
+
+func TestFlattenPerNodeMetrics(t *testing.T) {
+	// Each run reports the metric once per node.
+	runMetrics := func(nodeValues ...float64) map[string][]perftype.PerfData {
+		var dataItems []perftype.DataItem
+		for i, value := range nodeValues {
+			dataItems = append(dataItems, perftype.DataItem{
+				Data:   map[string]float64{"Perc99": value},
+				Unit:   "ms",
+				Labels: map[string]string{"Metric": "pod_startup", "Node": string(rune('a' + i))},
+			})
+		}
+		return map[string][]perftype.PerfData{"Density": {{Version: "v1", DataItems: dataItems}}}
+	}
+	leftMetrics := []map[string][]perftype.PerfData{runMetrics(10, 30, 20), runMetrics(15, 25, 20)}
+	rightMetrics := []map[string][]perftype.PerfData{runMetrics(10, 20, 40)}
+	metricKey := MetricKey{TestName: "Density", Verb: "Pod-Startup", Percentile: "Perc99"}
+
+	j := GetFlattennedComparisonDataWithOptions(leftMetrics, rightMetrics, FlattenOptions{
+		NodeLabel:       "Node",
+		CollisionPolicy: ErrorOnCollision,
+		AggregatorFor:   AggregateAllWith(MeanOfRuns),
+	})
+	if err := j.CollisionError(); err != nil {
+		t.Errorf("Values of different nodes were taken as colliding: %v", err)
+	}
+	metricData := j.Data[metricKey]
+	if metricData == nil {
+		t.Fatalf("Per-node metric %v missing: %v", metricKey, j.Data)
+	}
+	// The samples pool the nodes (aggregated across the runs here), the node maxes are per run.
+	if !reflect.DeepEqual(metricData.LeftJobSample, []float64{20}) || !reflect.DeepEqual(metricData.RightJobSample, []float64{70.0 / 3}) {
+		t.Errorf("Wrong samples of the per-node metric: %v, %v", metricData.LeftJobSample, metricData.RightJobSample)
+	}
+	if !reflect.DeepEqual(metricData.NodeMaxes(true), []float64{30, 25}) || !reflect.DeepEqual(metricData.NodeMaxes(false), []float64{40}) {
+		t.Errorf("Wrong node maxes: %v, %v", metricData.NodeMaxes(true), metricData.NodeMaxes(false))
+	}
+
+	// Without the node label, the nodes' values collide within the runs and no maxes are kept.
+	j = GetFlattennedComparisonDataWithOptions(leftMetrics, rightMetrics, FlattenOptions{CollisionPolicy: ErrorOnCollision})
+	if j.CollisionError() == nil {
+		t.Errorf("Values of different nodes didn't collide without a node label")
+	}
+	if metricData := j.Data[metricKey]; metricData.LeftJobNodeMaxes != nil || metricData.RightJobNodeMaxes != nil {
+		t.Errorf("Node maxes kept without a node label: %+v", metricData)
+	}
+}
@@ -0,0 +1,249 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// openMetricsPrefix is the default prefix (namespace) of the names of all the metric families
+// written in OpenMetrics format.
+const openMetricsPrefix = "benchmark_comparison"
+
+var (
+	invalidOpenMetricsNameChars = regexp.MustCompile("[^a-zA-Z0-9_]")
+	validOpenMetricsName        = regexp.MustCompile("^[a-zA-Z_:][a-zA-Z0-9_:]*$")
+)
+
+// openMetricsUnit turns a DataItem unit into a string usable as OpenMetrics unit (and name suffix).
+func openMetricsUnit(unit string) string {
+	if unit == "%" {
+		// The unit of percent-of-baseline normalized data.
+		return "percent"
+	}
+	return strings.Trim(invalidOpenMetricsNameChars.ReplaceAllString(unit, "_"), "_")
+}
+
+func openMetricsValue(value float64) string {
+	switch {
+	case math.IsNaN(value):
+		return "NaN"
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+var openMetricsLabelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func openMetricsLabels(key MetricKey, extraLabels ...string) string {
+	labels := []string{
+		"test", key.TestName,
+		"verb", key.Verb,
+		"resource", key.Resource,
+		"subresource", key.Subresource,
+		"scope", key.Scope,
+		"percentile", key.Percentile,
+	}
+	if key.Platform != "" {
+		labels = append(labels, "platform", key.Platform)
+	}
+	if key.SizeBucket != "" {
+		labels = append(labels, "size_bucket", key.SizeBucket)
+	}
+	if key.Population != "" {
+		labels = append(labels, "population", key.Population)
+	}
+	labels = append(labels, extraLabels...)
+	var pairs []string
+	for i := 0; i+1 < len(labels); i += 2 {
+		pairs = append(pairs, fmt.Sprintf(`%v="%v"`, labels[i], openMetricsLabelValueEscaper.Replace(labels[i+1])))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// openMetricsFamily describes a family (of gauges, unless it's a counter one) holding one of the
+// stats of every metric. Names of the unitless families mustn't start with that of a unit family
+// (followed by "_"), as they could then collide with the latter suffixed by some unit.
+type openMetricsFamily struct {
+	name    string
+	help    string
+	unit    string
+	counter bool
+	write   func(w io.Writer, name string, key MetricKey, data *MetricComparisonData)
+}
+
+// openMetricsWorstRunExemplar returns the exemplar (as suffix of a sample line) pointing to the
+// run of the metric's worst (i.e. max) right job sample value, or "" if its run is unknown (see
+// FlattenOptions.KeepRunIndices).
+func openMetricsWorstRunExemplar(data *MetricComparisonData) string {
+	if len(data.RightJobSample) == 0 || len(data.RightJobRunIndices) != len(data.RightJobSample) {
+		return ""
+	}
+	worst := 0
+	for i, value := range data.RightJobSample {
+		if value > data.RightJobSample[worst] {
+			worst = i
+		}
+	}
+	return fmt.Sprintf(` # {run_index="%v"} %v`, data.RightJobRunIndices[worst], openMetricsValue(data.RightJobSample[worst]))
+}
+
+func writeOpenMetricsSidedSamples(w io.Writer, name string, key MetricKey, left, right float64) {
+	fmt.Fprintf(w, "%v%v %v\n", name, openMetricsLabels(key, "side", "left"), openMetricsValue(left))
+	fmt.Fprintf(w, "%v%v %v\n", name, openMetricsLabels(key, "side", "right"), openMetricsValue(right))
+}
+
+// WriteOpenMetrics writes the verdict and stats of each metric to w in the OpenMetrics text
+// format. Stats having the unit of the samples are grouped into families per unit, which
+// is set as the family's UNIT metadata (and name suffix, as the format requires).
+func (j *JobComparisonData) WriteOpenMetrics(w io.Writer) error {
+	return j.WriteOpenMetricsWithNamespace(w, openMetricsPrefix)
+}
+
+// WriteOpenMetricsWithNamespace is like WriteOpenMetrics, but prefixes the names of the families
+// with the given namespace. Besides the matched gauges, the verdicts are written as a counter of
+// mismatches, where each mismatched metric's sample carries an exemplar with the worst (i.e. max)
+// value of its right job and the index of the run it's from, if known (see
+// FlattenOptions.KeepRunIndices). Exemplars go on a counter as the format only allows them on
+// counters and histograms.
+func (j *JobComparisonData) WriteOpenMetricsWithNamespace(w io.Writer, namespace string) error {
+	if !validOpenMetricsName.MatchString(namespace) {
+		return fmt.Errorf("invalid OpenMetrics namespace '%v'", namespace)
+	}
+	metricsByUnit := make(map[string]metricKeyDataPairList)
+	for _, metricPair := range getMetricsSortedByKey(j) {
+		unit := openMetricsUnit(metricPair.metricData.Unit)
+		metricsByUnit[unit] = append(metricsByUnit[unit], metricPair)
+	}
+	var units []string
+	for unit := range metricsByUnit {
+		units = append(units, unit)
+	}
+	sort.Strings(units)
+
+	unitlessFamilies := []openMetricsFamily{
+		{
+			name: "matched",
+			help: "Whether the metric matched across the left and right jobs (1) or not (0).",
+			write: func(w io.Writer, name string, key MetricKey, data *MetricComparisonData) {
+				matched := 0.0
+				if data.Matched {
+					matched = 1.0
+				}
+				fmt.Fprintf(w, "%v%v %v\n", name, openMetricsLabels(key), openMetricsValue(matched))
+			},
+		},
+		{
+			name:    "mismatches",
+			help:    "Whether the metric mismatched across the left and right jobs (1) or not (0).",
+			counter: true,
+			write: func(w io.Writer, name string, key MetricKey, data *MetricComparisonData) {
+				if data.Matched {
+					fmt.Fprintf(w, "%v%v 0\n", name, openMetricsLabels(key))
+					return
+				}
+				fmt.Fprintf(w, "%v%v 1%v\n", name, openMetricsLabels(key), openMetricsWorstRunExemplar(data))
+			},
+		},
+		{
+			name: "ratio_of_avgs",
+			help: "Ratio of the metric's left and right job sample averages.",
+			write: func(w io.Writer, name string, key MetricKey, data *MetricComparisonData) {
+				fmt.Fprintf(w, "%v%v %v\n", name, openMetricsLabels(key), openMetricsValue(data.AvgRatio))
+			},
+		},
+		{
+			name: "ratio_of_maxes",
+			help: "Ratio of the metric's right and left job sample maxes.",
+			write: func(w io.Writer, name string, key MetricKey, data *MetricComparisonData) {
+				fmt.Fprintf(w, "%v%v %v\n", name, openMetricsLabels(key), openMetricsValue(data.MaxRatio))
+			},
+		},
+		{
+			name: "sample_count",
+			help: "Number of samples of the metric in the left and right jobs.",
+			write: func(w io.Writer, name string, key MetricKey, data *MetricComparisonData) {
+				writeOpenMetricsSidedSamples(w, name, key, float64(len(data.LeftJobSample)), float64(len(data.RightJobSample)))
+			},
+		},
+	}
+	unitFamilies := []openMetricsFamily{
+		{
+			name: "avg",
+			help: "Average of the metric's samples in the left and right jobs.",
+			write: func(w io.Writer, name string, key MetricKey, data *MetricComparisonData) {
+				writeOpenMetricsSidedSamples(w, name, key, data.AvgL, data.AvgR)
+			},
+		},
+		{
+			name: "stdev",
+			help: "Standard deviation of the metric's samples in the left and right jobs.",
+			write: func(w io.Writer, name string, key MetricKey, data *MetricComparisonData) {
+				writeOpenMetricsSidedSamples(w, name, key, data.StDevL, data.StDevR)
+			},
+		},
+		{
+			name: "max",
+			help: "Max of the metric's samples in the left and right jobs.",
+			write: func(w io.Writer, name string, key MetricKey, data *MetricComparisonData) {
+				writeOpenMetricsSidedSamples(w, name, key, data.MaxL, data.MaxR)
+			},
+		},
+	}
+
+	bw := bufio.NewWriter(w)
+	writeFamily := func(family openMetricsFamily, metricsList metricKeyDataPairList) {
+		name := namespace + "_" + family.name
+		if family.unit != "" {
+			name += "_" + family.unit
+		}
+		familyType, sampleName := "gauge", name
+		if family.counter {
+			familyType, sampleName = "counter", name+"_total"
+		}
+		fmt.Fprintf(bw, "# TYPE %v %v\n", name, familyType)
+		if family.unit != "" {
+			fmt.Fprintf(bw, "# UNIT %v %v\n", name, family.unit)
+		}
+		fmt.Fprintf(bw, "# HELP %v %v\n", name, family.help)
+		for _, metricPair := range metricsList {
+			family.write(bw, sampleName, metricPair.metricKey, metricPair.metricData)
+		}
+	}
+	allMetrics := getMetricsSortedByKey(j)
+	for _, family := range unitlessFamilies {
+		writeFamily(family, allMetrics)
+	}
+	for _, unit := range units {
+		for _, family := range unitFamilies {
+			family.unit = unit
+			writeFamily(family, metricsByUnit[unit])
+		}
+	}
+	fmt.Fprintf(bw, "# EOF\n")
+	return bw.Flush()
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+
+	"k8s.io/kubernetes/test/e2e/perftype"
+)
+
+var (
+	openMetricsMetadataLine = regexp.MustCompile(`^# (TYPE|UNIT|HELP) ([a-zA-Z_:][a-zA-Z0-9_:]*) (.+)$`)
+	openMetricsSampleLine   = regexp.MustCompile(`^([a-zA-Z_:][a-zA-Z0-9_:]*)\{([a-z]+="(?:[^"\\]|\\.)*"(?:,[a-z]+="(?:[^"\\]|\\.)*")*)\} (NaN|[+-]Inf|[-+0-9.eE]+)( # \{[a-z_]+="[^"]*"\} [-+0-9.eE]+)?$`)
+)
+
+func TestWriteOpenMetrics(t *testing.T) {
+	jobComparisonData := &JobComparisonData{
+		Data: map[MetricKey]*MetricComparisonData{
+			{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}: {
+				Unit:           "ms",
+				LeftJobSample:  []float64{10, 20},
+				RightJobSample: []float64{30},
+				Matched:        true,
+			},
+			{TestName: "Load \"quoted\"", Verb: "LIST", Resource: "nodes", Percentile: "Perc50"}: {
+				Unit:          "ms",
+				LeftJobSample: []float64{10},
+			},
+			{TestName: "Load", Verb: "Pod-Startup", Percentile: "Perc99"}: {
+				Unit:           "s",
+				LeftJobSample:  []float64{1},
+				RightJobSample: []float64{1.5},
+			},
+			// A unit that could make a stat's family name collide with a unitless one.
+			{TestName: "Load", Verb: "Pod-Startup", Percentile: "Perc50"}: {
+				Unit:           "ratio",
+				LeftJobSample:  []float64{0.5},
+				RightJobSample: []float64{0.5},
+			},
+		},
+	}
+	jobComparisonData.ComputeStatsForMetricSamples()
+
+	var buf bytes.Buffer
+	if err := jobComparisonData.WriteOpenMetrics(&buf); err != nil {
+		t.Fatalf("WriteOpenMetrics failed: %v", err)
+	}
+	output := buf.String()
+	if !strings.HasSuffix(output, "\n# EOF\n") {
+		t.Fatalf("Output doesn't end with the EOF marker:\n%v", output)
+	}
+
+	// Check the exposition is well-formed: metadata precedes samples, families are
+	// contiguous and declared once, and units are suffixes of the family names.
+	lines := strings.Split(strings.TrimSuffix(output, "\n# EOF\n"), "\n")
+	declaredFamilies := make(map[string]bool)
+	currentFamily, currentSampleName := "", ""
+	samplesPerFamily := make(map[string]int)
+	for _, line := range lines {
+		if match := openMetricsMetadataLine.FindStringSubmatch(line); match != nil {
+			kind, name, value := match[1], match[2], match[3]
+			switch kind {
+			case "TYPE":
+				if declaredFamilies[name] {
+					t.Errorf("Family %v declared more than once", name)
+				}
+				// Counters' samples are suffixed by _total.
+				switch value {
+				case "gauge":
+					currentSampleName = name
+				case "counter":
+					currentSampleName = name + "_total"
+				default:
+					t.Errorf("Family %v has unexpected type %v", name, value)
+				}
+				declaredFamilies[name] = true
+				currentFamily = name
+			case "UNIT":
+				if name != currentFamily || !strings.HasSuffix(name, "_"+value) {
+					t.Errorf("Unit %v of family %v is misplaced or not a suffix of the family name", value, name)
+				}
+			case "HELP":
+				if name != currentFamily {
+					t.Errorf("Help of family %v is misplaced", name)
+				}
+			}
+			continue
+		}
+		match := openMetricsSampleLine.FindStringSubmatch(line)
+		if match == nil {
+			t.Errorf("Malformed line: %q", line)
+			continue
+		}
+		if match[1] != currentSampleName {
+			t.Errorf("Sample %q doesn't belong to the current family %v", line, currentFamily)
+		}
+		samplesPerFamily[currentFamily]++
+	}
+
+	expectedSamplesPerFamily := map[string]int{
+		"benchmark_comparison_matched":        4,
+		"benchmark_comparison_mismatches":     4,
+		"benchmark_comparison_ratio_of_avgs":  4,
+		"benchmark_comparison_ratio_of_maxes": 4,
+		"benchmark_comparison_sample_count":   8,
+		"benchmark_comparison_avg_ms":         4,
+		"benchmark_comparison_stdev_ms":       4,
+		"benchmark_comparison_max_ms":         4,
+		"benchmark_comparison_avg_s":          2,
+		"benchmark_comparison_stdev_s":        2,
+		"benchmark_comparison_max_s":          2,
+		"benchmark_comparison_avg_ratio":      2,
+		"benchmark_comparison_stdev_ratio":    2,
+		"benchmark_comparison_max_ratio":      2,
+	}
+	for family, expectedCount := range expectedSamplesPerFamily {
+		if samplesPerFamily[family] != expectedCount {
+			t.Errorf("Family %v has %v samples, but expected %v", family, samplesPerFamily[family], expectedCount)
+		}
+	}
+	if len(samplesPerFamily) != len(expectedSamplesPerFamily) {
+		t.Errorf("Unexpected families in output: %v", samplesPerFamily)
+	}
+	for _, expectedLine := range []string{
+		`benchmark_comparison_matched{test="Load",verb="GET",resource="pods",subresource="",scope="",percentile="Perc99"} 1`,
+		`benchmark_comparison_avg_ms{test="Load",verb="GET",resource="pods",subresource="",scope="",percentile="Perc99",side="left"} 15`,
+		`benchmark_comparison_avg_ms{test="Load \"quoted\"",verb="LIST",resource="nodes",subresource="",scope="",percentile="Perc50",side="right"} NaN`,
+	} {
+		if !strings.Contains(output, expectedLine+"\n") {
+			t.Errorf("Output doesn't contain line %q:\n%v", expectedLine, output)
+		}
+	}
+}
+
+func TestWriteOpenMetricsWithNamespace(t *testing.T) {
+	runMetrics := func(latency float64) map[string][]perftype.PerfData {
+		return map[string][]perftype.PerfData{
+			"Load": {{Version: "v1", DataItems: []perftype.DataItem{{Data: map[string]float64{"Perc99": latency}, Unit: "ms", Labels: map[string]string{"Count": "10", "Resource": "pods", "Verb": "GET"}}}}},
+		}
+	}
+	leftJobMetrics := []map[string][]perftype.PerfData{runMetrics(10), runMetrics(11)}
+	rightJobMetrics := []map[string][]perftype.PerfData{runMetrics(20), runMetrics(45), runMetrics(30)}
+	j := GetFlattennedComparisonDataWithOptions(leftJobMetrics, rightJobMetrics, FlattenOptions{MinAllowedAPIRequestCount: 10, KeepRunIndices: true})
+	j.ComputeStatsForMetricSamples()
+
+	var buf bytes.Buffer
+	if err := j.WriteOpenMetricsWithNamespace(&buf, "perf"); err != nil {
+		t.Fatalf("WriteOpenMetricsWithNamespace failed: %v", err)
+	}
+	// The mismatch points to the right job's slowest run.
+	for _, expectedLine := range []string{
+		"# TYPE perf_mismatches counter",
+		`perf_mismatches_total{test="Load",verb="GET",resource="pods",subresource="",scope="",percentile="Perc99"} 1 # {run_index="1"} 45`,
+		`perf_matched{test="Load",verb="GET",resource="pods",subresource="",scope="",percentile="Perc99"} 0`,
+		"# EOF",
+	} {
+		if !strings.Contains(buf.String(), expectedLine+"\n") {
+			t.Errorf("Output doesn't contain line %q:\n%v", expectedLine, buf.String())
+		}
+	}
+
+	// Without run indices, there's nothing to point to.
+	j = GetFlattennedComparisonData(leftJobMetrics, rightJobMetrics, 10)
+	buf.Reset()
+	if err := j.WriteOpenMetricsWithNamespace(&buf, "perf"); err != nil {
+		t.Fatalf("WriteOpenMetricsWithNamespace failed: %v", err)
+	}
+	if strings.Contains(buf.String(), "run_index") {
+		t.Errorf("Output has exemplars without run indices:\n%v", buf.String())
+	}
+
+	if err := j.WriteOpenMetricsWithNamespace(&buf, "perf-tests"); err == nil {
+		t.Errorf("Expected an error for an invalid namespace")
+	}
+}
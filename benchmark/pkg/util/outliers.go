@@ -0,0 +1,50 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"math"
+	"sort"
+)
+
+// SampleOutliersVsBaseline returns the indices (sorted) of the right job's runs with sample
+// values more than zThreshold std-devs away from the left job's avg, i.e. the runs that stand
+// out against the left (baseline) distribution, e.g. the culprits of a regression. If the left
+// sample has no spread, the runs of any other value stand out. It requires the samples to be
+// run-aligned (see FlattenOptions.KeepRunIndices) and the stats to have been computed already,
+// being nil otherwise or if the left sample is empty.
+func (d *MetricComparisonData) SampleOutliersVsBaseline(zThreshold float64) []int {
+	if len(d.LeftJobSample) == 0 || len(d.RightJobRunIndices) != len(d.RightJobSample) {
+		return nil
+	}
+	outlierRuns := make(map[int]bool)
+	for i, value := range d.RightJobSample {
+		z := zScore(value, d.AvgL, d.StDevL)
+		if d.StDevL == 0 && value != d.AvgL {
+			z = math.Inf(1)
+		}
+		if math.Abs(z) > zThreshold {
+			outlierRuns[d.RightJobRunIndices[i]] = true
+		}
+	}
+	var outliers []int
+	for runIndex := range outlierRuns {
+		outliers = append(outliers, runIndex)
+	}
+	sort.Ints(outliers)
+	return outliers
+}
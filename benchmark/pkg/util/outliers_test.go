@@ -0,0 +1,61 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/kubernetes/test/e2e/perftype"
+)
+
+func TestSampleOutliersVsBaseline(t *testing.T) {
+	runMetrics := func(latency float64) map[string][]perftype.PerfData {
+		return map[string][]perftype.PerfData{
+			"Load": {{Version: "v1", DataItems: []perftype.DataItem{{Data: map[string]float64{"Perc99": latency}, Unit: "ms", Labels: map[string]string{"Count": "10", "Resource": "pods", "Verb": "GET"}}}}},
+		}
+	}
+	metricKey := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	// The left job's runs average 100, with a std-dev of 10.
+	leftJobMetrics := []map[string][]perftype.PerfData{runMetrics(90), runMetrics(110), runMetrics(90), runMetrics(110)}
+	// The right job's third run is the anomalous one.
+	rightJobMetrics := []map[string][]perftype.PerfData{runMetrics(105), runMetrics(95), runMetrics(300), runMetrics(115)}
+	j := GetFlattennedComparisonDataWithOptions(leftJobMetrics, rightJobMetrics, FlattenOptions{MinAllowedAPIRequestCount: 10, KeepRunIndices: true})
+	j.ComputeStatsForMetricSamples()
+	metricData := j.Data[metricKey]
+
+	if outliers := metricData.SampleOutliersVsBaseline(3); !reflect.DeepEqual(outliers, []int{2}) {
+		t.Errorf("Outlier runs beyond 3 std-devs are %v, but expected [2]", outliers)
+	}
+	if outliers := metricData.SampleOutliersVsBaseline(1); !reflect.DeepEqual(outliers, []int{2, 3}) {
+		t.Errorf("Outlier runs beyond 1 std-dev are %v, but expected [2 3]", outliers)
+	}
+
+	// Left sample without spread.
+	metricData.LeftJobSample = []float64{100, 100}
+	j.ComputeStatsForMetricSamples()
+	if outliers := metricData.SampleOutliersVsBaseline(3); !reflect.DeepEqual(outliers, []int{0, 1, 2, 3}) {
+		t.Errorf("Outlier runs vs a baseline without spread are %v, but expected all of them", outliers)
+	}
+
+	// Without run indices, the runs can't be told.
+	j = GetFlattennedComparisonData(leftJobMetrics, rightJobMetrics, 10)
+	j.ComputeStatsForMetricSamples()
+	if outliers := j.Data[metricKey].SampleOutliersVsBaseline(3); outliers != nil {
+		t.Errorf("Outlier runs without run indices are %v, but expected none", outliers)
+	}
+}
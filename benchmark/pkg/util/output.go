@@ -0,0 +1,112 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"io"
+)
+
+// Allowed output formats.
+const (
+	JSONFormat               = "json"
+	MarkdownFormat           = "markdown"
+	OpenMetricsFormat        = "openmetrics"
+	RegressionManifestFormat = "regression-manifest"
+	MinimalJSONFormat        = "minimal-json"
+	HTMLFormat               = "html"
+	BoxPlotFormat            = "box-plot"
+	CSVFormat                = "csv"
+	ParquetFormat            = "parquet"
+)
+
+// WriteOptions tunes the job comparison data written by WriteWithOptions.
+type WriteOptions struct {
+	// PercentOfBaseline makes the samples and stats be written as percents of the left job's
+	// average (100% meaning unchanged), as per JobComparisonData.PercentOfBaseline.
+	PercentOfBaseline bool
+	// Mask, if set, redacts portions of the metric keys written (see LabelMask).
+	Mask *LabelMask
+	// NaNPolicy tells how to represent the non-finite stats, in the formats it applies to (JSON and CSV).
+	NaNPolicy NaNPolicy
+	// IncludeWarnings makes the JSON format hold the warnings about the metrics along with them
+	// (see WriteJSONWithWarnings).
+	IncludeWarnings bool
+}
+
+// Write is a wrapper function for writing the job comparison data to w in various formats.
+func (j *JobComparisonData) Write(w io.Writer, format string) error {
+	return j.WriteWithOptions(w, format, WriteOptions{})
+}
+
+// WriteWithOptions is like Write, but tunes the data written as per the given options.
+func (j *JobComparisonData) WriteWithOptions(w io.Writer, format string, options WriteOptions) error {
+	if options.PercentOfBaseline {
+		j = j.PercentOfBaseline()
+	}
+	j = j.Masked(options.Mask)
+	switch format {
+	case JSONFormat:
+		if options.IncludeWarnings {
+			return j.WriteJSONWithWarnings(w, false, options.NaNPolicy)
+		}
+		return j.WriteJSONWithNaNPolicy(w, false, options.NaNPolicy)
+	case CSVFormat:
+		return j.WriteCSVWithNaNPolicy(w, options.NaNPolicy)
+	case ParquetFormat:
+		return j.WriteParquet(w)
+	case MarkdownFormat:
+		return j.WriteMarkdown(w)
+	case HTMLFormat:
+		return j.WriteHTML(w)
+	case BoxPlotFormat:
+		return j.WriteBoxPlots(w)
+	case OpenMetricsFormat:
+		return j.WriteOpenMetrics(w)
+	case RegressionManifestFormat:
+		return j.WriteRegressionManifest(w)
+	case MinimalJSONFormat:
+		payload, err := j.ToMinimalJSON()
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(payload)
+		return err
+	default:
+		return fmt.Errorf("unknown output format '%v'", format)
+	}
+}
+
+// contentTypeForFormat returns the MIME type of the given output format.
+func contentTypeForFormat(format string) string {
+	switch format {
+	case JSONFormat, RegressionManifestFormat, MinimalJSONFormat:
+		return "application/json"
+	case MarkdownFormat:
+		return "text/markdown; charset=utf-8"
+	case HTMLFormat:
+		return "text/html; charset=utf-8"
+	case CSVFormat:
+		return "text/csv; charset=utf-8"
+	case ParquetFormat:
+		return "application/vnd.apache.parquet"
+	case OpenMetricsFormat:
+		return "application/openmetrics-text; version=1.0.0; charset=utf-8"
+	default:
+		return "text/plain; charset=utf-8"
+	}
+}
@@ -0,0 +1,306 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+)
+
+// Codes of the verdicts of the metrics, as written by WriteParquet.
+const (
+	ParquetVerdictMatched = iota
+	ParquetVerdictRegressed
+	ParquetVerdictImproved
+	ParquetVerdictMismatched // Neither for the worse nor for the better, e.g. of another shape but the same avgs
+	ParquetVerdictInconclusive
+)
+
+// verdictCode returns the code of the metric's verdict (see ParquetVerdictMatched, etc).
+func (d *MetricComparisonData) verdictCode() int32 {
+	switch {
+	case d.Inconclusive:
+		return ParquetVerdictInconclusive
+	case d.Matched:
+		return ParquetVerdictMatched
+	case d.regressed():
+		return ParquetVerdictRegressed
+	case d.improved():
+		return ParquetVerdictImproved
+	default:
+		return ParquetVerdictMismatched
+	}
+}
+
+// Values of the Parquet metadata written, as per its Thrift definition
+// (see https://github.com/apache/parquet-format/blob/master/src/main/thrift/parquet.thrift).
+const (
+	parquetMagic = "PAR1"
+
+	parquetInt32     = 1 // Type
+	parquetInt64     = 2
+	parquetDouble    = 5
+	parquetByteArray = 6
+
+	parquetRequired     = 0 // FieldRepetitionType
+	parquetUTF8         = 0 // ConvertedType
+	parquetPlain        = 0 // Encoding
+	parquetRLE          = 3
+	parquetUncompressed = 0 // CompressionCodec
+	parquetDataPage     = 0 // PageType
+)
+
+// parquetCreatedBy is the application that wrote the Parquet files, as per their metadata.
+const parquetCreatedBy = "k8s.io/perf-tests/benchmark"
+
+// parquetColumn is a column of the Parquet files written by WriteParquet.
+type parquetColumn struct {
+	name         string
+	physicalType int32
+	// value returns the column's value for the metric, a string, float64, int32 or int64 as per
+	// the physical type (a byte array being a UTF-8 string).
+	value func(key MetricKey, data *MetricComparisonData) interface{}
+}
+
+var parquetColumns = []parquetColumn{
+	{"testName", parquetByteArray, func(key MetricKey, _ *MetricComparisonData) interface{} { return key.TestName }},
+	{"verb", parquetByteArray, func(key MetricKey, _ *MetricComparisonData) interface{} { return key.Verb }},
+	{"resource", parquetByteArray, func(key MetricKey, _ *MetricComparisonData) interface{} { return key.Resource }},
+	{"subresource", parquetByteArray, func(key MetricKey, _ *MetricComparisonData) interface{} { return key.Subresource }},
+	{"scope", parquetByteArray, func(key MetricKey, _ *MetricComparisonData) interface{} { return key.Scope }},
+	{"percentile", parquetByteArray, func(key MetricKey, _ *MetricComparisonData) interface{} { return key.Percentile }},
+	{"platform", parquetByteArray, func(key MetricKey, _ *MetricComparisonData) interface{} { return key.Platform }},
+	{"sizeBucket", parquetByteArray, func(key MetricKey, _ *MetricComparisonData) interface{} { return key.SizeBucket }},
+	{"population", parquetByteArray, func(key MetricKey, _ *MetricComparisonData) interface{} { return key.Population }},
+	{"unit", parquetByteArray, func(_ MetricKey, data *MetricComparisonData) interface{} { return data.Unit }},
+	{"verdict", parquetInt32, func(_ MetricKey, data *MetricComparisonData) interface{} { return data.verdictCode() }},
+	{"avgL", parquetDouble, func(_ MetricKey, data *MetricComparisonData) interface{} { return data.AvgL }},
+	{"avgR", parquetDouble, func(_ MetricKey, data *MetricComparisonData) interface{} { return data.AvgR }},
+	{"avgRatio", parquetDouble, func(_ MetricKey, data *MetricComparisonData) interface{} { return data.AvgRatio }},
+	{"stDevL", parquetDouble, func(_ MetricKey, data *MetricComparisonData) interface{} { return data.StDevL }},
+	{"stDevR", parquetDouble, func(_ MetricKey, data *MetricComparisonData) interface{} { return data.StDevR }},
+	{"maxRatio", parquetDouble, func(_ MetricKey, data *MetricComparisonData) interface{} { return data.MaxRatio }},
+	{"n1", parquetInt64, func(_ MetricKey, data *MetricComparisonData) interface{} { return int64(len(data.LeftJobSample)) }},
+	{"n2", parquetInt64, func(_ MetricKey, data *MetricComparisonData) interface{} { return int64(len(data.RightJobSample)) }},
+	{"comments", parquetByteArray, func(_ MetricKey, data *MetricComparisonData) interface{} { return data.Comments }},
+}
+
+// writeParquetPlain appends the value to buf in Parquet's plain encoding.
+func writeParquetPlain(buf *bytes.Buffer, value interface{}) {
+	var scratch [8]byte
+	switch value := value.(type) {
+	case string:
+		binary.LittleEndian.PutUint32(scratch[:4], uint32(len(value)))
+		buf.Write(scratch[:4])
+		buf.WriteString(value)
+	case int32:
+		binary.LittleEndian.PutUint32(scratch[:4], uint32(value))
+		buf.Write(scratch[:4])
+	case int64:
+		binary.LittleEndian.PutUint64(scratch[:], uint64(value))
+		buf.Write(scratch[:])
+	case float64:
+		binary.LittleEndian.PutUint64(scratch[:], math.Float64bits(value))
+		buf.Write(scratch[:])
+	}
+}
+
+// Types of the fields of Thrift's compact protocol.
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftCompactWriter writes Thrift structs in the compact protocol, which Parquet's metadata is
+// serialized with. The fields of each struct have to be written in increasing order of ids.
+type thriftCompactWriter struct {
+	buf bytes.Buffer
+	// lastIDs are the ids of the last fields written in each of the (nested) structs being written.
+	lastIDs []int16
+}
+
+func (t *thriftCompactWriter) varint(value uint64) {
+	var scratch [binary.MaxVarintLen64]byte
+	t.buf.Write(scratch[:binary.PutUvarint(scratch[:], value)])
+}
+
+func (t *thriftCompactWriter) zigzag(value int64) {
+	t.varint(uint64((value << 1) ^ (value >> 63)))
+}
+
+func (t *thriftCompactWriter) binary(value string) {
+	t.varint(uint64(len(value)))
+	t.buf.WriteString(value)
+}
+
+func (t *thriftCompactWriter) field(id int16, fieldType byte) {
+	last := &t.lastIDs[len(t.lastIDs)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		t.buf.WriteByte(byte(delta)<<4 | fieldType)
+	} else {
+		t.buf.WriteByte(fieldType)
+		t.zigzag(int64(id))
+	}
+	*last = id
+}
+
+func (t *thriftCompactWriter) i32Field(id int16, value int32) {
+	t.field(id, thriftI32)
+	t.zigzag(int64(value))
+}
+
+func (t *thriftCompactWriter) i64Field(id int16, value int64) {
+	t.field(id, thriftI64)
+	t.zigzag(value)
+}
+
+func (t *thriftCompactWriter) binaryField(id int16, value string) {
+	t.field(id, thriftBinary)
+	t.binary(value)
+}
+
+// listField writes the header of a list of size elements, which are to be written right after.
+func (t *thriftCompactWriter) listField(id int16, elementType byte, size int) {
+	t.field(id, thriftList)
+	if size < 15 {
+		t.buf.WriteByte(byte(size)<<4 | elementType)
+	} else {
+		t.buf.WriteByte(0xf0 | elementType)
+		t.varint(uint64(size))
+	}
+}
+
+// beginStruct begins a struct (e.g. the top-level one, or an element of a list), to be ended by endStruct.
+func (t *thriftCompactWriter) beginStruct() {
+	t.lastIDs = append(t.lastIDs, 0)
+}
+
+func (t *thriftCompactWriter) structField(id int16) {
+	t.field(id, thriftStruct)
+	t.beginStruct()
+}
+
+func (t *thriftCompactWriter) endStruct() {
+	t.buf.WriteByte(0)
+	t.lastIDs = t.lastIDs[:len(t.lastIDs)-1]
+}
+
+// parquetChunk is where the column chunk of a Parquet column is in the file.
+type parquetChunk struct {
+	offset, size int64
+}
+
+// parquetFileMetaData returns the footer's FileMetaData of a Parquet file with a single row group
+// of the given rows, whose columns' chunks are the given ones.
+func parquetFileMetaData(rows int, chunks []parquetChunk) []byte {
+	meta := &thriftCompactWriter{}
+	meta.beginStruct()
+	meta.i32Field(1, 1) // version
+	meta.listField(2, thriftStruct, len(parquetColumns)+1)
+	meta.beginStruct() // The root of the schema
+	meta.binaryField(4, "schema")
+	meta.i32Field(5, int32(len(parquetColumns)))
+	meta.endStruct()
+	for _, column := range parquetColumns {
+		meta.beginStruct()
+		meta.i32Field(1, column.physicalType)
+		meta.i32Field(3, parquetRequired)
+		meta.binaryField(4, column.name)
+		if column.physicalType == parquetByteArray {
+			meta.i32Field(6, parquetUTF8)
+		}
+		meta.endStruct()
+	}
+	meta.i64Field(3, int64(rows))
+	meta.listField(4, thriftStruct, 1)
+	meta.beginStruct()
+	meta.listField(1, thriftStruct, len(chunks))
+	totalSize := int64(0)
+	for i, chunk := range chunks {
+		totalSize += chunk.size
+		meta.beginStruct()
+		meta.i64Field(2, chunk.offset)
+		meta.structField(3) // ColumnMetaData
+		meta.i32Field(1, parquetColumns[i].physicalType)
+		meta.listField(2, thriftI32, 2)
+		meta.zigzag(parquetPlain)
+		meta.zigzag(parquetRLE)
+		meta.listField(3, thriftBinary, 1)
+		meta.binary(parquetColumns[i].name)
+		meta.i32Field(4, parquetUncompressed)
+		meta.i64Field(5, int64(rows))
+		meta.i64Field(6, chunk.size)
+		meta.i64Field(7, chunk.size)
+		meta.i64Field(9, chunk.offset)
+		meta.endStruct()
+		meta.endStruct()
+	}
+	meta.i64Field(2, totalSize)
+	meta.i64Field(3, int64(rows))
+	meta.endStruct()
+	meta.binaryField(6, parquetCreatedBy)
+	meta.endStruct()
+	return meta.buf.Bytes()
+}
+
+// WriteParquet writes the job comparison data to w as a Parquet file, with a row per metric
+// (sorted by metric key) holding its key fields (as UTF-8 strings), unit, verdict (as an int32,
+// see ParquetVerdictMatched, etc), main stats (as doubles, non-finite ones kept as such), sample sizes
+// (as int64s) and comments, in the columns of the CSV (see WriteCSV). It's a plain (uncompressed,
+// with no dictionaries nor statistics) file of a single row group, which is written by this
+// package rather than with a library, so that it needs neither cgo nor dependencies.
+func (j *JobComparisonData) WriteParquet(w io.Writer) error {
+	metricsList := getMetricsSortedByKey(j)
+	var file bytes.Buffer
+	file.WriteString(parquetMagic)
+	chunks := make([]parquetChunk, len(parquetColumns))
+	for i, column := range parquetColumns {
+		var values bytes.Buffer
+		for _, metricPair := range metricsList {
+			writeParquetPlain(&values, column.value(metricPair.metricKey, metricPair.metricData))
+		}
+		// The columns are all required, so the page has no repetition nor definition levels.
+		header := &thriftCompactWriter{}
+		header.beginStruct()
+		header.i32Field(1, parquetDataPage)
+		header.i32Field(2, int32(values.Len()))
+		header.i32Field(3, int32(values.Len()))
+		header.structField(5) // DataPageHeader
+		header.i32Field(1, int32(len(metricsList)))
+		header.i32Field(2, parquetPlain)
+		header.i32Field(3, parquetRLE)
+		header.i32Field(4, parquetRLE)
+		header.endStruct()
+		header.endStruct()
+		chunks[i] = parquetChunk{offset: int64(file.Len()), size: int64(header.buf.Len() + values.Len())}
+		file.Write(header.buf.Bytes())
+		file.Write(values.Bytes())
+	}
+	footer := parquetFileMetaData(len(metricsList), chunks)
+	file.Write(footer)
+	var footerLength [4]byte
+	binary.LittleEndian.PutUint32(footerLength[:], uint32(len(footer)))
+	file.Write(footerLength[:])
+	file.WriteString(parquetMagic)
+	_, err := w.Write(file.Bytes())
+	return err
+}
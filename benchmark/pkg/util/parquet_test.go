@@ -0,0 +1,211 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"testing"
+)
+
+// thriftCompactReader reads Thrift structs in the compact protocol, as maps of their fields'
+// values by id, for the tests to read the Parquet metadata back independently of the writer.
+type thriftCompactReader struct {
+	r   *bytes.Reader
+	err error
+}
+
+func (r *thriftCompactReader) varint() uint64 {
+	value, err := binary.ReadUvarint(r.r)
+	if err != nil && r.err == nil {
+		r.err = err
+	}
+	return value
+}
+
+func (r *thriftCompactReader) zigzag() int64 {
+	value := r.varint()
+	return int64(value>>1) ^ -int64(value&1)
+}
+
+func (r *thriftCompactReader) value(valueType byte) interface{} {
+	switch valueType {
+	case 1, 2:
+		return valueType == 1
+	case 4, 5, 6:
+		return r.zigzag()
+	case 8:
+		value := make([]byte, r.varint())
+		if _, err := io.ReadFull(r.r, value); err != nil && r.err == nil {
+			r.err = err
+		}
+		return string(value)
+	case 9, 10:
+		header, _ := r.r.ReadByte()
+		size := int(header >> 4)
+		if size == 15 {
+			size = int(r.varint())
+		}
+		elements := make([]interface{}, size)
+		for i := range elements {
+			elements[i] = r.value(header & 0x0f)
+		}
+		return elements
+	case 12:
+		return r.readStruct()
+	default:
+		if r.err == nil {
+			r.err = fmt.Errorf("unexpected thrift type %v", valueType)
+		}
+		return nil
+	}
+}
+
+func (r *thriftCompactReader) readStruct() map[int16]interface{} {
+	fields := map[int16]interface{}{}
+	lastID := int16(0)
+	for r.err == nil {
+		header, err := r.r.ReadByte()
+		if err != nil {
+			r.err = err
+			break
+		}
+		if header == 0 {
+			break
+		}
+		id := lastID + int16(header>>4)
+		if header>>4 == 0 {
+			id = int16(r.zigzag())
+		}
+		fields[id] = r.value(header & 0x0f)
+		lastID = id
+	}
+	return fields
+}
+
+// readParquet reads back the columns of a Parquet file written by WriteParquet, by name.
+func readParquet(t *testing.T, file []byte) (columns map[string][]interface{}, numRows int64) {
+	if len(file) < 12 || string(file[:4]) != parquetMagic || string(file[len(file)-4:]) != parquetMagic {
+		t.Fatalf("Parquet file isn't delimited by its magic: %q", file)
+	}
+	footerLength := int(binary.LittleEndian.Uint32(file[len(file)-8:]))
+	footer := &thriftCompactReader{r: bytes.NewReader(file[len(file)-8-footerLength : len(file)-8])}
+	meta := footer.readStruct()
+	if footer.err != nil {
+		t.Fatalf("Parquet footer is malformed: %v", footer.err)
+	}
+	if createdBy := meta[6]; createdBy != parquetCreatedBy {
+		t.Errorf("Parquet file is created by %v, but expected %v", createdBy, parquetCreatedBy)
+	}
+	schema := meta[2].([]interface{})
+	rowGroup := meta[4].([]interface{})[0].(map[int16]interface{})
+	chunks := rowGroup[1].([]interface{})
+	if len(schema) != len(chunks)+1 {
+		t.Fatalf("Parquet schema has %v elements for %v column chunks", len(schema), len(chunks))
+	}
+	columns = map[string][]interface{}{}
+	for i, chunk := range chunks {
+		element := schema[i+1].(map[int16]interface{})
+		name, physicalType := element[4].(string), element[1].(int64)
+		columnMeta := chunk.(map[int16]interface{})[3].(map[int16]interface{})
+		page := &thriftCompactReader{r: bytes.NewReader(file[columnMeta[9].(int64):])}
+		pageHeader := page.readStruct()
+		numValues := pageHeader[5].(map[int16]interface{})[1].(int64)
+		values := make([]interface{}, numValues)
+		for k := range values {
+			switch physicalType {
+			case parquetByteArray:
+				var length uint32
+				binary.Read(page.r, binary.LittleEndian, &length)
+				value := make([]byte, length)
+				io.ReadFull(page.r, value)
+				values[k] = string(value)
+			case parquetInt32:
+				var value int32
+				binary.Read(page.r, binary.LittleEndian, &value)
+				values[k] = value
+			case parquetInt64:
+				var value int64
+				binary.Read(page.r, binary.LittleEndian, &value)
+				values[k] = value
+			case parquetDouble:
+				var value float64
+				binary.Read(page.r, binary.LittleEndian, &value)
+				values[k] = value
+			default:
+				t.Fatalf("Parquet column %v has unexpected type %v", name, physicalType)
+			}
+		}
+		if page.err != nil {
+			t.Fatalf("Parquet page of column %v is malformed: %v", name, page.err)
+		}
+		columns[name] = values
+	}
+	return columns, meta[3].(int64)
+}
+
+func TestWriteParquet(t *testing.T) {
+	regressed := MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99", Platform: "gce"}
+	improved := MetricKey{TestName: "Density", Verb: "POD STARTUP", Percentile: "Perc50"}
+	inconclusive := MetricKey{TestName: "Load", Verb: "GET", Resource: "nodes", Percentile: "Perc99"}
+	j := &JobComparisonData{Data: map[MetricKey]*MetricComparisonData{
+		regressed:    {LeftJobSample: []float64{100, 110}, RightJobSample: []float64{150, 160, 170}, Unit: "ms", AvgRatio: 0.65, MaxRatio: 1.55, Comments: "AvgRatio=0.65"},
+		improved:     {LeftJobSample: []float64{10, 12}, RightJobSample: []float64{20, 22}, IsRate: true, AvgRatio: 0.5, MaxRatio: 1.8},
+		inconclusive: {LeftJobSample: []float64{100}, AvgRatio: math.NaN(), MaxRatio: math.NaN(), Matched: true, Inconclusive: true},
+	}}
+	j.EnsureStats()
+
+	var buf bytes.Buffer
+	if err := j.WriteWithOptions(&buf, ParquetFormat, WriteOptions{}); err != nil {
+		t.Fatalf("WriteParquet failed: %v", err)
+	}
+	columns, numRows := readParquet(t, buf.Bytes())
+	if numRows != 3 || len(columns) != len(parquetColumns) {
+		t.Fatalf("Parquet file has %v rows of %v columns, but expected 3 rows of %v", numRows, len(columns), len(parquetColumns))
+	}
+	// The rows are sorted by metric key, the regressed one being last.
+	data := j.Data[regressed]
+	expected := map[string]interface{}{
+		"testName": "Load", "verb": "LIST", "resource": "pods", "subresource": "", "scope": "", "percentile": "Perc99",
+		"platform": "gce", "sizeBucket": "", "population": "", "unit": "ms", "verdict": int32(ParquetVerdictRegressed),
+		"avgL": data.AvgL, "avgR": data.AvgR, "avgRatio": 0.65, "stDevL": data.StDevL, "stDevR": data.StDevR, "maxRatio": data.MaxRatio,
+		"n1": int64(2), "n2": int64(3), "comments": "AvgRatio=0.65",
+	}
+	for name, value := range expected {
+		if got := columns[name][2]; got != value {
+			t.Errorf("Column %v of the regressed metric's row is %v, but expected %v", name, got, value)
+		}
+	}
+	if verdicts := columns["verdict"]; verdicts[0] != int32(ParquetVerdictImproved) || verdicts[1] != int32(ParquetVerdictInconclusive) {
+		t.Errorf("Verdicts are %v, but expected %v and %v first", verdicts, ParquetVerdictImproved, ParquetVerdictInconclusive)
+	}
+	// The non-finite stats are kept as such.
+	if avgRatio := columns["avgRatio"][1].(float64); !math.IsNaN(avgRatio) {
+		t.Errorf("Avg ratio of the inconclusive metric is %v, but expected NaN", avgRatio)
+	}
+
+	buf.Reset()
+	if err := (&JobComparisonData{Data: map[MetricKey]*MetricComparisonData{}}).WriteParquet(&buf); err != nil {
+		t.Fatalf("WriteParquet without metrics failed: %v", err)
+	}
+	if columns, numRows := readParquet(t, buf.Bytes()); numRows != 0 || len(columns["testName"]) != 0 {
+		t.Errorf("Parquet file without metrics has %v rows, but expected none", numRows)
+	}
+}
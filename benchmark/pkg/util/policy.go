@@ -0,0 +1,271 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"path"
+)
+
+// MetricPattern matches metric keys. Each field is a shell pattern (as in path.Match) for the
+// respective MetricKey field, with an empty pattern matching any value. A pattern is more specific
+// than another if it constrains more fields, whether to a literal value or a narrower pattern
+// (like "no*").
+type MetricPattern struct {
+	TestName    string `json:"testName,omitempty"`
+	Verb        string `json:"verb,omitempty"`
+	Resource    string `json:"resource,omitempty"`
+	Subresource string `json:"subresource,omitempty"`
+	Scope       string `json:"scope,omitempty"`
+	Percentile  string `json:"percentile,omitempty"`
+}
+
+func (p MetricPattern) fields() []string {
+	return []string{p.TestName, p.Verb, p.Resource, p.Subresource, p.Scope, p.Percentile}
+}
+
+func (k MetricKey) fields() []string {
+	return []string{k.TestName, k.Verb, k.Resource, k.Subresource, k.Scope, k.Percentile}
+}
+
+// Matches tells if the metric key matches the pattern.
+func (p MetricPattern) Matches(key MetricKey) bool {
+	keyFields := key.fields()
+	for i, pattern := range p.fields() {
+		if pattern == "" {
+			continue
+		}
+		if matched, err := path.Match(pattern, keyFields[i]); err != nil || !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// specificity is the number of fields the pattern constrains at all (i.e. that aren't empty or
+// "*"), whether to a literal value or a narrower pattern like "no*".
+func (p MetricPattern) specificity() int {
+	specificity := 0
+	for _, pattern := range p.fields() {
+		if pattern != "" && pattern != "*" {
+			specificity++
+		}
+	}
+	return specificity
+}
+
+// Statistics that policy rules can gate metrics on.
+const (
+	StatisticMean   = "mean"
+	StatisticMedian = "median"
+	StatisticP99    = "p99"
+	StatisticMax    = "max"
+)
+
+// sampleStatistic returns the given statistic of the sample (NaN if it's empty or the statistic unknown).
+func sampleStatistic(sample []float64, statistic string) float64 {
+	switch statistic {
+	case StatisticMean:
+		return Mean(sample)
+	case StatisticMedian:
+		return percentileOf(sample, 0.5)
+	case StatisticP99:
+		return percentileOf(sample, 0.99)
+	case StatisticMax:
+		return percentileOf(sample, 1)
+	default:
+		return math.NaN()
+	}
+}
+
+// PolicyRule tells how to compare the metrics matching a pattern: either using a comparison scheme
+// (and thresholds), or by gating on a statistic of their samples. In the latter case, a metric is
+// matched if the ratio of its left and right statistic is within [Threshold, 1/Threshold] (like
+// the ratio of avgs in Avg-Test), or if both the statistics are below MinMetricAvgForCompare.
+// If the ratio can't be computed otherwise, the metric is marked inconclusive.
+type PolicyRule struct {
+	Metric                 MetricPattern `json:"metric"`
+	Scheme                 string        `json:"scheme,omitempty"`
+	Statistic              string        `json:"statistic,omitempty"`
+	Threshold              float64       `json:"threshold"`
+	MinMetricAvgForCompare float64       `json:"minMetricAvgForCompare"`
+}
+
+// isSet tells if the rule says how to compare metrics at all.
+func (r *PolicyRule) isSet() bool {
+	return r.Scheme != "" || r.Statistic != ""
+}
+
+// validate checks that the rule uses either a known scheme or a known statistic.
+func (r *PolicyRule) validate() error {
+	if r.Statistic == "" {
+		_, err := GetComparisonScheme(r.Scheme)
+		return err
+	}
+	if r.Scheme != "" {
+		return fmt.Errorf("rule for %+v has both a scheme and a statistic", r.Metric)
+	}
+	if math.IsNaN(sampleStatistic([]float64{0}, r.Statistic)) {
+		return fmt.Errorf("unknown statistic '%v'", r.Statistic)
+	}
+	return nil
+}
+
+// compareUsingStatistic compares the metrics by the rule's statistic of their samples. Once
+// ctx is done, it marks the metrics it didn't get to as timed out.
+func (r *PolicyRule) compareUsingStatistic(ctx context.Context, j *JobComparisonData) {
+	if err := ctx.Err(); err != nil {
+		j.MarkTimedOut(err)
+		return
+	}
+	j.EnsureStats()
+	for _, metricData := range j.Data {
+		if err := ctx.Err(); err != nil {
+			metricData.MarkTimedOut(err)
+			continue
+		}
+		leftSampleCount := len(metricData.LeftJobSample)
+		rightSampleCount := len(metricData.RightJobSample)
+		metricData.ResetVerdict()
+		statL := sampleStatistic(metricData.LeftJobSample, r.Statistic)
+		statR := sampleStatistic(metricData.RightJobSample, r.Statistic)
+		ratio, ok := metricData.RatioOnOriginalScale(statL, statR)
+		comments := fmt.Sprintf("%v L/R=%.2f\t%v L(ms)=%.2f\t%v R(ms)=%.2f\tN1=%v\tN2=%v", r.Statistic, ratio, r.Statistic, statL, r.Statistic, statR, leftSampleCount, rightSampleCount)
+		if leftSampleCount == 0 || rightSampleCount == 0 {
+			metricData.Matched = true
+		} else if metricData.OriginalScale(statL) < r.MinMetricAvgForCompare && metricData.OriginalScale(statR) < r.MinMetricAvgForCompare {
+			metricData.Matched = true
+		} else if !ok {
+			metricData.MarkInconclusive(CannotComputeRatio + "\t" + comments)
+			continue
+		} else if metricData.WithinRatioBounds(ratio, r.Threshold) {
+			metricData.Matched = true
+		}
+		metricData.Comments = comments
+	}
+}
+
+// Policy is a declarative regression policy, describing how each metric is to be compared.
+// A metric is compared using the rule whose pattern matches it most specifically (i.e. that
+// constrains most key fields, see MetricPattern), with ties going to the earliest such rule.
+// Metrics not matching any rule are compared using the default rule (whose pattern is
+// ignored), or left untouched if the default rule has neither a scheme nor a statistic.
+type Policy struct {
+	Default PolicyRule   `json:"default"`
+	Rules   []PolicyRule `json:"rules"`
+}
+
+// LoadPolicy reads a policy from the JSON file at the given path and validates it. YAML
+// isn't supported. The schemes used are looked up among the registered ones (see
+// RegisterComparisonScheme).
+func LoadPolicy(filePath string) (*Policy, error) {
+	if extension := path.Ext(filePath); extension == ".yaml" || extension == ".yml" {
+		return nil, fmt.Errorf("policy file %v: YAML isn't supported, convert it to JSON", filePath)
+	}
+	contents, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read policy file: %v", err)
+	}
+	policy := &Policy{}
+	if err := Unmarshaler(contents, policy); err != nil {
+		return nil, fmt.Errorf("couldn't parse policy file %v: %v", filePath, err)
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid policy file %v: %v", filePath, err)
+	}
+	return policy, nil
+}
+
+// Validate checks that all the schemes, statistics and patterns used by the policy are valid.
+func (p *Policy) Validate() error {
+	rules := p.Rules
+	if p.Default.isSet() {
+		rules = append([]PolicyRule{p.Default}, rules...)
+	}
+	for _, rule := range rules {
+		if err := rule.validate(); err != nil {
+			return err
+		}
+		for _, pattern := range rule.Metric.fields() {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("bad pattern '%v': %v", pattern, err)
+			}
+		}
+	}
+	return nil
+}
+
+// RuleFor returns the policy rule to be used for comparing the given metric (nil if none).
+func (p *Policy) RuleFor(key MetricKey) *PolicyRule {
+	var bestRule *PolicyRule
+	for i := range p.Rules {
+		rule := &p.Rules[i]
+		if !rule.Metric.Matches(key) {
+			continue
+		}
+		if bestRule == nil || rule.Metric.specificity() > bestRule.Metric.specificity() {
+			bestRule = rule
+		}
+	}
+	if bestRule == nil && p.Default.isSet() {
+		bestRule = &p.Default
+	}
+	return bestRule
+}
+
+// ApplyPolicy compares the metrics of the job comparison data as described by the policy,
+// dispatching each metric to the scheme (and thresholds) of the policy rule it falls under.
+func (j *JobComparisonData) ApplyPolicy(p *Policy) error {
+	return j.ApplyPolicyWithContext(context.Background(), p)
+}
+
+// ApplyPolicyWithContext is like ApplyPolicy, but bounds the comparisons by the context
+// (see ContextComparisonScheme). Metrics with a transform are compared on the transformed
+// scale (see WithTransforms), and the ratios with hysteresis if set (see SetHysteresis).
+func (j *JobComparisonData) ApplyPolicyWithContext(ctx context.Context, p *Policy) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	metricsForRule := make(map[*PolicyRule]*JobComparisonData)
+	var rules []*PolicyRule
+	for metricKey, metricData := range j.Data {
+		rule := p.RuleFor(metricKey)
+		if rule == nil {
+			continue
+		}
+		if _, ok := metricsForRule[rule]; !ok {
+			metricsForRule[rule] = NewJobComparisonData()
+			rules = append(rules, rule)
+		}
+		metricsForRule[rule].Data[metricKey] = metricData
+	}
+	j.withTransforms(func() {
+		for _, rule := range rules {
+			if rule.Statistic != "" {
+				rule.compareUsingStatistic(ctx, metricsForRule[rule])
+				continue
+			}
+			scheme, _ := GetContextComparisonScheme(rule.Scheme)
+			scheme(ctx, metricsForRule[rule], rule.Threshold, rule.MinMetricAvgForCompare)
+		}
+	})
+	j.noteHysteresis()
+	return nil
+}
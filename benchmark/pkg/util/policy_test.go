@@ -0,0 +1,205 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// registerRecordingScheme registers a scheme that notes the threshold it was applied with in Comments,
+// for the rest of the test.
+func registerRecordingScheme(t *testing.T, name string) {
+	RegisterComparisonScheme(name, func(j *JobComparisonData, matchThreshold, minMetricAvgForCompare float64) {
+		for _, metricData := range j.Data {
+			metricData.Comments = name
+			metricData.AvgRatio = matchThreshold
+		}
+	})
+	t.Cleanup(func() { unregisterComparisonScheme(name) })
+}
+
+func TestPolicy(t *testing.T) {
+	registerRecordingScheme(t, "Fake-Default")
+	registerRecordingScheme(t, "Fake-A")
+	registerRecordingScheme(t, "Fake-B")
+
+	dir, err := ioutil.TempDir("", "policy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	policyPath := filepath.Join(dir, "policy.json")
+	policyContents := `{
+		"default": {"scheme": "Fake-Default", "threshold": 0.5},
+		"rules": [
+			{"metric": {"verb": "LIST", "percentile": "Perc9*"}, "scheme": "Fake-A", "threshold": 0.1},
+			{"metric": {"verb": "LIST", "resource": "pods", "percentile": "Perc99"}, "scheme": "Fake-B", "threshold": 0.2},
+			{"metric": {"testName": "*", "verb": "LIST", "resource": "pods", "percentile": "Perc99"}, "scheme": "Fake-A", "threshold": 0.3}
+		]
+	}`
+	if err := ioutil.WriteFile(policyPath, []byte(policyContents), 0644); err != nil {
+		t.Fatal(err)
+	}
+	policy, err := LoadPolicy(policyPath)
+	if err != nil {
+		t.Fatalf("Loading policy failed: %v", err)
+	}
+
+	listPods99 := MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}
+	listNodes90 := MetricKey{TestName: "Load", Verb: "LIST", Resource: "nodes", Percentile: "Perc90"}
+	listNodes50 := MetricKey{TestName: "Load", Verb: "LIST", Resource: "nodes", Percentile: "Perc50"}
+	getPods99 := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	j := &JobComparisonData{
+		Data: map[MetricKey]*MetricComparisonData{
+			listPods99:  {},
+			listNodes90: {},
+			listNodes50: {},
+			getPods99:   {},
+		},
+	}
+	if err := j.ApplyPolicy(policy); err != nil {
+		t.Fatalf("Applying policy failed: %v", err)
+	}
+
+	expected := map[MetricKey]struct {
+		scheme    string
+		threshold float64
+	}{
+		// The most specific rule wins, with the earlier one winning ties ("*" is no constraint).
+		listPods99: {"Fake-B", 0.2},
+		// Wildcard pattern on percentile.
+		listNodes90: {"Fake-A", 0.1},
+		// No rule matching, so the default applies.
+		listNodes50: {"Fake-Default", 0.5},
+		getPods99:   {"Fake-Default", 0.5},
+	}
+	for key, expectedResult := range expected {
+		if data := j.Data[key]; data.Comments != expectedResult.scheme || data.AvgRatio != expectedResult.threshold {
+			t.Errorf("Metric %v compared with %v at threshold %v, but expected %v at %v", key, data.Comments, data.AvgRatio, expectedResult.scheme, expectedResult.threshold)
+		}
+	}
+
+	// Without a default, unmatched metrics are left untouched.
+	policy.Default = PolicyRule{}
+	j.Data[getPods99].Comments = ""
+	if err := j.ApplyPolicy(policy); err != nil {
+		t.Fatalf("Applying policy failed: %v", err)
+	}
+	if j.Data[getPods99].Comments != "" {
+		t.Errorf("Metric %v not matching any rule was compared with %v", getPods99, j.Data[getPods99].Comments)
+	}
+
+	// Unknown schemes should be refused.
+	policy.Rules = append(policy.Rules, PolicyRule{Scheme: "Unknown"})
+	if err := j.ApplyPolicy(policy); err == nil {
+		t.Errorf("Expected an error for policy with an unknown scheme")
+	}
+
+	// YAML policies are refused upfront, rather than failing to parse as JSON.
+	yamlPolicyPath := filepath.Join(dir, "policy.yaml")
+	if err := ioutil.WriteFile(yamlPolicyPath, []byte("default:\n  scheme: Fake-Default\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadPolicy(yamlPolicyPath); err == nil || !strings.Contains(err.Error(), "YAML") {
+		t.Errorf("Expected an error about YAML not being supported, got: %v", err)
+	}
+}
+
+func TestUnregisterComparisonScheme(t *testing.T) {
+	registerRecordingScheme(t, "Fake-Unregistered")
+	unregisterComparisonScheme("Fake-Unregistered")
+	if _, err := GetComparisonScheme("Fake-Unregistered"); err == nil {
+		t.Errorf("Unregistered scheme still available")
+	}
+	for _, name := range ComparisonSchemeNames() {
+		if name == "Fake-Unregistered" {
+			t.Errorf("Unregistered scheme still listed")
+		}
+	}
+}
+
+func TestPolicyStatistics(t *testing.T) {
+	getPods99 := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	listPods99 := MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}
+	listNodes99 := MetricKey{TestName: "Load", Verb: "LIST", Resource: "nodes", Percentile: "Perc99"}
+	// All the metrics have a tail spike in the right job, moving the mean less than the max.
+	leftJobSample := []float64{10, 10, 10, 10}
+	rightJobSample := []float64{10, 10, 10, 30}
+	j := &JobComparisonData{
+		Data: map[MetricKey]*MetricComparisonData{
+			getPods99:   {LeftJobSample: leftJobSample, RightJobSample: rightJobSample},
+			listPods99:  {LeftJobSample: leftJobSample, RightJobSample: rightJobSample},
+			listNodes99: {LeftJobSample: leftJobSample, RightJobSample: rightJobSample},
+		},
+	}
+	policy := &Policy{
+		Rules: []PolicyRule{
+			{Metric: MetricPattern{Verb: "GET"}, Statistic: StatisticMean, Threshold: 0.5},
+			{Metric: MetricPattern{Verb: "LIST"}, Statistic: StatisticMax, Threshold: 0.5},
+			{Metric: MetricPattern{Verb: "LIST", Resource: "no*"}, Statistic: StatisticMax, Threshold: 0.5, MinMetricAvgForCompare: 50},
+		},
+	}
+	if err := j.ApplyPolicy(policy); err != nil {
+		t.Fatalf("Applying policy failed: %v", err)
+	}
+	// The mean ratio (10/15) is within bounds, but the max ratio (10/30) isn't.
+	if !j.Data[getPods99].Matched {
+		t.Errorf("Metric %v gated on mean mismatched: %v", getPods99, j.Data[getPods99].Comments)
+	}
+	if j.Data[listPods99].Matched {
+		t.Errorf("Metric %v gated on max matched: %v", listPods99, j.Data[listPods99].Comments)
+	}
+	if !j.Data[listNodes99].Matched {
+		t.Errorf("Metric %v gated on max below min-metric-avg-for-compare mismatched: %v", listNodes99, j.Data[listNodes99].Comments)
+	}
+
+	// A zero right statistic gives a ratio that can't be computed.
+	j.Data[getPods99].RightJobSample = []float64{0, 0}
+	if err := j.ApplyPolicy(policy); err != nil {
+		t.Fatalf("Applying policy failed: %v", err)
+	}
+	if !j.Data[getPods99].Inconclusive || !strings.HasPrefix(j.Data[getPods99].Comments, CannotComputeRatio) {
+		t.Errorf("Metric %v with a zero right mean not marked inconclusive: %v", getPods99, j.Data[getPods99].Comments)
+	}
+
+	// Statistics are bounded by the context too.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := j.ApplyPolicyWithContext(ctx, policy); err != nil {
+		t.Fatalf("Applying policy failed: %v", err)
+	}
+	for metricKey, metricData := range j.Data {
+		if !metricData.Inconclusive || metricData.Matched || !strings.HasPrefix(metricData.Comments, "Timed out") {
+			t.Errorf("Metric %v not marked as timed out with a cancelled context: %+v", metricKey, metricData)
+		}
+	}
+
+	for _, badRule := range []PolicyRule{
+		{Statistic: "p42"},
+		{Scheme: "Fake-Statistic", Statistic: StatisticMax},
+	} {
+		registerRecordingScheme(t, "Fake-Statistic")
+		if err := (&Policy{Rules: []PolicyRule{badRule}}).Validate(); err == nil {
+			t.Errorf("Expected an error validating rule %+v", badRule)
+		}
+	}
+}
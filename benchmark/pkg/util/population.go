@@ -0,0 +1,48 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+// rollsUpPopulation tells if the metric's value is also to be added to the rollup of the
+// sub-populations, as per the options' KeepPopulationRollup.
+func (o *FlattenOptions) rollsUpPopulation(metricKey MetricKey) bool {
+	return o.KeepPopulationRollup && metricKey.Population != ""
+}
+
+// rollsUpPopulationOf tells if the metric is the rollup the item's sub-population's value is
+// added to, in which case it's pooled with those of the other sub-populations.
+func (o *FlattenOptions) rollsUpPopulationOf(metricKey MetricKey, latency DataItemLike) bool {
+	return o.KeepPopulationRollup && o.PopulationLabel != "" && metricKey.Population == "" && latency.GetLabels()[o.PopulationLabel] != ""
+}
+
+// MaskedPopulationRegressions returns the keys (sorted) of the sub-populations that regressed
+// while their rollup (flattened with KeepPopulationRollup) didn't, i.e. the regressions isolated
+// to some of the sub-populations that comparing the aggregate alone masks. The metrics should
+// have been compared already.
+func (j *JobComparisonData) MaskedPopulationRegressions() []MetricKey {
+	var metricKeys []MetricKey
+	for _, metricPair := range getMetricsSortedByKey(j) {
+		if metricPair.metricKey.Population == "" || !metricPair.metricData.regressed() {
+			continue
+		}
+		rollupKey := metricPair.metricKey
+		rollupKey.Population = ""
+		if rollup, ok := j.Data[rollupKey]; ok && !rollup.regressed() {
+			metricKeys = append(metricKeys, metricPair.metricKey)
+		}
+	}
+	return metricKeys
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/kubernetes/test/e2e/perftype"
+)
+
+func TestFlattenSubPopulations(t *testing.T) {
+	// Each run reports the LIST pods latency of 4 tenants, the last of which regressed.
+	runMetrics := func(lastTenantLatency float64) map[string][]perftype.PerfData {
+		var dataItems []perftype.DataItem
+		for _, tenant := range []string{"a", "b", "c", "d"} {
+			latency := 100.0
+			if tenant == "d" {
+				latency = lastTenantLatency
+			}
+			dataItems = append(dataItems, perftype.DataItem{
+				Data:   map[string]float64{"Perc99": latency},
+				Unit:   "ms",
+				Labels: map[string]string{"Resource": "pods", "Verb": "LIST", "Tenant": tenant},
+			})
+		}
+		return map[string][]perftype.PerfData{"Load": {{Version: "v1", DataItems: dataItems}}}
+	}
+	leftMetrics := []map[string][]perftype.PerfData{runMetrics(100), runMetrics(100)}
+	rightMetrics := []map[string][]perftype.PerfData{runMetrics(160), runMetrics(160)}
+	rollupKey := MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}
+	regressedKey := rollupKey
+	regressedKey.Population = "d"
+
+	j := GetFlattennedComparisonDataWithOptions(leftMetrics, rightMetrics, FlattenOptions{
+		PopulationLabel:      "Tenant",
+		KeepPopulationRollup: true,
+		CollisionPolicy:      ErrorOnCollision,
+	})
+	if err := j.CollisionError(); err != nil {
+		t.Errorf("Values of the sub-populations rolled up were taken as colliding: %v", err)
+	}
+	if len(j.Data) != 5 {
+		t.Errorf("Expected the 4 sub-populations and their rollup, got %v", j.Data)
+	}
+	if !reflect.DeepEqual(j.Data[regressedKey].RightJobSample, []float64{160, 160}) {
+		t.Errorf("Wrong right sample of the regressed sub-population: %v", j.Data[regressedKey].RightJobSample)
+	}
+	if !reflect.DeepEqual(j.Data[rollupKey].RightJobSample, []float64{100, 100, 100, 160, 100, 100, 100, 160}) {
+		t.Errorf("Rollup doesn't pool the sub-populations: %v", j.Data[rollupKey].RightJobSample)
+	}
+
+	// The regression of a single tenant is within the threshold for the aggregate, but not for the tenant.
+	compareAvgsWithin(j, 0.2, 0)
+	if !j.Data[rollupKey].Matched || j.Data[regressedKey].Matched {
+		t.Errorf("Wrong comparison results for the rollup and the regressed sub-population: %+v, %+v", j.Data[rollupKey], j.Data[regressedKey])
+	}
+	if masked := j.MaskedPopulationRegressions(); !reflect.DeepEqual(masked, []MetricKey{regressedKey}) {
+		t.Errorf("Masked sub-population regressions are %v, but expected %v", masked, []MetricKey{regressedKey})
+	}
+
+	// Without the rollup, only the sub-populations are kept.
+	j = GetFlattennedComparisonDataWithOptions(leftMetrics, rightMetrics, FlattenOptions{PopulationLabel: "Tenant"})
+	if _, ok := j.Data[rollupKey]; ok || len(j.Data) != 4 {
+		t.Errorf("Expected only the 4 sub-populations without the rollup, got %v", j.Data)
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+)
+
+// MetricPresence tells in how many of each job's runs a metric is present.
+type MetricPresence struct {
+	LeftRuns, RightRuns         int // No. of runs with values of the metric
+	LeftRunCount, RightRunCount int // No. of runs flattened
+}
+
+// Partial tells if the metric is missing from some of the runs of either job.
+func (p MetricPresence) Partial() bool {
+	return p.LeftRuns < p.LeftRunCount || p.RightRuns < p.RightRunCount
+}
+
+// PresenceCounts returns, for each metric, the number of each job's runs it has values from.
+// It's based on the run indices of the samples, so only their metrics are included if those
+// weren't retained while flattening (see FlattenOptions.KeepRunIndices).
+func (j *JobComparisonData) PresenceCounts() map[MetricKey]MetricPresence {
+	presenceCounts := make(map[MetricKey]MetricPresence)
+	for metricKey, metricData := range j.Data {
+		if len(metricData.LeftJobRunIndices) != len(metricData.LeftJobSample) || len(metricData.RightJobRunIndices) != len(metricData.RightJobSample) {
+			continue
+		}
+		presence := MetricPresence{
+			LeftRuns:  countDistinct(metricData.LeftJobRunIndices),
+			RightRuns: countDistinct(metricData.RightJobRunIndices),
+		}
+		presence.LeftRunCount, presence.RightRunCount = j.RunCounts()
+		presenceCounts[metricKey] = presence
+	}
+	return presenceCounts
+}
+
+// RunCounts returns the no. of runs of the left and right jobs contributing to the comparison,
+// i.e. flattened (including appended ones), whether or not they had values of any metric, but
+// not those dropped as outside the time window. Unlike the metrics' sample counts, they are
+// how many runs each side had overall, which tells how much to trust the verdicts.
+func (j *JobComparisonData) RunCounts() (left, right int) {
+	return j.leftRunCount - j.leftRunsOutsideWindow, j.rightRunCount - j.rightRunsOutsideWindow
+}
+
+// runCountsHeader returns the header of the reports telling the run counts of the jobs (see
+// RunCounts), like "12 runs vs 4 runs", or "" if no runs were flattened (e.g. for data read
+// from a report).
+func (j *JobComparisonData) runCountsHeader() string {
+	left, right := j.RunCounts()
+	if left == 0 && right == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%v vs %v", pluralizeRuns(left), pluralizeRuns(right))
+}
+
+func pluralizeRuns(count int) string {
+	if count == 1 {
+		return "1 run"
+	}
+	return fmt.Sprintf("%v runs", count)
+}
+
+// PartiallyPresentMetrics returns the keys (sorted) of metrics missing from some of the runs
+// of either job (see PresenceCounts). Their comparison is to be treated cautiously, as the
+// runs they're missing from may well be the unusual ones (e.g. due to flaky test coverage).
+func (j *JobComparisonData) PartiallyPresentMetrics() []MetricKey {
+	presenceCounts := j.PresenceCounts()
+	var partialMetrics []MetricKey
+	for _, metricPair := range getMetricsSortedByKey(j) {
+		if presence, ok := presenceCounts[metricPair.metricKey]; ok && presence.Partial() {
+			partialMetrics = append(partialMetrics, metricPair.metricKey)
+		}
+	}
+	return partialMetrics
+}
+
+func countDistinct(values []int) int {
+	distinct := make(map[int]bool)
+	for _, value := range values {
+		distinct[value] = true
+	}
+	return len(distinct)
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/kubernetes/test/e2e/perftype"
+)
+
+func TestPresenceCounts(t *testing.T) {
+	runMetrics := func(withList bool) map[string][]perftype.PerfData {
+		dataItems := []perftype.DataItem{
+			{Data: map[string]float64{"Perc99": 10}, Unit: "ms", Labels: map[string]string{"Count": "10", "Resource": "pods", "Verb": "GET"}},
+		}
+		if withList {
+			dataItems = append(dataItems, perftype.DataItem{Data: map[string]float64{"Perc99": 20}, Unit: "ms", Labels: map[string]string{"Count": "10", "Resource": "pods", "Verb": "LIST"}})
+		}
+		return map[string][]perftype.PerfData{"Load": {{Version: "v1", DataItems: dataItems}}}
+	}
+	getPods99 := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	listPods99 := MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}
+	leftJobMetrics := []map[string][]perftype.PerfData{runMetrics(true), runMetrics(false)}
+	rightJobMetrics := []map[string][]perftype.PerfData{runMetrics(true)}
+
+	// Without run indices, there's nothing to tell presence by.
+	j := GetFlattennedComparisonData(leftJobMetrics, rightJobMetrics, 10)
+	if presenceCounts := j.PresenceCounts(); len(presenceCounts) != 0 {
+		t.Errorf("Presence counted without run indices: %v", presenceCounts)
+	}
+
+	j = GetFlattennedComparisonDataWithOptions(leftJobMetrics, rightJobMetrics, FlattenOptions{MinAllowedAPIRequestCount: 10, KeepRunIndices: true})
+	// LIST is missing from the 2nd left run, and from the appended 3rd one too.
+	j.AppendRuns([]map[string][]perftype.PerfData{runMetrics(false)}, []map[string][]perftype.PerfData{runMetrics(true)}, 10)
+	expected := map[MetricKey]MetricPresence{
+		getPods99:  {LeftRuns: 3, RightRuns: 2, LeftRunCount: 3, RightRunCount: 2},
+		listPods99: {LeftRuns: 1, RightRuns: 2, LeftRunCount: 3, RightRunCount: 2},
+	}
+	if presenceCounts := j.PresenceCounts(); !reflect.DeepEqual(presenceCounts, expected) {
+		t.Errorf("Presence counts are %v, but expected %v", presenceCounts, expected)
+	}
+	if expected := []int{0, 1, 2}; !reflect.DeepEqual(j.Data[getPods99].LeftJobRunIndices, expected) {
+		t.Errorf("Left run indices are %v, but expected %v", j.Data[getPods99].LeftJobRunIndices, expected)
+	}
+	if partialMetrics := j.PartiallyPresentMetrics(); !reflect.DeepEqual(partialMetrics, []MetricKey{listPods99}) {
+		t.Errorf("Partially present metrics are %v, but expected %v", partialMetrics, []MetricKey{listPods99})
+	}
+}
+
+func TestRunCounts(t *testing.T) {
+	runMetrics := func(dataItems ...perftype.DataItem) map[string][]perftype.PerfData {
+		return map[string][]perftype.PerfData{"Load": {{Version: "v1", DataItems: dataItems}}}
+	}
+	getPods := perftype.DataItem{Data: map[string]float64{"Perc99": 10}, Unit: "ms", Labels: map[string]string{"Count": "10", "Resource": "pods", "Verb": "GET"}}
+	// The 2nd left run has no metrics at all, but still counts.
+	leftJobMetrics := []map[string][]perftype.PerfData{runMetrics(getPods), runMetrics(), runMetrics(getPods)}
+	rightJobMetrics := []map[string][]perftype.PerfData{runMetrics(getPods)}
+
+	if left, right := NewJobComparisonData().RunCounts(); left != 0 || right != 0 {
+		t.Errorf("Run counts without runs are %v and %v, but expected 0", left, right)
+	}
+	j := GetFlattennedComparisonData(leftJobMetrics, rightJobMetrics, 10)
+	if left, right := j.RunCounts(); left != 3 || right != 1 {
+		t.Errorf("Run counts are %v and %v, but expected 3 and 1", left, right)
+	}
+	if sampleCount := len(j.Data[MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}].LeftJobSample); sampleCount != 2 {
+		t.Errorf("Left sample has %v values, but expected 2", sampleCount)
+	}
+	j.AppendRuns(nil, rightJobMetrics, 10)
+	if left, right := j.RunCounts(); left != 3 || right != 2 {
+		t.Errorf("Run counts once appended are %v and %v, but expected 3 and 2", left, right)
+	}
+
+	// Runs outside the time window don't count.
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	j = GetFlattennedComparisonDataWithOptions(leftJobMetrics, rightJobMetrics, FlattenOptions{
+		MinAllowedAPIRequestCount: 10,
+		LeftRunTimestamps:         map[int]time.Time{0: start.Add(-time.Hour), 1: start, 2: start.Add(time.Hour)},
+		RightRunTimestamps:        map[int]time.Time{0: start},
+		TimeWindow:                TimeWindow{Start: start},
+	})
+	if left, right := j.RunCounts(); left != 2 || right != 1 {
+		t.Errorf("Run counts within the time window are %v and %v, but expected 2 and 1", left, right)
+	}
+
+	// The reports are headed by them.
+	var markdown, html bytes.Buffer
+	if err := j.WriteMarkdown(&markdown); err != nil {
+		t.Fatalf("WriteMarkdown failed: %v", err)
+	}
+	if err := j.WriteHTML(&html); err != nil {
+		t.Fatalf("WriteHTML failed: %v", err)
+	}
+	for name, report := range map[string]string{"Markdown": markdown.String(), "HTML": html.String(), "table": j.formatTable(PrettyPrintOptions{})} {
+		if !strings.Contains(strings.SplitN(report, "\n", 2)[0], "2 runs vs 1 run") {
+			t.Errorf("The %v report isn't headed by the run counts:\n%v", name, report)
+		}
+	}
+	// So is the report of the percents of the baseline.
+	markdown.Reset()
+	if err := j.WriteWithOptions(&markdown, MarkdownFormat, WriteOptions{PercentOfBaseline: true}); err != nil || !strings.HasPrefix(markdown.String(), "**2 runs vs 1 run**") {
+		t.Errorf("The Markdown report of the percents of the baseline isn't headed by the run counts (error %v):\n%v", err, markdown.String())
+	}
+	markdown.Reset()
+	if err := NewJobComparisonData().WriteMarkdown(&markdown); err != nil || strings.Contains(markdown.String(), "runs") {
+		t.Errorf("The Markdown report without runs is headed by run counts (error %v):\n%v", err, markdown.String())
+	}
+}
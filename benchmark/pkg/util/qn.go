@@ -0,0 +1,186 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"math"
+	"sort"
+)
+
+// qnNormalConsistency scales the Qn to be a consistent estimator of the std-dev for normal data,
+// being 1/(√2·Φ⁻¹(5/8)).
+const qnNormalConsistency = 2.21914
+
+// qnSmallSampleCorrections are the factors making the Qn unbiased for normal samples of 2 to 9
+// values, as tabulated by Croux and Rousseeuw (1992). Larger samples get n/(n+1.4) if n is odd,
+// and n/(n+3.8) if it's even.
+var qnSmallSampleCorrections = []float64{0.399, 0.994, 0.512, 0.844, 0.611, 0.857, 0.669, 0.872}
+
+// computeQn returns the Rousseeuw-Croux Qn robust scale estimator of the sample, scaled to be
+// comparable to the std-dev for normally distributed data. It's the k-th smallest of the n(n-1)/2
+// absolute differences between the pairs of values, for k = h(h-1)/2 and h = n/2+1, i.e. about
+// their first quartile. Like the MAD, it has a breakdown point of 50% (half the values can be
+// arbitrarily bad), but it's much more efficient for normal data (82% vs 37%), and doesn't
+// assume the distribution to be symmetric. It's 0 for a single value (like the std-dev and
+// MAD), and NaN for an empty sample or one with a NaN value.
+//
+// Rather than sorting all the O(n²) differences, it selects the k-th smallest one in O(n log n)
+// time (on average), as proposed by Croux and Rousseeuw: with the values sorted, the differences
+// y[j]-y[i] (for j > i) form a matrix whose rows (by i) are sorted by their columns (j). Each
+// iteration keeps a range of candidate columns per row, takes the weighted median of the rows'
+// middle candidates (weighted by the no. of candidates of the rows, in linear time) as a trial
+// value, and counts the differences below and at most the trial value in O(n), walking the rows
+// and columns together. The k-th one is then either the trial value, or the candidates on one
+// side of it are all discarded, which discards at least a quarter of them. Once there are no
+// more than n left, the k-th is selected among them directly.
+func computeQn(sample []float64) float64 {
+	n := len(sample)
+	if n == 0 || hasNaN(sample) {
+		return math.NaN()
+	}
+	if n == 1 {
+		return 0
+	}
+	h := n/2 + 1
+	qn := qnNormalConsistency * kthPairwiseDifference(sortedCopy(sample), h*(h-1)/2)
+	if n <= 9 {
+		return qn * qnSmallSampleCorrections[n-2]
+	}
+	if n%2 == 1 {
+		return qn * float64(n) / (float64(n) + 1.4)
+	}
+	return qn * float64(n) / (float64(n) + 3.8)
+}
+
+// kthPairwiseDifference returns the k-th smallest (from 1) of the differences y[j]-y[i], for
+// j > i, of the sorted values (see computeQn).
+func kthPairwiseDifference(y []float64, k int) float64 {
+	n := len(y)
+	// Each row i has the candidate columns left[i] to right[i] (empty if left[i] > right[i]).
+	left, right := make([]int, n), make([]int, n)
+	for i := range y {
+		left[i], right[i] = i+1, n-1
+	}
+	candidates := n * (n - 1) / 2
+	below, atMost := make([]int, n), make([]int, n)
+	for candidates > n {
+		var middles, weights []float64
+		for i := range y {
+			if left[i] <= right[i] {
+				middles = append(middles, y[(left[i]+right[i])/2]-y[i])
+				weights = append(weights, float64(right[i]-left[i]+1))
+			}
+		}
+		trial := weightedMedian(middles, weights)
+		// below[i] and atMost[i] are the first columns of row i whose differences are at least
+		// and above the trial value, which don't decrease along the rows.
+		lessCount, atMostCount := 0, 0
+		column := 1
+		for i := range y {
+			if column <= i {
+				column = i + 1
+			}
+			for column < n && y[column]-y[i] < trial {
+				column++
+			}
+			below[i] = column
+			lessCount += column - (i + 1)
+		}
+		column = 1
+		for i := range y {
+			if column <= i {
+				column = i + 1
+			}
+			for column < n && y[column]-y[i] <= trial {
+				column++
+			}
+			atMost[i] = column
+			atMostCount += column - (i + 1)
+		}
+		switch {
+		case k <= lessCount:
+			for i := range y {
+				if below[i]-1 < right[i] {
+					right[i] = below[i] - 1
+				}
+			}
+		case k > atMostCount:
+			for i := range y {
+				if atMost[i] > left[i] {
+					left[i] = atMost[i]
+				}
+			}
+		default:
+			return trial
+		}
+		candidates = 0
+		for i := range y {
+			if left[i] <= right[i] {
+				candidates += right[i] - left[i] + 1
+			}
+		}
+	}
+	// The k-th is among the candidates left, all of which are above the differences discarded
+	// on their left.
+	var remaining []float64
+	for i := range y {
+		k -= left[i] - (i + 1)
+		for column := left[i]; column <= right[i]; column++ {
+			remaining = append(remaining, y[column]-y[i])
+		}
+	}
+	sort.Float64s(remaining)
+	return remaining[k-1]
+}
+
+// weightedMedian returns the (lower) weighted median of the values, i.e. the smallest one such
+// that the values up to it have at least half the total weight. It partitions the values around
+// pivots like quickselect does, taking linear time on average.
+func weightedMedian(values, weights []float64) float64 {
+	total := 0.0
+	for _, weight := range weights {
+		total += weight
+	}
+	values, weights = append([]float64{}, values...), append([]float64{}, weights...)
+	// The weight of the values discarded as below those left.
+	discarded := 0.0
+	for {
+		pivot := values[len(values)/2]
+		var lessValues, lessWeights, greaterValues, greaterWeights []float64
+		lessWeight, equalWeight := 0.0, 0.0
+		for i, value := range values {
+			switch {
+			case value < pivot:
+				lessValues, lessWeights = append(lessValues, value), append(lessWeights, weights[i])
+				lessWeight += weights[i]
+			case value > pivot:
+				greaterValues, greaterWeights = append(greaterValues, value), append(greaterWeights, weights[i])
+			default:
+				equalWeight += weights[i]
+			}
+		}
+		switch {
+		case 2*(discarded+lessWeight) >= total:
+			values, weights = lessValues, lessWeights
+		case 2*(discarded+lessWeight+equalWeight) >= total:
+			return pivot
+		default:
+			discarded += lessWeight + equalWeight
+			values, weights = greaterValues, greaterWeights
+		}
+	}
+}
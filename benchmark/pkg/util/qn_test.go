@@ -0,0 +1,80 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestComputeQn(t *testing.T) {
+	// For 1 to 10, h = 6 and the k = 15th smallest pairwise difference is 2 (there are 9
+	// differences of 1 and 8 of 2), corrected by 10/(10+3.8) for the even sample size.
+	if qn, expected := computeQn([]float64{3, 1, 4, 10, 5, 9, 2, 6, 8, 7}), 2.21914*2*10/13.8; math.Abs(qn-expected) > 1e-9 {
+		t.Errorf("Qn of 1 to 10 is %v, but expected %v", qn, expected)
+	}
+	// Two values are their difference apart, with the small-sample correction.
+	if qn, expected := computeQn([]float64{5, 1}), 2.21914*4*0.399; math.Abs(qn-expected) > 1e-9 {
+		t.Errorf("Qn of 2 values is %v, but expected %v", qn, expected)
+	}
+	// Up to half the values being arbitrarily bad barely change it.
+	clean := []float64{10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20}
+	contaminated := []float64{10, 11, 12, 13, 14, 15, 1e6, 1e6, 1e6, 1e6, 1e6}
+	if qn, contaminatedQn := computeQn(clean), computeQn(contaminated); contaminatedQn > 2*qn {
+		t.Errorf("Qn of the contaminated sample is %v, far from the clean one's %v", contaminatedQn, qn)
+	}
+	if qn := computeQn([]float64{7}); qn != 0 {
+		t.Errorf("Qn of a single value is %v, but expected 0", qn)
+	}
+	for _, sample := range [][]float64{nil, {1, math.NaN(), 2}} {
+		if qn := computeQn(sample); !math.IsNaN(qn) {
+			t.Errorf("Qn of %v was expected to be NaN, got %v", sample, qn)
+		}
+	}
+}
+
+func TestKthPairwiseDifference(t *testing.T) {
+	// Compare with sorting all the differences, including for samples with ties.
+	random := rand.New(rand.NewSource(1))
+	for _, n := range []int{2, 3, 4, 5, 10, 11, 50, 101, 400} {
+		for _, ties := range []bool{false, true} {
+			sample := make([]float64, n)
+			for i := range sample {
+				sample[i] = random.NormFloat64()
+				if ties {
+					sample[i] = math.Round(sample[i] * 3)
+				}
+			}
+			sorted := sortedCopy(sample)
+			var differences []float64
+			for i := range sorted {
+				for j := i + 1; j < n; j++ {
+					differences = append(differences, sorted[j]-sorted[i])
+				}
+			}
+			sort.Float64s(differences)
+			h := n/2 + 1
+			for _, k := range []int{1, h * (h - 1) / 2, len(differences)} {
+				if difference := kthPairwiseDifference(sorted, k); difference != differences[k-1] {
+					t.Errorf("The %v-th pairwise difference of %v values (ties: %v) is %v, but expected %v", k, n, ties, difference, differences[k-1])
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+// RebaseToRun makes the left job's run of the given index the reference the right job's runs
+// are compared against, rather than all the left job's runs pooled, e.g. for bisecting over
+// the runs. Each metric's left sample then holds only the value(s) from that run, along with
+// their request counts and timestamps (if retained), while the right sample is kept whole.
+// It requires the samples to be run-aligned (see FlattenOptions.KeepRunIndices). The metrics
+// missing from the chosen run, or without run indices, are left without a left sample, as
+// there's no reference to compare them against: they then show up as present only in the
+// right job (see CompatibilityReport). As the stats computed earlier (if any) are now
+// outdated, they are marked dirty until ComputeStatsForMetricSamples is called again.
+func (j *JobComparisonData) RebaseToRun(runIndex int) {
+	for _, metricData := range j.Data {
+		runIndices := metricData.LeftJobRunIndices
+		if len(runIndices) != len(metricData.LeftJobSample) {
+			runIndices = nil
+		}
+		var kept []int
+		for i, index := range runIndices {
+			if index == runIndex {
+				kept = append(kept, i)
+			}
+		}
+		metricData.LeftJobSample = selectFloats(metricData.LeftJobSample, kept)
+		metricData.invalidateStats()
+		if len(metricData.LeftJobRequestCounts) == len(runIndices) {
+			metricData.LeftJobRequestCounts = selectFloats(metricData.LeftJobRequestCounts, kept)
+		} else {
+			metricData.LeftJobRequestCounts = nil
+		}
+		if len(metricData.LeftJobTimestamps) == len(runIndices) && len(kept) > 0 {
+			timestamps := metricData.LeftJobTimestamps
+			metricData.LeftJobTimestamps = nil
+			for _, i := range kept {
+				metricData.LeftJobTimestamps = append(metricData.LeftJobTimestamps, timestamps[i])
+			}
+		} else {
+			metricData.LeftJobTimestamps = nil
+		}
+		metricData.LeftJobRunIndices = nil
+		for range kept {
+			metricData.LeftJobRunIndices = append(metricData.LeftJobRunIndices, runIndex)
+		}
+	}
+	j.statsDirty = true
+}
+
+// selectFloats returns the values at the given positions (nil if there are none).
+func selectFloats(values []float64, positions []int) []float64 {
+	var selected []float64
+	for _, i := range positions {
+		selected = append(selected, values[i])
+	}
+	return selected
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/kubernetes/test/e2e/perftype"
+)
+
+func TestRebaseToRun(t *testing.T) {
+	runMetrics := func(latency float64, withList bool) map[string][]perftype.PerfData {
+		dataItems := []perftype.DataItem{
+			{Data: map[string]float64{"Perc99": latency}, Unit: "ms", Labels: map[string]string{"Count": "10", "Resource": "pods", "Verb": "GET"}},
+		}
+		if withList {
+			dataItems = append(dataItems, perftype.DataItem{Data: map[string]float64{"Perc99": 2 * latency}, Unit: "ms", Labels: map[string]string{"Count": "10", "Resource": "pods", "Verb": "LIST"}})
+		}
+		return map[string][]perftype.PerfData{"Load": {{Version: "v1", DataItems: dataItems}}}
+	}
+	getKey := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	listKey := MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}
+	// The LIST metric is missing from the left job's second run.
+	leftJobMetrics := []map[string][]perftype.PerfData{runMetrics(10, true), runMetrics(50, false), runMetrics(20, true)}
+	rightJobMetrics := []map[string][]perftype.PerfData{runMetrics(45, true), runMetrics(55, true)}
+	j := GetFlattennedComparisonDataWithOptions(leftJobMetrics, rightJobMetrics, FlattenOptions{MinAllowedAPIRequestCount: 10, KeepRequestCounts: true, KeepRunIndices: true})
+	j.ComputeStatsForMetricSamples()
+
+	j.RebaseToRun(1)
+	if !j.StatsDirty() {
+		t.Errorf("Stats not marked dirty after rebasing")
+	}
+	getData := j.Data[getKey]
+	if !reflect.DeepEqual(getData.LeftJobSample, []float64{50}) || !reflect.DeepEqual(getData.LeftJobRequestCounts, []float64{10}) || !reflect.DeepEqual(getData.LeftJobRunIndices, []int{1}) {
+		t.Errorf("Left side not rebased to the run: %+v", getData)
+	}
+	if !reflect.DeepEqual(getData.RightJobSample, []float64{45, 55}) || !reflect.DeepEqual(getData.RightJobRunIndices, []int{0, 1}) {
+		t.Errorf("Right side changed by rebasing: %+v", getData)
+	}
+	j.ComputeStatsForMetricSamples()
+	if getData.AvgL != 50 {
+		t.Errorf("Left avg after rebasing is %v, but expected the run's value of 50", getData.AvgL)
+	}
+
+	// The metric missing from the run has no reference left.
+	listData := j.Data[listKey]
+	if listData.LeftJobSample != nil || listData.LeftJobRunIndices != nil || len(listData.RightJobSample) != 2 {
+		t.Errorf("Metric missing from the run has a left side after rebasing: %+v", listData)
+	}
+	if report := j.CompatibilityReport(); !reflect.DeepEqual(report.RightOnly, []MetricKey{listKey}) {
+		t.Errorf("Metrics present only in the right job after rebasing are %v, but expected %v", report.RightOnly, []MetricKey{listKey})
+	}
+
+	// Samples that aren't run-aligned can't be rebased.
+	j = GetFlattennedComparisonData(leftJobMetrics, rightJobMetrics, 10)
+	j.RebaseToRun(0)
+	if leftSample := j.Data[getKey].LeftJobSample; leftSample != nil {
+		t.Errorf("Left sample without run indices rebased to %v", leftSample)
+	}
+}
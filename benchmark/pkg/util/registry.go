@@ -0,0 +1,133 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ComparisonScheme compares the left and right job samples of each metric in the given
+// data and fills in the comparison results in the metric's object. Interpretation of
+// matchThreshold depends on the scheme.
+type ComparisonScheme func(j *JobComparisonData, matchThreshold, minMetricAvgForCompare float64)
+
+// WithThresholds returns the comparison scheme bound to the given thresholds, i.e. ignoring
+// those it gets called with. It's for passing schemes to callers that don't know which
+// thresholds they need (like SubsampleCompare).
+func WithThresholds(scheme ComparisonScheme, matchThreshold, minMetricAvgForCompare float64) ComparisonScheme {
+	return func(j *JobComparisonData, _, _ float64) {
+		scheme(j, matchThreshold, minMetricAvgForCompare)
+	}
+}
+
+// ContextComparisonScheme is like ComparisonScheme, but for schemes expensive enough to need
+// their runtime bounded. Once ctx is done, it stops comparing and marks the metrics it didn't
+// get to as timed out (see MarkTimedOut), rather than running to completion.
+type ContextComparisonScheme func(ctx context.Context, j *JobComparisonData, matchThreshold, minMetricAvgForCompare float64)
+
+// WithMetricSettings returns the comparison scheme honoring the settings of the metrics the
+// schemes don't know about themselves: the metrics with a transform are compared on the
+// transformed scale (see WithTransforms), noting in their comments when they kept their
+// previous verdict due to the hysteresis band (see WithHysteresis). Any comparison of the
+// metrics by a scheme is to go through it.
+func WithMetricSettings(scheme ContextComparisonScheme) ContextComparisonScheme {
+	return WithTransforms(WithHysteresis(scheme))
+}
+
+var (
+	comparisonSchemesLock    sync.RWMutex
+	comparisonSchemes        = make(map[string]ComparisonScheme)
+	contextComparisonSchemes = make(map[string]ContextComparisonScheme)
+)
+
+// RegisterComparisonScheme makes a comparison scheme available by the given name. The
+// built-in schemes are registered by the comparer package (k8s.io/perf-tests/benchmark/pkg/comparer)
+// when imported, so users of the registry (like policies) need it imported to find them.
+func RegisterComparisonScheme(name string, scheme ComparisonScheme) {
+	comparisonSchemesLock.Lock()
+	defer comparisonSchemesLock.Unlock()
+	comparisonSchemes[name] = scheme
+}
+
+// RegisterContextComparisonScheme makes a context-aware comparison scheme available by the given
+// name. It's also available through GetComparisonScheme, running without any bound then.
+func RegisterContextComparisonScheme(name string, scheme ContextComparisonScheme) {
+	comparisonSchemesLock.Lock()
+	defer comparisonSchemesLock.Unlock()
+	contextComparisonSchemes[name] = scheme
+	comparisonSchemes[name] = func(j *JobComparisonData, matchThreshold, minMetricAvgForCompare float64) {
+		scheme(context.Background(), j, matchThreshold, minMetricAvgForCompare)
+	}
+}
+
+// unregisterComparisonScheme makes the comparison scheme registered by the given name (if any)
+// unavailable again, so that tests registering fake schemes don't leak them into other tests.
+func unregisterComparisonScheme(name string) {
+	comparisonSchemesLock.Lock()
+	defer comparisonSchemesLock.Unlock()
+	delete(comparisonSchemes, name)
+	delete(contextComparisonSchemes, name)
+}
+
+// GetContextComparisonScheme returns the comparison scheme registered by the given name,
+// bounded by a context. Schemes registered without context support are run to completion
+// once started, but not started at all (timing out all metrics) if the context is done.
+func GetContextComparisonScheme(name string) (ContextComparisonScheme, error) {
+	comparisonSchemesLock.RLock()
+	contextScheme, ok := contextComparisonSchemes[name]
+	comparisonSchemesLock.RUnlock()
+	if ok {
+		return contextScheme, nil
+	}
+	scheme, err := GetComparisonScheme(name)
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context, j *JobComparisonData, matchThreshold, minMetricAvgForCompare float64) {
+		if err := ctx.Err(); err != nil {
+			j.MarkTimedOut(err)
+			return
+		}
+		scheme(j, matchThreshold, minMetricAvgForCompare)
+	}, nil
+}
+
+// GetComparisonScheme returns the comparison scheme registered by the given name.
+func GetComparisonScheme(name string) (ComparisonScheme, error) {
+	comparisonSchemesLock.RLock()
+	defer comparisonSchemesLock.RUnlock()
+	scheme, ok := comparisonSchemes[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown comparison scheme '%v' (is the comparer package imported?)", name)
+	}
+	return scheme, nil
+}
+
+// ComparisonSchemeNames returns the (sorted) names of all the registered comparison schemes.
+func ComparisonSchemeNames() []string {
+	comparisonSchemesLock.RLock()
+	defer comparisonSchemesLock.RUnlock()
+	var names []string
+	for name := range comparisonSchemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
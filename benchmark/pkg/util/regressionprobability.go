@@ -0,0 +1,57 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"math"
+)
+
+// RegressionProbability returns the confidence that the right sample regressed against the left
+// one, as 1 minus the p-value of a one-sided Welch's t-test of the right mean being above the
+// left one (or below it, for rates). It's a probability in [0, 1], e.g. 0.92 reading as "92%
+// confident it regressed", with 0.5 meaning no evidence either way and values near 0 telling
+// of an improvement. It's NaN if either sample has less than 2 values, for which the test
+// can't be run.
+func RegressionProbability(left, right []float64, isRate bool) float64 {
+	diff, standardError, df := welchTestStats(left, right)
+	if math.IsNaN(diff) || math.IsNaN(standardError) {
+		return math.NaN()
+	}
+	if isRate {
+		diff = -diff
+	}
+	if standardError == 0 {
+		// The difference is known exactly, the test is certain.
+		switch {
+		case diff > 0:
+			return 1
+		case diff < 0:
+			return 0
+		}
+		return 0.5
+	}
+	return StudentTCDF(diff/standardError, df)
+}
+
+// ComputeRegressionProbabilities sets each metric's RegressionProbability from its samples (see
+// RegressionProbability), in the regression direction of the metric (i.e. down for rates, so
+// Annotate should have been called already). The metrics' verdicts are left as they are.
+func (j *JobComparisonData) ComputeRegressionProbabilities() {
+	for _, metricData := range j.Data {
+		metricData.RegressionProbability = RegressionProbability(metricData.LeftJobSample, metricData.RightJobSample, metricData.IsRate)
+	}
+}
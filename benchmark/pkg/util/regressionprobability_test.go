@@ -0,0 +1,103 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRegressionProbability(t *testing.T) {
+	testCases := []struct {
+		name        string
+		left, right []float64
+		isRate      bool
+		low, high   float64
+	}{
+		{
+			name:  "clear regression",
+			left:  []float64{100, 102, 98, 101, 99},
+			right: []float64{130, 128, 132, 129, 131},
+			low:   0.999,
+			high:  1,
+		},
+		{
+			name:  "clear improvement",
+			left:  []float64{130, 128, 132, 129, 131},
+			right: []float64{100, 102, 98, 101, 99},
+			low:   0,
+			high:  0.001,
+		},
+		{
+			name:   "rate going down",
+			left:   []float64{130, 128, 132, 129, 131},
+			right:  []float64{100, 102, 98, 101, 99},
+			isRate: true,
+			low:    0.999,
+			high:   1,
+		},
+		{
+			name:  "noise",
+			left:  []float64{100, 120, 80, 110, 90},
+			right: []float64{101, 121, 81, 111, 91},
+			low:   0.5,
+			high:  0.6,
+		},
+		{
+			name:  "exact difference",
+			left:  []float64{100, 100},
+			right: []float64{110, 110},
+			low:   1,
+			high:  1,
+		},
+		{
+			name:  "no difference",
+			left:  []float64{100, 100},
+			right: []float64{100, 100},
+			low:   0.5,
+			high:  0.5,
+		},
+	}
+	for _, testCase := range testCases {
+		probability := RegressionProbability(testCase.left, testCase.right, testCase.isRate)
+		if !(probability >= testCase.low && probability <= testCase.high) {
+			t.Errorf("%v: regression probability %v out of [%v, %v]", testCase.name, probability, testCase.low, testCase.high)
+		}
+	}
+	if probability := RegressionProbability([]float64{100}, []float64{100, 110}, false); !math.IsNaN(probability) {
+		t.Errorf("Regression probability of a single-run sample was expected to be NaN, got %v", probability)
+	}
+}
+
+func TestComputeRegressionProbabilities(t *testing.T) {
+	regressed := MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}
+	singleRun := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	j := &JobComparisonData{Data: map[MetricKey]*MetricComparisonData{
+		regressed: {LeftJobSample: []float64{100, 102, 98}, RightJobSample: []float64{130, 128, 132}, Matched: true},
+		singleRun: {LeftJobSample: []float64{100}, RightJobSample: []float64{130}},
+	}}
+	j.ComputeRegressionProbabilities()
+	if probability := j.Data[regressed].RegressionProbability; !(probability > 0.99 && probability <= 1) {
+		t.Errorf("Wrong regression probability of the regressed metric: %v", probability)
+	}
+	if !j.Data[regressed].Matched {
+		t.Errorf("Verdict changed by computing the regression probabilities")
+	}
+	if probability := j.Data[singleRun].RegressionProbability; !math.IsNaN(probability) {
+		t.Errorf("Regression probability of the single-run metric was expected to be NaN, got %v", probability)
+	}
+}
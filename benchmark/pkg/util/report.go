@@ -0,0 +1,119 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+)
+
+// ReadJSONReport reads the job comparison data of a report written by WriteJSON (or with any
+// NaN policy, or with the warnings), e.g. that of a previous comparison to compare against.
+// The metrics get their keys, verdicts, comments, the stats written, and their samples and
+// labels if verbose. The stats not written (e.g. the expected shortfalls) are left zero, and
+// the stats written are kept by EnsureStats as long as the samples don't change.
+func ReadJSONReport(r io.Reader) (*JobComparisonData, error) {
+	contents, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var rawRecords []json.RawMessage
+	if trimmed := bytes.TrimSpace(contents); len(trimmed) > 0 && trimmed[0] == '{' {
+		var report struct {
+			Metrics []json.RawMessage `json:"metrics"`
+		}
+		err = Unmarshaler(contents, &report)
+		rawRecords = report.Metrics
+	} else {
+		err = Unmarshaler(contents, &rawRecords)
+	}
+	if err != nil {
+		return nil, err
+	}
+	j := NewJobComparisonData()
+	for i, rawRecord := range rawRecords {
+		record := metricRecord{}
+		if err := Unmarshaler(rawRecord, &record); err != nil {
+			return nil, fmt.Errorf("metric #%v: %v", i, err)
+		}
+		key := MetricKey{record.TestName, record.Verb, record.Resource, record.Subresource, record.Scope, record.Percentile, record.Platform, record.SizeBucket, record.Population}
+		if _, ok := j.Data[key]; ok {
+			return nil, fmt.Errorf("metric %v reported more than once", metricName(key))
+		}
+		j.Data[key] = record.metricData()
+	}
+	return j, nil
+}
+
+// metricData returns the comparison data of the metric the record was written for.
+func (record *metricRecord) metricData() *MetricComparisonData {
+	data := &MetricComparisonData{
+		LeftJobSample:  record.LeftJobSample,
+		RightJobSample: record.RightJobSample,
+		Unit:           record.Unit,
+		Matched:        record.Matched,
+		Inconclusive:   record.Inconclusive,
+		Comments:       record.Comments,
+		AvgL:           record.AvgL.float(),
+		AvgR:           record.AvgR.float(),
+		AvgRatio:       record.AvgRatio.float(),
+		StDevL:         record.StDevL.float(),
+		StDevR:         record.StDevR.float(),
+		MaxL:           record.MaxL.float(),
+		MaxR:           record.MaxR.float(),
+		MaxRatio:       record.MaxRatio.float(),
+		MADL:           record.MADL.float(),
+		MADR:           record.MADR.float(),
+		QnL:            record.QnL.float(),
+		QnR:            record.QnR.float(),
+		GlassDelta:     record.GlassDelta.float(),
+		CDFArea:        record.CDFArea.float(),
+		SNR:            record.SNR.float(),
+		Tier:           record.Tier,
+		Owner:          record.Owner,
+		Transform:      record.Transform,
+		IsRate:         record.Rate,
+		Labels:         record.Labels,
+
+		NegativeSampleCount:      record.NegativeSampleCount,
+		SustainedRegressionCount: record.SustainedRegressions,
+	}
+	if record.Rate {
+		data.HarmonicMeanL, data.HarmonicMeanR = record.HarmonicMeanL.float(), record.HarmonicMeanR.float()
+	}
+	data.PValue = math.NaN()
+	data.statsValid, data.statsFingerprint = true, data.samplesFingerprint()
+	return data
+}
+
+// LoadJSONReport reads the job comparison data of the report (see ReadJSONReport) from the JSON
+// file at the given path.
+func LoadJSONReport(filePath string) (*JobComparisonData, error) {
+	contents, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read report file: %v", err)
+	}
+	j, err := ReadJSONReport(bytes.NewReader(contents))
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse report file %v: %v", filePath, err)
+	}
+	return j, nil
+}
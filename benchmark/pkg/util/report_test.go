@@ -0,0 +1,112 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadJSONReport(t *testing.T) {
+	latency := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99", Population: "ns-1"}
+	rate := MetricKey{TestName: "Throughput", Verb: "POST", Resource: "pods", Percentile: "Perc50"}
+	j := &JobComparisonData{
+		Data: map[MetricKey]*MetricComparisonData{
+			latency: {LeftJobSample: []float64{90, 110}, RightJobSample: []float64{150}, Unit: "ms", Labels: map[string]string{"Namespace": "ns-1"}},
+			rate:    {LeftJobSample: []float64{20}, RightJobSample: []float64{15}, IsRate: true},
+		},
+	}
+	j.ComputeStatsForMetricSamples()
+	for _, metricData := range j.Data {
+		metricData.ResetVerdict()
+	}
+	j.Data[latency].Comments, j.Data[latency].AvgRatio = "AvgR/L=1.50", 1.5
+	j.Data[rate].MarkInconclusive("timed out")
+	j.Data[rate].AvgRatio = math.NaN()
+
+	for _, policy := range []NaNPolicy{NaNDefault, NaNAsString, NaNOmitted} {
+		var buf bytes.Buffer
+		if err := j.WriteJSONWithNaNPolicy(&buf, true, policy); err != nil {
+			t.Fatalf("Writing the report failed: %v", err)
+		}
+		report, err := ReadJSONReport(&buf)
+		if err != nil {
+			t.Fatalf("Reading the report (with NaN policy %v) failed: %v", policy, err)
+		}
+		if len(report.Data) != 2 {
+			t.Fatalf("Read metrics %v, but expected %v and %v", report.Data, latency, rate)
+		}
+		data, expected := report.Data[latency], j.Data[latency]
+		if data == nil || data.Matched || data.Comments != expected.Comments || data.Unit != "ms" || data.AvgR != 150 || data.AvgRatio != 1.5 ||
+			!reflect.DeepEqual(data.LeftJobSample, expected.LeftJobSample) || data.Labels["Namespace"] != "ns-1" {
+			t.Errorf("Read metric %v as %+v, but expected %+v", latency, data, expected)
+		}
+		// The stats read are kept, rather than recomputed (and lost) from the (non-verbose) samples.
+		report.EnsureStats()
+		if data.AvgR != 150 {
+			t.Errorf("Metric %v has a right avg of %v once its stats are ensured, but expected 150", latency, data.AvgR)
+		}
+		// The NaN avg ratio is NaN, whichever way it was written.
+		if data := report.Data[rate]; data == nil || !data.Inconclusive || !data.IsRate || data.HarmonicMeanL != 20 || !math.IsNaN(data.AvgRatio) {
+			t.Errorf("Read metric %v as %+v, but expected %+v", rate, data, j.Data[rate])
+		}
+	}
+
+	// The warnings are skipped.
+	var buf bytes.Buffer
+	if err := j.WriteJSONWithWarnings(&buf, false, NaNDefault); err != nil {
+		t.Fatalf("Writing the report failed: %v", err)
+	}
+	if report, err := ReadJSONReport(&buf); err != nil || len(report.Data) != 2 {
+		t.Errorf("Read the report with warnings as %v (%v), but expected the 2 metrics", report, err)
+	}
+
+	for _, contents := range []string{`{"metrics": 1}`, `[{"avgL": "x"}]`, `[{"testName": "Load"}, {"testName": "Load"}]`} {
+		if _, err := ReadJSONReport(strings.NewReader(contents)); err == nil {
+			t.Errorf("Reading report %v succeeded, but expected an error", contents)
+		}
+	}
+}
+
+func TestLoadJSONReport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "report")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "report.json")
+	if err := ioutil.WriteFile(path, []byte(`[{"testName": "Load", "verb": "GET", "percentile": "Perc99", "matched": false, "avgL": 100, "avgR": 150}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	report, err := LoadJSONReport(path)
+	if err != nil {
+		t.Fatalf("Loading the report failed: %v", err)
+	}
+	data := report.Data[MetricKey{TestName: "Load", Verb: "GET", Percentile: "Perc99"}]
+	if data == nil || !data.regressed() || !math.IsNaN(data.AvgRatio) {
+		t.Errorf("Loaded metrics %v, but expected a regression without an avg ratio", report.Data)
+	}
+	if _, err := LoadJSONReport(filepath.Join(dir, "missing.json")); err == nil {
+		t.Errorf("Loading a missing report succeeded, but expected an error")
+	}
+}
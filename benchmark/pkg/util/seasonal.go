@@ -0,0 +1,204 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"math"
+)
+
+// SeasonalDecomposition is the classical additive decomposition of a series into its trend,
+// its seasonal component of a given period, and the residual, i.e. value = trend + seasonal +
+// residual. The trend and residual are NaN at the ends of the series, where the moving average
+// doesn't fit.
+type SeasonalDecomposition struct {
+	Period   int
+	Trend    []float64
+	Seasonal []float64 // The component of each point (repeating every Period points)
+	Residual []float64
+}
+
+// MinSeasonalPeriods is the min no. of whole periods a series needs for it to be decomposed.
+const MinSeasonalPeriods = 2
+
+// DecomposeSeasonal decomposes the series into a trend, a seasonal component of the given period
+// (e.g. 7 for weekly seasonality of daily builds) and the residual, by simple moving averages:
+//  1. The trend is the centered moving average of a period (a 2xperiod one if the period is even,
+//     to be centered), which averages the seasonality out. It's undefined for the first and
+//     last period/2 points, whose window doesn't fit in the series.
+//  2. The seasonal component of each phase (the index modulo the period) is the mean of the
+//     detrended values (value - trend) of the points of that phase, shifted for the components
+//     of a period to sum to zero, so that they don't carry any of the level.
+//  3. The residual is what's left of the value.
+//
+// The period has to be at least 2, and the series has to have at least MinSeasonalPeriods whole
+// periods of values, for each phase to have a detrended value (and more than one for most). It
+// mustn't have any NaN values. The points are taken as evenly spaced, e.g. a build per day.
+func DecomposeSeasonal(values []float64, period int) (*SeasonalDecomposition, error) {
+	if period < 2 {
+		return nil, fmt.Errorf("seasonal period %v is less than 2", period)
+	}
+	if len(values) < MinSeasonalPeriods*period {
+		return nil, fmt.Errorf("series of %v values is shorter than %v periods of %v", len(values), MinSeasonalPeriods, period)
+	}
+	if hasNaN(values) {
+		return nil, fmt.Errorf("series has NaN values")
+	}
+	n, half := len(values), period/2
+	d := &SeasonalDecomposition{Period: period, Trend: make([]float64, n), Seasonal: make([]float64, n), Residual: make([]float64, n)}
+	for i := range values {
+		d.Trend[i] = math.NaN()
+		if i < half || i >= n-half {
+			continue
+		}
+		sum := 0.0
+		if period%2 == 1 {
+			for k := i - half; k <= i+half; k++ {
+				sum += values[k]
+			}
+		} else {
+			// The ends of the window of period+1 points get half the weight.
+			sum = (values[i-half] + values[i+half]) / 2
+			for k := i - half + 1; k < i+half; k++ {
+				sum += values[k]
+			}
+		}
+		d.Trend[i] = sum / float64(period)
+	}
+	phaseSums, phaseCounts := make([]float64, period), make([]int, period)
+	for i, value := range values {
+		if !math.IsNaN(d.Trend[i]) {
+			phaseSums[i%period] += value - d.Trend[i]
+			phaseCounts[i%period]++
+		}
+	}
+	components := make([]float64, period)
+	for phase := range components {
+		components[phase] = phaseSums[phase] / float64(phaseCounts[phase])
+	}
+	level := Mean(components)
+	for i, value := range values {
+		d.Seasonal[i] = components[i%period] - level
+		d.Residual[i] = value - d.Trend[i] - d.Seasonal[i]
+	}
+	return d, nil
+}
+
+// Expected returns the value expected at the given index of the series (e.g. the one right
+// after it, for its next build), adjusted for the trend and seasonality: the linear fit (least
+// squares) of the trend extrapolated to the index, plus the seasonal component of its phase.
+func (d *SeasonalDecomposition) Expected(index int) float64 {
+	var xs, ys []float64
+	for i, trend := range d.Trend {
+		if !math.IsNaN(trend) {
+			xs, ys = append(xs, float64(i)), append(ys, trend)
+		}
+	}
+	meanX, meanY := Mean(xs), Mean(ys)
+	slope := 0.0
+	if len(xs) > 1 {
+		covariance, varianceX := 0.0, 0.0
+		for i := range xs {
+			covariance += (xs[i] - meanX) * (ys[i] - meanY)
+			varianceX += (xs[i] - meanX) * (xs[i] - meanX)
+		}
+		slope = covariance / varianceX
+	}
+	phase := ((index % d.Period) + d.Period) % d.Period
+	return meanY + slope*(float64(index)-meanX) + d.Seasonal[phase]
+}
+
+// residualStdDev returns the std-dev of the (defined) residuals, NaN if there are less than 2.
+func (d *SeasonalDecomposition) residualStdDev() float64 {
+	var residuals []float64
+	for _, residual := range d.Residual {
+		if !math.IsNaN(residual) {
+			residuals = append(residuals, residual)
+		}
+	}
+	return math.Sqrt(SampleVariance(residuals))
+}
+
+// BandOptions tunes how CompareAgainstBand establishes the comparison band of each metric from
+// its series.
+type BandOptions struct {
+	// Width is the half-width of the band, in std-devs of the series (or of the residuals of its
+	// decomposition, if seasonally adjusted).
+	Width float64
+	// SeasonalPeriod, if positive, is the period (in builds) of the seasonality to adjust for, by
+	// detrending and deseasonalizing the series (see DecomposeSeasonal) before establishing the
+	// band, around the expected value of the next build instead of the mean of the series.
+	SeasonalPeriod int
+}
+
+// band returns the expected next value of the series, and the half-width of the band around it.
+func (o BandOptions) band(values []float64) (expected, halfWidth float64, err error) {
+	if o.SeasonalPeriod <= 0 {
+		if len(values) < 2 {
+			return math.NaN(), math.NaN(), fmt.Errorf("series of %v values is too short for a band", len(values))
+		}
+		return Mean(values), o.Width * math.Sqrt(SampleVariance(values)), nil
+	}
+	decomposition, err := DecomposeSeasonal(values, o.SeasonalPeriod)
+	if err != nil {
+		return math.NaN(), math.NaN(), err
+	}
+	return decomposition.Expected(len(values)), o.Width * decomposition.residualStdDev(), nil
+}
+
+// CompareAgainstBand compares each metric's right job avg (e.g. that of a new build's runs)
+// against the band its series (as the baseline) expects the next build in, i.e. the expected
+// value +/- Width std-devs. The metric is flagged as a mismatch if its avg is out of the band,
+// either way. Without a SeasonalPeriod, the expected value is the mean of the series, and the
+// std-dev that of its values, which for a series with drift or seasonality (e.g. slower builds
+// on Mondays than on Fridays) gives a wide band that is still off for most builds. With it, the
+// series is decomposed (see DecomposeSeasonal), and the build is judged against the seasonally
+// adjusted expectation (see SeasonalDecomposition.Expected), in a band of the std-dev of the
+// residuals, i.e. of the noise left. The builds are taken as evenly spaced, the new one coming
+// right after the series' latest.
+//
+// Metrics without a series (or right job samples) are treated as matched, while those whose
+// series is too short for the band (less than 2 values, or MinSeasonalPeriods periods if
+// seasonally adjusted) or has NaN values are marked inconclusive.
+func (ts *TimeSeries) CompareAgainstBand(j *JobComparisonData, options BandOptions) {
+	for metricKey, metricData := range j.Data {
+		metricData.ResetVerdict()
+		points, avgR := ts.Series[metricKey], Mean(metricData.RightJobSample)
+		if len(points) == 0 || len(metricData.RightJobSample) == 0 {
+			metricData.Matched = true
+			continue
+		}
+		values := make([]float64, len(points))
+		for i, point := range points {
+			values[i] = point.Value
+		}
+		expected, halfWidth, err := options.band(values)
+		if err == nil && (math.IsNaN(expected) || math.IsNaN(halfWidth)) {
+			err = fmt.Errorf("series has NaN values")
+		}
+		if err != nil {
+			metricData.MarkInconclusive(fmt.Sprintf("No band: %v\tAvgR(ms)=%.2f\tN=%v", err, avgR, len(values)))
+			continue
+		}
+		low, high := expected-halfWidth, expected+halfWidth
+		metricData.Matched = low <= avgR && avgR <= high
+		metricData.Comments = fmt.Sprintf("AvgR(ms)=%.2f\tExpected(ms)=%.2f\tBand=[%.2f, %.2f]\tN=%v", avgR, expected, low, high, len(values))
+		if options.SeasonalPeriod > 0 {
+			metricData.Comments += fmt.Sprintf("\tSeasonalPeriod=%v", options.SeasonalPeriod)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// weeklySeries returns a synthetic series of daily builds (starting on a Monday) with an
+// upward drift of 0.5 per build, a weekly seasonality (Mondays being the slowest and Fridays
+// the fastest) and a bit of noise.
+func weeklySeries(builds int) []float64 {
+	weekly := []float64{30, 10, 0, -5, -20, -10, -5}
+	values := make([]float64, builds)
+	for i := range values {
+		noise := float64((i*37)%5-2) * 0.2
+		values[i] = 100 + 0.5*float64(i) + weekly[i%7] + noise
+	}
+	return values
+}
+
+func TestDecomposeSeasonal(t *testing.T) {
+	values := weeklySeries(56)
+	d, err := DecomposeSeasonal(values, 7)
+	if err != nil {
+		t.Fatalf("Decomposing failed: %v", err)
+	}
+	// The week's components are recovered, up to the noise.
+	for phase, expected := range []float64{30, 10, 0, -5, -20, -10, -5} {
+		if math.Abs(d.Seasonal[phase]-expected) > 0.5 || d.Seasonal[phase+7] != d.Seasonal[phase] {
+			t.Errorf("Seasonal component of day %v is %v, but expected about %v", phase, d.Seasonal[phase], expected)
+		}
+	}
+	for i, trend := range d.Trend {
+		if i < 3 || i >= 53 {
+			if !math.IsNaN(trend) || !math.IsNaN(d.Residual[i]) {
+				t.Errorf("Trend at %v is %v, but expected it undefined at the ends", i, trend)
+			}
+			continue
+		}
+		if math.Abs(trend-(100+0.5*float64(i))) > 0.5 || math.Abs(values[i]-trend-d.Seasonal[i]-d.Residual[i]) > 1e-9 {
+			t.Errorf("Trend at %v is %v (residual %v), but expected about %v", i, trend, d.Residual[i], 100+0.5*float64(i))
+		}
+	}
+	// The next build is a Monday.
+	if expected := d.Expected(56); math.Abs(expected-158) > 1 {
+		t.Errorf("Expected next value is %v, but expected about 158", expected)
+	}
+
+	// An even period gets a centered (2x4) moving average, recovering a pure seasonality exactly.
+	d, err = DecomposeSeasonal([]float64{4, 0, 2, 2, 4, 0, 2, 2, 4, 0, 2, 2}, 4)
+	if err != nil {
+		t.Fatalf("Decomposing failed: %v", err)
+	}
+	for phase, expected := range []float64{2, -2, 0, 0} {
+		if math.Abs(d.Seasonal[phase]-expected) > 1e-9 || math.Abs(d.Trend[phase+4]-2) > 1e-9 {
+			t.Errorf("Decomposed phase %v into seasonal %v and trend %v, but expected %v and 2", phase, d.Seasonal[phase], d.Trend[phase+4], expected)
+		}
+	}
+
+	for _, testCase := range []struct {
+		values []float64
+		period int
+	}{
+		{weeklySeries(13), 7},
+		{weeklySeries(14), 1},
+		{append(weeklySeries(13), math.NaN()), 7},
+	} {
+		if _, err := DecomposeSeasonal(testCase.values, testCase.period); err == nil {
+			t.Errorf("Decomposing %v values with period %v succeeded, but expected an error", len(testCase.values), testCase.period)
+		}
+	}
+}
+
+func TestCompareAgainstBand(t *testing.T) {
+	key := MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}
+	ts := NewTimeSeries()
+	for _, value := range weeklySeries(56) {
+		ts.Series[key] = append(ts.Series[key], TimeSeriesPoint{Value: value})
+	}
+	newBuild := func(value float64) *JobComparisonData {
+		j := NewJobComparisonData()
+		j.Data[key] = &MetricComparisonData{RightJobSample: []float64{value}}
+		return j
+	}
+
+	// A usual Monday is out of the band of the raw series, which its drift and seasonality make
+	// both wide and off, but within that of the seasonally adjusted expectation.
+	j := newBuild(158)
+	ts.CompareAgainstBand(j, BandOptions{Width: 2})
+	if j.Data[key].Matched {
+		t.Errorf("Usual Monday matched the band of the raw series: %v", j.Data[key].Comments)
+	}
+	ts.CompareAgainstBand(j, BandOptions{Width: 2, SeasonalPeriod: 7})
+	if data := j.Data[key]; !data.Matched || !strings.Contains(data.Comments, "SeasonalPeriod=7") {
+		t.Errorf("Usual Monday mismatched the seasonally adjusted band: %v", data.Comments)
+	}
+
+	// A Monday as slow as usual on Fridays is an improvement, and one 10% slower than usual a regression.
+	for _, value := range []float64{108, 174} {
+		j = newBuild(value)
+		ts.CompareAgainstBand(j, BandOptions{Width: 2, SeasonalPeriod: 7})
+		if j.Data[key].Matched {
+			t.Errorf("Monday of %v matched the seasonally adjusted band: %v", value, j.Data[key].Comments)
+		}
+	}
+
+	// Too short a series has no seasonal band, and a metric without a series matches.
+	other := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	ts.Series[key] = ts.Series[key][:10]
+	j = newBuild(158)
+	j.Data[other] = &MetricComparisonData{RightJobSample: []float64{100}}
+	ts.CompareAgainstBand(j, BandOptions{Width: 2, SeasonalPeriod: 7})
+	if data := j.Data[key]; !data.Inconclusive || !strings.Contains(data.Comments, "shorter than 2 periods") {
+		t.Errorf("Metric with a short series compared as %+v, but expected it inconclusive", data)
+	}
+	if data := j.Data[other]; !data.Matched || data.Inconclusive {
+		t.Errorf("Metric without a series compared as %+v, but expected it matched", data)
+	}
+}
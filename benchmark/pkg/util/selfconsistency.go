@@ -0,0 +1,80 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"math"
+
+	"k8s.io/kubernetes/test/e2e/perftype"
+)
+
+// RunSplit tells how SplitRuns splits the runs of a job into two subsets.
+type RunSplit int
+
+// Allowed run splits.
+const (
+	SplitAlternate RunSplit = iota // Even runs vs odd ones, so that both subsets span the same time
+	SplitHalves                    // Earlier half vs later half (the middle run going to the later one), which also shows drift
+)
+
+// SplitRuns splits the runs of a job into two subsets as per the split, keeping their order.
+func SplitRuns(jobMetrics []map[string][]perftype.PerfData, split RunSplit) (left, right []map[string][]perftype.PerfData) {
+	if split == SplitHalves {
+		half := len(jobMetrics) / 2
+		return jobMetrics[:half], jobMetrics[half:]
+	}
+	for i, singleRunMetrics := range jobMetrics {
+		if i%2 == 0 {
+			left = append(left, singleRunMetrics)
+		} else {
+			right = append(right, singleRunMetrics)
+		}
+	}
+	return left, right
+}
+
+// GetSelfConsistencyData flattens two subsets of the runs of the same job (see SplitRuns) into
+// the left and right sides of a comparison, for checking that the job's runs agree with each
+// other before comparing it with another job. As both sides are the same job, any metric the
+// comparison then finds mismatched is a false positive, and the share of them (see
+// FalsePositiveRate) is the baseline false-positive rate of the scheme and thresholds used,
+// i.e. the job's noise floor for them. The run timestamps of the options (if any) are keyed by
+// the indices of the runs within their subset.
+func GetSelfConsistencyData(jobMetrics []map[string][]perftype.PerfData, split RunSplit, options FlattenOptions) *JobComparisonData {
+	left, right := SplitRuns(jobMetrics, split)
+	return GetFlattennedComparisonDataWithOptions(left, right, options)
+}
+
+// FalsePositiveRate returns the fraction of the conclusively compared metrics (i.e. those not
+// inconclusive) that mismatched, which is the false-positive rate for a comparison of a job
+// against itself (see GetSelfConsistencyData). It's NaN if no metric was conclusively compared.
+func (j *JobComparisonData) FalsePositiveRate() float64 {
+	compared, mismatched := 0, 0
+	for _, metricData := range j.Data {
+		if metricData.Inconclusive {
+			continue
+		}
+		compared++
+		if !metricData.Matched {
+			mismatched++
+		}
+	}
+	if compared == 0 {
+		return math.NaN()
+	}
+	return float64(mismatched) / float64(compared)
+}
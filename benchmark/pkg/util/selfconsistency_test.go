@@ -0,0 +1,88 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"math"
+	"testing"
+
+	"k8s.io/kubernetes/test/e2e/perftype"
+)
+
+func TestSplitRuns(t *testing.T) {
+	var runs []map[string][]perftype.PerfData
+	for i := 0; i < 5; i++ {
+		runs = append(runs, map[string][]perftype.PerfData{"Load": {{Version: string(rune('a' + i))}}})
+	}
+	versions := func(runs []map[string][]perftype.PerfData) string {
+		result := ""
+		for _, run := range runs {
+			result += run["Load"][0].Version
+		}
+		return result
+	}
+	if left, right := SplitRuns(runs, SplitAlternate); versions(left) != "ace" || versions(right) != "bd" {
+		t.Errorf("Runs split alternately into %v and %v", versions(left), versions(right))
+	}
+	if left, right := SplitRuns(runs, SplitHalves); versions(left) != "ab" || versions(right) != "cde" {
+		t.Errorf("Runs split in halves into %v and %v", versions(left), versions(right))
+	}
+}
+
+func TestSelfConsistency(t *testing.T) {
+	// The job's LIST latency is stable, while its GET one drifts up over the runs.
+	var runs []map[string][]perftype.PerfData
+	for i := 0; i < 6; i++ {
+		runs = append(runs, map[string][]perftype.PerfData{"Load": {{Version: "v1", DataItems: []perftype.DataItem{
+			{Data: map[string]float64{"Perc99": 100 + float64(i%2)}, Unit: "ms", Labels: map[string]string{"Resource": "pods", "Verb": "LIST"}},
+			{Data: map[string]float64{"Perc99": 100 + 20*float64(i)}, Unit: "ms", Labels: map[string]string{"Resource": "pods", "Verb": "GET"}},
+		}}}})
+	}
+	listPods := MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}
+	getPods := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+
+	for _, testCase := range []struct {
+		split                     RunSplit
+		expectedGetMatched        bool
+		expectedFalsePositiveRate float64
+	}{
+		// Alternate runs span the same time, so the drift doesn't show.
+		{SplitAlternate, true, 0},
+		// The later half is slower.
+		{SplitHalves, false, 0.5},
+	} {
+		j := GetSelfConsistencyData(runs, testCase.split, FlattenOptions{})
+		if len(j.Data[listPods].LeftJobSample) != 3 || len(j.Data[listPods].RightJobSample) != 3 {
+			t.Fatalf("Split %v: runs flattened into %+v, but expected 3 runs on each side", testCase.split, j.Data[listPods])
+		}
+		compareAvgsWithin(j, 0.2, 0)
+		if !j.Data[listPods].Matched || j.Data[getPods].Matched != testCase.expectedGetMatched {
+			t.Errorf("Split %v: LIST matched: %v, GET matched: %v, but expected %v", testCase.split, j.Data[listPods].Matched, j.Data[getPods].Matched, testCase.expectedGetMatched)
+		}
+		if rate := j.FalsePositiveRate(); rate != testCase.expectedFalsePositiveRate {
+			t.Errorf("Split %v: false-positive rate is %v, but expected %v", testCase.split, rate, testCase.expectedFalsePositiveRate)
+		}
+	}
+
+	j := GetSelfConsistencyData(runs, SplitAlternate, FlattenOptions{})
+	for _, metricData := range j.Data {
+		metricData.MarkInconclusive("Not enough data")
+	}
+	if rate := j.FalsePositiveRate(); !math.IsNaN(rate) {
+		t.Errorf("False-positive rate without conclusive comparisons is %v, but expected NaN", rate)
+	}
+}
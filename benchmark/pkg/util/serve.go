@@ -0,0 +1,150 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// servedMediaTypes maps the media types ServeHTTP negotiates to the output formats serving them.
+var servedMediaTypes = map[string]string{
+	"application/json": JSONFormat,
+	"text/csv":         CSVFormat,
+	"text/html":        HTMLFormat,
+}
+
+// mediaRange is one of the media ranges of an Accept header, with its quality.
+type mediaRange struct {
+	mediaType string
+	quality   float64
+}
+
+// negotiateFormat returns the output format to serve for the Accept header, telling if any of
+// the ones served is acceptable. The media ranges are tried by decreasing quality (in their
+// order for equal ones), */* and application/* matching JSON, and text/* matching HTML. An
+// empty header accepts JSON.
+func negotiateFormat(accept string) (string, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return JSONFormat, true
+	}
+	var ranges []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		acceptedRange := mediaRange{mediaType: strings.ToLower(strings.TrimSpace(params[0])), quality: 1}
+		for _, param := range params[1:] {
+			name, value := param, ""
+			if i := strings.Index(param, "="); i >= 0 {
+				name, value = param[:i], param[i+1:]
+			}
+			if strings.TrimSpace(name) == "q" {
+				if quality, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+					acceptedRange.quality = quality
+				}
+			}
+		}
+		if acceptedRange.quality > 0 {
+			ranges = append(ranges, acceptedRange)
+		}
+	}
+	sort.SliceStable(ranges, func(a, b int) bool { return ranges[a].quality > ranges[b].quality })
+	for _, acceptedRange := range ranges {
+		switch acceptedRange.mediaType {
+		case "*/*", "application/*":
+			return JSONFormat, true
+		case "text/*":
+			return HTMLFormat, true
+		}
+		if format, ok := servedMediaTypes[acceptedRange.mediaType]; ok {
+			return format, true
+		}
+	}
+	return "", false
+}
+
+// servedMetrics returns the job comparison data restricted to the metrics selected by the query:
+// those of any of the verbs and resources given (case-insensitively), and only the regressed
+// ones (see RegressionManifest) if regressions-only is true. The metrics' data is shared (see
+// Filter), while the stats aren't ensured, as the requests are served concurrently.
+func (j *JobComparisonData) servedMetrics(query map[string][]string) (*JobComparisonData, error) {
+	regressionsOnly := false
+	if values := query["regressions-only"]; len(values) > 0 {
+		var err error
+		if regressionsOnly, err = strconv.ParseBool(values[0]); err != nil {
+			return nil, fmt.Errorf("invalid regressions-only '%v'", values[0])
+		}
+	}
+	matchesAny := func(value string, allowed []string) bool {
+		if len(allowed) == 0 {
+			return true
+		}
+		for _, allowedValue := range allowed {
+			if strings.EqualFold(value, allowedValue) {
+				return true
+			}
+		}
+		return false
+	}
+	return j.filter(func(metricKey MetricKey, metricData *MetricComparisonData) bool {
+		return matchesAny(metricKey.Verb, query["verb"]) && matchesAny(metricKey.Resource, query["resource"]) &&
+			(!regressionsOnly || metricData.regressed())
+	}), nil
+}
+
+// ServeHTTP serves the job comparison data, which should have been compared already, making it
+// embeddable in a service (e.g. with http.Handle). The format (JSON, CSV or HTML) is negotiated
+// from the request's Accept header (see negotiateFormat), failing with 406 Not Acceptable if
+// none of them is, and the metrics served can be filtered with the query parameters:
+//   - verb and resource restrict them to those of the given verbs and resources (any of them,
+//     if repeated), e.g. ?verb=LIST&resource=pods.
+//   - regressions-only=true restricts them to the regressed ones.
+//
+// Only GET and HEAD requests are served. The data mustn't be modified while being served.
+func (j *JobComparisonData) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, fmt.Sprintf("method %v not allowed", r.Method), http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Vary", "Accept")
+	format, ok := negotiateFormat(r.Header.Get("Accept"))
+	if !ok {
+		http.Error(w, "none of application/json, text/csv and text/html is acceptable", http.StatusNotAcceptable)
+		return
+	}
+	served, err := j.servedMetrics(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	// Write to a buffer first, so that a failure can still be reported with an error status.
+	var body bytes.Buffer
+	if err := served.Write(&body, format); err != nil {
+		http.Error(w, fmt.Sprintf("couldn't write the comparison data: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentTypeForFormat(format))
+	w.Header().Set("Content-Length", strconv.Itoa(body.Len()))
+	w.WriteHeader(http.StatusOK)
+	if r.Method != http.MethodHead {
+		w.Write(body.Bytes())
+	}
+}
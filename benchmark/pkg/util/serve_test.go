@@ -0,0 +1,134 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newServedComparisonData() *JobComparisonData {
+	j := &JobComparisonData{
+		Data: map[MetricKey]*MetricComparisonData{
+			{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}:  {LeftJobSample: []float64{100}, RightJobSample: []float64{200}},
+			{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}:   {LeftJobSample: []float64{10}, RightJobSample: []float64{10}, Matched: true},
+			{TestName: "Load", Verb: "LIST", Resource: "nodes", Percentile: "Perc99"}: {LeftJobSample: []float64{50}, RightJobSample: []float64{25}},
+		},
+	}
+	j.ComputeStatsForMetricSamples()
+	return j
+}
+
+func TestServeHTTPContentTypes(t *testing.T) {
+	server := httptest.NewServer(newServedComparisonData())
+	defer server.Close()
+
+	testCases := []struct {
+		accept, expectedContentType string
+	}{
+		{"", "application/json"},
+		{"application/json", "application/json"},
+		{"*/*", "application/json"},
+		{"text/csv", "text/csv; charset=utf-8"},
+		{"text/html,application/xhtml+xml,*/*;q=0.8", "text/html; charset=utf-8"},
+		{"application/json;q=0.5, text/csv", "text/csv; charset=utf-8"},
+	}
+	for _, testCase := range testCases {
+		request, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		if testCase.accept != "" {
+			request.Header.Set("Accept", testCase.accept)
+		}
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			t.Fatalf("Request accepting '%v' failed: %v", testCase.accept, err)
+		}
+		if response.StatusCode != http.StatusOK || response.Header.Get("Content-Type") != testCase.expectedContentType {
+			t.Errorf("Accepting '%v' got status %v and content type %v, but expected 200 and %v", testCase.accept, response.StatusCode, response.Header.Get("Content-Type"), testCase.expectedContentType)
+		}
+		switch testCase.expectedContentType {
+		case "application/json":
+			var records []map[string]interface{}
+			if err := json.NewDecoder(response.Body).Decode(&records); err != nil || len(records) != 3 {
+				t.Errorf("Accepting '%v' got %v JSON records (error: %v), but expected 3", testCase.accept, len(records), err)
+			}
+		case "text/csv; charset=utf-8":
+			rows, err := csv.NewReader(response.Body).ReadAll()
+			if err != nil || len(rows) != 4 || rows[0][0] != "testName" {
+				t.Errorf("Accepting '%v' got CSV rows %v (error: %v), but expected a header and 3 rows", testCase.accept, rows, err)
+			}
+		}
+		response.Body.Close()
+	}
+
+	request, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	request.Header.Set("Accept", "application/xml")
+	if response, err := http.DefaultClient.Do(request); err != nil || response.StatusCode != http.StatusNotAcceptable {
+		t.Errorf("Request accepting only XML got %v (error: %v), but expected 406", response.Status, err)
+	}
+	if response, err := http.Post(server.URL, "application/json", strings.NewReader("{}")); err != nil || response.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("POST request got %v (error: %v), but expected 405", response.Status, err)
+	}
+}
+
+func TestServeHTTPFilters(t *testing.T) {
+	handler := newServedComparisonData()
+	testCases := []struct {
+		query         string
+		expectedCount int
+		expectedCode  int
+	}{
+		{query: "", expectedCount: 3, expectedCode: http.StatusOK},
+		{query: "verb=LIST", expectedCount: 2, expectedCode: http.StatusOK},
+		{query: "verb=list&resource=pods", expectedCount: 1, expectedCode: http.StatusOK},
+		{query: "verb=GET&verb=LIST&resource=pods", expectedCount: 2, expectedCode: http.StatusOK},
+		// Only LIST pods went up, LIST nodes improved.
+		{query: "regressions-only=true", expectedCount: 1, expectedCode: http.StatusOK},
+		{query: "regressions-only=false&resource=nodes", expectedCount: 1, expectedCode: http.StatusOK},
+		{query: "regressions-only=sure", expectedCode: http.StatusBadRequest},
+	}
+	for _, testCase := range testCases {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/?"+testCase.query, nil))
+		if recorder.Code != testCase.expectedCode {
+			t.Errorf("Query '%v' got status %v, but expected %v", testCase.query, recorder.Code, testCase.expectedCode)
+			continue
+		}
+		if testCase.expectedCode != http.StatusOK {
+			continue
+		}
+		var records []map[string]interface{}
+		if err := json.Unmarshal(recorder.Body.Bytes(), &records); err != nil || len(records) != testCase.expectedCount {
+			t.Errorf("Query '%v' got %v records (error: %v), but expected %v", testCase.query, len(records), err, testCase.expectedCount)
+		}
+	}
+	if len(handler.Data) != 3 {
+		t.Errorf("Serving filtered metrics changed the data: %v", handler.Data)
+	}
+
+	// So do the filters for the other formats.
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/?resource=nodes", nil)
+	request.Header.Set("Accept", "text/html")
+	handler.ServeHTTP(recorder, request)
+	if body := recorder.Body.String(); recorder.Code != http.StatusOK || !strings.Contains(body, "nodes") || strings.Contains(body, "pods") {
+		t.Errorf("HTML filtered by resource is wrong (status %v): %v", recorder.Code, body)
+	}
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"math"
+)
+
+// DefaultShortfallFraction is the fraction of the worst values whose mean is the expected
+// shortfall of the metrics' samples, unless JobComparisonData.ShortfallFraction says otherwise.
+const DefaultShortfallFraction = 0.05
+
+// ExpectedShortfall returns the expected shortfall (or CVaR) of the sample at q, i.e. the mean
+// of its worst (highest) q fraction of values, rounded up to at least one value. Unlike a
+// percentile, which only tells where the tail starts, it tells how bad the values in the tail
+// are, so it's more sensitive to the tail getting worse: e.g. the worst runs getting twice as
+// slow leaves the P99 of 10 runs about the same, but doubles their expected shortfall at 10%.
+// It's NaN if the sample is empty or q is out of (0, 1].
+func ExpectedShortfall(sample []float64, q float64) float64 {
+	if len(sample) == 0 || !(q > 0 && q <= 1) {
+		return math.NaN()
+	}
+	sorted := sortedCopy(sample)
+	count := int(math.Ceil(q * float64(len(sorted))))
+	return Mean(sorted[len(sorted)-count:])
+}
+
+// lowerExpectedShortfall is like ExpectedShortfall, but of the lowest values, which are the
+// worst ones for rates.
+func lowerExpectedShortfall(sample []float64, q float64) float64 {
+	negated := make([]float64, len(sample))
+	for i, value := range sample {
+		negated[i] = -value
+	}
+	return -ExpectedShortfall(negated, q)
+}
+
+// shortfallFraction returns the fraction the expected shortfalls of the metrics' samples are at.
+func (j *JobComparisonData) shortfallFraction() float64 {
+	if j.ShortfallFraction > 0 {
+		return j.ShortfallFraction
+	}
+	return DefaultShortfallFraction
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"math"
+	"testing"
+)
+
+func TestExpectedShortfall(t *testing.T) {
+	sample := []float64{10, 50, 20, 40, 30, 100, 60, 70, 80, 90}
+	for _, testCase := range []struct {
+		q        float64
+		expected float64
+	}{
+		{0.1, 100},
+		{0.2, 95},
+		{0.25, 90}, // Rounded up to 3 values.
+		{0.01, 100},
+		{1, 55},
+	} {
+		if shortfall := ExpectedShortfall(sample, testCase.q); shortfall != testCase.expected {
+			t.Errorf("Expected shortfall at %v is %v, but expected %v", testCase.q, shortfall, testCase.expected)
+		}
+	}
+	for _, q := range []float64{0, -0.1, 1.5, math.NaN()} {
+		if shortfall := ExpectedShortfall(sample, q); !math.IsNaN(shortfall) {
+			t.Errorf("Expected shortfall at %v is %v, but expected NaN", q, shortfall)
+		}
+	}
+	if shortfall := ExpectedShortfall(nil, 0.1); !math.IsNaN(shortfall) {
+		t.Errorf("Expected shortfall of an empty sample is %v, but expected NaN", shortfall)
+	}
+}
+
+func TestExpectedShortfallStats(t *testing.T) {
+	latency := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	throughput := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Throughput"}
+	j := NewJobComparisonData()
+	j.Data[latency] = &MetricComparisonData{LeftJobSample: []float64{1, 2, 3, 4}, RightJobSample: []float64{4, 6, 8, 10}}
+	j.Data[throughput] = &MetricComparisonData{LeftJobSample: []float64{1, 2, 3, 4}, RightJobSample: []float64{4, 6, 8, 10}, IsRate: true}
+	j.ShortfallFraction = 0.5
+	j.EnsureStats()
+	if data := j.Data[latency]; data.ESL != 3.5 || data.ESR != 9 {
+		t.Errorf("Latency's expected shortfalls are %v and %v, but expected the means of the highest halves", data.ESL, data.ESR)
+	}
+	if data := j.Data[throughput]; data.ESL != 1.5 || data.ESR != 5 {
+		t.Errorf("Throughput's expected shortfalls are %v and %v, but expected the means of the lowest halves", data.ESL, data.ESR)
+	}
+
+	// Changing the fraction makes the stats be recomputed.
+	j.ShortfallFraction = 0
+	j.EnsureStats()
+	if data := j.Data[latency]; data.ESL != 4 || data.ESR != 10 {
+		t.Errorf("Latency's expected shortfalls are %v and %v at the default fraction, but expected the max values", data.ESL, data.ESR)
+	}
+}
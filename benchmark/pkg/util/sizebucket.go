@@ -0,0 +1,71 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"strconv"
+	"strings"
+)
+
+// sizeUnits are the multipliers of the size suffixes a size bucket may have, longest first
+// so that e.g. "KiB" isn't taken for "B".
+var sizeUnits = []struct {
+	suffix     string
+	multiplier float64
+}{
+	{"KiB", 1 << 10}, {"MiB", 1 << 20}, {"GiB", 1 << 30},
+	{"KB", 1e3}, {"MB", 1e6}, {"GB", 1e9},
+	{"Ki", 1 << 10}, {"Mi", 1 << 20}, {"Gi", 1 << 30},
+	{"K", 1e3}, {"M", 1e6}, {"G", 1e9},
+	{"B", 1},
+}
+
+// parseSizeBucket returns the size (in bytes) a size bucket stands for, e.g. 65536 for "64KiB",
+// along with whether it's a size at all. A range's bucket (e.g. "1KiB-64KiB" or "<=64KiB") is
+// taken at its upper bound.
+func parseSizeBucket(bucket string) (float64, bool) {
+	bucket = strings.TrimSpace(bucket)
+	if i := strings.LastIndexAny(bucket, "-<=>"); i >= 0 {
+		bucket = bucket[i+1:]
+	}
+	multiplier := 1.0
+	for _, unit := range sizeUnits {
+		if strings.HasSuffix(bucket, unit.suffix) {
+			bucket, multiplier = strings.TrimSuffix(bucket, unit.suffix), unit.multiplier
+			break
+		}
+	}
+	size, err := strconv.ParseFloat(strings.TrimSpace(bucket), 64)
+	if err != nil {
+		return 0, false
+	}
+	return size * multiplier, true
+}
+
+// sizeBucketLess orders size buckets by their sizes, so that e.g. "64KiB" comes before "1MiB",
+// putting those which aren't sizes (including no bucket) first, in lexicographic order.
+func sizeBucketLess(a, b string) bool {
+	sizeA, isSizeA := parseSizeBucket(a)
+	sizeB, isSizeB := parseSizeBucket(b)
+	if isSizeA != isSizeB {
+		return isSizeB
+	}
+	if isSizeA && sizeA != sizeB {
+		return sizeA < sizeB
+	}
+	return a < b
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"testing"
+
+	"k8s.io/kubernetes/test/e2e/perftype"
+)
+
+func TestSizeBucketLess(t *testing.T) {
+	buckets := []string{"1MiB", "64KiB", "large", "512", "1KiB", "", "2KB", "<=4GiB", "1KiB-64KiB"}
+	sort.Slice(buckets, func(a, b int) bool { return sizeBucketLess(buckets[a], buckets[b]) })
+	expected := []string{"", "large", "512", "1KiB", "2KB", "1KiB-64KiB", "64KiB", "1MiB", "<=4GiB"}
+	if strings.Join(buckets, ",") != strings.Join(expected, ",") {
+		t.Errorf("Size buckets sorted as %v, but expected %v", buckets, expected)
+	}
+	if size, ok := parseSizeBucket("64KiB"); !ok || size != 65536 {
+		t.Errorf("Size bucket 64KiB parsed as %v (%v), but expected 65536", size, ok)
+	}
+}
+
+func TestSizeBucketDimension(t *testing.T) {
+	runMetrics := func(largeLatency float64) map[string][]perftype.PerfData {
+		var dataItems []perftype.DataItem
+		for bucket, latency := range map[string]float64{"1KiB": 100, "64KiB": 120, "1MiB": largeLatency} {
+			dataItems = append(dataItems, perftype.DataItem{
+				Data:   map[string]float64{"Perc99": latency},
+				Unit:   "ms",
+				Labels: map[string]string{"Count": "10", "Resource": "pods", "Verb": "POST", "Size": bucket},
+			})
+		}
+		return map[string][]perftype.PerfData{"Load": {{Version: "v1", DataItems: dataItems}}}
+	}
+	leftMetrics := []map[string][]perftype.PerfData{runMetrics(200)}
+	rightMetrics := []map[string][]perftype.PerfData{runMetrics(400)}
+
+	// Without the size bucket in the key, the buckets are pooled into a single metric, where
+	// the regression of the large one is diluted by the unchanged small ones.
+	j := GetFlattennedComparisonDataWithOptions(leftMetrics, rightMetrics, FlattenOptions{MinAllowedAPIRequestCount: 10})
+	if len(j.Data) != 1 {
+		t.Fatalf("Size buckets flattened into %v metrics without the size bucket label, but expected them pooled", len(j.Data))
+	}
+
+	j = GetFlattennedComparisonDataWithOptions(leftMetrics, rightMetrics, FlattenOptions{MinAllowedAPIRequestCount: 10, SizeBucketLabel: "Size"})
+	if len(j.Data) != 3 {
+		t.Fatalf("Size buckets flattened into %v metrics, but expected one per bucket", len(j.Data))
+	}
+	j.ComputeStatsForMetricSamples()
+	for _, metricData := range j.Data {
+		// As the avg test would, with its default threshold.
+		metricData.ResetVerdict()
+		metricData.AvgRatio = metricData.AvgL / metricData.AvgR
+		metricData.Matched = metricData.AvgRatio >= 0.66
+	}
+
+	manifest := j.RegressionManifest()
+	if len(manifest.Regressions) != 1 || manifest.Regressions[0].SizeBucket != "1MiB" {
+		t.Errorf("Manifest has regressions %+v, but expected only that of the 1MiB bucket", manifest.Regressions)
+	}
+
+	// The report lists the buckets by size, rather than lexicographically (1KiB, 1MiB, 64KiB).
+	var buf bytes.Buffer
+	if err := j.WriteMarkdown(&buf); err != nil {
+		t.Fatalf("WriteMarkdown failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	expectedLines := []string{
+		"**1 run vs 1 run**",
+		"",
+		"| E2E Test | Verb | Resource | Subresource | Scope | Size | Percentile | Matched | AvgL | AvgR | AvgL/R | MaxR/L | Comments |",
+		"|---|---|---|---|---|---|---|---|---|---|---|---|---|",
+		"| Load | POST | pods |  |  | 1KiB | Perc99 | true | 100.00 | 100.00 | 1.00 | 1.00 |  |",
+		"| Load | POST | pods |  |  | 64KiB | Perc99 | true | 120.00 | 120.00 | 1.00 | 1.00 |  |",
+		"| Load | POST | pods |  |  | 1MiB | Perc99 | false | 200.00 | 400.00 | 0.50 | 2.00 |  |",
+	}
+	if strings.Join(lines, "\n") != strings.Join(expectedLines, "\n") {
+		t.Errorf("Markdown output mismatched:\nReal:\n%v\nExpected:\n%v", buf.String(), strings.Join(expectedLines, "\n"))
+	}
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"math"
+)
+
+// sparklineBlocks are the characters used for the sparkline levels, lowest first.
+var sparklineBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders the sample (in order) as a line of at most width block characters,
+// normalized to the sample's own range. If the sample has more values than width, they are
+// averaged over consecutive buckets. Non-finite (NaN or infinite) values (or buckets) are
+// left out of the range and rendered as spaces, an empty sample as an empty string and a
+// sample with no spread as a flat middle line.
+func Sparkline(sample []float64, width int) string {
+	if len(sample) == 0 || width <= 0 {
+		return ""
+	}
+	values := sample
+	if len(sample) > width {
+		values = make([]float64, width)
+		for i := range values {
+			values[i] = Mean(sample[i*len(sample)/width : (i+1)*len(sample)/width])
+		}
+	}
+
+	isFinite := func(value float64) bool {
+		return !math.IsNaN(value) && !math.IsInf(value, 0)
+	}
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, value := range values {
+		if isFinite(value) {
+			min, max = math.Min(min, value), math.Max(max, value)
+		}
+	}
+	line := make([]rune, len(values))
+	for i, value := range values {
+		switch {
+		case !isFinite(value):
+			line[i] = ' '
+		case max == min:
+			line[i] = sparklineBlocks[len(sparklineBlocks)/2]
+		default:
+			// Halving the values keeps the range finite even for very large ones.
+			level := (value/2 - min/2) / (max/2 - min/2)
+			line[i] = sparklineBlocks[int(math.Round(level*float64(len(sparklineBlocks)-1)))]
+		}
+	}
+	return string(line)
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestSparkline(t *testing.T) {
+	testCases := []struct {
+		sample   []float64
+		width    int
+		expected string
+	}{
+		{sample: nil, width: 10, expected: ""},
+		{sample: []float64{1, 2, 3, 4, 5, 6, 7, 8}, width: 10, expected: "▁▂▃▄▅▆▇█"},
+		{sample: []float64{5, 5, 5}, width: 10, expected: "▅▅▅"},
+		{sample: []float64{1, math.NaN(), 8}, width: 10, expected: "▁ █"},
+		// Infinite values are rendered as gaps too, and don't stretch the range.
+		{sample: []float64{1, math.Inf(1), 8, math.Inf(-1)}, width: 10, expected: "▁ █ "},
+		{sample: []float64{math.Inf(1), math.NaN()}, width: 10, expected: "  "},
+		// A bucket with an infinite value averages to a non-finite value.
+		{sample: []float64{1, math.Inf(1), 2, 3, 8, 8}, width: 3, expected: " ▁█"},
+		// Consecutive pairs of values are averaged to fit the width.
+		{sample: []float64{0, 2, 10, 10, 0, 0}, width: 3, expected: "▂█▁"},
+		// Very large values are normalized to the sample's own range without overflowing.
+		{sample: []float64{-math.MaxFloat64, 0, math.MaxFloat64}, width: 10, expected: "▁▅█"},
+	}
+	for _, testCase := range testCases {
+		if actual := Sparkline(testCase.sample, testCase.width); actual != testCase.expected {
+			t.Errorf("Sparkline(%v, %v) = %q, but expected %q", testCase.sample, testCase.width, actual, testCase.expected)
+		}
+	}
+}
+
+func TestFormatTableWithSparklines(t *testing.T) {
+	j := &JobComparisonData{
+		Data: map[MetricKey]*MetricComparisonData{
+			{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}: {
+				LeftJobSample:  []float64{1, 8},
+				RightJobSample: []float64{3, 3},
+			},
+		},
+	}
+	table := j.formatTable(PrettyPrintOptions{Sparklines: true})
+	if !strings.Contains(table, "SPARK-L") || !strings.Contains(table, "▁█") || !strings.Contains(table, "▅▅") {
+		t.Errorf("Table lacks the sparklines:\n%v", table)
+	}
+}
@@ -0,0 +1,310 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SafeDiv returns a / b, along with whether it's a valid (finite) quotient. It isn't if b is 0
+// or either of them is NaN or infinite, in which case the returned quotient is NaN. Ratios of
+// stats should be computed with it, so that they don't silently poison downstream logic. It's
+// exported for the comparison schemes (in pkg/comparer/schemes), which compute ratios too.
+func SafeDiv(a, b float64) (float64, bool) {
+	if b == 0 || math.IsNaN(a) || math.IsNaN(b) || math.IsInf(a, 0) || math.IsInf(b, 0) {
+		return math.NaN(), false
+	}
+	quotient := a / b
+	if math.IsInf(quotient, 0) {
+		return math.NaN(), false
+	}
+	return quotient, true
+}
+
+// NormalCDF returns the cumulative distribution function of the standard normal distribution at x.
+func NormalCDF(x float64) float64 {
+	return 0.5 * math.Erfc(-x/math.Sqrt2)
+}
+
+// NormalQuantile returns the inverse of the standard normal CDF at p (NaN unless 0 < p < 1).
+func NormalQuantile(p float64) float64 {
+	if !(p > 0 && p < 1) {
+		return math.NaN()
+	}
+	return -math.Sqrt2 * math.Erfcinv(2*p)
+}
+
+// StudentTCDF returns the cumulative distribution function of Student's t-distribution with
+// df (possibly fractional) degrees of freedom at t. It's NaN unless df is positive.
+func StudentTCDF(t, df float64) float64 {
+	if !(df > 0) || math.IsNaN(t) {
+		return math.NaN()
+	}
+	tail := 0.5 * regularizedIncompleteBeta(df/(df+t*t), df/2, 0.5)
+	if t > 0 {
+		return 1 - tail
+	}
+	return tail
+}
+
+// FCDF returns the cumulative distribution function of the F-distribution with d1 and d2
+// (positive) degrees of freedom at f. It's NaN unless both degrees of freedom are positive.
+func FCDF(f, d1, d2 float64) float64 {
+	if !(d1 > 0 && d2 > 0) || math.IsNaN(f) {
+		return math.NaN()
+	}
+	if f <= 0 {
+		return 0
+	}
+	if math.IsInf(f, 1) {
+		return 1
+	}
+	return regularizedIncompleteBeta(d1*f/(d1*f+d2), d1/2, d2/2)
+}
+
+// regularizedIncompleteBeta returns the regularized incomplete beta function I_x(a, b), for
+// x in [0, 1] and positive a and b.
+func regularizedIncompleteBeta(x, a, b float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+	lgammaA, _ := math.Lgamma(a)
+	lgammaB, _ := math.Lgamma(b)
+	lgammaAB, _ := math.Lgamma(a + b)
+	front := math.Exp(a*math.Log(x) + b*math.Log(1-x) + lgammaAB - lgammaA - lgammaB)
+	// The continued fraction converges fast only on this side of the mean, use the symmetry otherwise.
+	if x < (a+1)/(a+b+2) {
+		return front * betaContinuedFraction(x, a, b) / a
+	}
+	return 1 - front*betaContinuedFraction(1-x, b, a)/b
+}
+
+// betaContinuedFraction evaluates the continued fraction of the incomplete beta function
+// using the modified Lentz's method.
+func betaContinuedFraction(x, a, b float64) float64 {
+	const (
+		maxIterations = 300
+		epsilon       = 1e-15
+		tiny          = 1e-300
+	)
+	clampTiny := func(value float64) float64 {
+		if math.Abs(value) < tiny {
+			return tiny
+		}
+		return value
+	}
+	c, d := 1.0, 1/clampTiny(1-(a+b)*x/(a+1))
+	result := d
+	for m := 1.0; m <= maxIterations; m++ {
+		// Even step.
+		numerator := m * (b - m) * x / ((a + 2*m - 1) * (a + 2*m))
+		d = 1 / clampTiny(1+numerator*d)
+		c = clampTiny(1 + numerator/c)
+		result *= d * c
+		// Odd step.
+		numerator = -(a + m) * (a + b + m) * x / ((a + 2*m) * (a + 2*m + 1))
+		d = 1 / clampTiny(1+numerator*d)
+		c = clampTiny(1 + numerator/c)
+		delta := d * c
+		result *= delta
+		if math.Abs(delta-1) < epsilon {
+			break
+		}
+	}
+	return result
+}
+
+// Mean returns the arithmetic mean of the sample (NaN if it's empty).
+func Mean(sample []float64) float64 {
+	if len(sample) == 0 {
+		return math.NaN()
+	}
+	sum := 0.0
+	for _, value := range sample {
+		sum += value
+	}
+	return sum / float64(len(sample))
+}
+
+// HarmonicMean returns the harmonic mean of the sample, the right average for rates (e.g. of
+// throughputs measured over the same amount of work). Note that it skips the non-positive
+// values, for which it isn't defined (and which aren't valid rates anyway), being NaN if
+// there are no values left.
+func HarmonicMean(sample []float64) float64 {
+	count, reciprocalSum := 0, 0.0
+	for _, value := range sample {
+		if value > 0 {
+			count++
+			reciprocalSum += 1 / value
+		}
+	}
+	if count == 0 {
+		return math.NaN()
+	}
+	return float64(count) / reciprocalSum
+}
+
+// SampleVariance returns the unbiased (n-1 denominator) variance of the sample
+// (NaN if it has less than 2 values).
+func SampleVariance(sample []float64) float64 {
+	if len(sample) < 2 {
+		return math.NaN()
+	}
+	mean := Mean(sample)
+	squareSum := 0.0
+	for _, value := range sample {
+		squareSum += (value - mean) * (value - mean)
+	}
+	return squareSum / float64(len(sample)-1)
+}
+
+func hasNaN(sample []float64) bool {
+	for _, value := range sample {
+		if math.IsNaN(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedCopy(sample []float64) []float64 {
+	sorted := append([]float64{}, sample...)
+	sort.Float64s(sorted)
+	return sorted
+}
+
+// percentileOfSorted linearly interpolates between the closest ranks of an already sorted sample.
+func percentileOfSorted(sorted []float64, fraction float64) float64 {
+	if len(sorted) == 0 {
+		return math.NaN()
+	}
+	rank := fraction * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (rank-float64(lower))*(sorted[upper]-sorted[lower])
+}
+
+// parsePercentileFraction validates a percentile given as a fraction, which must be in [0, 1]
+// (and not NaN), rather than producing garbage further down.
+func parsePercentileFraction(fraction float64) (float64, error) {
+	if !(fraction >= 0 && fraction <= 1) {
+		return math.NaN(), fmt.Errorf("percentile fraction %v out of [0, 1]", fraction)
+	}
+	return fraction, nil
+}
+
+// parsePercentileName validates the name of a metric's percentile (like "Perc99", "P99.9" or
+// "99", in percents of [0, 100]), and normalizes it into a fraction.
+func parsePercentileName(name string) (float64, error) {
+	number := strings.TrimPrefix(name, "P")
+	if strings.HasPrefix(name, "Perc") {
+		number = strings.TrimPrefix(name, "Perc")
+	}
+	percent, err := strconv.ParseFloat(number, 64)
+	if err != nil || !(percent >= 0 && percent <= 100) {
+		return math.NaN(), fmt.Errorf("bad percentile '%v'", name)
+	}
+	return percent / 100, nil
+}
+
+// ComputePercentile returns the given percentile (as a fraction in [0, 1]) of the sample,
+// linearly interpolating between the closest ranks. It's NaN for an empty sample, and an
+// error if the fraction is out of range.
+func ComputePercentile(sample []float64, fraction float64) (float64, error) {
+	fraction, err := parsePercentileFraction(fraction)
+	if err != nil {
+		return math.NaN(), err
+	}
+	return percentileOf(sample, fraction), nil
+}
+
+// percentileOf is ComputePercentile for a fraction known to be in range.
+func percentileOf(sample []float64, fraction float64) float64 {
+	return percentileOfSorted(sortedCopy(sample), fraction)
+}
+
+// CDFArea returns the area between the empirical CDFs of the left and right samples, i.e. the
+// integral of their absolute difference. In 1D this equals the Wasserstein (earth mover's)
+// distance between the samples, and unlike the KS statistic (the max gap between the CDFs),
+// it grows with how far apart the distributions are. It's NaN if either sample is empty or
+// has a NaN value.
+func CDFArea(left, right []float64) float64 {
+	if len(left) == 0 || len(right) == 0 || hasNaN(left) || hasNaN(right) {
+		return math.NaN()
+	}
+	sortedLeft, sortedRight := sortedCopy(left), sortedCopy(right)
+	nLeft, nRight := float64(len(sortedLeft)), float64(len(sortedRight))
+	area := 0.0
+	i, k := 0, 0
+	previous := math.Min(sortedLeft[0], sortedRight[0])
+	for i < len(sortedLeft) || k < len(sortedRight) {
+		// Move to the next point where either of the CDFs steps up.
+		next := math.Inf(1)
+		if i < len(sortedLeft) {
+			next = sortedLeft[i]
+		}
+		if k < len(sortedRight) {
+			next = math.Min(next, sortedRight[k])
+		}
+		area += math.Abs(float64(i)/nLeft-float64(k)/nRight) * (next - previous)
+		for i < len(sortedLeft) && sortedLeft[i] == next {
+			i++
+		}
+		for k < len(sortedRight) && sortedRight[k] == next {
+			k++
+		}
+		previous = next
+	}
+	return area
+}
+
+// madNormalConsistency scales the MAD to be a consistent estimator of the std-dev for normal data.
+const madNormalConsistency = 1.4826
+
+// MAD returns the median absolute deviation of the sample (i.e. the median of the absolute
+// deviations from its median), scaled by 1.4826 to be comparable to the std-dev for normally
+// distributed data. Unlike the std-dev, it isn't wrecked by a few outliers, which makes it
+// the natural scale for outlier thresholds (e.g. values more than k MADs from the median).
+// It's NaN for an empty sample.
+func MAD(sample []float64) float64 {
+	median := percentileOf(sample, 0.5)
+	deviations := make([]float64, len(sample))
+	for i, value := range sample {
+		deviations[i] = math.Abs(value - median)
+	}
+	return madNormalConsistency * percentileOf(deviations, 0.5)
+}
+
+// PercentileOfSamples returns the given percentile (as a fraction in [0, 1]) of the metric's
+// left or right sample. Note that the sample values are themselves percentiles, as reported by
+// each run (e.g. their P99 latencies), so this is a percentile of percentiles: e.g. the P95 of
+// the runs' P99s is a robust estimate of the tail across the runs, which is generally not the
+// P95 (or P99) of all the runs' raw latencies pooled together. It's NaN for an empty sample,
+// and an error if p is out of range.
+func (d *MetricComparisonData) PercentileOfSamples(p float64, fromLeftJob bool) (float64, error) {
+	if fromLeftJob {
+		return ComputePercentile(d.LeftJobSample, p)
+	}
+	return ComputePercentile(d.RightJobSample, p)
+}
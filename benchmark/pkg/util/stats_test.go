@@ -0,0 +1,202 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCDFArea(t *testing.T) {
+	testCases := []struct {
+		left, right []float64
+		expected    float64
+	}{
+		{left: []float64{1, 2, 3}, right: []float64{1, 2, 3}, expected: 0},
+		// Shifting a sample moves its CDF by the same amount everywhere.
+		{left: []float64{1, 2, 3}, right: []float64{6, 7, 8}, expected: 5},
+		{left: []float64{0}, right: []float64{0, 10}, expected: 5},
+		// The CDFs cross: |F_L-F_R| is 0.5 over [1,2], 0 over [2,3] and 0.5 over [3,5].
+		{left: []float64{1, 5}, right: []float64{2, 3}, expected: 1.5},
+	}
+	for _, testCase := range testCases {
+		if area := CDFArea(testCase.left, testCase.right); math.Abs(area-testCase.expected) > 1e-9 {
+			t.Errorf("CDFArea(%v, %v) = %v, but expected %v", testCase.left, testCase.right, area, testCase.expected)
+		}
+		if area := CDFArea(testCase.right, testCase.left); math.Abs(area-testCase.expected) > 1e-9 {
+			t.Errorf("CDFArea(%v, %v) = %v, but expected %v", testCase.right, testCase.left, area, testCase.expected)
+		}
+	}
+	if !math.IsNaN(CDFArea([]float64{1}, nil)) {
+		t.Errorf("CDFArea not NaN for an empty sample")
+	}
+	if !math.IsNaN(CDFArea([]float64{1}, []float64{2, math.NaN()})) {
+		t.Errorf("CDFArea not NaN for a sample with a NaN value")
+	}
+}
+
+func TestMAD(t *testing.T) {
+	// Median is 3 and the absolute deviations are {2, 1, 0, 1, 97}, with a median of 1.
+	if mad := MAD([]float64{1, 2, 3, 4, 100}); math.Abs(mad-1.4826) > 1e-9 {
+		t.Errorf("MAD computed as %v, but expected 1.4826", mad)
+	}
+	if mad := MAD([]float64{5, 5, 5}); mad != 0 {
+		t.Errorf("MAD of a sample without spread computed as %v, but expected 0", mad)
+	}
+	if !math.IsNaN(MAD(nil)) {
+		t.Errorf("MAD not NaN for an empty sample")
+	}
+}
+
+func TestHarmonicMean(t *testing.T) {
+	// Going 60 km/h one way and 40 km/h back averages to 48 km/h, not 50.
+	if mean := HarmonicMean([]float64{60, 40}); math.Abs(mean-48) > 1e-9 {
+		t.Errorf("Harmonic mean computed as %v, but expected 48", mean)
+	}
+	// Non-positive values are skipped.
+	if mean := HarmonicMean([]float64{60, 0, -5, 40}); math.Abs(mean-48) > 1e-9 {
+		t.Errorf("Harmonic mean with non-positive values computed as %v, but expected 48", mean)
+	}
+	for _, sample := range [][]float64{nil, {0, -1}} {
+		if mean := HarmonicMean(sample); !math.IsNaN(mean) {
+			t.Errorf("Harmonic mean of %v computed as %v, but expected NaN", sample, mean)
+		}
+	}
+}
+
+func TestPercentileOfSamples(t *testing.T) {
+	// The raw latencies of 4 runs, each reporting their P90 (of 10 values).
+	rawLatencies := [][]float64{
+		{1, 1, 1, 1, 1, 1, 1, 1, 1, 11},
+		{1, 1, 1, 1, 1, 1, 1, 1, 1, 21},
+		{1, 1, 1, 1, 1, 1, 1, 1, 1, 31},
+		{1, 1, 1, 1, 1, 1, 1, 1, 1, 41},
+	}
+	var runPercentiles, pooled []float64
+	for _, latencies := range rawLatencies {
+		percentile, err := ComputePercentile(latencies, 0.9)
+		if err != nil {
+			t.Fatalf("ComputePercentile failed: %v", err)
+		}
+		runPercentiles = append(runPercentiles, percentile)
+		pooled = append(pooled, latencies...)
+	}
+	data := &MetricComparisonData{LeftJobSample: runPercentiles, RightJobSample: []float64{5}}
+
+	// The runs' P90s are 2, 3, 4 and 5, whose P50 is 3.5, while the P90 of all the latencies
+	// pooled is 2 (the spikes being 4 of 40).
+	if percentile, _ := data.PercentileOfSamples(0.5, true); math.Abs(percentile-3.5) > 1e-9 {
+		t.Errorf("P50 of the runs' P90s computed as %v, but expected 3.5", percentile)
+	}
+	if percentile, _ := ComputePercentile(pooled, 0.9); math.Abs(percentile-2) > 1e-9 {
+		t.Errorf("P90 of the pooled latencies computed as %v, but expected 2", percentile)
+	}
+	if percentile, _ := data.PercentileOfSamples(0.95, false); percentile != 5 {
+		t.Errorf("P95 of the right sample computed as %v, but expected 5", percentile)
+	}
+	if percentile, err := (&MetricComparisonData{}).PercentileOfSamples(0.95, true); err != nil || !math.IsNaN(percentile) {
+		t.Errorf("P95 of an empty sample computed as %v, %v, but expected NaN", percentile, err)
+	}
+	if percentile, err := data.PercentileOfSamples(95, true); err == nil {
+		t.Errorf("P9500 of the left sample computed as %v, but expected an error", percentile)
+	}
+}
+
+func TestParsePercentile(t *testing.T) {
+	for _, test := range []struct {
+		percentile interface{}
+		expected   float64
+		valid      bool
+	}{
+		{0.0, 0, true},
+		{0.99, 0.99, true},
+		{1.0, 1, true},
+		{"Perc99", 0.99, true},
+		{"Perc99.9", 0.999, true},
+		{"Perc100", 1, true},
+		{"P50", 0.5, true},
+		{"0", 0, true},
+		{1.5, 0, false},
+		{150.0, 0, false},
+		{-0.1, 0, false},
+		{math.NaN(), 0, false},
+		{"PercAbc", 0, false},
+		{"Perc150", 0, false},
+		{"Perc-1", 0, false},
+		{"PercNaN", 0, false},
+		{"PercP99", 0, false},
+		{"Perc", 0, false},
+		{"", 0, false},
+	} {
+		var fraction float64
+		var err error
+		switch percentile := test.percentile.(type) {
+		case float64:
+			fraction, err = parsePercentileFraction(percentile)
+		case string:
+			fraction, err = parsePercentileName(percentile)
+		}
+		if !test.valid {
+			if err == nil {
+				t.Errorf("Parsing percentile %#v = %v, but expected an error", test.percentile, fraction)
+			}
+			continue
+		}
+		if err != nil || math.Abs(fraction-test.expected) > 1e-12 {
+			t.Errorf("Parsing percentile %#v = %v, %v, but expected %v", test.percentile, fraction, err, test.expected)
+		}
+	}
+
+	if percentile, err := ComputePercentile([]float64{1, 2}, 99); err == nil || !math.IsNaN(percentile) {
+		t.Errorf("ComputePercentile for a fraction out of range = %v, %v, but expected an error", percentile, err)
+	}
+}
+
+func TestSafeDiv(t *testing.T) {
+	if quotient, ok := SafeDiv(3, 2); !ok || quotient != 1.5 {
+		t.Errorf("SafeDiv(3, 2) = %v, %v", quotient, ok)
+	}
+	for _, operands := range [][2]float64{{1, 0}, {0, 0}, {math.NaN(), 1}, {1, math.NaN()}, {math.Inf(1), 1}, {1, math.Inf(-1)}, {math.MaxFloat64, 0.5}} {
+		if quotient, ok := SafeDiv(operands[0], operands[1]); ok || !math.IsNaN(quotient) {
+			t.Errorf("SafeDiv(%v, %v) = %v, %v, but expected it to be invalid", operands[0], operands[1], quotient, ok)
+		}
+	}
+}
+
+func TestStudentTCDF(t *testing.T) {
+	testCases := []struct {
+		t, df, expected float64
+	}{
+		{t: 0, df: 5, expected: 0.5},
+		// Closed forms for 1 (Cauchy) and 2 degrees of freedom.
+		{t: 2, df: 1, expected: 0.5 + math.Atan(2)/math.Pi},
+		{t: -3, df: 2, expected: 0.5 - 3/(2*math.Sqrt(2+9))},
+		// Critical values from t-tables.
+		{t: 2.228, df: 10, expected: 0.975},
+		{t: -1.812, df: 10, expected: 0.05},
+		{t: 2.576, df: 1e6, expected: 0.995},
+		{t: math.Inf(1), df: 3, expected: 1},
+	}
+	for _, testCase := range testCases {
+		if cdf := StudentTCDF(testCase.t, testCase.df); math.Abs(cdf-testCase.expected) > 5e-4 {
+			t.Errorf("StudentTCDF(%v, %v) = %v, but expected %v", testCase.t, testCase.df, cdf, testCase.expected)
+		}
+	}
+	if !math.IsNaN(StudentTCDF(1, 0)) {
+		t.Errorf("StudentTCDF not NaN for 0 degrees of freedom")
+	}
+}
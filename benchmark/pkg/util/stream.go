@@ -0,0 +1,69 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+)
+
+// MetricVerdict is the verdict a metric was given by a comparison, along with its data (e.g.
+// for rendering its row of a report).
+type MetricVerdict struct {
+	Key          MetricKey
+	Matched      bool
+	Inconclusive bool
+	Comments     string
+	Data         *MetricComparisonData
+}
+
+// StreamVerdicts compares the metrics one by one with the scheme (computing their stats as
+// needed, like the scheme would), in the order of their keys, sending each metric's verdict on
+// the returned channel as soon as it's ready, e.g. for an interactive tool to render the rows
+// of a large report as they come rather than once all are done. The channel is closed once all
+// the metrics are compared, or once ctx is done, whichever comes first: the metrics not yet
+// compared by then are left untouched. As each metric is compared on its own, the schemes
+// relying on the whole set of metrics (e.g. correcting their p-values for multiple testing)
+// aren't to be streamed. Like for the other comparisons, the metrics' transforms and
+// hysteresis are honored (see WithMetricSettings). The metrics (which are compared in place)
+// mustn't be changed until the channel is closed.
+func (j *JobComparisonData) StreamVerdicts(ctx context.Context, scheme ComparisonScheme, matchThreshold, minMetricAvgForCompare float64) <-chan MetricVerdict {
+	verdicts := make(chan MetricVerdict)
+	metricsList := getMetricsSortedByKey(j)
+	compare := WithMetricSettings(func(_ context.Context, j *JobComparisonData, matchThreshold, minMetricAvgForCompare float64) {
+		scheme(j, matchThreshold, minMetricAvgForCompare)
+	})
+	go func() {
+		defer close(verdicts)
+		for _, metricPair := range metricsList {
+			if ctx.Err() != nil {
+				return
+			}
+			// The metric is compared with the job's settings (e.g. its ShortfallFraction and Baseline).
+			single := *j
+			single.Data = map[MetricKey]*MetricComparisonData{metricPair.metricKey: metricPair.metricData}
+			compare(ctx, &single, matchThreshold, minMetricAvgForCompare)
+			data := metricPair.metricData
+			verdict := MetricVerdict{Key: metricPair.metricKey, Matched: data.Matched, Inconclusive: data.Inconclusive, Comments: data.Comments, Data: data}
+			select {
+			case verdicts <- verdict:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return verdicts
+}
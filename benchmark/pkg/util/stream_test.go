@@ -0,0 +1,131 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+// compareAvgsWithin is a minimal scheme for the tests, matching the metrics whose right avg
+// is at most (1+matchThreshold) times the left one.
+func compareAvgsWithin(j *JobComparisonData, matchThreshold, _ float64) {
+	j.EnsureStats()
+	for _, metricData := range j.Data {
+		metricData.ResetVerdict()
+		metricData.Matched = metricData.AvgR <= (1+matchThreshold)*metricData.AvgL
+		metricData.Comments = fmt.Sprintf("AvgR/L=%.2f", metricData.AvgR/metricData.AvgL)
+	}
+}
+
+func TestStreamVerdicts(t *testing.T) {
+	newData := func() *JobComparisonData {
+		j := NewJobComparisonData()
+		for i := 0; i < 5; i++ {
+			// Every other metric regressed by 50%.
+			right := 100.0 + float64(i%2)*50
+			j.Data[MetricKey{TestName: "Load", Verb: fmt.Sprintf("VERB%v", i), Percentile: "Perc99"}] = &MetricComparisonData{
+				LeftJobSample:  []float64{100, 100},
+				RightJobSample: []float64{right, right},
+			}
+		}
+		return j
+	}
+
+	j := newData()
+	var streamed []MetricVerdict
+	for verdict := range j.StreamVerdicts(context.Background(), compareAvgsWithin, 0.1, 0) {
+		streamed = append(streamed, verdict)
+	}
+	if len(streamed) != 5 {
+		t.Fatalf("Streamed %v verdicts, but expected 5", len(streamed))
+	}
+	for i, verdict := range streamed {
+		if verdict.Key.Verb != fmt.Sprintf("VERB%v", i) {
+			t.Errorf("Verdict %v is of %v, but expected the metrics in the order of keys", i, verdict.Key)
+		}
+		if verdict.Matched != (i%2 == 0) || verdict.Data != j.Data[verdict.Key] || !verdict.Data.statsValid || verdict.Data.AvgL != 100 {
+			t.Errorf("Verdict %v is %+v, but expected it matched iff not regressed, with the stats computed", i, verdict)
+		}
+	}
+
+	// Cancelling stops the comparisons, leaving the remaining metrics untouched.
+	j = newData()
+	ctx, cancel := context.WithCancel(context.Background())
+	verdicts := j.StreamVerdicts(ctx, compareAvgsWithin, 0.1, 0)
+	first := <-verdicts
+	cancel()
+	received := 1
+	for range verdicts {
+		received++
+	}
+	if first.Key.Verb != "VERB0" || received > 2 {
+		t.Errorf("Received %v verdicts (the first for %v), but expected the streaming to stop once cancelled", received, first.Key)
+	}
+	for key, metricData := range j.Data {
+		if key.Verb >= "VERB2" && metricData.Comments != "" {
+			t.Errorf("Metric %v compared after cancelling", key)
+		}
+	}
+}
+
+func TestStreamVerdictsWithJobSettings(t *testing.T) {
+	// A scheme gating on the ESR, which depends on the job's ShortfallFraction.
+	compareShortfalls := func(j *JobComparisonData, matchThreshold, _ float64) {
+		j.EnsureStats()
+		for _, metricData := range j.Data {
+			metricData.ResetVerdict()
+			metricData.Matched = metricData.ESR <= (1+matchThreshold)*metricData.ESL
+			metricData.Comments = fmt.Sprintf("ESL/R=%.2f", metricData.ESL/metricData.ESR)
+		}
+	}
+	newData := func() *JobComparisonData {
+		j := NewJobComparisonData()
+		j.ShortfallFraction = 0.5
+		// Only the worst value regressed.
+		j.Data[MetricKey{TestName: "Load", Verb: "LIST", Percentile: "Perc99"}] = &MetricComparisonData{
+			LeftJobSample:  []float64{100, 100, 100, 100},
+			RightJobSample: []float64{100, 100, 100, 300},
+		}
+		return j
+	}
+
+	direct := newData()
+	compareShortfalls(direct, 0.5, 0)
+	streamed := newData()
+	for verdict := range streamed.StreamVerdicts(context.Background(), compareShortfalls, 0.5, 0) {
+		expected := direct.Data[verdict.Key]
+		if verdict.Matched != expected.Matched || verdict.Comments != expected.Comments || verdict.Data.ESR != expected.ESR {
+			t.Errorf("Streamed verdict is %+v, but expected the direct one %+v", verdict, expected)
+		}
+	}
+}
+
+func TestStreamVerdictsWithTransforms(t *testing.T) {
+	key := MetricKey{TestName: "Load", Verb: "LIST", Percentile: "Perc99"}
+	j := NewJobComparisonData()
+	j.Data[key] = &MetricComparisonData{LeftJobSample: []float64{10, 1000}, RightJobSample: []float64{100, 100}, Transform: TransformLog}
+	// The geometric means are both 100, while the arithmetic left avg is 505.
+	for verdict := range j.StreamVerdicts(context.Background(), compareAvgsWithin, 0.1, 0) {
+		if !verdict.Matched || !strings.Contains(verdict.Comments, "Transform=log") || math.Abs(verdict.Data.AvgL-100) > 1e-9 {
+			t.Errorf("Streamed verdict is %+v, but expected it matched on the log scale", verdict)
+		}
+	}
+}
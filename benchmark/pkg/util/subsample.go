@@ -0,0 +1,71 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"math/rand"
+)
+
+// subsample returns targetN values picked from the sample without replacement,
+// or a copy of the whole sample if it doesn't have more than targetN values.
+func subsample(sample []float64, targetN int, rng *rand.Rand) []float64 {
+	if len(sample) <= targetN {
+		return append([]float64{}, sample...)
+	}
+	picked := make([]float64, 0, targetN)
+	for _, index := range rng.Perm(len(sample))[:targetN] {
+		picked = append(picked, sample[index])
+	}
+	return picked
+}
+
+// SubsampleCompare repeatedly subsamples the left job's sample of each metric down to
+// targetN values and compares it against the (complete) right job's sample using the
+// given scheme. This makes the comparison fair when the left job (typically a baseline)
+// has many more runs than the right one. It returns, for each metric, the fraction of
+// the iterations whose comparison flagged the metric as mismatched. The receiver is left
+// unchanged, and the rng is injected so that results can be made deterministic. The scheme
+// gets called with zero thresholds, so it should have its own bound (see WithThresholds).
+func (j *JobComparisonData) SubsampleCompare(targetN, iterations int, scheme ComparisonScheme, rng *rand.Rand) map[MetricKey]float64 {
+	flaggedCount := make(map[MetricKey]int, len(j.Data))
+	// Metrics are visited in a fixed order, so that the rng draws are reproducible.
+	sortedMetrics := getMetricsSortedByKey(j)
+	for i := 0; i < iterations; i++ {
+		subsampled := NewJobComparisonData()
+		for _, metricPair := range sortedMetrics {
+			subsampled.Data[metricPair.metricKey] = &MetricComparisonData{
+				LeftJobSample:  subsample(metricPair.metricData.LeftJobSample, targetN, rng),
+				RightJobSample: append([]float64{}, metricPair.metricData.RightJobSample...),
+				Unit:           metricPair.metricData.Unit,
+			}
+		}
+		scheme(subsampled, 0, 0)
+		for metricKey, metricData := range subsampled.Data {
+			if !metricData.Matched {
+				flaggedCount[metricKey]++
+			}
+		}
+	}
+	flaggedFraction := make(map[MetricKey]float64, len(j.Data))
+	for metricKey := range j.Data {
+		flaggedFraction[metricKey] = 0
+		if iterations > 0 {
+			flaggedFraction[metricKey] = float64(flaggedCount[metricKey]) / float64(iterations)
+		}
+	}
+	return flaggedFraction
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// flagIfLeftMaxAbove is a toy comparison scheme flagging metrics whose left sample
+// has a value above the match threshold.
+func flagIfLeftMaxAbove(j *JobComparisonData, matchThreshold, minMetricAvgForCompare float64) {
+	j.ComputeStatsForMetricSamples()
+	for _, metricData := range j.Data {
+		metricData.Matched = metricData.MaxL <= matchThreshold
+	}
+}
+
+func TestSubsampleCompare(t *testing.T) {
+	metricKey1 := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	metricKey2 := MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}
+	metricKey3 := MetricKey{TestName: "Load", Verb: "PUT", Resource: "pods", Percentile: "Perc99"}
+	leftSample1 := []float64{1, 1, 1, 1, 1, 1, 1, 1, 1, 100}
+	j := &JobComparisonData{
+		Data: map[MetricKey]*MetricComparisonData{
+			// Only 1 of the 10 left values is flagged, so half of the 5-value subsamples have it.
+			metricKey1: {LeftJobSample: leftSample1, RightJobSample: []float64{1, 1, 1, 1, 1}},
+			// No left value is flagged.
+			metricKey2: {LeftJobSample: []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, RightJobSample: []float64{1}},
+			// Left sample is smaller than the target, so it's always used as a whole.
+			metricKey3: {LeftJobSample: []float64{50, 60}, RightJobSample: []float64{1}},
+		},
+	}
+
+	fractions := j.SubsampleCompare(5, 2000, WithThresholds(flagIfLeftMaxAbove, 10, 0), rand.New(rand.NewSource(1)))
+	if math.Abs(fractions[metricKey1]-0.5) > 0.05 {
+		t.Errorf("Flagged fraction for %v computed as %v, but expected ~0.5", metricKey1, fractions[metricKey1])
+	}
+	if fractions[metricKey2] != 0 {
+		t.Errorf("Flagged fraction for %v computed as %v, but expected 0", metricKey2, fractions[metricKey2])
+	}
+	if math.Abs(fractions[metricKey3]-1) > 1e-9 {
+		t.Errorf("Flagged fraction for %v computed as %v, but expected 1", metricKey3, fractions[metricKey3])
+	}
+
+	// The subsampling must be deterministic given the rng, and leave the receiver unchanged.
+	again := j.SubsampleCompare(5, 2000, WithThresholds(flagIfLeftMaxAbove, 10, 0), rand.New(rand.NewSource(1)))
+	if !reflect.DeepEqual(fractions, again) {
+		t.Errorf("Flagged fractions differ across runs with the same seed:\n%v\n%v", fractions, again)
+	}
+	if !reflect.DeepEqual(j.Data[metricKey1].LeftJobSample, leftSample1) || j.Data[metricKey1].Matched {
+		t.Errorf("Receiver modified by SubsampleCompare: %+v", j.Data[metricKey1])
+	}
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// tableFormat tells how the cells of a table of the job comparison data are formatted.
+type tableFormat struct {
+	// formatFloat formats the stats (e.g. formatRatio, which PrettyPrint uses).
+	formatFloat func(float64) string
+	// normalized is the job comparison data as percents of its baseline (see PercentOfBaseline),
+	// for the percent columns. It's only computed if any of them is asked for.
+	normalized *JobComparisonData
+	// enforcedTier, if set, marks the comments of the mismatches of less important tiers
+	// (see PrettyPrintOptions.EnforcedTier).
+	enforcedTier *Tier
+	// mask, if set, masks the fields of the metric keys (see PrettyPrintOptions.Mask).
+	mask *LabelMask
+}
+
+// tableColumn is a column of a table of the job comparison data.
+type tableColumn struct {
+	header string
+	cell   func(key MetricKey, data *MetricComparisonData, format *tableFormat) string
+}
+
+func percentColumn(header string, stat func(data *MetricComparisonData) float64) tableColumn {
+	return tableColumn{header, func(key MetricKey, _ *MetricComparisonData, format *tableFormat) string {
+		return formatPercent(stat(format.normalized.Data[key]))
+	}}
+}
+
+func floatColumn(header string, stat func(data *MetricComparisonData) float64) tableColumn {
+	return tableColumn{header, func(_ MetricKey, data *MetricComparisonData, format *tableFormat) string {
+		return format.formatFloat(stat(data))
+	}}
+}
+
+func keyColumn(header string, field func(key MetricKey) string) tableColumn {
+	return tableColumn{header, func(key MetricKey, _ *MetricComparisonData, format *tableFormat) string {
+		return field(format.mask.Apply(key))
+	}}
+}
+
+// tableColumnNames are the names of the columns of the tables, in the order they're listed
+// by TableColumnNames. They're the CSV ones (see WriteCSV), plus a few PrettyPrint has.
+var tableColumnNames = []string{"testName", "verb", "resource", "subresource", "scope", "percentile", "platform",
+	"sizeBucket", "population", "unit", "matched", "inconclusive", "avgL", "avgR", "avgRatio", "stDevL", "stDevR",
+	"maxR", "maxRatio", "n1", "n2", "avgLPercent", "avgRPercent", "stDevRPercent", "maxRPercent", "sparkL", "sparkR",
+	"change", "comments"}
+
+var tableColumns = map[string]tableColumn{
+	"testName":    keyColumn("E2E TEST", func(key MetricKey) string { return key.TestName }),
+	"verb":        keyColumn("VERB", func(key MetricKey) string { return key.Verb }),
+	"resource":    keyColumn("RESOURCE", func(key MetricKey) string { return key.Resource }),
+	"subresource": keyColumn("SUBRESOURCE", func(key MetricKey) string { return key.Subresource }),
+	"scope":       keyColumn("SCOPE", func(key MetricKey) string { return key.Scope }),
+	"percentile":  keyColumn("PERCENTILE", func(key MetricKey) string { return key.Percentile }),
+	"platform":    keyColumn("PLATFORM", func(key MetricKey) string { return key.Platform }),
+	"sizeBucket":  keyColumn("SIZE", func(key MetricKey) string { return key.SizeBucket }),
+	"population":  keyColumn("POPULATION", func(key MetricKey) string { return key.Population }),
+	"unit": {"UNIT", func(_ MetricKey, data *MetricComparisonData, _ *tableFormat) string {
+		return data.Unit
+	}},
+	"matched": {"MATCHED", func(_ MetricKey, data *MetricComparisonData, _ *tableFormat) string {
+		return strconv.FormatBool(data.Matched)
+	}},
+	"inconclusive": {"INCONCLUSIVE", func(_ MetricKey, data *MetricComparisonData, _ *tableFormat) string {
+		return strconv.FormatBool(data.Inconclusive)
+	}},
+	"avgL":          floatColumn("AVGL", func(data *MetricComparisonData) float64 { return data.AvgL }),
+	"avgR":          floatColumn("AVGR", func(data *MetricComparisonData) float64 { return data.AvgR }),
+	"avgRatio":      floatColumn("AVG-L/R", func(data *MetricComparisonData) float64 { return data.AvgRatio }),
+	"stDevL":        floatColumn("STDEVL", func(data *MetricComparisonData) float64 { return data.StDevL }),
+	"stDevR":        floatColumn("STDEVR", func(data *MetricComparisonData) float64 { return data.StDevR }),
+	"maxR":          floatColumn("MAXR", func(data *MetricComparisonData) float64 { return data.MaxR }),
+	"maxRatio":      floatColumn("MAX-R/L", func(data *MetricComparisonData) float64 { return data.MaxRatio }),
+	"avgLPercent":   percentColumn("AVG-L", func(data *MetricComparisonData) float64 { return data.AvgL }),
+	"avgRPercent":   percentColumn("AVG-R", func(data *MetricComparisonData) float64 { return data.AvgR }),
+	"stDevRPercent": percentColumn("STDEV-R", func(data *MetricComparisonData) float64 { return data.StDevR }),
+	"maxRPercent":   percentColumn("MAX-R", func(data *MetricComparisonData) float64 { return data.MaxR }),
+	"n1": {"N1", func(_ MetricKey, data *MetricComparisonData, _ *tableFormat) string {
+		return strconv.Itoa(len(data.LeftJobSample))
+	}},
+	"n2": {"N2", func(_ MetricKey, data *MetricComparisonData, _ *tableFormat) string {
+		return strconv.Itoa(len(data.RightJobSample))
+	}},
+	"sparkL": {"SPARK-L", func(_ MetricKey, data *MetricComparisonData, _ *tableFormat) string {
+		return Sparkline(data.LeftJobSample, sparklineWidth)
+	}},
+	"sparkR": {"SPARK-R", func(_ MetricKey, data *MetricComparisonData, _ *tableFormat) string {
+		return Sparkline(data.RightJobSample, sparklineWidth)
+	}},
+	"change": {"CHANGE", func(_ MetricKey, data *MetricComparisonData, _ *tableFormat) string {
+		return data.formatChangeCI()
+	}},
+	"comments": {"COMMENTS", func(_ MetricKey, data *MetricComparisonData, format *tableFormat) string {
+		if format.enforcedTier != nil && !data.Matched && data.Tier > *format.enforcedTier {
+			return informationalMarker + data.Comments
+		}
+		return data.Comments
+	}},
+}
+
+// DefaultTableColumns are the columns of the table PrettyPrint prints by default.
+var DefaultTableColumns = []string{"testName", "verb", "resource", "subresource", "scope", "percentile", "maxRatio", "comments"}
+
+// TableColumnNames returns the names of the columns Table can produce.
+func TableColumnNames() []string {
+	return append([]string{}, tableColumnNames...)
+}
+
+func isPercentColumn(column string) bool {
+	switch column {
+	case "avgLPercent", "avgRPercent", "stDevRPercent", "maxRPercent":
+		return true
+	}
+	return false
+}
+
+// Table returns the job comparison data as a table of the given columns (see TableColumnNames,
+// DefaultTableColumns if none), with the cells PrettyPrint prints, so that it can be embedded
+// (e.g. rendered by another tool). The rows are sorted by avg ratio, like PrettyPrint's, and the
+// headers are PrettyPrint's ones. It's an error to ask for an unknown column. The stats should
+// have been computed already for the percent columns (see PercentOfBaseline).
+func (j *JobComparisonData) Table(cols []string) (headers []string, rows [][]string, err error) {
+	if len(cols) == 0 {
+		cols = DefaultTableColumns
+	}
+	return j.table(cols, getMetricsSortedByAvgRatio(j), &tableFormat{formatFloat: formatRatio}, nil)
+}
+
+// table is the single source of the tables of the job comparison data, which PrettyPrint and
+// the reports write with their own headers and float formats. It skips the metrics the filter
+// (if any) removes.
+func (j *JobComparisonData) table(cols []string, metricsList metricKeyDataPairList, format *tableFormat, filter MetricFilterFunc) (headers []string, rows [][]string, err error) {
+	columns := make([]tableColumn, 0, len(cols))
+	for _, name := range cols {
+		column, ok := tableColumns[name]
+		if !ok {
+			return nil, nil, fmt.Errorf("unknown table column '%v'", name)
+		}
+		if isPercentColumn(name) && format.normalized == nil {
+			format.normalized = j.PercentOfBaseline()
+		}
+		columns = append(columns, column)
+		headers = append(headers, column.header)
+	}
+	rows = [][]string{}
+	for _, metricPair := range metricsList {
+		key, data := metricPair.metricKey, metricPair.metricData
+		if filter != nil && filter(key, *data) {
+			continue
+		}
+		cells := make([]string, len(columns))
+		for i, column := range columns {
+			cells[i] = column.cell(key, data, format)
+		}
+		rows = append(rows, cells)
+	}
+	return headers, rows, nil
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTable(t *testing.T) {
+	regressed := MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}
+	steady := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	j := &JobComparisonData{Data: map[MetricKey]*MetricComparisonData{
+		regressed: {LeftJobSample: []float64{100, 100}, RightJobSample: []float64{150, 150}, AvgRatio: 0.67, Comments: "Regressed"},
+		steady:    {LeftJobSample: []float64{100, 100}, RightJobSample: []float64{100, math.NaN()}, AvgRatio: 1, Matched: true},
+	}}
+	j.EnsureStats()
+
+	headers, rows, err := j.Table(nil)
+	if err != nil {
+		t.Fatalf("Table failed: %v", err)
+	}
+	if expected := []string{"E2E TEST", "VERB", "RESOURCE", "SUBRESOURCE", "SCOPE", "PERCENTILE", "MAX-R/L", "COMMENTS"}; !reflect.DeepEqual(headers, expected) {
+		t.Errorf("Default headers are %v, but expected %v", headers, expected)
+	}
+	// The rows are sorted by avg ratio, like PrettyPrint's, with the undefined ratios formatted as such.
+	expectedRows := [][]string{
+		{"Load", "GET", "pods", "", "", "Perc99", "-", ""},
+		{"Load", "LIST", "pods", "", "", "Perc99", "1.50", "Regressed"},
+	}
+	if !reflect.DeepEqual(rows, expectedRows) {
+		t.Errorf("Default rows are %v, but expected %v", rows, expectedRows)
+	}
+	// They're the cells PrettyPrint prints.
+	lines := strings.Split(j.formatTable(PrettyPrintOptions{}), "\n")
+	if len(lines) < len(rows)+1 || !reflect.DeepEqual(strings.Fields(lines[0]), strings.Fields(strings.Join(headers, "\t"))) {
+		t.Fatalf("PrettyPrint's table doesn't have headers %v:\n%v", headers, strings.Join(lines, "\n"))
+	}
+	for i, cells := range rows {
+		if fields := strings.Fields(lines[i+1]); !reflect.DeepEqual(fields, strings.Fields(strings.Join(cells, "\t"))) {
+			t.Errorf("PrettyPrint's table has row %v, but expected %v", fields, cells)
+		}
+	}
+
+	headers, rows, err = j.Table([]string{"verb", "matched", "avgRPercent", "n2"})
+	if err != nil {
+		t.Fatalf("Table of given columns failed: %v", err)
+	}
+	if expected := []string{"VERB", "MATCHED", "AVG-R", "N2"}; !reflect.DeepEqual(headers, expected) {
+		t.Errorf("Headers are %v, but expected %v", headers, expected)
+	}
+	if expected := [][]string{{"GET", "true", "-", "2"}, {"LIST", "false", "150.0%", "2"}}; !reflect.DeepEqual(rows, expected) {
+		t.Errorf("Rows are %v, but expected %v", rows, expected)
+	}
+
+	if _, _, err := j.Table([]string{"verb", "bogus"}); err == nil {
+		t.Errorf("Table of an unknown column didn't fail")
+	}
+	for _, name := range TableColumnNames() {
+		if _, _, err := j.Table([]string{name}); err != nil {
+			t.Errorf("Table of listed column %v failed: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"math"
+)
+
+// throughputFor returns the throughput metric a latency metric (i.e. any metric that isn't a
+// rate) is linked to, if any. The throughput metrics are the rates (see Annotate) of the same
+// test, platform, size bucket and sub-population. Among them, the one of the same verb,
+// resource, subresource and scope (e.g. the POST pods calls per second, for the POST pods
+// latencies) is linked, falling back to the test's only throughput metric if there's a single
+// one (e.g. a test-wide pods per second). The latency is linked to none if the choice is
+// ambiguous.
+func (j *JobComparisonData) throughputFor(latencyKey MetricKey) (MetricKey, *MetricComparisonData, bool) {
+	var candidates []MetricKey
+	for metricKey, metricData := range j.Data {
+		if !metricData.IsRate || metricKey.TestName != latencyKey.TestName || metricKey.Platform != latencyKey.Platform || metricKey.SizeBucket != latencyKey.SizeBucket || metricKey.Population != latencyKey.Population {
+			continue
+		}
+		if metricKey.Verb == latencyKey.Verb && metricKey.Resource == latencyKey.Resource && metricKey.Subresource == latencyKey.Subresource && metricKey.Scope == latencyKey.Scope {
+			return metricKey, metricData, true
+		}
+		candidates = append(candidates, metricKey)
+	}
+	if len(candidates) != 1 {
+		return MetricKey{}, nil, false
+	}
+	return candidates[0], j.Data[candidates[0]], true
+}
+
+// throughputRatio returns the ratio of the right and left job's (harmonic) avg throughputs,
+// telling if it can be computed.
+func throughputRatio(throughput *MetricComparisonData) (float64, bool) {
+	return SafeDiv(HarmonicMean(throughput.RightJobSample), HarmonicMean(throughput.LeftJobSample))
+}
+
+// InvalidateOnThroughputChange marks the conclusively compared latency metrics whose linked
+// throughput (see throughputFor) changed by more than maxChange (relative to the left job's)
+// as inconclusive: as latencies naturally rise under a higher load, a change of the latency
+// is then no evidence of a regression (nor an improvement). It should be called after the
+// comparison, and returns the keys (sorted) of the metrics invalidated.
+func (j *JobComparisonData) InvalidateOnThroughputChange(maxChange float64) []MetricKey {
+	var invalidated []MetricKey
+	for _, metricPair := range getMetricsSortedByKey(j) {
+		key, data := metricPair.metricKey, metricPair.metricData
+		if data.IsRate || data.Inconclusive {
+			continue
+		}
+		throughputKey, throughput, ok := j.throughputFor(key)
+		if !ok {
+			continue
+		}
+		ratio, ok := throughputRatio(throughput)
+		if !ok || math.Abs(ratio-1) <= maxChange {
+			continue
+		}
+		comments := fmt.Sprintf("Throughput %v changed by %v", metricName(throughputKey), formatPercentChange(ratio-1))
+		if data.Comments != "" {
+			comments += "\t" + data.Comments
+		}
+		data.MarkInconclusive(comments)
+		invalidated = append(invalidated, key)
+	}
+	return invalidated
+}
+
+// NormalizeByThroughput adjusts the right job's samples of the latency metrics to the left
+// job's throughput, scaling them by the ratio of the left and right (harmonic) avg throughputs
+// of their linked throughput metric (see throughputFor). This assumes the latencies grow in
+// proportion to the throughput, which is only a first order approximation: where throughputs
+// differ a lot, InvalidateOnThroughputChange is the safer choice. The left samples, and the
+// latencies without a throughput (or whose ratio can't be computed), are left untouched. It
+// should be done before computing the stats, which it marks dirty, and returns the keys
+// (sorted) of the metrics normalized.
+func (j *JobComparisonData) NormalizeByThroughput() []MetricKey {
+	var normalized []MetricKey
+	for _, metricPair := range getMetricsSortedByKey(j) {
+		key, data := metricPair.metricKey, metricPair.metricData
+		if data.IsRate {
+			continue
+		}
+		_, throughput, ok := j.throughputFor(key)
+		if !ok {
+			continue
+		}
+		ratio, ok := throughputRatio(throughput)
+		if !ok || ratio <= 0 {
+			continue
+		}
+		data.RightJobSample = scaleSample(data.RightJobSample, 1/ratio)
+		data.invalidateStats()
+		normalized = append(normalized, key)
+	}
+	j.statsDirty = true
+	return normalized
+}
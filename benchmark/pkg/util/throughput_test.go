@@ -0,0 +1,85 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestThroughputAdjustment(t *testing.T) {
+	postLatency := MetricKey{TestName: "Load", Verb: "POST", Resource: "pods", Percentile: "Perc99"}
+	postThroughput := MetricKey{TestName: "Load", Verb: "POST", Resource: "pods", Percentile: "Perc50"}
+	getLatency := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	otherTestLatency := MetricKey{TestName: "Density", Verb: "POST", Resource: "pods", Percentile: "Perc99"}
+	newData := func() *JobComparisonData {
+		// The right job drove 50% more POSTs, and its POST latency rose by 30%.
+		j := &JobComparisonData{
+			Data: map[MetricKey]*MetricComparisonData{
+				postLatency:      {LeftJobSample: []float64{100, 100}, RightJobSample: []float64{130, 130}, Comments: "Change=+30.0%"},
+				postThroughput:   {LeftJobSample: []float64{100, 100}, RightJobSample: []float64{150, 150}, IsRate: true, Matched: true},
+				getLatency:       {LeftJobSample: []float64{100}, RightJobSample: []float64{100}, Matched: true},
+				otherTestLatency: {LeftJobSample: []float64{100}, RightJobSample: []float64{130}},
+			},
+		}
+		j.ComputeStatsForMetricSamples()
+		return j
+	}
+
+	// The POST latency "regression" is invalidated by the POST throughput change, as is the
+	// GET latency (linked to the test's only throughput metric), while the other test's isn't.
+	j := newData()
+	invalidated := j.InvalidateOnThroughputChange(0.1)
+	if !reflect.DeepEqual(invalidated, []MetricKey{getLatency, postLatency}) {
+		t.Errorf("Invalidated metrics %v, but expected %v", invalidated, []MetricKey{getLatency, postLatency})
+	}
+	if data := j.Data[postLatency]; !data.Inconclusive || data.regressed() || data.Comments != "Throughput Load POST pods Perc50 changed by +50.0%\tChange=+30.0%" {
+		t.Errorf("Latency regression under a higher throughput not invalidated: %+v", data)
+	}
+	if data := j.Data[otherTestLatency]; data.Inconclusive || !data.regressed() {
+		t.Errorf("Latency regression without a throughput invalidated: %+v", data)
+	}
+	// A throughput change within the bound doesn't.
+	if invalidated := newData().InvalidateOnThroughputChange(0.5); len(invalidated) != 0 {
+		t.Errorf("Invalidated metrics %v, but expected none", invalidated)
+	}
+
+	// Normalized to the left job's throughput, the POST latency went down.
+	j = newData()
+	normalized := j.NormalizeByThroughput()
+	if !reflect.DeepEqual(normalized, []MetricKey{getLatency, postLatency}) {
+		t.Errorf("Normalized metrics %v, but expected %v", normalized, []MetricKey{getLatency, postLatency})
+	}
+	if !j.StatsDirty() {
+		t.Errorf("Stats not marked dirty by normalizing the samples")
+	}
+	j.ComputeStatsForMetricSamples()
+	if avg := j.Data[postLatency].AvgR; math.Abs(avg-130/1.5) > 1e-9 {
+		t.Errorf("Normalized right avg latency is %v, but expected %v", avg, 130/1.5)
+	}
+	if avg := j.Data[otherTestLatency].AvgR; avg != 130 {
+		t.Errorf("Right avg latency without a throughput is %v, but expected it unchanged", avg)
+	}
+
+	// With two throughput metrics, the GET latency's link is ambiguous.
+	j = newData()
+	j.Data[MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc50"}] = &MetricComparisonData{LeftJobSample: []float64{10}, RightJobSample: []float64{20}, IsRate: true}
+	if invalidated := j.InvalidateOnThroughputChange(0.1); !reflect.DeepEqual(invalidated, []MetricKey{postLatency}) {
+		t.Errorf("Invalidated metrics %v, but expected %v", invalidated, []MetricKey{postLatency})
+	}
+}
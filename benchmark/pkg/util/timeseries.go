@@ -0,0 +1,216 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"math"
+	"sort"
+	"time"
+)
+
+// TimeSeriesPoint is the value of a metric in a build, and the time of the build (zero if unknown).
+type TimeSeriesPoint struct {
+	BuildID   string
+	Value     float64
+	Timestamp time.Time
+}
+
+// TimeSeries accumulates the right job avgs of metrics across the comparisons for many builds,
+// into a series per metric (in the order the builds were added), for trend charts.
+type TimeSeries struct {
+	Series map[MetricKey][]TimeSeriesPoint
+}
+
+// NewTimeSeries is a constructor for TimeSeries struct.
+func NewTimeSeries() *TimeSeries {
+	return &TimeSeries{
+		Series: make(map[MetricKey][]TimeSeriesPoint),
+	}
+}
+
+// Add appends the avg of each metric's right job sample in the comparison data to the
+// metric's series, tagged with the ID of the build. Metrics without right job samples
+// are skipped.
+func (ts *TimeSeries) Add(buildID string, j *JobComparisonData) {
+	ts.AddAt(buildID, time.Time{}, j)
+}
+
+// AddAt is like Add, but also tags the metrics' values with the time of the build, e.g. for
+// comparing the builds of different time windows (see CompareWindows).
+func (ts *TimeSeries) AddAt(buildID string, timestamp time.Time, j *JobComparisonData) {
+	for metricKey, metricData := range j.Data {
+		if len(metricData.RightJobSample) == 0 {
+			continue
+		}
+		ts.Series[metricKey] = append(ts.Series[metricKey], TimeSeriesPoint{BuildID: buildID, Value: Mean(metricData.RightJobSample), Timestamp: timestamp})
+	}
+}
+
+// CompareWindows pools the values of each metric's builds in the left window [leftStart,
+// leftEnd) and in the right window [rightStart, rightEnd) into the left and right samples of
+// a standard comparison, e.g. of this week's builds against last week's, with a sample value
+// per build (the avg of its runs). It requires the builds' times (see AddAt): builds of an
+// unknown time are in neither window. Like for TimeWindow, a zero end leaves a window
+// unbounded. Metrics with less than minCount builds in either window are left out (so that a
+// window with a single build isn't taken for a trend). As with the flattened data, the stats
+// are yet to be computed, by the comparison scheme.
+func (ts *TimeSeries) CompareWindows(leftStart, leftEnd, rightStart, rightEnd time.Time, minCount int) *JobComparisonData {
+	leftWindow, rightWindow := TimeWindow{Start: leftStart, End: leftEnd}, TimeWindow{Start: rightStart, End: rightEnd}
+	j := NewJobComparisonData()
+	for metricKey, points := range ts.Series {
+		metricData := &MetricComparisonData{}
+		for _, point := range points {
+			if point.Timestamp.IsZero() {
+				continue
+			}
+			if leftWindow.Contains(point.Timestamp) {
+				metricData.LeftJobSample = append(metricData.LeftJobSample, point.Value)
+				metricData.LeftJobTimestamps = append(metricData.LeftJobTimestamps, point.Timestamp)
+			}
+			if rightWindow.Contains(point.Timestamp) {
+				metricData.RightJobSample = append(metricData.RightJobSample, point.Value)
+				metricData.RightJobTimestamps = append(metricData.RightJobTimestamps, point.Timestamp)
+			}
+		}
+		if len(metricData.LeftJobSample) == 0 || len(metricData.RightJobSample) == 0 ||
+			len(metricData.LeftJobSample) < minCount || len(metricData.RightJobSample) < minCount {
+			continue
+		}
+		j.Data[metricKey] = metricData
+	}
+	return j
+}
+
+func (ts *TimeSeries) sortedKeys() []MetricKey {
+	keys := make([]MetricKey, 0, len(ts.Series))
+	for metricKey := range ts.Series {
+		keys = append(keys, metricKey)
+	}
+	sort.Slice(keys, func(a, b int) bool { return metricKeyLess(keys[a], keys[b]) })
+	return keys
+}
+
+// WriteCSV writes the series to w in CSV, with a row per metric and build (sorted by
+// metric key, then in the order of builds). Non-finite values are left empty.
+func (ts *TimeSeries) WriteCSV(w io.Writer) error {
+	return ts.WriteCSVWithNaNPolicy(w, NaNDefault)
+}
+
+// WriteCSVWithNaNPolicy is like WriteCSV, but represents the non-finite values as per the
+// policy (see NaNPolicy).
+func (ts *TimeSeries) WriteCSVWithNaNPolicy(w io.Writer, policy NaNPolicy) error {
+	csvWriter := csv.NewWriter(w)
+	if err := csvWriter.Write([]string{"testName", "verb", "resource", "subresource", "scope", "percentile", "buildId", "value"}); err != nil {
+		return err
+	}
+	for _, metricKey := range ts.sortedKeys() {
+		for _, point := range ts.Series[metricKey] {
+			record := append(metricKey.fields(), point.BuildID, formatCSVFloat(point.Value, policy))
+			if err := csvWriter.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+type timeSeriesPointRecord struct {
+	BuildID   string     `json:"buildId"`
+	Value     *jsonFloat `json:"value,omitempty"`
+	Timestamp *time.Time `json:"timestamp,omitempty"` // Left out if unknown
+}
+
+type timeSeriesRecord struct {
+	TestName    string                  `json:"testName"`
+	Verb        string                  `json:"verb"`
+	Resource    string                  `json:"resource,omitempty"`
+	Subresource string                  `json:"subresource,omitempty"`
+	Scope       string                  `json:"scope,omitempty"`
+	Percentile  string                  `json:"percentile"`
+	Points      []timeSeriesPointRecord `json:"points"`
+}
+
+// ToJSON encodes the series as a JSON array with a record per metric (sorted by metric key),
+// each holding the metric's points in the order of builds, along with the times of the builds
+// (if known, see AddAt) for the series to be compared by time windows once reloaded.
+// Non-finite values are null.
+func (ts *TimeSeries) ToJSON() ([]byte, error) {
+	return ts.ToJSONWithNaNPolicy(NaNDefault)
+}
+
+// ToJSONWithNaNPolicy is like ToJSON, but represents the non-finite values as per the policy
+// (see NaNPolicy).
+func (ts *TimeSeries) ToJSONWithNaNPolicy(policy NaNPolicy) ([]byte, error) {
+	records := make([]timeSeriesRecord, 0, len(ts.Series))
+	for _, metricKey := range ts.sortedKeys() {
+		record := timeSeriesRecord{
+			TestName:    metricKey.TestName,
+			Verb:        metricKey.Verb,
+			Resource:    metricKey.Resource,
+			Subresource: metricKey.Subresource,
+			Scope:       metricKey.Scope,
+			Percentile:  metricKey.Percentile,
+		}
+		for _, point := range ts.Series[metricKey] {
+			pointRecord := timeSeriesPointRecord{BuildID: point.BuildID, Value: newJSONFloat(point.Value, policy)}
+			if !point.Timestamp.IsZero() {
+				timestamp := point.Timestamp
+				pointRecord.Timestamp = &timestamp
+			}
+			record.Points = append(record.Points, pointRecord)
+		}
+		records = append(records, record)
+	}
+	return json.Marshal(records)
+}
+
+// ProjectBreach projects how many builds after the latest one the metric will breach the SLO at
+// the current rate of regression, by fitting a linear trend (least squares over the builds'
+// order) to its series and extrapolating it to the SLO. It's 0 if the trend has reached the
+// SLO already. It returns ok=false if the trend is flat or improving (or can't be fit, as the
+// series has less than 2 finite values), as the SLO won't be breached then.
+func (ts *TimeSeries) ProjectBreach(key MetricKey, slo float64) (builds int, ok bool) {
+	var xs, ys []float64
+	for i, point := range ts.Series[key] {
+		if !math.IsNaN(point.Value) && !math.IsInf(point.Value, 0) {
+			xs, ys = append(xs, float64(i)), append(ys, point.Value)
+		}
+	}
+	if len(xs) < 2 {
+		return 0, false
+	}
+	meanX, meanY := Mean(xs), Mean(ys)
+	covariance, varianceX := 0.0, 0.0
+	for i := range xs {
+		covariance += (xs[i] - meanX) * (ys[i] - meanY)
+		varianceX += (xs[i] - meanX) * (xs[i] - meanX)
+	}
+	slope := covariance / varianceX
+	if !(slope > 0) {
+		return 0, false
+	}
+	latest := float64(len(ts.Series[key]) - 1)
+	remaining := (slo - (meanY + slope*(latest-meanX))) / slope
+	if remaining <= 0 {
+		return 0, true
+	}
+	return int(math.Ceil(remaining)), true
+}
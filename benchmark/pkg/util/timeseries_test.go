@@ -0,0 +1,140 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestTimeSeries(t *testing.T) {
+	getPods := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	listPods := MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}
+	ts := NewTimeSeries()
+	ts.Add("101", &JobComparisonData{Data: map[MetricKey]*MetricComparisonData{
+		getPods:  {LeftJobSample: []float64{1}, RightJobSample: []float64{10, 20}},
+		listPods: {RightJobSample: []float64{100}},
+	}})
+	ts.Add("102", &JobComparisonData{Data: map[MetricKey]*MetricComparisonData{
+		getPods:  {RightJobSample: []float64{16}},
+		listPods: {RightJobSample: []float64{}},
+	}})
+	ts.Add("103", &JobComparisonData{Data: map[MetricKey]*MetricComparisonData{
+		getPods:  {RightJobSample: []float64{17.5}},
+		listPods: {RightJobSample: []float64{120}},
+	}})
+
+	var buf bytes.Buffer
+	if err := ts.WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV failed: %v", err)
+	}
+	expectedCSV := `testName,verb,resource,subresource,scope,percentile,buildId,value
+Load,GET,pods,,,Perc99,101,15
+Load,GET,pods,,,Perc99,102,16
+Load,GET,pods,,,Perc99,103,17.5
+Load,LIST,pods,,,Perc99,101,100
+Load,LIST,pods,,,Perc99,103,120
+`
+	if buf.String() != expectedCSV {
+		t.Errorf("CSV mismatched:\nReal:\n%v\nExpected:\n%v", buf.String(), expectedCSV)
+	}
+
+	contents, err := ts.ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON failed: %v", err)
+	}
+	expectedJSON := `[{"testName":"Load","verb":"GET","resource":"pods","percentile":"Perc99","points":[{"buildId":"101","value":15},{"buildId":"102","value":16},{"buildId":"103","value":17.5}]},` +
+		`{"testName":"Load","verb":"LIST","resource":"pods","percentile":"Perc99","points":[{"buildId":"101","value":100},{"buildId":"103","value":120}]}]`
+	if string(contents) != expectedJSON {
+		t.Errorf("JSON mismatched:\nReal: %v\nExpected: %v", string(contents), expectedJSON)
+	}
+}
+
+func TestProjectBreach(t *testing.T) {
+	degrading := MetricKey{TestName: "Density", Verb: "Pod-Startup", Percentile: "Perc99"}
+	improving := MetricKey{TestName: "Density", Verb: "Pod-Startup", Percentile: "Perc50"}
+	ts := NewTimeSeries()
+	for i, value := range []float64{100, 110, 120, 130, 140} {
+		buildID := string(rune('a' + i))
+		ts.Series[degrading] = append(ts.Series[degrading], TimeSeriesPoint{BuildID: buildID, Value: value})
+		ts.Series[improving] = append(ts.Series[improving], TimeSeriesPoint{BuildID: buildID, Value: 200 - value})
+	}
+
+	// Degrading by 10 per build from 140, 200 is breached 6 builds later.
+	if builds, ok := ts.ProjectBreach(degrading, 200); !ok || builds != 6 {
+		t.Errorf("Breach of 200 projected in %v builds (ok=%v), but expected 6", builds, ok)
+	}
+	if builds, ok := ts.ProjectBreach(degrading, 135); !ok || builds != 0 {
+		t.Errorf("Breach of an already exceeded SLO projected in %v builds (ok=%v), but expected 0", builds, ok)
+	}
+	if _, ok := ts.ProjectBreach(improving, 200); ok {
+		t.Errorf("Breach projected for an improving metric")
+	}
+	if _, ok := ts.ProjectBreach(MetricKey{TestName: "Unknown"}, 200); ok {
+		t.Errorf("Breach projected for a metric without series")
+	}
+}
+
+func TestCompareWindows(t *testing.T) {
+	getPods := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	listPods := MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}
+	lastWeek := time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC)
+	thisWeek := lastWeek.AddDate(0, 0, 7)
+	ts := NewTimeSeries()
+	// A daily build over the two weeks, GET getting slower this week and LIST only built once last week.
+	for day := 0; day < 14; day++ {
+		getLatency := 100.0
+		if day >= 7 {
+			getLatency = 150
+		}
+		data := map[MetricKey]*MetricComparisonData{getPods: {RightJobSample: []float64{getLatency}}}
+		if day >= 6 {
+			data[listPods] = &MetricComparisonData{RightJobSample: []float64{50}}
+		}
+		ts.AddAt(fmt.Sprintf("%v", 100+day), lastWeek.AddDate(0, 0, day), &JobComparisonData{Data: data})
+	}
+	// Builds of an unknown time aren't in either window.
+	ts.Add("999", &JobComparisonData{Data: map[MetricKey]*MetricComparisonData{getPods: {RightJobSample: []float64{1000}}}})
+
+	j := ts.CompareWindows(lastWeek, thisWeek, thisWeek, time.Time{}, 2)
+	if len(j.Data) != 1 {
+		t.Fatalf("Compared %v metrics, but expected only GET (LIST having a single build last week)", len(j.Data))
+	}
+	getData := j.Data[getPods]
+	if len(getData.LeftJobSample) != 7 || len(getData.RightJobSample) != 7 || Mean(getData.LeftJobSample) != 100 || Mean(getData.RightJobSample) != 150 {
+		t.Errorf("GET compared as %v vs %v, but expected 7 builds at 100 vs 7 at 150", getData.LeftJobSample, getData.RightJobSample)
+	}
+	if !getData.LeftJobTimestamps[0].Equal(lastWeek) || !getData.RightJobTimestamps[0].Equal(thisWeek) {
+		t.Errorf("GET's builds timestamped %v vs %v", getData.LeftJobTimestamps, getData.RightJobTimestamps)
+	}
+
+	j = ts.CompareWindows(lastWeek, thisWeek, thisWeek, time.Time{}, 1)
+	if listData, ok := j.Data[listPods]; !ok || len(listData.LeftJobSample) != 1 || len(listData.RightJobSample) != 7 {
+		t.Errorf("LIST compared as %+v, but expected a build vs 7", listData)
+	}
+
+	// The times of the builds are kept in the JSON, for the series to be compared once reloaded.
+	contents, err := ts.ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON failed: %v", err)
+	}
+	if !bytes.Contains(contents, []byte(`{"buildId":"100","value":100,"timestamp":"2026-10-05T00:00:00Z"}`)) || !bytes.Contains(contents, []byte(`{"buildId":"999","value":1000}`)) {
+		t.Errorf("Series encoded as %s, but expected the builds' times (if known)", contents)
+	}
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"sort"
+	"strconv"
+	"time"
+)
+
+// TimeWindow is a span of time runs are to be within. Either of its ends may be left zero,
+// for it to be unbounded on that side, the zero TimeWindow not restricting runs at all.
+type TimeWindow struct {
+	Start, End time.Time // Inclusive start and exclusive end
+}
+
+// LastWindow returns the window of the given duration until now, e.g. for comparing the
+// runs of the last 7 days.
+func LastWindow(duration time.Duration, now time.Time) TimeWindow {
+	return TimeWindow{Start: now.Add(-duration), End: now}
+}
+
+// IsZero tells if the window is unbounded on both sides.
+func (w TimeWindow) IsZero() bool {
+	return w.Start.IsZero() && w.End.IsZero()
+}
+
+// Contains tells if the given time is within the window.
+func (w TimeWindow) Contains(t time.Time) bool {
+	return !t.Before(w.Start) && (w.End.IsZero() || t.Before(w.End))
+}
+
+// includes tells if the run is to be flattened, i.e. the window is unbounded or the run is of
+// a known time within it.
+func (w TimeWindow) includes(run sampleRun) bool {
+	return w.IsZero() || (!run.timestamp.IsZero() && w.Contains(run.timestamp))
+}
+
+func (j *JobComparisonData) dropRunOutsideWindow(fromLeftJob bool) {
+	if fromLeftJob {
+		j.leftRunsOutsideWindow++
+	} else {
+		j.rightRunsOutsideWindow++
+	}
+}
+
+// keepTimestamps tells if the options ask for retaining the samples' timestamps.
+func (o *FlattenOptions) keepTimestamps() bool {
+	return o.LeftRunTimestamps != nil || o.RightRunTimestamps != nil || o.RunTimestamp != nil || o.TimestampLabel != ""
+}
+
+// runTimestamp returns the time of the run of the given index (zero if unknown).
+func (o *FlattenOptions) runTimestamp(run int, fromLeftJob bool) time.Time {
+	if o.RunTimestamp != nil {
+		if timestamp, ok := o.RunTimestamp(run, fromLeftJob); ok {
+			return timestamp
+		}
+		return time.Time{}
+	}
+	if fromLeftJob {
+		return o.LeftRunTimestamps[run]
+	}
+	return o.RightRunTimestamps[run]
+}
+
+// itemTimestamp returns the time of the run a DataItem with the given labels is from, which is
+// that of its TimestampLabel if valid, or else runTime.
+func (o *FlattenOptions) itemTimestamp(labels map[string]string, runTime time.Time) time.Time {
+	if o.TimestampLabel == "" {
+		return runTime
+	}
+	if timestamp, ok := ParseTimestamp(labels[o.TimestampLabel]); ok {
+		return timestamp
+	}
+	return runTime
+}
+
+// ParseTimestamp parses a timestamp given either in RFC 3339 format or as seconds since the
+// Unix epoch, telling if it's valid.
+func ParseTimestamp(value string) (time.Time, bool) {
+	if timestamp, err := time.Parse(time.RFC3339, value); err == nil {
+		return timestamp, true
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0).UTC(), true
+	}
+	return time.Time{}, false
+}
+
+// SortByTime orders the metric's left and right samples (along with their request counts and
+// run indices, if retained) chronologically by the times of the runs they're from, rather than
+// the order the runs were flattened in. Samples of the same or an unknown (zero) time keep their
+// relative order, the latter coming first. Samples without timestamps (as none were given while
+// flattening) are left as they are.
+func (d *MetricComparisonData) SortByTime() {
+	sortSampleByTime(d.LeftJobSample, d.LeftJobRequestCounts, d.LeftJobTimestamps, d.LeftJobRunIndices)
+	sortSampleByTime(d.RightJobSample, d.RightJobRequestCounts, d.RightJobTimestamps, d.RightJobRunIndices)
+}
+
+func sortSampleByTime(sample, requestCounts []float64, timestamps []time.Time, runIndices []int) {
+	if len(timestamps) != len(sample) {
+		return
+	}
+	order := make([]int, len(sample))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return timestamps[order[a]].Before(timestamps[order[b]]) })
+	sortedSample := make([]float64, len(sample))
+	sortedTimestamps := make([]time.Time, len(timestamps))
+	for i, index := range order {
+		sortedSample[i], sortedTimestamps[i] = sample[index], timestamps[index]
+	}
+	copy(sample, sortedSample)
+	copy(timestamps, sortedTimestamps)
+	if len(requestCounts) == len(sample) {
+		sortedRequestCounts := make([]float64, len(requestCounts))
+		for i, index := range order {
+			sortedRequestCounts[i] = requestCounts[index]
+		}
+		copy(requestCounts, sortedRequestCounts)
+	}
+	if len(runIndices) == len(sample) {
+		sortedRunIndices := make([]int, len(runIndices))
+		for i, index := range order {
+			sortedRunIndices[i] = runIndices[index]
+		}
+		copy(runIndices, sortedRunIndices)
+	}
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/kubernetes/test/e2e/perftype"
+)
+
+func TestSortByTime(t *testing.T) {
+	runMetrics := func(latency float64, timestamp string) map[string][]perftype.PerfData {
+		labels := map[string]string{"Count": "10", "Resource": "pods", "Verb": "GET"}
+		if timestamp != "" {
+			labels["Timestamp"] = timestamp
+		}
+		return map[string][]perftype.PerfData{
+			"Load": {{Version: "v1", DataItems: []perftype.DataItem{{Data: map[string]float64{"Perc99": latency}, Unit: "ms", Labels: labels}}}},
+		}
+	}
+	metricKey := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	day := func(d int) time.Time { return time.Date(2026, time.October, d, 0, 0, 0, 0, time.UTC) }
+
+	// The right job's runs were discovered out of order, the second one's time being given by label.
+	leftJobMetrics := []map[string][]perftype.PerfData{runMetrics(10, ""), runMetrics(20, "")}
+	rightJobMetrics := []map[string][]perftype.PerfData{runMetrics(30, ""), runMetrics(40, "2026-10-01T00:00:00Z"), runMetrics(50, "")}
+	options := FlattenOptions{
+		MinAllowedAPIRequestCount: 10,
+		KeepRequestCounts:         true,
+		KeepRunIndices:            true,
+		RightRunTimestamps:        map[int]time.Time{0: day(3), 1: day(9), 2: day(2)},
+		TimestampLabel:            "Timestamp",
+	}
+	j := GetFlattennedComparisonDataWithOptions(leftJobMetrics, rightJobMetrics, options)
+	// Runs appended later have an unknown time, unless given by label.
+	j.AppendRuns(nil, []map[string][]perftype.PerfData{runMetrics(60, "1791072000"), runMetrics(70, "not a time")}, 10)
+	metricData := j.Data[metricKey]
+	metricData.RightJobRequestCounts[0] = 3 // To follow the sample value of 30 around.
+
+	metricData.SortByTime()
+	if expected := []float64{70, 40, 50, 30, 60}; !reflect.DeepEqual(metricData.RightJobSample, expected) {
+		t.Errorf("Right sample sorted by time as %v, but expected %v", metricData.RightJobSample, expected)
+	}
+	if expected := []time.Time{{}, day(1), day(2), day(3), day(4)}; !reflect.DeepEqual(metricData.RightJobTimestamps, expected) {
+		t.Errorf("Right sample timestamps sorted as %v, but expected %v", metricData.RightJobTimestamps, expected)
+	}
+	if expected := []float64{10, 10, 10, 3, 10}; !reflect.DeepEqual(metricData.RightJobRequestCounts, expected) {
+		t.Errorf("Right sample request counts sorted as %v, but expected %v", metricData.RightJobRequestCounts, expected)
+	}
+	if expected := []int{4, 1, 2, 0, 3}; !reflect.DeepEqual(metricData.RightJobRunIndices, expected) {
+		t.Errorf("Right sample run indices sorted as %v, but expected %v", metricData.RightJobRunIndices, expected)
+	}
+	// Samples of unknown time keep their order.
+	if expected := []float64{10, 20}; !reflect.DeepEqual(metricData.LeftJobSample, expected) {
+		t.Errorf("Left sample sorted by time as %v, but expected %v", metricData.LeftJobSample, expected)
+	}
+
+	// Without timestamps, the discovery order is preserved.
+	j = GetFlattennedComparisonData(leftJobMetrics, rightJobMetrics, 10)
+	metricData = j.Data[metricKey]
+	metricData.SortByTime()
+	if metricData.RightJobTimestamps != nil || !reflect.DeepEqual(metricData.RightJobSample, []float64{30, 40, 50}) {
+		t.Errorf("Sample without timestamps reordered: %+v", metricData)
+	}
+}
+
+func TestTimeWindow(t *testing.T) {
+	runMetrics := func(latency float64) map[string][]perftype.PerfData {
+		return map[string][]perftype.PerfData{
+			"Load": {{Version: "v1", DataItems: []perftype.DataItem{{Data: map[string]float64{"Perc99": latency}, Unit: "ms", Labels: map[string]string{"Count": "10", "Resource": "pods", "Verb": "GET"}}}}},
+		}
+	}
+	metricKey := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	day := func(d int) time.Time { return time.Date(2026, time.October, d, 0, 0, 0, 0, time.UTC) }
+
+	leftJobMetrics := []map[string][]perftype.PerfData{runMetrics(10), runMetrics(20), runMetrics(30)}
+	rightJobMetrics := []map[string][]perftype.PerfData{runMetrics(40), runMetrics(50), runMetrics(60), runMetrics(70)}
+	leftDays, rightDays := []int{1, 8, 14}, []int{6, 7, 13, 0}
+	options := FlattenOptions{
+		MinAllowedAPIRequestCount: 10,
+		KeepRunIndices:            true,
+		RunTimestamp: func(run int, fromLeftJob bool) (time.Time, bool) {
+			d := rightDays[run]
+			if fromLeftJob {
+				d = leftDays[run]
+			}
+			return day(d), d != 0
+		},
+		// The last 7 days, as of Oct 14th; the right job's last run is of unknown time.
+		TimeWindow: LastWindow(7*24*time.Hour, day(14)),
+	}
+	j := GetFlattennedComparisonDataWithOptions(leftJobMetrics, rightJobMetrics, options)
+	metricData := j.Data[metricKey]
+	if expected := []float64{20}; !reflect.DeepEqual(metricData.LeftJobSample, expected) {
+		t.Errorf("Left sample in the window is %v, but expected %v", metricData.LeftJobSample, expected)
+	}
+	if expected := []float64{50, 60}; !reflect.DeepEqual(metricData.RightJobSample, expected) {
+		t.Errorf("Right sample in the window is %v, but expected %v", metricData.RightJobSample, expected)
+	}
+	if expected := []int{1, 2}; !reflect.DeepEqual(metricData.RightJobRunIndices, expected) {
+		t.Errorf("Right sample run indices are %v, but expected %v", metricData.RightJobRunIndices, expected)
+	}
+	if expected := []time.Time{day(7), day(13)}; !reflect.DeepEqual(metricData.RightJobTimestamps, expected) {
+		t.Errorf("Right sample timestamps are %v, but expected %v", metricData.RightJobTimestamps, expected)
+	}
+	// The runs dropped don't make the metric partially present.
+	if partialMetrics := j.PartiallyPresentMetrics(); len(partialMetrics) != 0 {
+		t.Errorf("Metrics partially present across the runs in the window: %v", partialMetrics)
+	}
+
+	// Runs appended aren't filtered.
+	j.AppendRuns(nil, []map[string][]perftype.PerfData{runMetrics(80)}, 10)
+	if expected := []float64{50, 60, 80}; !reflect.DeepEqual(metricData.RightJobSample, expected) {
+		t.Errorf("Right sample after appending runs is %v, but expected %v", metricData.RightJobSample, expected)
+	}
+
+	if !(TimeWindow{}).Contains(day(1)) || (TimeWindow{Start: day(2)}).Contains(day(1)) || (TimeWindow{End: day(2)}).Contains(day(2)) {
+		t.Errorf("TimeWindow.Contains doesn't treat the window's ends as expected")
+	}
+}
@@ -0,0 +1,241 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// Transform is a transformation applied to a metric's values before computing its stats and
+// comparing it, e.g. to compare latencies (often log-normal) on the log scale, where the
+// mean-based schemes are more valid.
+type Transform int
+
+// Transforms a metric can be compared with.
+const (
+	TransformNone Transform = iota // Default, comparing the values as they are
+	TransformLog                   // Natural log, for positive values
+	TransformSqrt                  // Square root, for non-negative values
+)
+
+var transformNames = []string{"none", "log", "sqrt"}
+
+func (t Transform) String() string {
+	if t < 0 || int(t) >= len(transformNames) {
+		return fmt.Sprintf("Transform(%d)", int(t))
+	}
+	return transformNames[t]
+}
+
+// ParseTransform parses a transform by its name ("none", "log" or "sqrt").
+func ParseTransform(s string) (Transform, error) {
+	for i, name := range transformNames {
+		if s == name {
+			return Transform(i), nil
+		}
+	}
+	return TransformNone, fmt.Errorf("bad transform '%v'", s)
+}
+
+// MarshalJSON encodes the transform as its name (like "log").
+func (t Transform) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+// UnmarshalJSON decodes a transform from its name (like "log").
+func (t *Transform) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	transform, err := ParseTransform(s)
+	if err != nil {
+		return err
+	}
+	*t = transform
+	return nil
+}
+
+// admits tells if the transform is defined for the value.
+func (t Transform) admits(value float64) bool {
+	switch t {
+	case TransformLog:
+		return value > 0
+	case TransformSqrt:
+		return value >= 0
+	default:
+		return true
+	}
+}
+
+func (t Transform) apply(value float64) float64 {
+	switch t {
+	case TransformLog:
+		return math.Log(value)
+	case TransformSqrt:
+		return math.Sqrt(value)
+	default:
+		return value
+	}
+}
+
+// invert maps a value on the transformed scale back to the original one.
+func (t Transform) invert(value float64) float64 {
+	switch t {
+	case TransformLog:
+		return math.Exp(value)
+	case TransformSqrt:
+		return value * value
+	default:
+		return value
+	}
+}
+
+// transformedValues returns the values transformed.
+func (t Transform) transformedValues(values []float64) []float64 {
+	if values == nil {
+		return nil
+	}
+	transformed := make([]float64, len(values))
+	for i, value := range values {
+		transformed[i] = t.apply(value)
+	}
+	return transformed
+}
+
+// inadmissibleValueCount returns the no. of the metric's values the transform isn't defined for.
+func (d *MetricComparisonData) inadmissibleValueCount() int {
+	count := 0
+	for _, values := range [][]float64{d.LeftJobSample, d.RightJobSample, d.LeftJobNodeMaxes, d.RightJobNodeMaxes} {
+		for _, value := range values {
+			if !d.Transform.admits(value) && !math.IsNaN(value) {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// OriginalScale maps a location statistic of the metric's values (e.g. an avg or a percentile)
+// back to their original scale while they're being compared transformed (see WithTransforms),
+// returning it as it is otherwise.
+func (d *MetricComparisonData) OriginalScale(value float64) float64 {
+	if !d.transformed {
+		return value
+	}
+	return d.Transform.invert(value)
+}
+
+// BelowMinAvg tells if both the metric's avgs are below minMetricAvgForCompare, which is on the
+// original scale of its values, even while they're being compared transformed.
+func (d *MetricComparisonData) BelowMinAvg(minMetricAvgForCompare float64) bool {
+	return d.OriginalScale(d.AvgL) < minMetricAvgForCompare && d.OriginalScale(d.AvgR) < minMetricAvgForCompare
+}
+
+// RatioOnOriginalScale is like SafeDiv of the left and right location statistics of the metric
+// (e.g. its avgs), taking their ratio on the original scale of its values while they're being
+// compared transformed: e.g. exp(left-right) for Log, which for the avgs is the ratio of the
+// geometric means. Unlike the ratio of the statistics of the logs, it's independent of the unit,
+// doesn't shrink towards 1 and is never negative, so it can be gated on the same bounds.
+func (d *MetricComparisonData) RatioOnOriginalScale(left, right float64) (float64, bool) {
+	if d.transformed && d.Transform == TransformLog {
+		return SafeDiv(math.Exp(left-right), 1)
+	}
+	return SafeDiv(d.OriginalScale(left), d.OriginalScale(right))
+}
+
+// originalValues holds a metric's values from before transforming them.
+type originalValues struct {
+	leftSample, rightSample       []float64
+	leftNodeMaxes, rightNodeMaxes []float64
+}
+
+// withTransforms runs the comparison with the values of the metrics having a Transform (see
+// WithTransforms) transformed, and then reports them on the original scale.
+func (j *JobComparisonData) withTransforms(compare func()) {
+	originals := make(map[*MetricComparisonData]originalValues)
+	skipped := make(map[*MetricComparisonData]int)
+	for _, metricData := range j.Data {
+		if metricData.Transform == TransformNone {
+			continue
+		}
+		if count := metricData.inadmissibleValueCount(); count > 0 {
+			skipped[metricData] = count
+			continue
+		}
+		originals[metricData] = originalValues{metricData.LeftJobSample, metricData.RightJobSample, metricData.LeftJobNodeMaxes, metricData.RightJobNodeMaxes}
+		metricData.LeftJobSample = metricData.Transform.transformedValues(metricData.LeftJobSample)
+		metricData.RightJobSample = metricData.Transform.transformedValues(metricData.RightJobSample)
+		metricData.LeftJobNodeMaxes = metricData.Transform.transformedValues(metricData.LeftJobNodeMaxes)
+		metricData.RightJobNodeMaxes = metricData.Transform.transformedValues(metricData.RightJobNodeMaxes)
+		metricData.transformed = true
+		metricData.invalidateStats()
+	}
+	if len(originals) > 0 {
+		j.statsDirty = true
+	}
+	compare()
+	if len(originals) > 0 {
+		// The stats of the metrics the comparison didn't get to are needed on the transformed scale too.
+		j.EnsureStats()
+	}
+	for metricData, original := range originals {
+		avgL, avgR := metricData.AvgL, metricData.AvgR
+		zScore, glassDelta, snr := metricData.ZScoreOfRight, metricData.GlassDelta, metricData.SNR
+		metricData.LeftJobSample, metricData.RightJobSample = original.leftSample, original.rightSample
+		metricData.LeftJobNodeMaxes, metricData.RightJobNodeMaxes = original.leftNodeMaxes, original.rightNodeMaxes
+		metricData.transformed = false
+		j.computeMetricStats(metricData)
+		metricData.AvgL, metricData.AvgR = metricData.Transform.invert(avgL), metricData.Transform.invert(avgR)
+		metricData.AvgRatio, _ = SafeDiv(metricData.AvgL, metricData.AvgR)
+		metricData.ZScoreOfRight, metricData.GlassDelta, metricData.SNR = zScore, glassDelta, snr
+		metricData.Comments += fmt.Sprintf("\tTransform=%v", metricData.Transform)
+	}
+	for metricData, count := range skipped {
+		metricData.Comments += fmt.Sprintf("\tTransform=%v skipped for %v values out of its domain", metricData.Transform, count)
+	}
+}
+
+// WithTransforms returns the comparison scheme comparing the metrics that have a Transform
+// (e.g. annotated with one) on the transformed scale: their values (samples and per-node maxes)
+// are transformed before computing their stats and comparing them, so that the verdicts,
+// p-values and the scheme's comments are those on the transformed scale. The schemes gating
+// on ratios (e.g. Avg-Test) take them on the original scale though, and so are the min avgs
+// for comparing the metrics (see RatioOnOriginalScale and BelowMinAvg).
+//
+// The results are then reported on the original scale where sensible: the avgs are those of
+// the transformed scale mapped back (i.e. geometric means for Log, and squared means of square
+// roots for Sqrt), the avg ratios are those of these avgs, and the standardized effects (the
+// z-scores, Glass's deltas and SNRs) are kept from the transformed scale, as the ones the
+// verdicts are based on. The rest of the stats (e.g. the std-devs, maxes and MADs) are those of
+// the original values. The metrics' comments note the transform.
+//
+// As Log is only defined for positive values, and Sqrt for non-negative ones, a metric with
+// any value out of its transform's domain (e.g. a zero latency under Log) is compared on the
+// original scale instead, with its comments noting the transform was skipped, rather than
+// dropping or clamping the values (biasing the comparison) or failing the whole comparison.
+// NaN values are kept as they are.
+func WithTransforms(scheme ContextComparisonScheme) ContextComparisonScheme {
+	return func(ctx context.Context, j *JobComparisonData, matchThreshold, minMetricAvgForCompare float64) {
+		j.withTransforms(func() {
+			scheme(ctx, j, matchThreshold, minMetricAvgForCompare)
+		})
+	}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseTransform(t *testing.T) {
+	for _, transform := range []Transform{TransformNone, TransformLog, TransformSqrt} {
+		if parsed, err := ParseTransform(transform.String()); err != nil || parsed != transform {
+			t.Errorf("Parsed transform %v as %v (%v)", transform, parsed, err)
+		}
+	}
+	if _, err := ParseTransform("exp"); err == nil {
+		t.Errorf("Parsing transform 'exp' succeeded, but expected an error")
+	}
+	annotations := &Annotations{}
+	if err := json.Unmarshal([]byte(`{"annotations": [{"metric": {"verb": "LIST"}, "tier": "P1", "transform": "log"}]}`), annotations); err != nil {
+		t.Fatalf("Parsing the annotations failed: %v", err)
+	}
+	if transform := annotations.Annotations[0].Transform; transform != TransformLog {
+		t.Errorf("Annotation has transform %v, but expected %v", transform, TransformLog)
+	}
+	if err := json.Unmarshal([]byte(`{"annotations": [{"metric": {}, "tier": "P1", "transform": "exp"}]}`), annotations); err == nil {
+		t.Errorf("Parsing annotations with transform 'exp' succeeded, but expected an error")
+	}
+}
+
+func TestWithTransforms(t *testing.T) {
+	logged := MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}
+	rooted := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	nonPositive := MetricKey{TestName: "Load", Verb: "PUT", Resource: "pods", Percentile: "Perc99"}
+	plain := MetricKey{TestName: "Load", Verb: "POST", Resource: "pods", Percentile: "Perc99"}
+	j := NewJobComparisonData()
+	j.Data[logged] = &MetricComparisonData{LeftJobSample: []float64{10, 1000}, RightJobSample: []float64{100, 100}, Transform: TransformLog}
+	j.Data[rooted] = &MetricComparisonData{LeftJobSample: []float64{1, 9}, RightJobSample: []float64{4, 4}, Transform: TransformSqrt}
+	j.Data[nonPositive] = &MetricComparisonData{LeftJobSample: []float64{0, 100}, RightJobSample: []float64{100, 100}, Transform: TransformLog}
+	j.Data[plain] = &MetricComparisonData{LeftJobSample: []float64{10, 1000}, RightJobSample: []float64{100, 100}}
+
+	// The scheme sees the samples transformed, and matches the metrics whose avgs are equal.
+	seen := make(map[MetricKey][]float64)
+	scheme := func(ctx context.Context, j *JobComparisonData, _, _ float64) {
+		j.EnsureStats()
+		for key, metricData := range j.Data {
+			seen[key] = append([]float64{}, metricData.LeftJobSample...)
+			metricData.ResetVerdict()
+			metricData.Matched = math.Abs(metricData.AvgL-metricData.AvgR) < 1e-9
+			metricData.Comments = "compared"
+		}
+	}
+	WithTransforms(scheme)(context.Background(), j, 0, 0)
+
+	if expected := []float64{math.Log(10), math.Log(1000)}; !reflect.DeepEqual(seen[logged], expected) {
+		t.Errorf("Scheme saw the left sample of %v as %v, but expected %v", logged, seen[logged], expected)
+	}
+	// The arithmetic means differ (505 vs 100), but the geometric ones don't.
+	data := j.Data[logged]
+	if !data.Matched || math.Abs(data.AvgL-100) > 1e-9 || math.Abs(data.AvgRatio-1) > 1e-9 {
+		t.Errorf("Metric %v compared on the log scale as %+v, but expected it matched with a geometric left avg of 100", logged, data)
+	}
+	if !reflect.DeepEqual(data.LeftJobSample, []float64{10, 1000}) || data.MaxL != 1000 || data.Comments != "compared\tTransform=log" {
+		t.Errorf("Metric %v wasn't reported on the original scale: %+v", logged, data)
+	}
+	// The stats reported are kept, rather than recomputed as arithmetic.
+	j.EnsureStats()
+	if math.Abs(data.AvgL-100) > 1e-9 {
+		t.Errorf("Metric %v has a left avg of %v once its stats are ensured, but expected 100", logged, data.AvgL)
+	}
+
+	// The mean of the roots is 2 on both sides, i.e. an avg of 4.
+	if data := j.Data[rooted]; !data.Matched || data.AvgL != 4 || data.AvgR != 4 || !reflect.DeepEqual(data.LeftJobSample, []float64{1, 9}) {
+		t.Errorf("Metric %v compared on the sqrt scale as %+v, but expected it matched with avgs of 4", rooted, data)
+	}
+
+	// The zero can't be logged, so the metric is compared as it is.
+	if expected := []float64{0, 100}; !reflect.DeepEqual(seen[nonPositive], expected) {
+		t.Errorf("Scheme saw the left sample of %v as %v, but expected %v", nonPositive, seen[nonPositive], expected)
+	}
+	if data := j.Data[nonPositive]; data.Matched || data.AvgL != 50 || !strings.Contains(data.Comments, "Transform=log skipped for 1 values out of its domain") {
+		t.Errorf("Metric %v with a zero value compared as %+v, but expected it compared untransformed", nonPositive, data)
+	}
+
+	if data := j.Data[plain]; data.Matched || data.AvgL != 505 || data.Comments != "compared" {
+		t.Errorf("Metric %v without a transform compared as %+v, but expected a left avg of 505", plain, data)
+	}
+}
+
+func TestApplyPolicyWithTransforms(t *testing.T) {
+	key := MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}
+	j := NewJobComparisonData()
+	j.Data[key] = &MetricComparisonData{LeftJobSample: []float64{10, 1000}, RightJobSample: []float64{100, 100}, Transform: TransformLog}
+	policy := &Policy{Rules: []PolicyRule{{Metric: MetricPattern{}, Statistic: StatisticMean, Threshold: 0.99}}}
+	if err := j.ApplyPolicy(policy); err != nil {
+		t.Fatalf("Applying the policy failed: %v", err)
+	}
+	if data := j.Data[key]; !data.Matched || !strings.HasSuffix(data.Comments, "\tTransform=log") || math.Abs(data.AvgL-100) > 1e-9 {
+		t.Errorf("Metric %v compared by the policy as %+v, but expected it matched on the log scale", key, data)
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"math"
+	"sort"
+)
+
+// WelchTTest runs a two-sided Welch's t-test of the left and right samples having the same mean
+// (without assuming they've the same variance), returning its p-value. It assumes the means to
+// be about normally distributed, as they are for normal samples. The p-value is NaN if either
+// sample has less than 2 values. Samples without any spread have a p-value of 1 if their means
+// are the same, and 0 otherwise.
+func WelchTTest(left, right []float64) float64 {
+	diff, standardError, df := welchTestStats(left, right)
+	if math.IsNaN(diff) || math.IsNaN(standardError) {
+		return math.NaN()
+	}
+	if standardError == 0 {
+		if diff == 0 {
+			return 1
+		}
+		return 0
+	}
+	return 2 * (1 - StudentTCDF(math.Abs(diff)/standardError, df))
+}
+
+// MannWhitneyUTest runs a two-sided Mann-Whitney U test (aka Wilcoxon rank-sum test) of the
+// left and right samples being from the same distribution, against one of them tending to have
+// larger values, returning its p-value. Being based on the ranks of the values only, it makes no
+// assumption on the distribution (e.g. it suits skewed or heavy-tailed ones), and isn't thrown
+// off by outliers. The p-value is from the normal approximation of the U statistic, with the
+// continuity and tie corrections, and so is rough for very small samples. It's NaN if either
+// sample is empty or has a NaN value, and 1 if all the values are tied.
+func MannWhitneyUTest(left, right []float64) float64 {
+	nL, nR := len(left), len(right)
+	if nL == 0 || nR == 0 || hasNaN(left) || hasNaN(right) {
+		return math.NaN()
+	}
+	type rankedValue struct {
+		value     float64
+		fromRight bool
+	}
+	pooled := make([]rankedValue, 0, nL+nR)
+	for _, value := range left {
+		pooled = append(pooled, rankedValue{value, false})
+	}
+	for _, value := range right {
+		pooled = append(pooled, rankedValue{value, true})
+	}
+	sort.Slice(pooled, func(a, b int) bool { return pooled[a].value < pooled[b].value })
+	// Sum the ranks of the right values, the tied ones getting the average of their ranks.
+	rightRankSum, tieCorrection := 0.0, 0.0
+	for i := 0; i < len(pooled); {
+		k := i
+		for k < len(pooled) && pooled[k].value == pooled[i].value {
+			k++
+		}
+		averageRank := float64(i+k+1) / 2
+		for _, tied := range pooled[i:k] {
+			if tied.fromRight {
+				rightRankSum += averageRank
+			}
+		}
+		ties := float64(k - i)
+		tieCorrection += ties*ties*ties - ties
+		i = k
+	}
+	n := float64(nL + nR)
+	u := rightRankSum - float64(nR*(nR+1))/2
+	mean := float64(nL*nR) / 2
+	variance := float64(nL*nR) / 12 * ((n + 1) - tieCorrection/(n*(n-1)))
+	if !(variance > 0) {
+		return 1
+	}
+	deviation := math.Max(math.Abs(u-mean)-0.5, 0)
+	return math.Min(1, 2*(1-NormalCDF(deviation/math.Sqrt(variance))))
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWelchTTest(t *testing.T) {
+	// As per R's t.test(1:5, c(2, 4, 6, 8, 10)): t = -1.8974, df = 5.882, p-value = 0.1075.
+	if pValue := WelchTTest([]float64{1, 2, 3, 4, 5}, []float64{2, 4, 6, 8, 10}); math.Abs(pValue-0.1075) > 0.0005 {
+		t.Errorf("Welch's t-test p-value is %v, but expected 0.1075", pValue)
+	}
+	if pValue := WelchTTest([]float64{5, 5}, []float64{5, 5}); pValue != 1 {
+		t.Errorf("Same samples without spread got a p-value of %v, but expected 1", pValue)
+	}
+	if pValue := WelchTTest([]float64{5, 5}, []float64{6, 6}); pValue != 0 {
+		t.Errorf("Different samples without spread got a p-value of %v, but expected 0", pValue)
+	}
+	if pValue := WelchTTest([]float64{5}, []float64{6, 7}); !math.IsNaN(pValue) {
+		t.Errorf("Single-value sample got a p-value of %v, but expected NaN", pValue)
+	}
+}
+
+func TestMannWhitneyUTest(t *testing.T) {
+	// As per R's wilcox.test(1:5, 6:10, exact = FALSE): W = 0, p-value = 0.01219.
+	if pValue := MannWhitneyUTest([]float64{1, 2, 3, 4, 5}, []float64{6, 7, 8, 9, 10}); math.Abs(pValue-0.01219) > 0.00005 {
+		t.Errorf("Mann-Whitney U test p-value is %v, but expected 0.01219", pValue)
+	}
+	// The right values' ranks are 3, 7, 7, 10.5 and 10.5, so U = 38-15 = 23 against a mean of 15,
+	// with a tie-corrected variance of 2.5*(12-150/110), giving z = (8-0.5)/5.157 and p = 0.1458.
+	if pValue := MannWhitneyUTest([]float64{1, 2, 2, 3, 3, 3}, []float64{2, 3, 3, 4, 4}); math.Abs(pValue-0.1458) > 0.0005 {
+		t.Errorf("Mann-Whitney U test p-value with ties is %v, but expected 0.1458", pValue)
+	}
+	// It's only about the ranks, so an outlier doesn't change it.
+	if a, b := MannWhitneyUTest([]float64{1, 2, 3}, []float64{4, 5, 6}), MannWhitneyUTest([]float64{1, 2, 3}, []float64{4, 5, 6e9}); a != b {
+		t.Errorf("Mann-Whitney U test p-value changed with an outlier: %v vs %v", a, b)
+	}
+	if pValue := MannWhitneyUTest([]float64{3, 3}, []float64{3}); pValue != 1 {
+		t.Errorf("All-tied samples got a p-value of %v, but expected 1", pValue)
+	}
+	if pValue := MannWhitneyUTest(nil, []float64{3}); !math.IsNaN(pValue) {
+		t.Errorf("Empty sample got a p-value of %v, but expected NaN", pValue)
+	}
+}
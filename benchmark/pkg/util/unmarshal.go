@@ -0,0 +1,29 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"encoding/json"
+)
+
+// Unmarshaler decodes JSON data into v, as json.Unmarshal does (which it defaults to). All the
+// loaders decode the files they read with it: the policies, annotations and reports here, and
+// the metrics files (artifacts) in the scraper (k8s.io/perf-tests/benchmark/pkg/metricsfetcher/scraper).
+// It can be replaced by a faster decoder (like json-iterator's ConfigCompatibleWithStandardLibrary
+// Unmarshal), which has to honor the json struct tags, json.RawMessage and json.Unmarshaler
+// like encoding/json does. It should be set before loading anything, as it isn't synchronized.
+var Unmarshaler func(data []byte, v interface{}) error = json.Unmarshal
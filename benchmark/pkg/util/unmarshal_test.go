@@ -0,0 +1,77 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUnmarshaler(t *testing.T) {
+	defer func(unmarshaler func([]byte, interface{}) error) {
+		Unmarshaler = unmarshaler
+	}(Unmarshaler)
+	dir, err := ioutil.TempDir("", "unmarshaler")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "empty.json")
+	if err := ioutil.WriteFile(path, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A stub decoding every file as the same annotations.
+	var decoded []string
+	Unmarshaler = func(data []byte, v interface{}) error {
+		decoded = append(decoded, string(data))
+		if annotations, ok := v.(*Annotations); ok {
+			annotations.Annotations = []MetricAnnotation{{Tier: TierP1, Owner: "stub"}}
+			return nil
+		}
+		return json.Unmarshal(data, v)
+	}
+	annotations, err := LoadAnnotations(path)
+	if err != nil || len(annotations.Annotations) != 1 || annotations.Annotations[0].Owner != "stub" {
+		t.Errorf("Loaded annotations %+v (error %v), but expected the stub's", annotations, err)
+	}
+	if _, err := ReadJSONReport(strings.NewReader(`[{"testName": "Load", "verb": "GET", "percentile": "Perc99", "matched": true}]`)); err != nil {
+		t.Errorf("Reading the report failed: %v", err)
+	}
+	// The report and its metric record are decoded with it.
+	if len(decoded) != 3 || decoded[0] != "{}" {
+		t.Errorf("Unmarshaler decoded %q, but expected the annotations file and the report", decoded)
+	}
+
+	Unmarshaler = func(data []byte, v interface{}) error {
+		return errors.New("stub")
+	}
+	for name, load := range map[string]func() error{
+		"annotations": func() error { _, err := LoadAnnotations(path); return err },
+		"policy":      func() error { _, err := LoadPolicy(path); return err },
+		"report":      func() error { _, err := LoadJSONReport(path); return err },
+	} {
+		if err := load(); err == nil || !strings.Contains(err.Error(), "stub") {
+			t.Errorf("Loading the %v with a failing unmarshaler returned error %v, but expected the stub's", name, err)
+		}
+	}
+}
@@ -18,11 +18,16 @@ package util
 
 import (
 	"bytes"
+	"encoding/binary"
 	"fmt"
+	"hash/fnv"
 	"math"
+	"path"
 	"sort"
 	"strconv"
+	"strings"
 	"text/tabwriter"
+	"time"
 
 	"k8s.io/kubernetes/test/e2e/perftype"
 
@@ -37,13 +42,20 @@ type MetricKey struct {
 	Subresource string // "status","binding",etc. Empty for pod startup and most API calls
 	Scope       string // Used for API calls: "resource" (for GETs), "namespace"/"cluster" (for LISTs).
 	Percentile  string // The percentile string ("Perc50", "Perc90", etc)
+	Platform    string // Platform the metric was measured on ("linux/arm64", etc), if part of the key (see FlattenOptions)
+	SizeBucket  string // Request size bucket the metric was measured for ("64KiB", etc), if part of the key (see FlattenOptions)
+	Population  string // Sub-population ("tenant-a", etc) the metric was measured for, if part of the key (see FlattenOptions)
 }
 
-// MetricComparisonData holds all the values corresponding to a metric's comparison.
+// MetricComparisonData holds all the values corresponding to a metric's comparison. Its
+// samples hold the values from each of the jobs' runs, unless they were aggregated across
+// the runs while flattening (see FlattenOptions.AggregatorFor).
 type MetricComparisonData struct {
 	LeftJobSample  []float64 // Sample values from the left job's runs
 	RightJobSample []float64 // Sample values from the right job's runs
+	Unit           string    // Unit of the sample values (as reported by the DataItems)
 	Matched        bool      // Boolean indicating if the samples matched
+	Inconclusive   bool      // Boolean indicating if the comparison couldn't reach a verdict (e.g. timed out)
 	Comments       string    // Any comments wrt the matching (for human interpretation)
 
 	// Below are some common statistical measures, that we would compute for the left
@@ -51,12 +63,185 @@ type MetricComparisonData struct {
 	AvgL, AvgR, AvgRatio float64 // Average
 	StDevL, StDevR       float64 // Standard deviation
 	MaxL, MaxR           float64 // Max value
+	MADL, MADR           float64 // Median absolute deviation (scaled to be comparable to std-dev)
+	QnL, QnR             float64 // Rousseeuw-Croux Qn scale estimator (scaled to be comparable to std-dev, see computeQn)
+	ESL, ESR             float64 // Expected shortfall, the mean of the worst values (the lowest for rates, see ExpectedShortfall)
+	MaxRatio             float64 // Ratio of right and left max values (NaN if it can't be computed)
+	ZScoreOfRight        float64 // No. of left job std-devs the right avg is away from the left avg
+	GlassDelta           float64 // Glass's delta effect size, (AvgR-AvgL)/StDevL (NaN if StDevL is 0)
+	CDFArea              float64 // Area between the left and right samples' empirical CDFs
+	SNR                  float64 // Signal-to-noise ratio of the change of avg (see signalToNoise)
+	HarmonicMeanL        float64 // Harmonic mean of the left sample, for rates (0 otherwise)
+	HarmonicMeanR        float64 // Harmonic mean of the right sample, for rates (0 otherwise)
+
+	// PValue is the p-value of the statistical test used by the comparison scheme, for the
+	// schemes based on one (as told by HasPValue). It's NaN if the test couldn't be run.
+	PValue    float64
+	HasPValue bool
+
+	// VarianceRatio is the ratio of the right and left samples' variances, and VariancePValue
+	// the p-value of the variance gaining, as set by CompareVariances (along with whether
+	// VarianceIncreased significantly). They're independent of the verdict on the avgs.
+	VarianceRatio, VariancePValue float64
+	VarianceIncreased             bool
+
+	// RegressionProbability is the confidence, in [0, 1], that the metric regressed (e.g. 0.92
+	// for "92% confident"), as set by ComputeRegressionProbabilities. It's NaN if the test it's
+	// derived from couldn't be run.
+	RegressionProbability float64
+
+	// RelativeChange is the relative change of the avg, (AvgR-AvgL)/AvgL, and RelativeChangeLow
+	// and RelativeChangeHigh the bounds of its confidence interval at the ChangeConfidence level,
+	// as set by RelativeChangeCI (ChangeConfidence being 0 until then).
+	RelativeChange, RelativeChangeLow, RelativeChangeHigh float64
+	ChangeConfidence                                      float64
+
+	// NegativeSampleCount is the number of negative sample values seen while flattening,
+	// which have been kept, dropped or clamped as per the NegativeSamplePolicy used.
+	NegativeSampleCount int
+
+	// LeftJobRequestCounts and RightJobRequestCounts hold the request count (from the Count
+	// label, or 1 if absent) backing each of the sample values, in the same order. They're
+	// only retained if requested while flattening, and used for count-weighted stats.
+	LeftJobRequestCounts, RightJobRequestCounts []float64
+
+	// LeftJobTimestamps and RightJobTimestamps hold the time of the run (zero if unknown) each
+	// of the sample values is from, in the same order. They're only retained if timestamps
+	// were given while flattening (see FlattenOptions), and used for ordering by time.
+	LeftJobTimestamps, RightJobTimestamps []time.Time
+
+	// LeftJobRunIndices and RightJobRunIndices hold the index of the run (among those of its
+	// job, appended runs coming after earlier ones) each of the sample values is from, in the
+	// same order. They're only retained if requested while flattening.
+	LeftJobRunIndices, RightJobRunIndices []int
+
+	// LeftJobNodeMaxes and RightJobNodeMaxes hold the max value across the nodes of each of the
+	// jobs' runs, i.e. the run's worst node, for per-node metrics flattened with a NodeLabel
+	// (see nodes.go for the two levels of aggregation). They're unaffected by AggregatorFor.
+	LeftJobNodeMaxes, RightJobNodeMaxes []float64
+
+	// SustainedRegressionCount is the no. of consecutive comparisons, up to this one, the metric
+	// regressed in, as set by EscalateSustainedRegressions (0 if it didn't regress, or if not set).
+	// Regressions sustained across more than one comparison are a level more severe.
+	SustainedRegressionCount int
+
+	// Tier and Owner of the metric, whether it's a rate (e.g. a throughput, for which the
+	// harmonic mean is computed), and the transform to compare it with (see WithTransforms),
+	// as set by Annotate.
+	Tier      Tier
+	Owner     string
+	IsRate    bool
+	Transform Transform
+
+	hysteresis  hysteresisState // Previous verdict to compare the ratios with hysteresis, if set
+	transformed bool            // Whether the values are transformed, while comparing them (see WithTransforms)
+
+	// Labels is the full label set of one of the DataItems contributing to this
+	// metric. It's only retained if requested while flattening (for debugging).
+	Labels map[string]string
+
+	// statsValid tells if the stats above were computed from the current samples (see EnsureStats),
+	// which had the statsFingerprint.
+	statsValid       bool
+	statsFingerprint uint64
+}
+
+// samplesFingerprint returns a hash of what the metric's stats are computed from.
+func (d *MetricComparisonData) samplesFingerprint() uint64 {
+	hash := fnv.New64a()
+	var buf [8]byte
+	for _, values := range [][]float64{d.LeftJobSample, d.RightJobSample, d.LeftJobRequestCounts, d.RightJobRequestCounts} {
+		binary.LittleEndian.PutUint64(buf[:], uint64(len(values)))
+		hash.Write(buf[:])
+		for _, value := range values {
+			binary.LittleEndian.PutUint64(buf[:], math.Float64bits(value))
+			hash.Write(buf[:])
+		}
+	}
+	if d.IsRate {
+		hash.Write([]byte{1})
+	}
+	return hash.Sum64()
+}
+
+// invalidateStats records that the metric's samples changed, for its stats to be recomputed.
+func (d *MetricComparisonData) invalidateStats() {
+	d.statsValid = false
+}
+
+// ResetVerdict clears the outcome of any previous comparison of the metric (its verdict and
+// p-value), for comparison schemes to start from. Leaving a p-value of a previous test-based
+// scheme around would feed it to the multiple-testing correction (see AdjustPValuesBH).
+func (d *MetricComparisonData) ResetVerdict() {
+	d.Matched = false
+	d.Inconclusive = false
+	d.PValue, d.HasPValue = math.NaN(), false
+	d.hysteresis.held = false
 }
 
+// MarkInconclusive records that the metric's comparison couldn't reach a verdict, for the reason
+// given in the comments. It's marked inconclusive and, so as not to be reported as a regression,
+// matched.
+func (d *MetricComparisonData) MarkInconclusive(comments string) {
+	d.ResetVerdict()
+	d.Matched = true
+	d.Inconclusive = true
+	d.Comments = comments
+}
+
+// MarkTimedOut records that the metric's comparison was stopped (with the given context error)
+// before reaching a verdict, marking it inconclusive. Unlike for other inconclusive metrics, it's
+// left unmatched, so that a bounded comparison which ran out of time fails rather than passes.
+func (d *MetricComparisonData) MarkTimedOut(err error) {
+	d.ResetVerdict()
+	d.Inconclusive = true
+	d.Comments = fmt.Sprintf("Timed out (%v)", err)
+}
+
+// MarkTimedOut marks all the metrics as timed out (see MetricComparisonData.MarkTimedOut).
+func (j *JobComparisonData) MarkTimedOut(err error) {
+	for _, metricData := range j.Data {
+		metricData.MarkTimedOut(err)
+	}
+}
+
+// CannotComputeRatio starts the comments of metrics whose comparison needs a ratio that can't be
+// computed (e.g. due to a zero denominator).
+const CannotComputeRatio = "Cannot compute ratio"
+
 // JobComparisonData is a struct holding a map with keys as the metrics' keys and
 // values as their comparison data.
 type JobComparisonData struct {
 	Data map[MetricKey]*MetricComparisonData
+
+	// WeightByRequestCount makes the avgs and std-devs computed for the metrics' samples
+	// weight each sample value by its request count, giving values backed by more requests
+	// proportionally more influence. It takes effect only for the metrics whose request
+	// counts were retained while flattening (see FlattenOptions.KeepRequestCounts).
+	WeightByRequestCount bool
+
+	// ShortfallFraction is the fraction of the worst values whose mean is the expected shortfall
+	// of the metrics' samples (ESL and ESR). It's DefaultShortfallFraction if not positive.
+	ShortfallFraction float64
+
+	// Baseline holds the recent runs' values of the metrics, for comparing against a moving median.
+	Baseline *Baseline
+
+	flattenOptions                                FlattenOptions   // Options the data was flattened with, reused for appended runs
+	statsDirty                                    bool             // Whether the samples changed after computing the stats
+	statsWeighted                                 bool             // Whether the stats were last computed weighted by request count
+	statsShortfallFraction                        float64          // Fraction the expected shortfalls were last computed at
+	leftRunCount                                  int              // No. of left job runs flattened (or dropped as outside the time window)
+	rightRunCount                                 int              // No. of right job runs flattened (or dropped as outside the time window)
+	leftRunsOutsideWindow, rightRunsOutsideWindow int              // No. of runs dropped as outside the time window
+	dropLog                                       []DropRecord     // Values left out while flattening, if recorded
+	collisions                                    collisionTracker // Metrics having got a value in the run being flattened
+	nodeMaxes                                     nodeMaxTracker   // Metrics' node maxes of the run being flattened
+	warnings                                      []Warning        // Data-quality issues found while flattening
+
+	// Negative values seen for metrics not added (yet), counted in their
+	// NegativeSampleCount once they are.
+	droppedNegatives map[MetricKey]int
 }
 
 // MetricFilterFunc tells if a given MetricKey is to be filtered out.
@@ -104,22 +289,82 @@ func getMetricsSortedByAvgRatio(j *JobComparisonData) metricKeyDataPairList {
 	return metricsList
 }
 
-// PrettyPrintWithFilter prints the job comparison data in a table with columns aligned,
-// after sorting the metrics by their avg ratio and removing entries based on filter.
-func (j *JobComparisonData) PrettyPrintWithFilter(filter MetricFilterFunc) {
-	metricsList := getMetricsSortedByAvgRatio(j)
+// PrettyPrintOptions tunes the table printed by PrettyPrintWithOptions.
+type PrettyPrintOptions struct {
+	// Filter tells which metrics to leave out of the table (none, if nil).
+	Filter MetricFilterFunc
+	// PercentOfBaseline adds columns with the averages and right job's stats as
+	// percents of the left job's average (100% meaning unchanged).
+	PercentOfBaseline bool
+	// Sparklines adds columns with sparklines of the left and right samples.
+	Sparklines bool
+	// EnforcedTier, if set, marks mismatches of metrics of less important tiers as informational.
+	EnforcedTier *Tier
+	// Mask, if set, redacts portions of the metric keys printed (see LabelMask). The filter
+	// still gets the unmasked keys.
+	Mask *LabelMask
+}
+
+// sparklineWidth is the max width of the sparklines in the pretty printed table.
+const sparklineWidth = 10
+
+// informationalMarker prefixes the comments of mismatches that aren't enforced.
+const informationalMarker = "[info] "
+
+func formatPercent(value float64) string {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", value)
+}
+
+func formatRatio(value float64) string {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f", value)
+}
+
+// formatTable renders the job comparison data in a table with columns aligned, headed by the
+// run counts of the jobs (see RunCounts) if any runs were flattened, after sorting the metrics
+// by their avg ratio and removing entries based on filter, followed by a summary line counting
+// the regressions and improvements (of all the metrics).
+func (j *JobComparisonData) formatTable(options PrettyPrintOptions) string {
+	cols := []string{"testName", "verb", "resource", "subresource", "scope", "percentile"}
+	if options.PercentOfBaseline {
+		cols = append(cols, "avgLPercent", "avgRPercent", "stDevRPercent", "maxRPercent")
+	}
+	if options.Sparklines {
+		cols = append(cols, "sparkL", "sparkR")
+	}
+	cols = append(cols, "maxRatio", "comments")
+	format := &tableFormat{formatFloat: formatRatio, enforcedTier: options.EnforcedTier, mask: options.Mask}
+	// The columns are all known, so there's no error.
+	headers, rows, _ := j.table(cols, getMetricsSortedByAvgRatio(j), format, options.Filter)
 	var buf bytes.Buffer
+	if runCounts := j.runCountsHeader(); runCounts != "" {
+		fmt.Fprintf(&buf, "%v\n", runCounts)
+	}
 	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
-	fmt.Fprintf(w, "E2E TEST\tVERB\tRESOURCE\tSUBRESOURCE\tSCOPE\tPERCENTILE\tCOMMENTS\n")
-	for _, metricPair := range metricsList {
-		key, data := metricPair.metricKey, metricPair.metricData
-		if filter(key, *data) {
-			continue
-		}
-		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\t%v\n", key.TestName, key.Verb, key.Resource, key.Subresource, key.Scope, key.Percentile, data.Comments)
+	fmt.Fprintf(w, "%v\n", strings.Join(headers, "\t"))
+	for _, cells := range rows {
+		fmt.Fprintf(w, "%v\n", strings.Join(cells, "\t"))
 	}
 	w.Flush()
-	glog.Infof("\n%v", buf.String())
+	fmt.Fprintf(&buf, "%v\n", j.verdictSummary())
+	return buf.String()
+}
+
+// PrettyPrintWithOptions prints the job comparison data in a table with columns aligned,
+// after sorting the metrics by their avg ratio, as tuned by the given options.
+func (j *JobComparisonData) PrettyPrintWithOptions(options PrettyPrintOptions) {
+	glog.Infof("\n%v", j.formatTable(options))
+}
+
+// PrettyPrintWithFilter prints the job comparison data in a table with columns aligned,
+// after sorting the metrics by their avg ratio and removing entries based on filter.
+func (j *JobComparisonData) PrettyPrintWithFilter(filter MetricFilterFunc) {
+	j.PrettyPrintWithOptions(PrettyPrintOptions{Filter: filter})
 }
 
 // PrettyPrint prints the job comparison data in a table without any filtering.
@@ -127,65 +372,388 @@ func (j *JobComparisonData) PrettyPrint() {
 	j.PrettyPrintWithFilter(func(k MetricKey, d MetricComparisonData) bool { return false })
 }
 
-// Adds a sample value (if not NaN) to a given metric's MetricComparisonData.
-func (j *JobComparisonData) addSampleValue(sample float64, testName, verb, resource, subresource, scope, percentile string, fromLeftJob bool) {
+// NegativeSamplePolicy tells what to do with negative sample values while flattening. These are
+// physically impossible for latencies and indicate a bug in the producer (clock skew, underflow, etc).
+type NegativeSamplePolicy int
+
+// Allowed negative sample policies.
+const (
+	WarnOnNegativeSamples NegativeSamplePolicy = iota // Keep them, but log a warning
+	DropNegativeSamples                               // Discard them
+	ClampNegativeSamples                              // Replace them with 0
+)
+
+// FlattenOptions tunes how the metrics of left and right jobs are flattened into JobComparisonData.
+type FlattenOptions struct {
+	// MinAllowedAPIRequestCount is the minimum request count for an API call latency to be included.
+	MinAllowedAPIRequestCount int
+	// KeepLabels makes each metric retain the labels of one of its contributing DataItems.
+	KeepLabels bool
+	// KeepRequestCounts makes each metric retain the request counts backing its sample values.
+	KeepRequestCounts bool
+	// NegativeSamplePolicy tells how to handle negative sample values.
+	NegativeSamplePolicy NegativeSamplePolicy
+	// CollisionPolicy tells how to handle a metric getting more than one value within a run.
+	CollisionPolicy CollisionPolicy
+	// MinSampleValue, if positive, is the floor below which sample values are dropped.
+	MinSampleValue float64
+	// PercentilePattern, if set, is a shell pattern (as in path.Match) for the percentiles to keep.
+	PercentilePattern string
+	// ValidatePercentiles makes the values of invalid percentiles (like "PercAbc" or "Perc150")
+	// be dropped, rather than compared as any other. It's only for metrics whose data are all
+	// percentiles (i.e. like "Perc99", "P99" or "99").
+	ValidatePercentiles bool
+	// RecordDrops makes the values left out while flattening be recorded in the DropLog.
+	RecordDrops bool
+	// LeftRunTimestamps and RightRunTimestamps give the times the left and right job runs
+	// happened, keyed by the runs' indices in the job metrics being flattened. They don't
+	// apply to appended runs (see AppendRuns), whose indices start over.
+	LeftRunTimestamps, RightRunTimestamps map[int]time.Time
+	// RunTimestamp, if set, returns the time of the left or right job run of the given index
+	// (like the run timestamps, which it takes precedence over), telling if it's known.
+	RunTimestamp func(run int, fromLeftJob bool) (time.Time, bool)
+	// TimeWindow, if set, restricts flattening to the runs whose time (as given by the run
+	// timestamps) is within it, dropping the others (including those of unknown time) before
+	// their values are aggregated into the samples. Like the run timestamps, it doesn't apply
+	// to appended runs.
+	TimeWindow TimeWindow
+	// KeepRunIndices makes each metric retain the indices of the runs its sample values are from.
+	KeepRunIndices bool
+	// MergedSubresources lists subresources to fold into the empty one, e.g. for counting calls
+	// to a resource's "binding" subresource as calls to the resource itself. Their values then
+	// add to the samples of the metric without a subresource.
+	MergedSubresources []string
+	// PlatformLabel, if set, is the DataItem label holding the platform (e.g. architecture) the
+	// item was measured on, which is then made part of the metric keys (as their Platform). The
+	// same metric measured on different platforms is then never compared across them.
+	PlatformLabel string
+	// SizeBucketLabel, if set, is the DataItem label holding the (request body) size bucket the
+	// item was measured for, which is then made part of the metric keys (as their SizeBucket).
+	// Each bucket is then compared on its own, so that a regression of the large requests isn't
+	// watered down by the unchanged small ones. Reports order the buckets by size.
+	SizeBucketLabel string
+	// PopulationLabel, if set, is the DataItem label holding the sub-population (e.g. "Namespace"
+	// or "Tenant") the item was measured for, which is then made part of the metric keys (as their
+	// Population). Each sub-population is then compared on its own, revealing the regressions
+	// isolated to one of them that the aggregate masks.
+	PopulationLabel string
+	// KeepPopulationRollup makes the values of all the sub-populations also be added to the
+	// aggregate metric (the one without a Population), as without a PopulationLabel, so that
+	// both the sub-populations and their rollup are compared. The values of the sub-populations
+	// are pooled into the rollup's samples, rather than colliding within the run.
+	KeepPopulationRollup bool
+	// TimestampLabel, if set, is the DataItem label holding the time of the run the item is
+	// from (see ParseTimestamp). It takes precedence over the run timestamps.
+	TimestampLabel string
+	// AggregatorFor, if set, returns the aggregator (e.g. MedianOfRuns) to reduce each metric's
+	// values across the runs to, if any. Such a metric's samples then hold a single value each,
+	// the aggregate of the job's runs (backed by the sum of their request counts), rather than a
+	// value per run, and they have no timestamps or run indices. Without it, as by default, the
+	// runs' values are all kept as independent sample values (and so implicitly averaged by the
+	// avg-based schemes). Appended runs are aggregated on their own, adding another value.
+	AggregatorFor func(MetricKey) Aggregator
+	// ContextLabel, if set, is the DataItem label holding a numeric context of the run the item
+	// is from (e.g. "Nodes", the cluster size), which each of its values is combined with (see
+	// ContextCombinerFor) before being sampled, e.g. to compare the latency per node of jobs on
+	// clusters of different sizes.
+	ContextLabel string
+	// ContextCombinerFor, if set, returns the combiner of each metric's values with the context,
+	// if any (metrics without one are sampled as they are). Without it, all the values are
+	// divided by the context.
+	ContextCombinerFor func(MetricKey) ContextCombiner
+	// MissingContextPolicy tells how to handle the values whose context label is missing or
+	// isn't a positive number.
+	MissingContextPolicy MissingContextPolicy
+	// NodeLabel, if set, is the DataItem label holding the node (or pod) a per-node metric's
+	// item was measured on. The values of all the nodes are then added to the samples (rather
+	// than colliding within the run), and each run's max across its nodes is kept in the
+	// metrics' node maxes, as compared by CompareMaxOverNodes.
+	NodeLabel string
+}
+
+// Adds a sample value (if not NaN or filtered out) to a given metric's MetricComparisonData.
+// Negative values are handled as per the options' NegativeSamplePolicy, and those kept (or
+// clamped) are then subject to the MinSampleValue floor like any other. A value the metric
+// already got one of in the run is handled as per the CollisionPolicy. Like with NaNs, a
+// metric isn't added if all its values get dropped.
+func (j *JobComparisonData) addSampleValue(sample float64, metricKey MetricKey, latency DataItemLike, run sampleRun, fromLeftJob bool, options *FlattenOptions) {
 	if math.IsNaN(sample) {
+		j.recordDrop(options, metricKey, fromLeftJob, DropNaN, "value is NaN")
+		j.warn(WarningNaNSample, metricKey, "dropped a NaN value of the %v job", jobSide(fromLeftJob))
+		return
+	}
+	if options.PercentilePattern != "" {
+		if matched, _ := path.Match(options.PercentilePattern, metricKey.Percentile); !matched {
+			j.recordDrop(options, metricKey, fromLeftJob, DropPercentileMismatch, fmt.Sprintf("percentile doesn't match '%v'", options.PercentilePattern))
+			return
+		}
+	}
+	if options.ValidatePercentiles {
+		if _, err := parsePercentileName(metricKey.Percentile); err != nil {
+			j.recordDrop(options, metricKey, fromLeftJob, DropBadPercentile, err.Error())
+			return
+		}
+	}
+	negative := sample < 0
+	if negative {
+		if metricData, ok := j.Data[metricKey]; ok {
+			metricData.NegativeSampleCount++
+		} else {
+			if j.droppedNegatives == nil {
+				j.droppedNegatives = make(map[MetricKey]int)
+			}
+			j.droppedNegatives[metricKey]++
+		}
+		switch options.NegativeSamplePolicy {
+		case DropNegativeSamples:
+			j.recordDrop(options, metricKey, fromLeftJob, DropNegative, fmt.Sprintf("value %v is negative", sample))
+			return
+		case ClampNegativeSamples:
+			sample = 0
+		}
+	}
+	if options.MinSampleValue > 0 && sample < options.MinSampleValue {
+		j.recordDrop(options, metricKey, fromLeftJob, DropBelowFloor, fmt.Sprintf("value %v below floor %v", sample, options.MinSampleValue))
+		return
+	}
+	if negative && options.NegativeSamplePolicy == WarnOnNegativeSamples {
+		glog.Warningf("Negative sample value %v for metric %v (keeping it)", sample, metricKey)
+		j.warn(WarningNegativeSample, metricKey, "kept a negative value %v of the %v job", sample, jobSide(fromLeftJob))
+	}
+	if !j.resolveCollision(sample, metricKey, latency, run, fromLeftJob, options) {
 		return
 	}
 	// Check if the metric exists in the map already, and add it if necessary.
-	metricKey := MetricKey{testName, verb, resource, subresource, scope, percentile}
 	if _, ok := j.Data[metricKey]; !ok {
-		j.Data[metricKey] = &MetricComparisonData{}
+		j.Data[metricKey] = &MetricComparisonData{Unit: latency.GetUnit(), NegativeSampleCount: j.droppedNegatives[metricKey]}
+		delete(j.droppedNegatives, metricKey)
+		if options.KeepLabels {
+			j.Data[metricKey].Labels = copyLabels(latency.GetLabels())
+		}
 	}
 	// Add the sample to the metric's comparison data.
+	metricData := j.Data[metricKey]
+	metricData.invalidateStats()
 	if fromLeftJob {
-		j.Data[metricKey].LeftJobSample = append(j.Data[metricKey].LeftJobSample, sample)
+		metricData.LeftJobSample = append(metricData.LeftJobSample, sample)
 	} else {
-		j.Data[metricKey].RightJobSample = append(j.Data[metricKey].RightJobSample, sample)
+		metricData.RightJobSample = append(metricData.RightJobSample, sample)
+	}
+	if options.KeepRequestCounts {
+		if fromLeftJob {
+			metricData.LeftJobRequestCounts = append(metricData.LeftJobRequestCounts, requestCount(latency))
+		} else {
+			metricData.RightJobRequestCounts = append(metricData.RightJobRequestCounts, requestCount(latency))
+		}
+	}
+	if options.keepTimestamps() {
+		if fromLeftJob {
+			metricData.LeftJobTimestamps = append(metricData.LeftJobTimestamps, run.timestamp)
+		} else {
+			metricData.RightJobTimestamps = append(metricData.RightJobTimestamps, run.timestamp)
+		}
+	}
+	if options.KeepRunIndices {
+		if fromLeftJob {
+			metricData.LeftJobRunIndices = append(metricData.LeftJobRunIndices, run.index)
+		} else {
+			metricData.RightJobRunIndices = append(metricData.RightJobRunIndices, run.index)
+		}
+	}
+	if options.fromNode(latency) {
+		j.trackNodeMax(metricData, sample, metricKey, run, fromLeftJob)
 	}
 }
 
-func (j *JobComparisonData) addLatencyValue(latency *perftype.DataItem, minAllowedRequestCount int, testName string, fromLeftJob bool) {
-	if latency.Labels["Count"] != "" {
-		if count, err := strconv.Atoi(latency.Labels["Count"]); err != nil || count < minAllowedRequestCount {
-			return
+// sampleRun identifies the run a sample value is from.
+type sampleRun struct {
+	index     int       // Index of the run among those of its job
+	timestamp time.Time // Time of the run (zero if unknown)
+}
+
+// mergesSubresource tells if the options fold the given subresource into the empty one.
+func (o *FlattenOptions) mergesSubresource(subresource string) bool {
+	for _, merged := range o.MergedSubresources {
+		if subresource == merged {
+			return true
 		}
 	}
-	verb := latency.Labels["Verb"]
-	resource := latency.Labels["Resource"]
-	subresource := latency.Labels["Subresource"]
-	scope := latency.Labels["Scope"]
-	if latency.Labels["Metric"] == "pod_startup" {
+	return false
+}
+
+// requestCount returns the request count from the DataItem's Count label (1 if absent or invalid).
+func requestCount(latency DataItemLike) float64 {
+	count, err := strconv.Atoi(latency.GetLabels()["Count"])
+	if err != nil || count <= 0 {
+		return 1
+	}
+	return float64(count)
+}
+
+func copyLabels(labels map[string]string) map[string]string {
+	labelsCopy := make(map[string]string, len(labels))
+	for name, value := range labels {
+		labelsCopy[name] = value
+	}
+	return labelsCopy
+}
+
+func (j *JobComparisonData) addLatencyValue(latency DataItemLike, testName string, run sampleRun, fromLeftJob bool, options *FlattenOptions) {
+	labels := latency.GetLabels()
+	run.timestamp = options.itemTimestamp(labels, run.timestamp)
+	verb := labels["Verb"]
+	resource := labels["Resource"]
+	subresource := labels["Subresource"]
+	if options.mergesSubresource(subresource) {
+		subresource = ""
+	}
+	scope := labels["Scope"]
+	platform := ""
+	if options.PlatformLabel != "" {
+		platform = labels[options.PlatformLabel]
+	}
+	sizeBucket := ""
+	if options.SizeBucketLabel != "" {
+		sizeBucket = labels[options.SizeBucketLabel]
+	}
+	population := ""
+	if options.PopulationLabel != "" {
+		population = labels[options.PopulationLabel]
+	}
+	if labels["Metric"] == "pod_startup" {
 		verb = "Pod-Startup"
 	}
-	for percentile, value := range latency.Data {
-		j.addSampleValue(value, testName, verb, resource, subresource, scope, percentile, fromLeftJob)
+	if labels["Count"] != "" {
+		if count, err := strconv.Atoi(labels["Count"]); err != nil || count < options.MinAllowedAPIRequestCount {
+			itemKey := MetricKey{testName, verb, resource, subresource, scope, "", platform, sizeBucket, population}
+			j.recordDrop(options, itemKey, fromLeftJob, DropLowCount, fmt.Sprintf("request count '%v' below %v", labels["Count"], options.MinAllowedAPIRequestCount))
+			if err != nil {
+				j.warn(WarningBadRequestCount, itemKey, "dropped the values of the %v job, as their request count '%v' can't be parsed", jobSide(fromLeftJob), labels["Count"])
+			}
+			return
+		}
+	}
+	for percentile, value := range latency.GetData() {
+		metricKey := MetricKey{testName, verb, resource, subresource, scope, percentile, platform, sizeBucket, population}
+		value, ok := j.combineWithContext(value, metricKey, labels, fromLeftJob, options)
+		if !ok {
+			continue
+		}
+		j.addSampleValue(value, metricKey, latency, run, fromLeftJob, options)
+		if options.rollsUpPopulation(metricKey) {
+			metricKey.Population = ""
+			j.addSampleValue(value, metricKey, latency, run, fromLeftJob, options)
+		}
 	}
 }
 
 // GetFlattennedComparisonData flattens latencies from various runs of left & right jobs into JobComparisonData.
 // In the process, it also discards those metric samples with request count less than minAllowedAPIRequestCount.
 func GetFlattennedComparisonData(leftJobMetrics, rightJobMetrics []map[string][]perftype.PerfData, minAllowedAPIRequestCount int) *JobComparisonData {
+	return GetFlattennedComparisonDataWithOptions(leftJobMetrics, rightJobMetrics, FlattenOptions{MinAllowedAPIRequestCount: minAllowedAPIRequestCount})
+}
+
+// GetFlattennedComparisonDataWithOptions is like GetFlattennedComparisonData, but lets the caller tune flattening.
+func GetFlattennedComparisonDataWithOptions(leftJobMetrics, rightJobMetrics []map[string][]perftype.PerfData, options FlattenOptions) *JobComparisonData {
 	j := NewJobComparisonData()
-	for _, singleRunMetrics := range leftJobMetrics {
+	j.flattenOptions = options
+	j.addRuns(leftJobMetrics, true, &options)
+	j.addRuns(rightJobMetrics, false, &options)
+	j.aggregateRuns(&options, nil)
+	return j
+}
+
+func (j *JobComparisonData) addRuns(jobMetrics []map[string][]perftype.PerfData, fromLeftJob bool, options *FlattenOptions) {
+	for i, singleRunMetrics := range jobMetrics {
+		run := j.newSampleRun(i, fromLeftJob, options)
+		if !options.TimeWindow.includes(run) {
+			j.dropRunOutsideWindow(fromLeftJob)
+			continue
+		}
 		for testName, latenciesArray := range singleRunMetrics {
 			for _, latencies := range latenciesArray {
 				for _, latency := range latencies.DataItems {
-					j.addLatencyValue(&latency, minAllowedAPIRequestCount, testName, true)
+					j.addLatencyValue(PerfTypeDataItem{&latency}, testName, run, fromLeftJob, options)
 				}
 			}
 		}
 	}
-	for _, singleRunMetrics := range rightJobMetrics {
-		for testName, latenciesArray := range singleRunMetrics {
-			for _, latencies := range latenciesArray {
-				for _, latency := range latencies.DataItems {
-					j.addLatencyValue(&latency, minAllowedAPIRequestCount, testName, false)
-				}
+	j.countRuns(len(jobMetrics), fromLeftJob)
+	j.forgetRunMetrics()
+	j.forgetRunNodes()
+}
+
+// newSampleRun returns the run of the given index among those being flattened (which
+// come after any flattened earlier).
+func (j *JobComparisonData) newSampleRun(i int, fromLeftJob bool, options *FlattenOptions) sampleRun {
+	run := sampleRun{index: j.rightRunCount + i, timestamp: options.runTimestamp(i, fromLeftJob)}
+	if fromLeftJob {
+		run.index = j.leftRunCount + i
+	}
+	return run
+}
+
+func (j *JobComparisonData) countRuns(runs int, fromLeftJob bool) {
+	if fromLeftJob {
+		j.leftRunCount += runs
+	} else {
+		j.rightRunCount += runs
+	}
+}
+
+// GetFlattennedComparisonDataFromItems is like GetFlattennedComparisonDataWithOptions, but flattens
+// runs' metrics given as any data item type implementing DataItemLike (keyed by test name).
+func GetFlattennedComparisonDataFromItems(leftJobMetrics, rightJobMetrics []map[string][]DataItemLike, options FlattenOptions) *JobComparisonData {
+	j := NewJobComparisonData()
+	j.flattenOptions = options
+	j.addItemRuns(leftJobMetrics, true, &options)
+	j.addItemRuns(rightJobMetrics, false, &options)
+	j.aggregateRuns(&options, nil)
+	return j
+}
+
+func (j *JobComparisonData) addItemRuns(jobMetrics []map[string][]DataItemLike, fromLeftJob bool, options *FlattenOptions) {
+	for i, singleRunMetrics := range jobMetrics {
+		run := j.newSampleRun(i, fromLeftJob, options)
+		if !options.TimeWindow.includes(run) {
+			j.dropRunOutsideWindow(fromLeftJob)
+			continue
+		}
+		for testName, latencies := range singleRunMetrics {
+			for _, latency := range latencies {
+				j.addLatencyValue(latency, testName, run, fromLeftJob, options)
 			}
 		}
 	}
-	return j
+	j.countRuns(len(jobMetrics), fromLeftJob)
+	j.forgetRunMetrics()
+	j.forgetRunNodes()
+}
+
+// AppendRuns flattens the latencies from additional runs of left & right jobs into the
+// existing JobComparisonData, appending them to the metrics' samples as if they had been
+// flattened along with the earlier runs (i.e. with the same FlattenOptions, apart from the
+// given min request count). As the stats computed earlier (if any) are now outdated, they
+// are marked dirty until ComputeStatsForMetricSamples is called again.
+func (j *JobComparisonData) AppendRuns(leftJobMetrics, rightJobMetrics []map[string][]perftype.PerfData, minAllowedAPIRequestCount int) {
+	options := j.flattenOptions
+	options.MinAllowedAPIRequestCount = minAllowedAPIRequestCount
+	if options.keepTimestamps() {
+		// Still retain (unknown) timestamps for the appended runs, to keep them aligned with the samples.
+		options.LeftRunTimestamps, options.RightRunTimestamps = map[int]time.Time{}, map[int]time.Time{}
+	}
+	options.RunTimestamp, options.TimeWindow = nil, TimeWindow{}
+	previousLengths := j.sampleLengths()
+	j.addRuns(leftJobMetrics, true, &options)
+	j.addRuns(rightJobMetrics, false, &options)
+	j.aggregateRuns(&options, previousLengths)
+	j.statsDirty = true
+}
+
+// StatsDirty tells if the samples have changed (e.g. runs have been appended) since the stats
+// were last computed.
+func (j *JobComparisonData) StatsDirty() bool {
+	return j.statsDirty
 }
 
 func computeSampleStats(sample []float64, avg, stDev, max *float64) {
@@ -198,6 +766,8 @@ func computeSampleStats(sample []float64, avg, stDev, max *float64) {
 	}
 	sum := 0.0
 	squareSum := 0.0
+	// Don't start from the earlier max, as the stats may be getting recomputed.
+	*max = math.Inf(-1)
 	for i := 0; i < len; i++ {
 		sum += sample[i]
 		squareSum += sample[i] * sample[i]
@@ -207,10 +777,141 @@ func computeSampleStats(sample []float64, avg, stDev, max *float64) {
 	*stDev = math.Sqrt(squareSum/float64(len) - (*avg * *avg))
 }
 
-// ComputeStatsForMetricSamples computes avg, std-dev and max for each metric's left and right samples.
+// computeWeightedSampleStats is like computeSampleStats, but with the avg and std-dev weighted
+// by the given weights. It falls back to unweighted stats if the weights don't match the sample.
+func computeWeightedSampleStats(sample, weights []float64, avg, stDev, max *float64) {
+	computeSampleStats(sample, avg, stDev, max)
+	if len(sample) == 0 || len(weights) != len(sample) {
+		return
+	}
+	weightSum := 0.0
+	weightedSum := 0.0
+	for i := range sample {
+		weightSum += weights[i]
+		weightedSum += weights[i] * sample[i]
+	}
+	*avg = weightedSum / weightSum
+	weightedSquareSum := 0.0
+	for i := range sample {
+		weightedSquareSum += weights[i] * (sample[i] - *avg) * (sample[i] - *avg)
+	}
+	*stDev = math.Sqrt(weightedSquareSum / weightSum)
+}
+
+func scaleSample(sample []float64, factor float64) []float64 {
+	if sample == nil {
+		return nil
+	}
+	scaled := make([]float64, len(sample))
+	for i, value := range sample {
+		scaled[i] = value * factor
+	}
+	return scaled
+}
+
+// PercentOfBaseline returns a copy of the job comparison data where each metric's samples and
+// stats are expressed as percents of its left job average (so AvgL is 100 and an unchanged AvgR
+// is 100 as well). Metrics with a zero or undefined left average get NaN values instead.
+// The rest of the data (e.g. the run counts) is kept, but for the Baseline, whose values
+// aren't normalized. The stats should have been computed already.
+func (j *JobComparisonData) PercentOfBaseline() *JobComparisonData {
+	normalized := *j
+	normalized.Data = make(map[MetricKey]*MetricComparisonData, len(j.Data))
+	normalized.Baseline = nil
+	for metricKey, metricData := range j.Data {
+		factor, _ := SafeDiv(100, metricData.AvgL)
+		normalizedData := *metricData
+		normalizedData.Unit = "%"
+		normalizedData.LeftJobSample = scaleSample(metricData.LeftJobSample, factor)
+		normalizedData.RightJobSample = scaleSample(metricData.RightJobSample, factor)
+		normalizedData.AvgL, normalizedData.AvgR = metricData.AvgL*factor, metricData.AvgR*factor
+		normalizedData.StDevL, normalizedData.StDevR = metricData.StDevL*factor, metricData.StDevR*factor
+		normalizedData.MaxL, normalizedData.MaxR = metricData.MaxL*factor, metricData.MaxR*factor
+		normalizedData.MADL, normalizedData.MADR = metricData.MADL*factor, metricData.MADR*factor
+		normalizedData.QnL, normalizedData.QnR = metricData.QnL*factor, metricData.QnR*factor
+		normalizedData.ESL, normalizedData.ESR = metricData.ESL*factor, metricData.ESR*factor
+		normalizedData.CDFArea = metricData.CDFArea * factor
+		normalizedData.LeftJobNodeMaxes = scaleSample(metricData.LeftJobNodeMaxes, factor)
+		normalizedData.RightJobNodeMaxes = scaleSample(metricData.RightJobNodeMaxes, factor)
+		normalized.Data[metricKey] = &normalizedData
+	}
+	return &normalized
+}
+
+// zScore returns how many std-devs the value is away from the mean. It's NaN if that can't
+// be computed, e.g. if the std-dev is 0.
+func zScore(value, mean, stDev float64) float64 {
+	z, _ := SafeDiv(value-mean, stDev)
+	return z
+}
+
+// signalToNoise returns how much the change of avg stands out against the noise of both
+// samples, i.e. |avgR-avgL| / sqrt(stDevL^2 + stDevR^2). Note that without any noise (e.g. for
+// single-run samples) it's +Inf if the avg changed, and NaN if it didn't (or can't be computed).
+func signalToNoise(avgL, avgR, stDevL, stDevR float64) float64 {
+	signal, noise := math.Abs(avgR-avgL), math.Sqrt(stDevL*stDevL+stDevR*stDevR)
+	if noise == 0 && signal > 0 {
+		return math.Inf(1)
+	}
+	snr, _ := SafeDiv(signal, noise)
+	return snr
+}
+
+// ComputeStatsForMetricSamples computes avg, std-dev and max for each metric's left and right samples,
+// along with the ratio of maxes, the z-score of the right avg w.r.t the left sample (and Glass's delta),
+// the signal-to-noise ratio of the change of avg, the area between their CDFs and their expected
+// shortfalls. For rates, it also computes the harmonic means of the samples. It recomputes them
+// all, even if up to date (see EnsureStats).
 func (j *JobComparisonData) ComputeStatsForMetricSamples() {
 	for _, metricData := range j.Data {
+		j.computeMetricStats(metricData)
+	}
+	j.statsDirty = false
+	j.statsWeighted, j.statsShortfallFraction = j.WeightByRequestCount, j.shortfallFraction()
+}
+
+// EnsureStats is like ComputeStatsForMetricSamples, but computes the stats lazily: only those
+// of the metrics whose samples changed since, or which were never computed, are. Comparison
+// schemes should call it rather than blindly recomputing the stats. The metrics are marked
+// dirty as they get values while flattening or appending runs, are rebased, winsorized, etc.
+// Samples changed directly (rather than by the methods of JobComparisonData) are noticed too,
+// by their fingerprint, which is cheap to check compared to sorting them for the MADs and CDF
+// areas. Changing the WeightByRequestCount or the ShortfallFraction makes all the stats be recomputed.
+func (j *JobComparisonData) EnsureStats() {
+	recomputeAll := j.statsWeighted != j.WeightByRequestCount || j.statsShortfallFraction != j.shortfallFraction()
+	for _, metricData := range j.Data {
+		if recomputeAll || !metricData.statsValid || metricData.statsFingerprint != metricData.samplesFingerprint() {
+			j.computeMetricStats(metricData)
+		}
+	}
+	j.statsDirty = false
+	j.statsWeighted, j.statsShortfallFraction = j.WeightByRequestCount, j.shortfallFraction()
+}
+
+// computeMetricStats computes the stats of the metric (see ComputeStatsForMetricSamples).
+func (j *JobComparisonData) computeMetricStats(metricData *MetricComparisonData) {
+	if j.WeightByRequestCount {
+		computeWeightedSampleStats(metricData.LeftJobSample, metricData.LeftJobRequestCounts, &metricData.AvgL, &metricData.StDevL, &metricData.MaxL)
+		computeWeightedSampleStats(metricData.RightJobSample, metricData.RightJobRequestCounts, &metricData.AvgR, &metricData.StDevR, &metricData.MaxR)
+	} else {
 		computeSampleStats(metricData.LeftJobSample, &metricData.AvgL, &metricData.StDevL, &metricData.MaxL)
 		computeSampleStats(metricData.RightJobSample, &metricData.AvgR, &metricData.StDevR, &metricData.MaxR)
 	}
+	metricData.MaxRatio, _ = SafeDiv(metricData.MaxR, metricData.MaxL)
+	metricData.MADL, metricData.MADR = MAD(metricData.LeftJobSample), MAD(metricData.RightJobSample)
+	metricData.QnL, metricData.QnR = computeQn(metricData.LeftJobSample), computeQn(metricData.RightJobSample)
+	if metricData.IsRate {
+		metricData.ESL, metricData.ESR = lowerExpectedShortfall(metricData.LeftJobSample, j.shortfallFraction()), lowerExpectedShortfall(metricData.RightJobSample, j.shortfallFraction())
+	} else {
+		metricData.ESL, metricData.ESR = ExpectedShortfall(metricData.LeftJobSample, j.shortfallFraction()), ExpectedShortfall(metricData.RightJobSample, j.shortfallFraction())
+	}
+	metricData.ZScoreOfRight = zScore(metricData.AvgR, metricData.AvgL, metricData.StDevL)
+	metricData.GlassDelta, _ = SafeDiv(metricData.AvgR-metricData.AvgL, metricData.StDevL)
+	metricData.SNR = signalToNoise(metricData.AvgL, metricData.AvgR, metricData.StDevL, metricData.StDevR)
+	metricData.HarmonicMeanL, metricData.HarmonicMeanR = 0, 0
+	if metricData.IsRate {
+		metricData.HarmonicMeanL, metricData.HarmonicMeanR = HarmonicMean(metricData.LeftJobSample), HarmonicMean(metricData.RightJobSample)
+	}
+	metricData.CDFArea = CDFArea(metricData.LeftJobSample, metricData.RightJobSample)
+	metricData.statsValid, metricData.statsFingerprint = true, metricData.samplesFingerprint()
 }
@@ -17,8 +17,11 @@ limitations under the License.
 package util
 
 import (
+	"bytes"
+	"fmt"
 	"math"
 	"reflect"
+	"strings"
 	"testing"
 
 	"k8s.io/kubernetes/test/e2e/perftype"
@@ -353,6 +356,7 @@ func TestGetFlattennedComparisonData(t *testing.T) {
 				Scope:      "cluster",
 				Percentile: "Perc50",
 			}: {
+				Unit:           "ms",
 				LeftJobSample:  []float64{434506, 385699},
 				RightJobSample: []float64{540908, 587656},
 			},
@@ -363,6 +367,7 @@ func TestGetFlattennedComparisonData(t *testing.T) {
 				Scope:      "cluster",
 				Percentile: "Perc90",
 			}: {
+				Unit:           "ms",
 				LeftJobSample:  []float64{17499, 181956},
 				RightJobSample: []float64{130667, 899073},
 			},
@@ -373,6 +378,7 @@ func TestGetFlattennedComparisonData(t *testing.T) {
 				Scope:      "cluster",
 				Percentile: "Perc99",
 			}: {
+				Unit:           "ms",
 				LeftJobSample:  []float64{360726, 564837},
 				RightJobSample: []float64{898554, 29665},
 			},
@@ -384,6 +390,7 @@ func TestGetFlattennedComparisonData(t *testing.T) {
 				Scope:       "namespace",
 				Percentile:  "Perc50",
 			}: {
+				Unit:           "ms",
 				LeftJobSample:  []float64{708401},
 				RightJobSample: nil,
 			},
@@ -395,6 +402,7 @@ func TestGetFlattennedComparisonData(t *testing.T) {
 				Scope:       "namespace",
 				Percentile:  "Perc90",
 			}: {
+				Unit:           "ms",
 				LeftJobSample:  []float64{99265},
 				RightJobSample: nil,
 			},
@@ -406,6 +414,7 @@ func TestGetFlattennedComparisonData(t *testing.T) {
 				Scope:       "namespace",
 				Percentile:  "Perc99",
 			}: {
+				Unit:           "ms",
 				LeftJobSample:  []float64{889297},
 				RightJobSample: nil,
 			},
@@ -416,6 +425,7 @@ func TestGetFlattennedComparisonData(t *testing.T) {
 				Scope:      "namespace",
 				Percentile: "Perc50",
 			}: {
+				Unit:           "ms",
 				LeftJobSample:  []float64{560427},
 				RightJobSample: []float64{370847},
 			},
@@ -426,6 +436,7 @@ func TestGetFlattennedComparisonData(t *testing.T) {
 				Scope:      "namespace",
 				Percentile: "Perc90",
 			}: {
+				Unit:           "ms",
 				LeftJobSample:  []float64{735918},
 				RightJobSample: []float64{843692},
 			},
@@ -436,6 +447,7 @@ func TestGetFlattennedComparisonData(t *testing.T) {
 				Scope:      "namespace",
 				Percentile: "Perc99",
 			}: {
+				Unit:           "ms",
 				LeftJobSample:  []float64{725196},
 				RightJobSample: []float64{763390},
 			},
@@ -445,6 +457,7 @@ func TestGetFlattennedComparisonData(t *testing.T) {
 				Resource:   "",
 				Percentile: "Perc50",
 			}: {
+				Unit:           "ms",
 				LeftJobSample:  []float64{110369, 692132},
 				RightJobSample: []float64{975403, 270962},
 			},
@@ -454,6 +467,7 @@ func TestGetFlattennedComparisonData(t *testing.T) {
 				Resource:   "",
 				Percentile: "Perc90",
 			}: {
+				Unit:           "ms",
 				LeftJobSample:  []float64{918387, 697577},
 				RightJobSample: []float64{286765, 588448},
 			},
@@ -463,6 +477,7 @@ func TestGetFlattennedComparisonData(t *testing.T) {
 				Resource:   "",
 				Percentile: "Perc99",
 			}: {
+				Unit:           "ms",
 				LeftJobSample:  []float64{602585, 944434},
 				RightJobSample: []float64{137867, 549149},
 			},
@@ -472,6 +487,7 @@ func TestGetFlattennedComparisonData(t *testing.T) {
 				Resource:   "",
 				Percentile: "Perc100",
 			}: {
+				Unit:           "ms",
 				LeftJobSample:  []float64{843511, 32134},
 				RightJobSample: []float64{905950, 811366},
 			},
@@ -481,6 +497,7 @@ func TestGetFlattennedComparisonData(t *testing.T) {
 				Resource:   "",
 				Percentile: "Perc50",
 			}: {
+				Unit:           "ms",
 				LeftJobSample:  []float64{110369, 855293},
 				RightJobSample: []float64{247128, 774048},
 			},
@@ -490,6 +507,7 @@ func TestGetFlattennedComparisonData(t *testing.T) {
 				Resource:   "",
 				Percentile: "Perc90",
 			}: {
+				Unit:           "ms",
 				LeftJobSample:  []float64{918387, 647678},
 				RightJobSample: []float64{463653, 810676},
 			},
@@ -499,6 +517,7 @@ func TestGetFlattennedComparisonData(t *testing.T) {
 				Resource:   "",
 				Percentile: "Perc99",
 			}: {
+				Unit:           "ms",
 				LeftJobSample:  []float64{602585, 886836},
 				RightJobSample: []float64{180198, 532709},
 			},
@@ -508,12 +527,15 @@ func TestGetFlattennedComparisonData(t *testing.T) {
 				Resource:   "",
 				Percentile: "Perc100",
 			}: {
+				Unit:           "ms",
 				LeftJobSample:  []float64{843511, 668049},
 				RightJobSample: []float64{164989, 200269},
 			},
 		},
 	}
 
+	expectedJobComparisonData.flattenOptions = FlattenOptions{MinAllowedAPIRequestCount: 10}
+	expectedJobComparisonData.leftRunCount, expectedJobComparisonData.rightRunCount = 2, 2
 	if !reflect.DeepEqual(*jobComparisonData, *expectedJobComparisonData) {
 		t.Errorf("Flattenned comparison data mismatched from what was expected:\nReal: %v\nExpected: %v", *jobComparisonData, *expectedJobComparisonData)
 	}
@@ -546,4 +568,383 @@ func TestComputeStatsForMetricSamples(t *testing.T) {
 	if jobComparisonData.Data[metricKey].MaxL != 5.0 {
 		t.Errorf("Max computed as %v, but expected 5.0", jobComparisonData.Data[metricKey].MaxL)
 	}
+	if math.Abs(jobComparisonData.Data[metricKey].MADL-1.4826) > 0.00001 || !math.IsNaN(jobComparisonData.Data[metricKey].MADR) {
+		t.Errorf("MADs computed as %v and %v, but expected 1.4826 and NaN", jobComparisonData.Data[metricKey].MADL, jobComparisonData.Data[metricKey].MADR)
+	}
+	if !math.IsNaN(jobComparisonData.Data[metricKey].ZScoreOfRight) {
+		t.Errorf("Z-score of right avg not NaN when right array is empty")
+	}
+
+	// Check the z-score of the right avg, including when left sample has no spread.
+	jobComparisonData.Data[metricKey].RightJobSample = []float64{6.0, 6.0}
+	jobComparisonData.ComputeStatsForMetricSamples()
+	if math.Abs(jobComparisonData.Data[metricKey].ZScoreOfRight-2.12132) > 0.00001 {
+		t.Errorf("Z-score of right avg computed as %v, but expected 2.12132", jobComparisonData.Data[metricKey].ZScoreOfRight)
+	}
+	if math.Abs(jobComparisonData.Data[metricKey].GlassDelta-2.12132) > 0.00001 {
+		t.Errorf("Glass's delta computed as %v, but expected 2.12132", jobComparisonData.Data[metricKey].GlassDelta)
+	}
+	jobComparisonData.Data[metricKey].LeftJobSample = []float64{2.0, 2.0}
+	jobComparisonData.ComputeStatsForMetricSamples()
+	if !math.IsNaN(jobComparisonData.Data[metricKey].ZScoreOfRight) {
+		t.Errorf("Z-score of right avg computed as %v, but expected NaN for left sample without spread", jobComparisonData.Data[metricKey].ZScoreOfRight)
+	}
+	if !math.IsNaN(jobComparisonData.Data[metricKey].GlassDelta) {
+		t.Errorf("Glass's delta computed as %v, but expected NaN for left sample without spread", jobComparisonData.Data[metricKey].GlassDelta)
+	}
+}
+
+func TestEnsureStats(t *testing.T) {
+	metricKey := MetricKey{TestName: "xyz", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	runMetrics := func(latency float64) map[string][]perftype.PerfData {
+		return map[string][]perftype.PerfData{
+			"xyz": {{Version: "v1", DataItems: []perftype.DataItem{{
+				Data:   map[string]float64{"Perc99": latency},
+				Labels: map[string]string{"Count": "10", "Resource": "pods", "Verb": "GET"},
+			}}}},
+		}
+	}
+	j := GetFlattennedComparisonDataWithOptions([]map[string][]perftype.PerfData{runMetrics(10)}, []map[string][]perftype.PerfData{runMetrics(20)}, FlattenOptions{KeepRequestCounts: true})
+	j.EnsureStats()
+	metricData := j.Data[metricKey]
+	if metricData.AvgL != 10 || metricData.AvgR != 20 || j.StatsDirty() {
+		t.Fatalf("Stats computed as AvgL=%v, AvgR=%v (dirty: %v), but expected 10 and 20", metricData.AvgL, metricData.AvgR, j.StatsDirty())
+	}
+
+	// Up to date stats aren't recomputed (which overwriting one of them tells).
+	metricData.AvgL = -1
+	j.EnsureStats()
+	if metricData.AvgL != -1 {
+		t.Errorf("Stats of unchanged samples recomputed")
+	}
+	// Unlike when forced.
+	j.ComputeStatsForMetricSamples()
+	if metricData.AvgL != 10 {
+		t.Errorf("Stats not recomputed by ComputeStatsForMetricSamples")
+	}
+
+	// Runs appended make the metrics getting values dirty.
+	j.AppendRuns([]map[string][]perftype.PerfData{runMetrics(30)}, nil, 0)
+	j.EnsureStats()
+	if metricData.AvgL != 20 || metricData.AvgR != 20 {
+		t.Errorf("Stats computed as AvgL=%v, AvgR=%v after appending runs, but expected 20 and 20", metricData.AvgL, metricData.AvgR)
+	}
+	// So do samples changed directly.
+	metricData.RightJobSample[0] = 40
+	j.EnsureStats()
+	if metricData.AvgR != 40 {
+		t.Errorf("Stats computed as AvgR=%v after changing the sample, but expected 40", metricData.AvgR)
+	}
+	// Weighting the stats makes them all be recomputed.
+	metricData.LeftJobRequestCounts = []float64{1, 3}
+	metricData.statsFingerprint = metricData.samplesFingerprint()
+	j.WeightByRequestCount = true
+	j.EnsureStats()
+	if metricData.AvgL != 25 {
+		t.Errorf("Stats computed as AvgL=%v after weighting them, but expected 25", metricData.AvgL)
+	}
+}
+
+func TestNegativeSamplePolicies(t *testing.T) {
+	jobMetrics := []map[string][]perftype.PerfData{
+		{
+			"Load": []perftype.PerfData{
+				{
+					Version: "v1",
+					DataItems: []perftype.DataItem{
+						{
+							Data:   map[string]float64{"Perc50": -5, "Perc90": -1, "Perc99": 20},
+							Unit:   "ms",
+							Labels: map[string]string{"Metric": "pod_startup"},
+						},
+						{
+							Data:   map[string]float64{"Perc90": 10},
+							Unit:   "ms",
+							Labels: map[string]string{"Metric": "pod_startup"},
+						},
+					},
+				},
+			},
+		},
+	}
+	perc50Key := MetricKey{TestName: "Load", Verb: "Pod-Startup", Percentile: "Perc50"}
+	perc90Key := MetricKey{TestName: "Load", Verb: "Pod-Startup", Percentile: "Perc90"}
+	perc99Key := MetricKey{TestName: "Load", Verb: "Pod-Startup", Percentile: "Perc99"}
+
+	testCases := []struct {
+		policy              NegativeSamplePolicy
+		expectedSample      []float64
+		expectedMixedSample []float64
+	}{
+		{policy: WarnOnNegativeSamples, expectedSample: []float64{-5}, expectedMixedSample: []float64{-1, 10}},
+		{policy: DropNegativeSamples, expectedSample: nil, expectedMixedSample: []float64{10}},
+		{policy: ClampNegativeSamples, expectedSample: []float64{0}, expectedMixedSample: []float64{0, 10}},
+	}
+	for _, testCase := range testCases {
+		j := GetFlattennedComparisonDataWithOptions(jobMetrics, nil, FlattenOptions{NegativeSamplePolicy: testCase.policy})
+		if testCase.expectedSample == nil {
+			// Like with NaNs, a metric whose values all got dropped isn't added.
+			if metricData, ok := j.Data[perc50Key]; ok {
+				t.Errorf("With policy %v, metric with only negative values added: %v", testCase.policy, *metricData)
+			}
+		} else {
+			if !reflect.DeepEqual(j.Data[perc50Key].LeftJobSample, testCase.expectedSample) {
+				t.Errorf("With policy %v, negative sample flattened to %v, but expected %v", testCase.policy, j.Data[perc50Key].LeftJobSample, testCase.expectedSample)
+			}
+			if j.Data[perc50Key].NegativeSampleCount != 1 {
+				t.Errorf("With policy %v, negative sample count is %v, but expected 1", testCase.policy, j.Data[perc50Key].NegativeSampleCount)
+			}
+		}
+		// The negative value comes before the metric gets added, but is still counted.
+		if !reflect.DeepEqual(j.Data[perc90Key].LeftJobSample, testCase.expectedMixedSample) || j.Data[perc90Key].NegativeSampleCount != 1 {
+			t.Errorf("With policy %v, metric with a negative and a positive value wrongly handled: %v", testCase.policy, *j.Data[perc90Key])
+		}
+		if !reflect.DeepEqual(j.Data[perc99Key].LeftJobSample, []float64{20}) || j.Data[perc99Key].NegativeSampleCount != 0 {
+			t.Errorf("With policy %v, non-negative sample wrongly handled: %v", testCase.policy, *j.Data[perc99Key])
+		}
+	}
+}
+
+func TestMergedSubresources(t *testing.T) {
+	dataItem := func(latency float64, subresource string) perftype.DataItem {
+		return perftype.DataItem{
+			Data:   map[string]float64{"Perc99": latency},
+			Unit:   "ms",
+			Labels: map[string]string{"Count": "10", "Resource": "pods", "Subresource": subresource, "Verb": "POST"},
+		}
+	}
+	jobMetrics := []map[string][]perftype.PerfData{
+		{
+			"Load": []perftype.PerfData{
+				{
+					Version:   "v1",
+					DataItems: []perftype.DataItem{dataItem(10, ""), dataItem(20, "binding"), dataItem(30, "status"), dataItem(40, "eviction")},
+				},
+			},
+		},
+	}
+	metricKey := func(subresource string) MetricKey {
+		return MetricKey{TestName: "Load", Verb: "POST", Resource: "pods", Subresource: subresource, Percentile: "Perc99"}
+	}
+
+	// By default, every subresource is a metric of its own.
+	j := GetFlattennedComparisonData(jobMetrics, jobMetrics, 10)
+	if len(j.Data) != 4 {
+		t.Errorf("Expected 4 metrics without merging subresources, got %v", len(j.Data))
+	}
+
+	j = GetFlattennedComparisonDataWithOptions(jobMetrics, jobMetrics, FlattenOptions{MinAllowedAPIRequestCount: 10, MergedSubresources: []string{"binding", "status"}})
+	if len(j.Data) != 2 {
+		t.Errorf("Expected 2 metrics after merging subresources, got %v", len(j.Data))
+	}
+	if sample := j.Data[metricKey("")].RightJobSample; !reflect.DeepEqual(sample, []float64{10, 20, 30}) {
+		t.Errorf("Merged sample is %v, but expected %v", sample, []float64{10, 20, 30})
+	}
+	if sample := j.Data[metricKey("eviction")].RightJobSample; !reflect.DeepEqual(sample, []float64{40}) {
+		t.Errorf("Sample of subresource not merged is %v, but expected %v", sample, []float64{40})
+	}
+}
+
+func TestPercentOfBaseline(t *testing.T) {
+	metricKey1 := MetricKey{TestName: "xyz", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	metricKey2 := MetricKey{TestName: "xyz", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}
+	jobComparisonData := &JobComparisonData{
+		Data: map[MetricKey]*MetricComparisonData{
+			metricKey1: {
+				Unit:           "ms",
+				LeftJobSample:  []float64{40, 60},
+				RightJobSample: []float64{60, 60},
+			},
+			metricKey2: {
+				Unit:           "ms",
+				LeftJobSample:  []float64{0, 0},
+				RightJobSample: []float64{10},
+			},
+		},
+	}
+	jobComparisonData.ComputeStatsForMetricSamples()
+	normalized := jobComparisonData.PercentOfBaseline()
+
+	data := normalized.Data[metricKey1]
+	if data.AvgL != 100 || data.AvgR != 120 || data.MaxR != 120 || data.StDevL != 20 || data.Unit != "%" {
+		t.Errorf("Wrongly normalized metric: %+v", *data)
+	}
+	if !reflect.DeepEqual(data.RightJobSample, []float64{120, 120}) {
+		t.Errorf("Wrongly normalized samples: %v", data.RightJobSample)
+	}
+	if jobComparisonData.Data[metricKey1].AvgR != 60 || jobComparisonData.Data[metricKey1].RightJobSample[0] != 60 {
+		t.Errorf("Normalization modified the original data: %+v", *jobComparisonData.Data[metricKey1])
+	}
+	if !math.IsNaN(normalized.Data[metricKey2].AvgR) {
+		t.Errorf("Normalized average of metric with zero baseline is %v, but expected NaN", normalized.Data[metricKey2].AvgR)
+	}
+
+	table := jobComparisonData.formatTable(PrettyPrintOptions{PercentOfBaseline: true})
+	// The max ratio doesn't depend on the normalization.
+	for _, expected := range []string{"100.0%  120.0%  0.0%     120.0%  1.00", "-       -       -        -       -", "MAX-R/L"} {
+		if !strings.Contains(table, expected) {
+			t.Errorf("Table doesn't contain %q:\n%v", expected, table)
+		}
+	}
+
+	expectedOutputs := map[string][]string{
+		JSONFormat:        {`"unit": "%"`, `"avgR": 120,`, `"avgR": null,`},
+		MarkdownFormat:    {"| false | 100.00 | 120.00 |", "| false | - | - |"},
+		OpenMetricsFormat: {`benchmark_comparison_avg_percent{test="xyz",verb="GET",resource="pods",subresource="",scope="",percentile="Perc99",side="right"} 120`},
+	}
+	for format, expectedStrings := range expectedOutputs {
+		var buf bytes.Buffer
+		if err := jobComparisonData.WriteWithOptions(&buf, format, WriteOptions{PercentOfBaseline: true}); err != nil {
+			t.Fatalf("Writing %v output failed: %v", format, err)
+		}
+		for _, expected := range expectedStrings {
+			if !strings.Contains(buf.String(), expected) {
+				t.Errorf("Normalized %v output doesn't contain %q:\n%v", format, expected, buf.String())
+			}
+		}
+	}
+}
+
+func TestAppendRuns(t *testing.T) {
+	runMetrics := func(getLatency, listLatency float64, listCount string) map[string][]perftype.PerfData {
+		return map[string][]perftype.PerfData{
+			"Load": {
+				{
+					Version: "v1",
+					DataItems: []perftype.DataItem{
+						{
+							Data:   map[string]float64{"Perc50": getLatency / 2, "Perc99": getLatency},
+							Unit:   "ms",
+							Labels: map[string]string{"Count": "10", "Resource": "pods", "Verb": "GET"},
+						},
+						{
+							Data:   map[string]float64{"Perc99": listLatency},
+							Unit:   "ms",
+							Labels: map[string]string{"Count": listCount, "Resource": "pods", "Verb": "LIST"},
+						},
+					},
+				},
+			},
+		}
+	}
+	leftJobMetrics := []map[string][]perftype.PerfData{runMetrics(10, 20, "10"), runMetrics(11, 21, "1"), runMetrics(12, 22, "10")}
+	rightJobMetrics := []map[string][]perftype.PerfData{runMetrics(13, 23, "1"), runMetrics(14, 24, "10")}
+
+	// The appended runs should be flattened with the options of the earlier ones.
+	for _, options := range []FlattenOptions{
+		{MinAllowedAPIRequestCount: 5},
+		{MinAllowedAPIRequestCount: 5, KeepRequestCounts: true, PercentilePattern: "Perc99", RecordDrops: true},
+	} {
+		batched := GetFlattennedComparisonDataWithOptions(leftJobMetrics, rightJobMetrics, options)
+		batched.WeightByRequestCount = options.KeepRequestCounts
+
+		j := GetFlattennedComparisonDataWithOptions(leftJobMetrics[:1], rightJobMetrics[:1], options)
+		j.WeightByRequestCount = options.KeepRequestCounts
+		j.ComputeStatsForMetricSamples()
+		if j.StatsDirty() {
+			t.Errorf("Stats marked dirty right after computing them")
+		}
+		j.AppendRuns(leftJobMetrics[1:2], nil, 5)
+		j.AppendRuns(leftJobMetrics[2:], rightJobMetrics[1:], 5)
+		if !j.StatsDirty() {
+			t.Errorf("Stats not marked dirty after appending runs")
+		}
+		j.ComputeStatsForMetricSamples()
+		batched.ComputeStatsForMetricSamples()
+		if j.StatsDirty() {
+			t.Errorf("Stats still marked dirty after recomputing them")
+		}
+		if !reflect.DeepEqual(j.Data, batched.Data) {
+			t.Errorf("With options %+v, appended runs gave different results than a single flattening:\nAppended: %v\nBatched: %v", options, j.Data, batched.Data)
+		}
+		// The drops are the same, though recorded in a different order.
+		if len(j.DropLog()) != len(batched.DropLog()) {
+			t.Errorf("With options %+v, appended runs gave a different drop log than a single flattening:\nAppended: %v\nBatched: %v", options, j.DropLog(), batched.DropLog())
+		}
+		if options.KeepRequestCounts {
+			for metricKey, metricData := range j.Data {
+				if metricKey.Percentile != "Perc99" || len(metricData.LeftJobRequestCounts) != len(metricData.LeftJobSample) {
+					t.Errorf("With options %+v, metric %v wrongly flattened from appended runs: %+v", options, metricKey, *metricData)
+				}
+			}
+		}
+	}
+}
+
+func TestRequestCountWeighting(t *testing.T) {
+	runMetrics := func(latency float64, count string) map[string][]perftype.PerfData {
+		return map[string][]perftype.PerfData{
+			"Load": {
+				{
+					Version: "v1",
+					DataItems: []perftype.DataItem{
+						{
+							Data:   map[string]float64{"Perc99": latency},
+							Unit:   "ms",
+							Labels: map[string]string{"Count": count, "Resource": "pods", "Verb": "LIST"},
+						},
+					},
+				},
+			},
+		}
+	}
+	// The high-count run dominates the weighted avg of the left job.
+	leftJobMetrics := []map[string][]perftype.PerfData{runMetrics(10, "9000"), runMetrics(100, "500"), runMetrics(100, "500")}
+	rightJobMetrics := []map[string][]perftype.PerfData{runMetrics(20, "10"), runMetrics(40, "")}
+	metricKey := MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}
+
+	j := GetFlattennedComparisonDataWithOptions(leftJobMetrics, rightJobMetrics, FlattenOptions{KeepRequestCounts: true})
+	if !reflect.DeepEqual(j.Data[metricKey].LeftJobRequestCounts, []float64{9000, 500, 500}) || !reflect.DeepEqual(j.Data[metricKey].RightJobRequestCounts, []float64{10, 1}) {
+		t.Errorf("Wrong request counts retained: %v and %v", j.Data[metricKey].LeftJobRequestCounts, j.Data[metricKey].RightJobRequestCounts)
+	}
+	j.ComputeStatsForMetricSamples()
+	if j.Data[metricKey].AvgL != 70 {
+		t.Errorf("Unweighted avg computed as %v, but expected 70", j.Data[metricKey].AvgL)
+	}
+
+	j.WeightByRequestCount = true
+	j.ComputeStatsForMetricSamples()
+	metricData := j.Data[metricKey]
+	if math.Abs(metricData.AvgL-19) > 1e-9 || math.Abs(metricData.StDevL-27) > 1e-9 || metricData.MaxL != 100 {
+		t.Errorf("Weighted left stats computed as avg=%v, stdev=%v, max=%v, but expected 19, 27 and 100", metricData.AvgL, metricData.StDevL, metricData.MaxL)
+	}
+	if math.Abs(metricData.AvgR-240.0/11) > 1e-9 {
+		t.Errorf("Weighted right avg computed as %v, but expected %v", metricData.AvgR, 240.0/11)
+	}
+
+	// Weighting has no effect without retained request counts.
+	j = GetFlattennedComparisonData(leftJobMetrics, rightJobMetrics, 0)
+	j.WeightByRequestCount = true
+	j.ComputeStatsForMetricSamples()
+	if j.Data[metricKey].AvgL != 70 {
+		t.Errorf("Avg computed as %v without request counts, but expected 70", j.Data[metricKey].AvgL)
+	}
+}
+
+// benchmarkStats compares the metrics' stats the way schemes do, computing them for each,
+// in repeated comparisons of the same (unchanged) data.
+func benchmarkStats(b *testing.B, computeStats func(j *JobComparisonData)) {
+	j := NewJobComparisonData()
+	for i := 0; i < 200; i++ {
+		metricData := &MetricComparisonData{}
+		for run := 0; run < 50; run++ {
+			metricData.LeftJobSample = append(metricData.LeftJobSample, float64(i+run%7))
+			metricData.RightJobSample = append(metricData.RightJobSample, float64(i+run%5))
+		}
+		j.Data[MetricKey{TestName: "xyz", Verb: "GET", Resource: fmt.Sprintf("resource-%v", i), Percentile: "Perc99"}] = metricData
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for comparison := 0; comparison < 5; comparison++ {
+			computeStats(j)
+		}
+	}
+}
+
+func BenchmarkComputeStatsForMetricSamples(b *testing.B) {
+	benchmarkStats(b, (*JobComparisonData).ComputeStatsForMetricSamples)
+}
+
+func BenchmarkEnsureStats(b *testing.B) {
+	benchmarkStats(b, (*JobComparisonData).EnsureStats)
 }
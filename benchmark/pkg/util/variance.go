@@ -0,0 +1,63 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"math"
+)
+
+// VarianceTest runs a one-sided F-test of the right sample's variance being greater than the
+// left one's, returning the ratio of the (unbiased) variances and the p-value. As the F-test
+// is sensitive to the samples not being normally distributed, it's best used as an early
+// warning (of a metric becoming noisier) rather than a gate. Both are NaN if either sample has
+// less than 2 values, or neither has any spread. A left sample without spread against a right
+// one with some gives an infinite ratio and a p-value of 0.
+func VarianceTest(left, right []float64) (ratio, pValue float64) {
+	varL, varR := SampleVariance(left), SampleVariance(right)
+	if math.IsNaN(varL) || math.IsNaN(varR) || (varL == 0 && varR == 0) {
+		return math.NaN(), math.NaN()
+	}
+	if varL == 0 {
+		return math.Inf(1), 0
+	}
+	ratio = varR / varL
+	return ratio, 1 - FCDF(ratio, float64(len(right)-1), float64(len(left)-1))
+}
+
+// CompareVariances compares each metric's run-to-run variance across the jobs with an F-test
+// (see VarianceTest), setting its VarianceRatio and VariancePValue, and flagging it as having
+// VarianceIncreased if the right job's variance is significantly greater at the significance
+// level alpha. That's regardless of whether the avg changed, as a metric getting flakier often
+// precedes it getting slower. The metrics' verdicts on the avgs are left as they are.
+func (j *JobComparisonData) CompareVariances(alpha float64) {
+	for _, metricData := range j.Data {
+		metricData.VarianceRatio, metricData.VariancePValue = VarianceTest(metricData.LeftJobSample, metricData.RightJobSample)
+		metricData.VarianceIncreased = metricData.VariancePValue < alpha
+	}
+}
+
+// IncreasedVarianceMetrics returns the keys (sorted) of the metrics flagged by CompareVariances
+// as having their variance increased.
+func (j *JobComparisonData) IncreasedVarianceMetrics() []MetricKey {
+	var metricKeys []MetricKey
+	for _, metricPair := range getMetricsSortedByKey(j) {
+		if metricPair.metricData.VarianceIncreased {
+			metricKeys = append(metricKeys, metricPair.metricKey)
+		}
+	}
+	return metricKeys
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestVarianceTest(t *testing.T) {
+	// An F(1, 1) distribution's CDF is 2/pi * atan(sqrt(f)), so that of 25 is about 0.87433.
+	ratio, pValue := VarianceTest([]float64{9, 11}, []float64{5, 15})
+	if ratio != 25 || math.Abs(pValue-0.12567) > 0.00001 {
+		t.Errorf("Variance test gave a ratio of %v and p-value of %v, but expected 25 and 0.12567", ratio, pValue)
+	}
+	if ratio, pValue := VarianceTest([]float64{10, 10}, []float64{5, 15}); !math.IsInf(ratio, 1) || pValue != 0 {
+		t.Errorf("Variance test against a left sample without spread gave %v and %v, but expected +Inf and 0", ratio, pValue)
+	}
+	for _, samples := range [][2][]float64{{{10}, {5, 15}}, {{10, 10}, {10, 10}}} {
+		if ratio, pValue := VarianceTest(samples[0], samples[1]); !math.IsNaN(ratio) || !math.IsNaN(pValue) {
+			t.Errorf("Variance test of %v gave %v and %v, but expected NaNs", samples, ratio, pValue)
+		}
+	}
+}
+
+func TestCompareVariances(t *testing.T) {
+	flakier := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	stable := MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}
+	j := &JobComparisonData{
+		Data: map[MetricKey]*MetricComparisonData{
+			// The avgs are the same, but the right job's runs are all over the place.
+			flakier: {LeftJobSample: []float64{99, 101, 100, 99, 101, 100}, RightJobSample: []float64{80, 120, 100, 70, 130, 100}, Matched: true},
+			stable:  {LeftJobSample: []float64{99, 101, 100, 99, 101, 100}, RightJobSample: []float64{100, 102, 101, 100, 102, 101}, Matched: true},
+		},
+	}
+	j.CompareVariances(0.05)
+	flakierData := j.Data[flakier]
+	if !flakierData.VarianceIncreased || flakierData.VarianceRatio != 650 || flakierData.VariancePValue > 0.0001 {
+		t.Errorf("Flakier metric's variance compared as %+v", flakierData)
+	}
+	if j.Data[stable].VarianceIncreased || j.Data[stable].VarianceRatio != 1 {
+		t.Errorf("Stable metric's variance compared as %+v", j.Data[stable])
+	}
+	if !flakierData.Matched {
+		t.Errorf("Comparing variances changed the verdict")
+	}
+	if increased := j.IncreasedVarianceMetrics(); !reflect.DeepEqual(increased, []MetricKey{flakier}) {
+		t.Errorf("Metrics of increased variance are %v, but expected %v", increased, []MetricKey{flakier})
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"io"
+)
+
+// WarningType tells what kind of data-quality issue a Warning is about.
+type WarningType string
+
+// Types of warnings.
+const (
+	WarningNaNSample       WarningType = "NaNSample"       // A NaN value was dropped while flattening
+	WarningBadRequestCount WarningType = "BadRequestCount" // An API call's request count couldn't be parsed, dropping its values
+	WarningNegativeSample  WarningType = "NegativeSample"  // A negative value was kept, with WarnOnNegativeSamples policy
+	WarningOneSidedMetric  WarningType = "OneSidedMetric"  // A metric has values in only one of the jobs, so it can't really be compared
+)
+
+// Warning is a data-quality issue found with the metrics, e.g. while flattening them, which
+// automated consumers may want to act upon (rather than it only being logged).
+type Warning struct {
+	Type WarningType
+	// Metric is the key of the metric the warning is about. For a whole DataItem (e.g. with
+	// a bad request count), its Percentile is empty.
+	Metric  MetricKey
+	Message string
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("%v: %v (%v)", w.Type, metricName(w.Metric), w.Message)
+}
+
+// warn records a warning about the metric.
+func (j *JobComparisonData) warn(warningType WarningType, metricKey MetricKey, format string, args ...interface{}) {
+	j.warnings = append(j.warnings, Warning{Type: warningType, Metric: metricKey, Message: fmt.Sprintf(format, args...)})
+}
+
+// Warnings returns the warnings about the metrics: those recorded while flattening (in the
+// order they were encountered), followed by one about each metric which has values in only
+// one of the jobs (sorted by key), as of the call.
+func (j *JobComparisonData) Warnings() []Warning {
+	warnings := append([]Warning{}, j.warnings...)
+	for _, metricPair := range getMetricsSortedByKey(j) {
+		key, data := metricPair.metricKey, metricPair.metricData
+		if nL, nR := len(data.LeftJobSample), len(data.RightJobSample); (nL == 0) != (nR == 0) {
+			warnings = append(warnings, Warning{Type: WarningOneSidedMetric, Metric: key, Message: fmt.Sprintf("%v left and %v right values", nL, nR)})
+		}
+	}
+	return warnings
+}
+
+// warningRecord is the JSON representation of a Warning.
+type warningRecord struct {
+	Type    WarningType `json:"type"`
+	Metric  string      `json:"metric"` // Name of the metric (see metricName)
+	Message string      `json:"message"`
+}
+
+// jsonReport is the JSON representation of the job comparison data along with its warnings.
+type jsonReport struct {
+	Metrics  []metricRecord  `json:"metrics"`
+	Warnings []warningRecord `json:"warnings"`
+}
+
+// WriteJSONWithWarnings is like WriteJSONWithNaNPolicy, but writes an object holding the
+// array of metrics (as "metrics") along with the warnings about them (see Warnings, as
+// "warnings"), for automated consumers to see the data-quality issues too.
+func (j *JobComparisonData) WriteJSONWithWarnings(w io.Writer, verbose bool, policy NaNPolicy) error {
+	report := jsonReport{Metrics: j.metricRecords(verbose, policy), Warnings: []warningRecord{}}
+	for _, warning := range j.Warnings() {
+		report.Warnings = append(report.Warnings, warningRecord{Type: warning.Type, Metric: metricName(warning.Metric), Message: warning.Message})
+	}
+	return writeIndentedJSON(w, report, true)
+}
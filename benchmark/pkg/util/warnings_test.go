@@ -0,0 +1,83 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"k8s.io/kubernetes/test/e2e/perftype"
+)
+
+func TestWarnings(t *testing.T) {
+	runMetrics := func(dataItems ...perftype.DataItem) []map[string][]perftype.PerfData {
+		return []map[string][]perftype.PerfData{{"Load": {{Version: "v1", DataItems: dataItems}}}}
+	}
+	podsItem := func(verb, count string, perc99 float64) perftype.DataItem {
+		return perftype.DataItem{Data: map[string]float64{"Perc99": perc99}, Unit: "ms", Labels: map[string]string{"Count": count, "Resource": "pods", "Verb": verb}}
+	}
+	leftMetrics := runMetrics(podsItem("GET", "10", math.NaN()), podsItem("LIST", "10", 100), podsItem("POST", "10", 50))
+	rightMetrics := runMetrics(podsItem("GET", "10", 20), podsItem("LIST", "many", 100), podsItem("POST", "10", -1))
+	j := GetFlattennedComparisonDataWithOptions(leftMetrics, rightMetrics, FlattenOptions{MinAllowedAPIRequestCount: 10})
+
+	getPods := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	listPodsItem := MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods"}
+	listPods := MetricKey{TestName: "Load", Verb: "LIST", Resource: "pods", Percentile: "Perc99"}
+	postPods := MetricKey{TestName: "Load", Verb: "POST", Resource: "pods", Percentile: "Perc99"}
+	expected := []Warning{
+		{Type: WarningNaNSample, Metric: getPods, Message: "dropped a NaN value of the left job"},
+		{Type: WarningBadRequestCount, Metric: listPodsItem, Message: "dropped the values of the right job, as their request count 'many' can't be parsed"},
+		{Type: WarningNegativeSample, Metric: postPods, Message: "kept a negative value -1 of the right job"},
+		{Type: WarningOneSidedMetric, Metric: getPods, Message: "0 left and 1 right values"},
+		{Type: WarningOneSidedMetric, Metric: listPods, Message: "1 left and 0 right values"},
+	}
+	if warnings := j.Warnings(); !reflect.DeepEqual(warnings, expected) {
+		t.Errorf("Warnings mismatched:\nReal: %v\nExpected: %v", warnings, expected)
+	}
+
+	// They're only in the JSON output if asked for, with the metrics masked like the rest.
+	var buf bytes.Buffer
+	if err := j.WriteWithOptions(&buf, JSONFormat, WriteOptions{}); err != nil {
+		t.Fatalf("Writing JSON failed: %v", err)
+	}
+	var records []map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &records); err != nil {
+		t.Errorf("JSON without warnings isn't an array of metrics: %v", err)
+	}
+	buf.Reset()
+	if err := j.WriteWithOptions(&buf, JSONFormat, WriteOptions{IncludeWarnings: true, Mask: &LabelMask{Pattern: regexp.MustCompile("pods")}}); err != nil {
+		t.Fatalf("Writing JSON with warnings failed: %v", err)
+	}
+	var report struct {
+		Metrics  []map[string]interface{} `json:"metrics"`
+		Warnings []map[string]string      `json:"warnings"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("JSON with warnings is malformed: %v\n%v", err, buf.String())
+	}
+	if len(report.Metrics) != 3 || len(report.Warnings) != 5 {
+		t.Fatalf("JSON with warnings holds %v metrics and %v warnings, but expected 3 and 5:\n%v", len(report.Metrics), len(report.Warnings), buf.String())
+	}
+	expectedWarning := map[string]string{"type": "BadRequestCount", "metric": "Load LIST " + DefaultMaskPlaceholder, "message": expected[1].Message}
+	if !reflect.DeepEqual(report.Warnings[1], expectedWarning) {
+		t.Errorf("Warning written as %v, but expected %v", report.Warnings[1], expectedWarning)
+	}
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// Parameters for retrying webhook posts that failed with a server error.
+var (
+	WebhookMaxAttempts    = 3
+	WebhookRetryBaseDelay = time.Second
+)
+
+// PostToWebhook serializes the job comparison data in the given format and POSTs it to the url.
+// Requests failing due to server errors (5xx) are retried with an exponential backoff. The
+// ctx bounds the time taken by all the attempts together.
+func (j *JobComparisonData) PostToWebhook(ctx context.Context, url string, format string) error {
+	var body bytes.Buffer
+	if err := j.Write(&body, format); err != nil {
+		return fmt.Errorf("couldn't serialize comparison data: %v", err)
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < WebhookMaxAttempts; attempt++ {
+		if attempt > 0 {
+			delay := WebhookRetryBaseDelay * time.Duration(1<<uint(attempt-1))
+			glog.V(2).Infof("Retrying webhook post to %v in %v after: %v", url, delay, lastErr)
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("webhook post interrupted: %v (last error: %v)", ctx.Err(), lastErr)
+			case <-time.After(delay):
+			}
+		}
+		request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body.Bytes()))
+		if err != nil {
+			return fmt.Errorf("couldn't create webhook request: %v", err)
+		}
+		request = request.WithContext(ctx)
+		request.Header.Set("Content-Type", contentTypeForFormat(format))
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			if ctx.Err() != nil {
+				return fmt.Errorf("webhook post interrupted: %v", err)
+			}
+			lastErr = err
+			continue
+		}
+		io.Copy(ioutil.Discard, response.Body)
+		response.Body.Close()
+		switch {
+		case response.StatusCode >= 500:
+			lastErr = fmt.Errorf("server error: %v", response.Status)
+			continue
+		case response.StatusCode >= 300:
+			return fmt.Errorf("webhook rejected the post: %v", response.Status)
+		}
+		return nil
+	}
+	return fmt.Errorf("webhook post failed after %v attempts: %v", WebhookMaxAttempts, lastErr)
+}
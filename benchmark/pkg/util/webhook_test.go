@@ -0,0 +1,91 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPostToWebhook(t *testing.T) {
+	defer func(delay time.Duration) { WebhookRetryBaseDelay = delay }(WebhookRetryBaseDelay)
+	WebhookRetryBaseDelay = time.Millisecond
+	jobComparisonData := &JobComparisonData{
+		Data: map[MetricKey]*MetricComparisonData{
+			{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}: {LeftJobSample: []float64{1}, RightJobSample: []float64{2}},
+		},
+	}
+	jobComparisonData.ComputeStatsForMetricSamples()
+
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Unexpected content type %v", r.Header.Get("Content-Type"))
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		var records []metricRecord
+		if err := json.Unmarshal(body, &records); err != nil || len(records) != 1 {
+			t.Errorf("Unexpected body posted (err: %v): %s", err, body)
+		}
+	}))
+	defer server.Close()
+	if err := jobComparisonData.PostToWebhook(context.Background(), server.URL, JSONFormat); err != nil {
+		t.Errorf("Posting to webhook failed: %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("Webhook was called %v times, but expected 2 (retry after server error)", attempts)
+	}
+
+	// Client errors shouldn't be retried.
+	attempts = 0
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if !strings.HasPrefix(r.Header.Get("Content-Type"), "text/markdown") {
+			t.Errorf("Unexpected content type %v", r.Header.Get("Content-Type"))
+		}
+		w.WriteHeader(http.StatusBadRequest)
+	})
+	if err := jobComparisonData.PostToWebhook(context.Background(), server.URL, MarkdownFormat); err == nil || attempts != 1 {
+		t.Errorf("Expected a single failed attempt on client error, got %v attempts (err: %v)", attempts, err)
+	}
+
+	// Persistent server errors should stop being retried once the context is done.
+	WebhookRetryBaseDelay = time.Hour
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := jobComparisonData.PostToWebhook(ctx, server.URL, JSONFormat); err == nil {
+		t.Errorf("Expected an error on persistent server errors")
+	}
+
+	if err := jobComparisonData.PostToWebhook(context.Background(), server.URL, "yaml"); err == nil {
+		t.Errorf("Expected an error for unknown format")
+	}
+}
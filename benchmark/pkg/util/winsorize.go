@@ -0,0 +1,50 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+// Winsorize clamps each metric's left and right sample values to the [lowPct, highPct]
+// percentile range (given as fractions in [0, 1]) of their own side, bounding the influence
+// a single dominant run has on the stats. Unlike trimming, which drops the values outside
+// the range, winsorizing keeps them (at the range's bounds), so the sample sizes and the
+// alignment with request counts and timestamps are preserved. Samples are left untouched if
+// the range is invalid. It should be done before computing the stats, which it marks dirty.
+func (j *JobComparisonData) Winsorize(lowPct, highPct float64) {
+	if !(0 <= lowPct && lowPct <= highPct && highPct <= 1) {
+		return
+	}
+	for _, metricData := range j.Data {
+		winsorizeSample(metricData.LeftJobSample, lowPct, highPct)
+		winsorizeSample(metricData.RightJobSample, lowPct, highPct)
+		metricData.invalidateStats()
+	}
+	j.statsDirty = true
+}
+
+func winsorizeSample(sample []float64, lowPct, highPct float64) {
+	if len(sample) == 0 || hasNaN(sample) {
+		return
+	}
+	sorted := sortedCopy(sample)
+	low, high := percentileOfSorted(sorted, lowPct), percentileOfSorted(sorted, highPct)
+	for i, value := range sample {
+		if value < low {
+			sample[i] = low
+		} else if value > high {
+			sample[i] = high
+		}
+	}
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWinsorize(t *testing.T) {
+	metricKey := MetricKey{TestName: "Load", Verb: "GET", Resource: "pods", Percentile: "Perc99"}
+	j := &JobComparisonData{
+		Data: map[MetricKey]*MetricComparisonData{
+			metricKey: {
+				LeftJobSample:  []float64{10, 11, 12, 13, 14, 15, 16, 17, 18, 100},
+				RightJobSample: []float64{-50, 20, 20, 20, 20},
+			},
+		},
+	}
+	j.ComputeStatsForMetricSamples()
+
+	// Invalid ranges leave the samples alone.
+	j.Winsorize(0.9, 0.1)
+	if metricData := j.Data[metricKey]; metricData.LeftJobSample[9] != 100 || j.StatsDirty() {
+		t.Errorf("Samples winsorized with an invalid range: %+v", metricData)
+	}
+
+	j.Winsorize(0, 0.8)
+	metricData := j.Data[metricKey]
+	// The extreme values are clamped to the 80th percentile (17.2 on the left and 20 on the right), not dropped.
+	if expected := []float64{10, 11, 12, 13, 14, 15, 16, 17, 17.2, 17.2}; !reflect.DeepEqual(metricData.LeftJobSample, expected) {
+		t.Errorf("Left sample winsorized as %v, but expected %v", metricData.LeftJobSample, expected)
+	}
+	if expected := []float64{-50, 20, 20, 20, 20}; !reflect.DeepEqual(metricData.RightJobSample, expected) {
+		t.Errorf("Right sample winsorized as %v, but expected %v", metricData.RightJobSample, expected)
+	}
+	if !j.StatsDirty() {
+		t.Errorf("Stats not marked dirty by winsorizing")
+	}
+
+	j.Winsorize(0.25, 1)
+	if expected := []float64{20, 20, 20, 20, 20}; !reflect.DeepEqual(metricData.RightJobSample, expected) {
+		t.Errorf("Right sample winsorized as %v, but expected %v", metricData.RightJobSample, expected)
+	}
+}